@@ -95,18 +95,14 @@ func (acc *Account) Copy() *Account {
 }
 
 func (acc *Account) Equal(accOther *Account) bool {
-	buf := proto.NewBuffer(nil)
-	err := buf.Marshal(acc)
+	accEnc, err := proto.Marshal(acc)
 	if err != nil {
 		return false
 	}
-	accEnc := buf.Bytes()
-	buf.Reset()
-	err = buf.Marshal(accOther)
+	accOtherEnc, err := proto.Marshal(accOther)
 	if err != nil {
 		return false
 	}
-	accOtherEnc := buf.Bytes()
 	return bytes.Equal(accEnc, accOtherEnc)
 }
 
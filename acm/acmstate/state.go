@@ -110,6 +110,13 @@ type StorageIterable interface {
 	IterateStorage(address crypto.Address, consumer func(key binary.Word256, value []byte) error) (err error)
 }
 
+type StorageRangeIterable interface {
+	// Iterates through the storage of account at address in ascending order of key from start (inclusive) to end
+	// (exclusive, or unbounded if nil) without visiting the keys outside that range
+	IterateStorageRange(address crypto.Address, start binary.Word256, end *binary.Word256,
+		consumer func(key binary.Word256, value []byte) error) (err error)
+}
+
 type MetadataReader interface {
 	// Get an Metadata by its hash. This is content-addressed
 	GetMetadata(metahash MetadataHash) (string, error)
@@ -187,3 +194,42 @@ func GlobalAccountPermissions(getter AccountGetter) (permission.AccountPermissio
 	}
 	return acc.Permissions, nil
 }
+
+var errEndOfStorageRange = fmt.Errorf("end of storage range")
+
+// IterateStorageRange calls consumer with each storage key of address from start (inclusive) to end (exclusive, or
+// unbounded if nil) until limit entries have been visited (no limit if zero). It relies on iterable visiting keys in
+// ascending order, as State does. If iterable is a StorageRangeIterable only the keys in range are visited, otherwise
+// the account's storage is walked from its first key. If the range was cut short by limit then the key at which to
+// resume is returned.
+func IterateStorageRange(iterable StorageIterable, address crypto.Address, start binary.Word256, end *binary.Word256,
+	limit uint64, consumer func(key binary.Word256, value []byte) error) (*binary.Word256, error) {
+	var next *binary.Word256
+	count := uint64(0)
+	visit := func(key binary.Word256, value []byte) error {
+		if limit > 0 && count == limit {
+			next = &key
+			return errEndOfStorageRange
+		}
+		count++
+		return consumer(key, value)
+	}
+	var err error
+	if ranged, ok := iterable.(StorageRangeIterable); ok {
+		err = ranged.IterateStorageRange(address, start, end, visit)
+	} else {
+		err = iterable.IterateStorage(address, func(key binary.Word256, value []byte) error {
+			if key.Compare(start) < 0 {
+				return nil
+			}
+			if end != nil && key.Compare(*end) >= 0 {
+				return errEndOfStorageRange
+			}
+			return visit(key, value)
+		})
+	}
+	if err != nil && err != errEndOfStorageRange {
+		return nil, err
+	}
+	return next, nil
+}
@@ -4,6 +4,7 @@
 package acmstate
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"sync"
@@ -242,6 +243,93 @@ func (cache *Cache) Sync(st Writer) error {
 	return nil
 }
 
+// AccountChange describes how an account held by a Cache differs from the account in its backend
+type AccountChange struct {
+	Address crypto.Address
+	// The account in the backend or nil if it does not exist there
+	Before *acm.Account
+	// The account in the cache or nil if it has been removed
+	After *acm.Account
+	// Storage written in the cache whose value differs from the backend's, in order of key
+	Storage []StorageChange
+}
+
+type StorageChange struct {
+	Key    binary.Word256
+	Before []byte
+	After  []byte
+}
+
+// Changes returns, in order of address, what would be written to the backend by Sync where it differs from the
+// backend's current value
+func (cache *Cache) Changes() ([]*AccountChange, error) {
+	if cache.readonly {
+		return nil, nil
+	}
+	cache.RLock()
+	defer cache.RUnlock()
+	var addresses crypto.Addresses
+	for address := range cache.accounts {
+		addresses = append(addresses, address)
+	}
+
+	sort.Sort(addresses)
+	var changes []*AccountChange
+	for _, address := range addresses {
+		accInfo := cache.accounts[address]
+		accInfo.RLock()
+		change, err := cache.change(address, accInfo)
+		accInfo.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (cache *Cache) change(address crypto.Address, accInfo *accountInfo) (*AccountChange, error) {
+	if !accInfo.removed && !accInfo.updated {
+		return nil, nil
+	}
+	before, err := cache.backend.GetAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	if accInfo.removed {
+		if before == nil {
+			return nil, nil
+		}
+		return &AccountChange{Address: address, Before: before}, nil
+	}
+	change := &AccountChange{Address: address, Before: before, After: accInfo.account}
+	var keys binary.Words256
+	for key := range accInfo.storage {
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+	for _, key := range keys {
+		value := accInfo.storage[key]
+		var previous []byte
+		if before != nil {
+			// Values read but not written are also cached
+			previous, err = cache.backend.GetStorage(address, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !bytes.Equal(previous, value) {
+			change.Storage = append(change.Storage, StorageChange{Key: key, Before: previous, After: value})
+		}
+	}
+	if len(change.Storage) == 0 && before != nil && before.Equal(accInfo.account) {
+		return nil, nil
+	}
+	return change, nil
+}
+
 // Resets the cache to empty initialising the backing map to the same size as the previous iteration.
 func (cache *Cache) Reset(backend Reader) {
 	cache.Lock()
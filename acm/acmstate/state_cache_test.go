@@ -281,6 +281,55 @@ func TestStateCache_Sync(t *testing.T) {
 	require.Nil(t, newAccOut)
 }
 
+func TestStateCache_Changes(t *testing.T) {
+	backend := testAccounts()
+	cache := NewCache(backend)
+	acc1Address, acc2Address := addressOf("acc1"), addressOf("acc2")
+
+	// Reads are not changes
+	_, err := cache.GetStorage(acc1Address, word("I AM A KEY"))
+	require.NoError(t, err)
+	acc1, err := cache.GetAccount(acc1Address)
+	require.NoError(t, err)
+	require.NoError(t, cache.UpdateAccount(acc1))
+	// Neither are writes of the existing value
+	require.NoError(t, cache.SetStorage(acc1Address, word("I AM A KEY"), []byte("NO YOU ARE A KEY")))
+	changes, err := cache.Changes()
+	require.NoError(t, err)
+	assert.Len(t, changes, 0)
+
+	acc1.Balance = 42
+	require.NoError(t, cache.UpdateAccount(acc1))
+	require.NoError(t, cache.SetStorage(acc1Address, word("I AM A KEY"), []byte("I AM")))
+	require.NoError(t, cache.SetStorage(acc1Address, word("new"), []byte("value")))
+	require.NoError(t, cache.RemoveAccount(acc2Address))
+	newAcc := acm.NewAccountFromSecret("newAcc")
+	require.NoError(t, cache.UpdateAccount(newAcc))
+
+	changes, err = cache.Changes()
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	for _, change := range changes {
+		switch change.Address {
+		case acc1Address:
+			assert.Equal(t, uint64(0), change.Before.Balance)
+			assert.Equal(t, uint64(42), change.After.Balance)
+			assert.Equal(t, []StorageChange{
+				{Key: word("new"), Before: nil, After: []byte("value")},
+				{Key: word("I AM A KEY"), Before: []byte("NO YOU ARE A KEY"), After: []byte("I AM")},
+			}, change.Storage)
+		case acc2Address:
+			assert.NotNil(t, change.Before)
+			assert.Nil(t, change.After)
+		case newAcc.Address:
+			assert.Nil(t, change.Before)
+			assert.True(t, newAcc.Equal(change.After))
+		default:
+			t.Fatalf("unexpected change to %v", change.Address)
+		}
+	}
+}
+
 func TestStateCache_get(t *testing.T) {
 	backend := NewCache(NewMemoryState())
 	cache := NewCache(backend)
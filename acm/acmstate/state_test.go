@@ -0,0 +1,72 @@
+package acmstate
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterateStorageRange(t *testing.T) {
+	st := sortedStorage{NewMemoryState()}
+	address := addressOf("contract")
+	for i := byte(1); i <= 10; i++ {
+		require.NoError(t, st.SetStorage(address, wordOf(i), []byte{i}))
+	}
+
+	collect := func(start binary.Word256, end *binary.Word256, limit uint64) ([]byte, *binary.Word256) {
+		var values []byte
+		next, err := IterateStorageRange(st, address, start, end, limit, func(key binary.Word256, value []byte) error {
+			values = append(values, value[0])
+			return nil
+		})
+		require.NoError(t, err)
+		return values, next
+	}
+
+	values, next := collect(binary.Zero256, nil, 0)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, values)
+	assert.Nil(t, next)
+
+	end := wordOf(7)
+	values, next = collect(wordOf(3), &end, 0)
+	assert.Equal(t, []byte{3, 4, 5, 6}, values)
+	assert.Nil(t, next)
+
+	values, next = collect(wordOf(3), &end, 2)
+	assert.Equal(t, []byte{3, 4}, values)
+	require.NotNil(t, next)
+	assert.Equal(t, wordOf(5), *next)
+
+	// Exactly filling the limit leaves nothing to resume from
+	values, next = collect(wordOf(3), &end, 4)
+	assert.Equal(t, []byte{3, 4, 5, 6}, values)
+	assert.Nil(t, next)
+}
+
+func wordOf(i byte) binary.Word256 {
+	return binary.LeftPadWord256([]byte{i})
+}
+
+// Visits storage in ascending order of key as State does
+type sortedStorage struct {
+	*MemoryState
+}
+
+func (ss sortedStorage) IterateStorage(address crypto.Address,
+	consumer func(key binary.Word256, value []byte) error) error {
+	var keys []binary.Word256
+	for key := range ss.Storage[address] {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Compare(keys[j]) < 0 })
+	for _, key := range keys {
+		if err := consumer(key, ss.Storage[address][key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
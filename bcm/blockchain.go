@@ -258,6 +258,15 @@ func (bc *Blockchain) GetBlockHeader(height uint64) (*types.Header, error) {
 	return &blockMeta.Header, nil
 }
 
+// GetBlock returns the block at any given height including its transactions
+func (bc *Blockchain) GetBlock(height uint64) (*Block, error) {
+	if bc.blockStore == nil {
+		return nil, fmt.Errorf("GetBlock(): could not get block because Blockchain has not been given access to " +
+			"tendermint BlockStore")
+	}
+	return bc.blockStore.Block(int64(height))
+}
+
 // GetNumTxs returns the number of transactions included in a block
 func (bc *Blockchain) GetNumTxs(height uint64) (int, error) {
 	const errHeader = "GetNumTxs():"
@@ -59,7 +59,7 @@ func Dump(output Output) func(cmd *cli.Cmd) {
 					output.Fatalf("could not obtain config: %v", err)
 				}
 
-				kern, err := core.NewKernel(conf.BurrowDir)
+				kern, err := core.NewKernelWithDatabase(conf.BurrowDir, conf.Database)
 				if err != nil {
 					output.Fatalf("could not create burrow kernel: %v", err)
 				}
@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"github.com/hyperledger/burrow/config"
+	"github.com/hyperledger/burrow/core"
+	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/forensics"
 
 	"github.com/hyperledger/burrow/bcm"
@@ -52,7 +54,10 @@ func Explore(output Output) func(cmd *cli.Cmd) {
 			}
 
 			cmd.Action = func() {
-				replay := forensics.NewSourceFromDir(conf.GenesisDoc, *stateDir)
+				replay, err := forensics.NewSourceFromDir(conf.GenesisDoc, *stateDir, conf.Database)
+				if err != nil {
+					output.Fatalf("could not open state: %v", err)
+				}
 				height := uint64(*heightOpt)
 				if height == 0 {
 					height, err = replay.LatestHeight()
@@ -60,7 +65,7 @@ func Explore(output Output) func(cmd *cli.Cmd) {
 						output.Fatalf("could not read latest height: %v", err)
 					}
 				}
-				err := replay.LoadAt(height)
+				err = replay.LoadAt(height)
 				if err != nil {
 					output.Fatalf("could not load state: %v", err)
 				}
@@ -85,14 +90,16 @@ func Explore(output Output) func(cmd *cli.Cmd) {
 			}
 
 			cmd.Action = func() {
-				replay1 := forensics.NewReplay(
-					forensics.NewSourceFromDir(conf.GenesisDoc, *goodDir),
-					forensics.NewSourceFromGenesis(conf.GenesisDoc),
-				)
-				replay2 := forensics.NewReplay(
-					forensics.NewSourceFromDir(conf.GenesisDoc, *badDir),
-					forensics.NewSourceFromGenesis(conf.GenesisDoc),
-				)
+				good, err := forensics.NewSourceFromDir(conf.GenesisDoc, *goodDir, conf.Database)
+				if err != nil {
+					output.Fatalf("could not open state: %v", err)
+				}
+				bad, err := forensics.NewSourceFromDir(conf.GenesisDoc, *badDir, conf.Database)
+				if err != nil {
+					output.Fatalf("could not open state: %v", err)
+				}
+				replay1 := forensics.NewReplay(good, forensics.NewSourceFromGenesis(conf.GenesisDoc))
+				replay2 := forensics.NewReplay(bad, forensics.NewSourceFromGenesis(conf.GenesisDoc))
 
 				h1, err := replay1.Src.LatestHeight()
 				if err != nil {
@@ -166,7 +173,10 @@ func Explore(output Output) func(cmd *cli.Cmd) {
 			rangeArg := cmd.StringArg("RANGE", "", "Range as START_HEIGHT:END_HEIGHT where omitting "+
 				"either endpoint implicitly describes the start/end and a negative index counts back from the last block")
 
-			cmd.Spec = "[RANGE]"
+			stateDiffOpt := cmd.BoolOpt("state-diff", false, "Include the changes each transaction made to "+
+				"account state (only recorded when the genesis enables StateDiffs)")
+
+			cmd.Spec = "[--state-diff] [RANGE]"
 
 			cmd.Action = func() {
 				start, end, err := parseRange(*rangeArg)
@@ -174,16 +184,37 @@ func Explore(output Output) func(cmd *cli.Cmd) {
 					output.Fatalf("could not parse range '%s': %v", *rangeArg, err)
 				}
 
+				var kern *core.Kernel
+				if *stateDiffOpt {
+					kern, err = core.NewKernelWithDatabase(conf.BurrowDir, conf.Database)
+					if err != nil {
+						output.Fatalf("could not create Burrow kernel: %v", err)
+					}
+					err = kern.LoadState(conf.GenesisDoc)
+					if err != nil {
+						output.Fatalf("could not load Burrow state: %v", err)
+					}
+				}
+
 				err = explorer.Blocks(start, end,
 					func(block *bcm.Block) error {
 						err := block.Transactions(func(txEnv *txs.Envelope) error {
 							wrapper := struct {
-								Height int64
-								Tx     *txs.Envelope
+								Height    int64
+								Tx        *txs.Envelope
+								StateDiff *exec.StateDiff `json:",omitempty"`
 							}{
 								Height: block.Height,
 								Tx:     txEnv,
 							}
+							if kern != nil {
+								txe, err := kern.State.TxByHash(txEnv.Tx.Hash())
+								if err != nil {
+									output.Fatalf("Could not read execution of transaction %v: %v",
+										txEnv.Tx.Hash(), err)
+								}
+								wrapper.StateDiff = txe.GetStateDiff()
+							}
 							bs, err := json.Marshal(wrapper)
 							if err != nil {
 								output.Fatalf("Could not deserialise transaction: %v", err)
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/hyperledger/burrow/bcm"
+	"github.com/hyperledger/burrow/config/source"
+	"github.com/hyperledger/burrow/core"
+	"github.com/hyperledger/burrow/execution/state"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/storage"
+	cli "github.com/jawher/mow.cli"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// The databases Tendermint may keep in its data directory
+var tendermintDBNames = []string{"blockstore", "state", "evidence", "tx_index"}
+
+// MigrateDB copies the databases of a Burrow directory into a new directory using different backends
+func MigrateDB(output Output) func(cmd *cli.Cmd) {
+	return func(cmd *cli.Cmd) {
+		configFileOpt := cmd.String(configFileOption)
+		genesisFileOpt := cmd.String(genesisFileOption)
+		forestOpt := cmd.StringOpt("forest", "", "Backend for the forest, defaults to the one in config")
+		plainOpt := cmd.StringOpt("plain", "", "Backend for the plain, defaults to the one in config")
+		blockStoreOpt := cmd.StringOpt("block-store", "",
+			"Backend for the Tendermint block store, defaults to the one in config")
+		destinationArg := cmd.StringArg("DEST", "", "Directory in which to write the migrated databases")
+		cmd.Spec += "[--forest=<backend>] [--plain=<backend>] [--block-store=<backend>] " + configFileSpec + " " +
+			genesisFileSpec + " DEST"
+
+		cmd.Action = func() {
+			conf, err := obtainDefaultConfig(*configFileOpt, *genesisFileOpt)
+			if err != nil {
+				output.Fatalf("could not obtain config: %v", err)
+			}
+			if conf.GenesisDoc == nil {
+				output.Fatalf("genesis doc is required")
+			}
+			tmConf, err := conf.TendermintConfig()
+			if err != nil {
+				output.Fatalf("could not build Tendermint config: %v", err)
+			}
+			from := conf.Database
+			if from == nil {
+				from = state.DefaultDatabaseConfig()
+			}
+			to := &state.DatabaseConfig{
+				Forest:     backendOrDefault(*forestOpt, from.Forest),
+				Plain:      backendOrDefault(*plainOpt, from.Plain),
+				BlockStore: backendOrDefault(*blockStoreOpt, dbm.BackendType(tmConf.DBBackend)),
+			}
+			destination := *destinationArg
+			fromDataDir := tmConf.DBDir()
+			toDataDir := filepath.Join(destination, tmConf.DBPath)
+			for _, name := range append(to.Databases(), state.PlainDBName) {
+				if state.Exists(name, destination) {
+					output.Fatalf("database %s already exists in %s", name, destination)
+				}
+			}
+
+			// Burrow's state is routed across databases by the config on each side
+			fromDB, err := from.Open(conf.BurrowDir)
+			if err != nil {
+				output.Fatalf("could not open source database: %v", err)
+			}
+			_, exists, err := bcm.LoadOrNewBlockchain(fromDB, conf.GenesisDoc, logging.NewNoopLogger())
+			if err != nil {
+				output.Fatalf("could not load source blockchain: %v", err)
+			}
+			if !exists {
+				output.Fatalf("no Burrow state found in %s", conf.BurrowDir)
+			}
+			toDB, err := to.Open(destination)
+			if err != nil {
+				output.Fatalf("could not open destination database: %v", err)
+			}
+			count, err := storage.CopyDB(toDB, fromDB)
+			if err != nil {
+				output.Fatalf("could not copy Burrow state: %v", err)
+			}
+			output.Logf("Copied %d entries of Burrow state from %v to %v", count, from.Databases(),
+				to.Databases())
+			fromDB.Close()
+			toDB.Close()
+
+			for _, name := range tendermintDBNames {
+				if !state.Exists(name, fromDataDir) {
+					continue
+				}
+				count, err := copyDB(name, dbm.BackendType(tmConf.DBBackend), fromDataDir, to.BlockStore, toDataDir)
+				if err != nil {
+					output.Fatalf("could not copy Tendermint database %s: %v", name, err)
+				}
+				output.Logf("Copied %d entries of Tendermint database %s", count, name)
+			}
+
+			// Check the copy loads to the same state and chain as the original
+			fromKern, err := core.NewKernelWithDatabase(conf.BurrowDir, from)
+			if err != nil {
+				output.Fatalf("could not create Burrow kernel: %v", err)
+			}
+			err = fromKern.LoadState(conf.GenesisDoc)
+			if err != nil {
+				output.Fatalf("could not load source state: %v", err)
+			}
+			toKern, err := core.NewKernelWithDatabase(destination, to)
+			if err != nil {
+				output.Fatalf("could not create Burrow kernel: %v", err)
+			}
+			// This checks the state's hash against the AppHash of the blockchain
+			err = toKern.LoadState(conf.GenesisDoc)
+			if err != nil {
+				output.Fatalf("could not load migrated state: %v", err)
+			}
+			height := toKern.Blockchain.LastBlockHeight()
+			if height != fromKern.Blockchain.LastBlockHeight() ||
+				!bytes.Equal(toKern.State.Hash(), fromKern.State.Hash()) {
+				output.Fatalf("migrated state at height %d has AppHash %X but source has AppHash %X at height %d",
+					height, toKern.State.Hash(), fromKern.State.Hash(), fromKern.Blockchain.LastBlockHeight())
+			}
+			if state.Exists("blockstore", fromDataDir) && height > 0 {
+				fromMeta, err := bcm.NewBlockExplorer(dbm.BackendType(tmConf.DBBackend), fromDataDir).
+					BlockMeta(int64(height))
+				if err != nil || fromMeta == nil {
+					output.Fatalf("could not read block at height %d from source block store: %v", height, err)
+				}
+				toMeta, err := bcm.NewBlockExplorer(to.BlockStore, toDataDir).BlockMeta(int64(height))
+				if err != nil || toMeta == nil {
+					output.Fatalf("could not read block at height %d from migrated block store: %v", height, err)
+				}
+				if !toMeta.BlockID.Equals(fromMeta.BlockID) {
+					output.Fatalf("migrated block store has block %v at height %d but source has block %v",
+						toMeta.BlockID, height, fromMeta.BlockID)
+				}
+			}
+			output.Logf("Migrated state at height %d has AppHash %X, to use it move the databases in %s into "+
+				"place and set the following in config:", height, toKern.State.Hash(), destination)
+			output.Printf("%s", source.TOMLString(struct{ Database *state.DatabaseConfig }{to}))
+		}
+	}
+}
+
+func backendOrDefault(backend string, def dbm.BackendType) dbm.BackendType {
+	if backend == "" {
+		return def
+	}
+	return dbm.BackendType(backend)
+}
+
+func copyDB(name string, fromBackend dbm.BackendType, fromDir string, toBackend dbm.BackendType,
+	toDir string) (int, error) {
+	from, err := state.NewDB(name, fromBackend, fromDir)
+	if err != nil {
+		return 0, err
+	}
+	defer from.Close()
+	to, err := state.NewDB(name, toBackend, toDir)
+	if err != nil {
+		return 0, err
+	}
+	defer to.Close()
+	return storage.CopyDB(to, from)
+}
@@ -25,7 +25,7 @@ func Restore(output Output) func(cmd *cli.Cmd) {
 
 			output.Logf("Using validator address: %s", *conf.ValidatorAddress)
 
-			kern, err := core.NewKernel(conf.BurrowDir)
+			kern, err := core.NewKernelWithDatabase(conf.BurrowDir, conf.Database)
 			if err != nil {
 				output.Fatalf("could not create Burrow kernel: %v", err)
 			}
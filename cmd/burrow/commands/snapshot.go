@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/hyperledger/burrow/bcm"
+	"github.com/hyperledger/burrow/config"
+	"github.com/hyperledger/burrow/core"
+	"github.com/hyperledger/burrow/snapshot"
+	cli "github.com/jawher/mow.cli"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// Snapshot creates, checks, and restores from snapshots of state
+func Snapshot(output Output) func(cmd *cli.Cmd) {
+	return func(cmd *cli.Cmd) {
+		configFileOpt := cmd.String(configFileOption)
+		genesisFileOpt := cmd.String(genesisFileOption)
+		directoryOpt := cmd.StringOpt("d directory", "", "Directory holding snapshots, defaults to the one in config")
+		cmd.Spec += "[--directory=<snapshot directory>] " + configFileSpec + " " + genesisFileSpec
+
+		var conf *config.BurrowConfig
+		var store *snapshot.Store
+
+		cmd.Before = func() {
+			var err error
+			conf, err = obtainDefaultConfig(*configFileOpt, *genesisFileOpt)
+			if err != nil {
+				output.Fatalf("could not obtain config: %v", err)
+			}
+			if conf.GenesisDoc == nil {
+				output.Fatalf("genesis doc is required")
+			}
+			directory := *directoryOpt
+			if directory == "" {
+				directory = conf.Snapshots.Path(conf.BurrowDir)
+			}
+			store = snapshot.NewStore(directory)
+		}
+
+		// The block store lets us check snapshots against the AppHash in block headers
+		blockStore := func() *bcm.BlockStore {
+			tmConf, err := conf.TendermintConfig()
+			if err != nil {
+				output.Fatalf("could not build Tendermint config: %v", err)
+			}
+			return bcm.NewBlockExplorer(dbm.BackendType(tmConf.DBBackend), tmConf.DBDir())
+		}
+
+		// Pick the requested snapshot or the latest one
+		snapshotHeight := func(height int) uint64 {
+			if height > 0 {
+				return uint64(height)
+			}
+			manifests, err := store.List()
+			if err != nil {
+				output.Fatalf("could not list snapshots: %v", err)
+			}
+			if len(manifests) == 0 {
+				output.Fatalf("no snapshots found")
+			}
+			return manifests[len(manifests)-1].Height
+		}
+
+		cmd.Command("create", "snapshot the state of the local Burrow directory", func(cmd *cli.Cmd) {
+			heightOpt := cmd.IntOpt("height", 0, "Height to snapshot, defaults to the latest height")
+			cmd.Spec = "[--height=<height>]"
+
+			cmd.Action = func() {
+				kern, err := core.NewKernelWithDatabase(conf.BurrowDir, conf.Database)
+				if err != nil {
+					output.Fatalf("could not create Burrow kernel: %v", err)
+				}
+				err = kern.LoadState(conf.GenesisDoc)
+				if err != nil {
+					output.Fatalf("could not load Burrow state: %v", err)
+				}
+				height := uint64(*heightOpt)
+				if height == 0 {
+					height = kern.Blockchain.LastBlockHeight()
+				}
+				blockTime := kern.Blockchain.LastBlockTime()
+				if height != kern.Blockchain.LastBlockHeight() {
+					meta, err := blockStore().BlockMeta(int64(height))
+					if err != nil || meta == nil {
+						output.Fatalf("could not get block at height %d: %v", height, err)
+					}
+					blockTime = meta.Header.Time
+				}
+				manifest, err := store.Create(kern.State, conf.GenesisDoc.ChainID(), height, blockTime,
+					conf.Snapshots.ChunkSize)
+				if err != nil {
+					output.Fatalf("could not create snapshot: %v", err)
+				}
+				output.Printf("Created snapshot at height %d with AppHash %v in %d chunks", manifest.Height,
+					manifest.AppHash, len(manifest.ChunkHashes))
+			}
+		})
+
+		cmd.Command("list", "list the snapshots in the snapshot directory", func(cmd *cli.Cmd) {
+			cmd.Action = func() {
+				manifests, err := store.List()
+				if err != nil {
+					output.Fatalf("could not list snapshots: %v", err)
+				}
+				for _, manifest := range manifests {
+					output.Printf("%d\t%v\t%v\t%d chunks", manifest.Height, manifest.BlockTime, manifest.AppHash,
+						len(manifest.ChunkHashes))
+				}
+			}
+		})
+
+		cmd.Command("verify", "check that a snapshot rebuilds the state with the AppHash recorded for its height",
+			func(cmd *cli.Cmd) {
+				heightOpt := cmd.IntOpt("height", 0, "Height of snapshot to verify, defaults to the latest")
+				appHashOpt := cmd.StringOpt("app-hash", "",
+					"Trusted AppHash for the height, if not given it is read from the local block store")
+				cmd.Spec = "[--height=<height>] [--app-hash=<hex AppHash>]"
+
+				cmd.Action = func() {
+					height := snapshotHeight(*heightOpt)
+					manifest, err := store.Verify(height)
+					if err != nil {
+						output.Fatalf("snapshot is invalid: %v", err)
+					}
+					appHash := parseAppHash(output, *appHashOpt)
+					if appHash == nil {
+						// The AppHash resulting from a block is recorded in the header of the next
+						meta, err := blockStore().BlockMeta(int64(height + 1))
+						if err != nil || meta == nil {
+							output.Fatalf("could not get header at height %d to check AppHash against, "+
+								"provide one with --app-hash", height+1)
+						}
+						appHash = meta.Header.AppHash
+					}
+					err = manifest.VerifyAppHash(appHash)
+					if err != nil {
+						output.Fatalf("snapshot does not match chain: %v", err)
+					}
+					output.Printf("Snapshot at height %d is valid with AppHash %v", height, manifest.AppHash)
+				}
+			})
+
+		cmd.Command("restore", "load Burrow's state from a snapshot into an empty Burrow directory (Tendermint's block "+
+			"store and state for the height must be supplied separately)", func(cmd *cli.Cmd) {
+			heightOpt := cmd.IntOpt("height", 0, "Height of snapshot to restore, defaults to the latest")
+			appHashOpt := cmd.StringOpt("app-hash", "", "Trusted AppHash for the height to check the snapshot against")
+			cmd.Spec = "[--height=<height>] [--app-hash=<hex AppHash>]"
+
+			cmd.Action = func() {
+				height := snapshotHeight(*heightOpt)
+				if appHash := parseAppHash(output, *appHashOpt); appHash != nil {
+					manifest, err := store.Manifest(height)
+					if err != nil {
+						output.Fatalf("could not read snapshot: %v", err)
+					}
+					err = manifest.VerifyAppHash(appHash)
+					if err != nil {
+						output.Fatalf("snapshot does not match chain: %v", err)
+					}
+				}
+
+				kern, err := core.NewKernelWithDatabase(conf.BurrowDir, conf.Database)
+				if err != nil {
+					output.Fatalf("could not create Burrow kernel: %v", err)
+				}
+				if err = kern.LoadLoggerFromConfig(conf.Logging); err != nil {
+					output.Fatalf("could not create Burrow kernel: %v", err)
+				}
+				if err = kern.LoadSnapshot(conf.GenesisDoc, store, height); err != nil {
+					output.Fatalf("could not restore snapshot: %v", err)
+				}
+				if blockHeight := blockStore().Height(); blockHeight < int64(height) {
+					output.Printf("Restored state at height %d but the Tendermint block store only reaches height %d, "+
+						"the node cannot start until Tendermint's block store and state for the height are supplied",
+						height, blockHeight)
+				}
+				kern.ShutdownAndExit()
+			}
+		})
+	}
+}
+
+func parseAppHash(output Output, appHash string) []byte {
+	if appHash == "" {
+		return nil
+	}
+	bs, err := hex.DecodeString(strings.TrimPrefix(appHash, "0x"))
+	if err != nil {
+		output.Fatalf("could not decode AppHash: %v", err)
+	}
+	return bs
+}
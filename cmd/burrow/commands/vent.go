@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
@@ -12,8 +13,10 @@ import (
 	"github.com/hyperledger/burrow/config/source"
 	"github.com/hyperledger/burrow/execution/evm/abi"
 	"github.com/hyperledger/burrow/logging/logconfig"
+	"github.com/hyperledger/burrow/vent/api"
 	"github.com/hyperledger/burrow/vent/config"
 	"github.com/hyperledger/burrow/vent/service"
+	"github.com/hyperledger/burrow/vent/sink"
 	"github.com/hyperledger/burrow/vent/sqldb"
 	"github.com/hyperledger/burrow/vent/sqlsol"
 	"github.com/hyperledger/burrow/vent/types"
@@ -38,6 +41,13 @@ func Vent(output Output) func(cmd *cli.Cmd) {
 				dbTxOpt := cmd.BoolOpt("txs", false, "Create tx tables and persist related data")
 
 				announceEveryOpt := cmd.StringOpt("announce-every", "5s", "Announce vent status every period as a Go duration, e.g. 1ms, 3s, 1h")
+				apiOpt := cmd.BoolOpt("api", cfg.API, "Serve a read-only REST and GraphQL API over the database tables at "+api.Path+" on the HTTP server")
+
+				sinkOpt := cmd.StringOpt("sink", cfg.Sink.Type, "Deliver rows to a 'jsonl' file, 'webhook', 'kafka' REST proxy, or 'nats' server instead of the SQL database")
+				sinkURLOpt := cmd.StringOpt("sink-url", cfg.Sink.URL, "JSON Lines file path, webhook URL, Kafka REST proxy URL, or NATS server URL (nats://host:port)")
+				sinkTopicOpt := cmd.StringOpt("sink-topic", cfg.Sink.Topic, "Kafka topic or NATS subject")
+				sinkCheckpointOpt := cmd.StringOpt("sink-checkpoint", cfg.Sink.Checkpoint, "File recording the last block delivered to the sink")
+				sinkRetriesOpt := cmd.IntOpt("sink-retries", cfg.Sink.Retries, "Times to retry a failed delivery before stopping, negative to retry indefinitely")
 
 				cmd.Before = func() {
 					// Rather annoying boilerplate here... but there is no way to pass mow.cli a pointer for it to fill you value
@@ -49,6 +59,12 @@ func Vent(output Output) func(cmd *cli.Cmd) {
 					cfg.LogLevel = *logLevelOpt
 					cfg.AbiFileOrDirs = *abiFileOpt
 					cfg.SpecFileOrDirs = *specFileOrDirOpt
+					cfg.Sink.Type = *sinkOpt
+					cfg.Sink.URL = *sinkURLOpt
+					cfg.Sink.Topic = *sinkTopicOpt
+					cfg.Sink.Checkpoint = *sinkCheckpointOpt
+					cfg.Sink.Retries = *sinkRetriesOpt
+					cfg.API = *apiOpt
 					if *dbBlockOpt {
 						cfg.SpecOpt |= sqlsol.Block
 					}
@@ -66,7 +82,8 @@ func Vent(output Output) func(cmd *cli.Cmd) {
 				}
 
 				cmd.Spec = "--spec=<spec file or dir> [--abi=<abi file or dir>] [--db-adapter] [--db-url] [--db-schema] " +
-					"[--blocks] [--txs] [--grpc-addr] [--http-addr] [--log-level] [--announce-every=<duration>]"
+					"[--blocks] [--txs] [--grpc-addr] [--http-addr] [--log-level] [--announce-every=<duration>] [--api] " +
+					"[--sink=<sink type> --sink-url=<file or URL> [--sink-topic] [--sink-checkpoint] [--sink-retries]]"
 
 				cmd.Action = func() {
 					log, err := logconfig.New().NewLogger()
@@ -83,6 +100,28 @@ func Vent(output Output) func(cmd *cli.Cmd) {
 						output.Fatalf("Spec loader error: %v", err)
 					}
 
+					if cfg.API {
+						if cfg.Sink.Type != sink.SQL {
+							output.Fatalf("The API reads from the database so cannot be used with the %s sink", cfg.Sink.Type)
+						}
+						db, err := sqldb.NewSQLDB(types.SQLConnection{
+							DBAdapter: cfg.DBAdapter,
+							DBURL:     cfg.DBURL,
+							DBSchema:  cfg.DBSchema,
+							Log:       log,
+						})
+						if err != nil {
+							output.Fatalf("Could not connect to SQL DB for API: %v", err)
+						}
+						defer db.Close()
+						handler, err := api.New(projection, db, cfg.DBURL, log)
+						if err != nil {
+							output.Fatalf("Could not build API: %v", err)
+						}
+						defer handler.Close()
+						server.Handle(api.Path+"/", http.StripPrefix(api.Path, handler))
+					}
+
 					var wg sync.WaitGroup
 
 					// setup channel for termination signals
@@ -224,8 +263,8 @@ type dbOpts struct {
 
 func sqlDBOpts(cmd *cli.Cmd, cfg *config.VentConfig) dbOpts {
 	return dbOpts{
-		adapter: cmd.StringOpt("db-adapter", cfg.DBAdapter, "Database adapter, 'postgres' or 'sqlite' (if built with the sqlite tag) are supported"),
-		url:     cmd.StringOpt("db-url", cfg.DBURL, "PostgreSQL database URL or SQLite db file path"),
-		schema:  cmd.StringOpt("db-schema", cfg.DBSchema, "PostgreSQL database schema (empty for SQLite)"),
+		adapter: cmd.StringOpt("db-adapter", cfg.DBAdapter, "Database adapter, 'postgres', 'mysql', or 'sqlite' (if built with the sqlite tag) are supported"),
+		url:     cmd.StringOpt("db-url", cfg.DBURL, "PostgreSQL database URL, MySQL DSN, or SQLite db file path"),
+		schema:  cmd.StringOpt("db-schema", cfg.DBSchema, "PostgreSQL database schema or MySQL database (empty for SQLite)"),
 	}
 }
@@ -78,6 +78,12 @@ func burrow(output commands.Output) *cli.Cli {
 	app.Command("restore", "Restore new chain from backup",
 		commands.Restore(output))
 
+	app.Command("snapshot", "Create, verify, and restore from chunked snapshots of state",
+		commands.Snapshot(output))
+
+	app.Command("migrate-db", "Copy the databases of a Burrow directory to different database backends",
+		commands.MigrateDB(output))
+
 	app.Command("accounts", "List accounts and metadata",
 		commands.Accounts(output))
 
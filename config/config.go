@@ -4,13 +4,16 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/burrow/config/source"
+	"github.com/hyperledger/burrow/consensus/abci"
 	"github.com/hyperledger/burrow/consensus/tendermint"
 	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/state"
 	"github.com/hyperledger/burrow/genesis"
 	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/logging/logconfig"
 	"github.com/hyperledger/burrow/rpc"
+	"github.com/hyperledger/burrow/snapshot"
 	tmConfig "github.com/tendermint/tendermint/config"
 )
 
@@ -27,6 +30,11 @@ type BurrowConfig struct {
 	GenesisDoc *genesis.GenesisDoc                `json:",omitempty" toml:",omitempty"`
 	Tendermint *tendermint.BurrowTendermintConfig `json:",omitempty" toml:",omitempty"`
 	Execution  *execution.ExecutionConfig         `json:",omitempty" toml:",omitempty"`
+	Mempool    *abci.MempoolConfig                `json:",omitempty" toml:",omitempty"`
+	Database   *state.DatabaseConfig              `json:",omitempty" toml:",omitempty"`
+	Pruning    *state.PruningConfig               `json:",omitempty" toml:",omitempty"`
+	Events     *state.EventsConfig                `json:",omitempty" toml:",omitempty"`
+	Snapshots  *snapshot.SnapshotConfig           `json:",omitempty" toml:",omitempty"`
 	Keys       *keys.KeysConfig                   `json:",omitempty" toml:",omitempty"`
 	RPC        *rpc.RPCConfig                     `json:",omitempty" toml:",omitempty"`
 	Logging    *logconfig.LoggingConfig           `json:",omitempty" toml:",omitempty"`
@@ -39,6 +47,10 @@ func DefaultBurrowConfig() *BurrowConfig {
 		Keys:       keys.DefaultKeysConfig(),
 		RPC:        rpc.DefaultRPCConfig(),
 		Execution:  execution.DefaultExecutionConfig(),
+		Database:   state.DefaultDatabaseConfig(),
+		Pruning:    state.DefaultPruningConfig(),
+		Events:     state.DefaultEventsConfig(),
+		Snapshots:  snapshot.DefaultSnapshotConfig(),
 		Logging:    logconfig.DefaultNodeLoggingConfig(),
 	}
 }
@@ -51,7 +63,14 @@ func (conf *BurrowConfig) Verify() error {
 }
 
 func (conf *BurrowConfig) TendermintConfig() (*tmConfig.Config, error) {
-	return conf.Tendermint.Config(conf.BurrowDir, conf.Execution.TimeoutFactor)
+	tmConf, err := conf.Tendermint.Config(conf.BurrowDir, conf.Execution.TimeoutFactor)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Database != nil && conf.Database.BlockStore != "" {
+		tmConf.DBBackend = string(conf.Database.BlockStore)
+	}
+	return tmConf, nil
 }
 
 func (conf *BurrowConfig) JSONString() string {
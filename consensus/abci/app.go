@@ -35,6 +35,7 @@ type App struct {
 	blockchain      *bcm.Blockchain
 	validators      Validators
 	mempoolLocker   sync.Locker
+	mempoolPolicy   *MempoolPolicy
 	authorizedPeers AuthorizedPeers
 	// We need to cache these from BeginBlock for when we need actually need it in Commit
 	block *types.RequestBeginBlock
@@ -42,8 +43,10 @@ type App struct {
 	panicFunc func(error)
 	checker   execution.BatchExecutor
 	committer execution.BatchCommitter
-	txDecoder txs.Decoder
-	logger    *logging.Logger
+	// Set when the committer can execute a block's transactions in parallel ahead of DeliverTx
+	speculator execution.Speculator
+	txDecoder  txs.Decoder
+	logger     *logging.Logger
 }
 
 var _ types.Application = &App{}
@@ -51,12 +54,14 @@ var _ types.Application = &App{}
 func NewApp(nodeInfo string, blockchain *bcm.Blockchain, validators Validators, checker execution.BatchExecutor,
 	committer execution.BatchCommitter, txDecoder txs.Decoder, authorizedPeers AuthorizedPeers,
 	panicFunc func(error), logger *logging.Logger) *App {
+	speculator, _ := committer.(execution.Speculator)
 	return &App{
 		nodeInfo:        nodeInfo,
 		blockchain:      blockchain,
 		validators:      validators,
 		checker:         checker,
 		committer:       committer,
+		speculator:      speculator,
 		txDecoder:       txDecoder,
 		authorizedPeers: authorizedPeers,
 		panicFunc:       panicFunc,
@@ -72,6 +77,11 @@ func (app *App) SetMempoolLocker(mempoolLocker sync.Locker) {
 	app.mempoolLocker = mempoolLocker
 }
 
+// Provide a policy limiting the transactions accepted by CheckTx. Its Reset is called on each Commit.
+func (app *App) SetMempoolPolicy(mempoolPolicy *MempoolPolicy) {
+	app.mempoolPolicy = mempoolPolicy
+}
+
 func (app *App) Info(info types.RequestInfo) types.ResponseInfo {
 	return types.ResponseInfo{
 		Data:             app.nodeInfo,
@@ -160,9 +170,33 @@ func (app *App) BeginBlock(block types.RequestBeginBlock) (respBeginBlock types.
 			}
 		}
 	}
+	if app.speculator != nil {
+		app.speculate(uint64(block.Header.Height))
+	}
 	return
 }
 
+// Tendermint stores a block before applying it so we can hand its transactions to the committer to execute in
+// parallel ahead of DeliverTx. Failing that they are executed in order as they are delivered.
+func (app *App) speculate(height uint64) {
+	var txEnvs []*txs.Envelope
+	block, err := app.blockchain.GetBlock(height)
+	if err == nil {
+		err = block.Transactions(func(txEnv *txs.Envelope) error {
+			txEnvs = append(txEnvs, txEnv)
+			return nil
+		})
+	}
+	if err == nil {
+		err = app.speculator.Speculate(txEnvs)
+	}
+	if err != nil {
+		app.logger.InfoMsg("Could not execute block speculatively",
+			"height", height,
+			structure.ErrorKey, err)
+	}
+}
+
 func (app *App) checkValidatorMatches(ours validator.Reader, v types.Validator) error {
 	address, err := crypto.AddressFromBytes(v.Address)
 	if err != nil {
@@ -187,7 +221,11 @@ func (app *App) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 		}
 	}()
 
-	checkTx := ExecuteTx(logHeader, app.checker, app.txDecoder, req.GetTx())
+	var checker execution.Executor = app.checker
+	if app.mempoolPolicy != nil {
+		checker = app.mempoolPolicy.Executor(app.checker, req.Type == types.CheckTxType_Recheck)
+	}
+	checkTx := ExecuteTx(logHeader, checker, app.txDecoder, req.GetTx())
 
 	logger := WithEvents(app.logger, checkTx.Events)
 
@@ -301,6 +339,9 @@ func (app *App) Commit() types.ResponseCommit {
 	if err != nil {
 		panic(errors.Wrap(err, "could not reset check cache during commit"))
 	}
+	if app.mempoolPolicy != nil {
+		app.mempoolPolicy.Reset()
+	}
 	// Commit to our blockchain state which will checkpoint the previous app hash by saving it to the database
 	// (we know the previous app hash is safely committed because we are about to commit the next)
 	err = app.blockchain.CommitBlock(blockTime, app.block.Hash, appHash)
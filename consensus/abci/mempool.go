@@ -0,0 +1,325 @@
+package abci
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/acm/validator"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/errors"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/logging/structure"
+	"github.com/hyperledger/burrow/permission"
+	"github.com/hyperledger/burrow/txs"
+	"github.com/hyperledger/burrow/txs/payload"
+)
+
+// MempoolConfig limits the transactions accounts may add to the mempool through CheckTx
+type MempoolConfig struct {
+	// The most transactions signed by a single account that may be waiting to be included in a block, zero for no
+	// limit
+	MaxPendingPerSigner int `json:",omitempty" toml:",omitempty"`
+	// How often an account may submit transactions unless a rate limit for one of its permissions applies
+	RateLimit *RateLimit `json:",omitempty" toml:",omitempty"`
+	// Rate limits by permission name (e.g. "call" or "createContract") for accounts holding that permission. Where an
+	// account holds more than one of these permissions the most generous limit applies.
+	PermissionRateLimits map[string]*RateLimit `json:",omitempty" toml:",omitempty"`
+	// Once this many transactions are waiting to be included in a block only priority transactions are accepted, zero
+	// for no limit
+	PriorityThreshold int `json:",omitempty" toml:",omitempty"`
+	// Accounts whose transactions have priority
+	PriorityAccounts []crypto.Address `json:",omitempty" toml:",omitempty"`
+	// Give priority to transactions signed by an account with the root permission
+	PrioritiseRoot bool `json:",omitempty" toml:",omitempty"`
+	// Give priority to transactions signed by a current validator
+	PrioritiseValidators bool `json:",omitempty" toml:",omitempty"`
+	// Give priority to CallTxs offering at least this gas price, zero to disable
+	PriorityGasPrice uint64 `json:",omitempty" toml:",omitempty"`
+}
+
+// RateLimit is a token bucket, refilled at Rate tokens per second up to Burst, from which each transaction takes one
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (rl *RateLimit) generous(other *RateLimit) bool {
+	return other == nil || rl.Rate > other.Rate || (rl.Rate == other.Rate && rl.Burst > other.Burst)
+}
+
+type bucket struct {
+	limit  *RateLimit
+	tokens float64
+	filled time.Time
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens += b.limit.Rate * now.Sub(b.filled).Seconds()
+	if b.tokens > float64(b.limit.Burst) {
+		b.tokens = float64(b.limit.Burst)
+	}
+	b.filled = now
+}
+
+// MempoolPolicy admits transactions to the mempool according to a MempoolConfig. Transactions are counted as pending
+// from when they are accepted until Reset is called on commit, after which Tendermint rechecks (and so counts again)
+// those still in the mempool.
+type MempoolPolicy struct {
+	sync.Mutex
+	chainID              string
+	accounts             acmstate.Reader
+	validators           validator.History
+	maxPendingPerSigner  int
+	rateLimit            *RateLimit
+	permissionRateLimits map[permission.PermFlag]*RateLimit
+	priorityThreshold    int
+	priorityAccounts     map[crypto.Address]struct{}
+	prioritiseRoot       bool
+	prioritiseValidators bool
+	priorityGasPrice     uint64
+	buckets              map[crypto.Address]*bucket
+	pending              map[crypto.Address]int
+	totalPending         int
+	now                  func() time.Time
+	logger               *logging.Logger
+}
+
+// NewMempoolPolicy builds a policy for conf reading accounts (and their permissions) from accounts, which should
+// reflect the transactions already in the mempool
+func NewMempoolPolicy(conf *MempoolConfig, chainID string, accounts acmstate.Reader, validators validator.History,
+	logger *logging.Logger) (*MempoolPolicy, error) {
+
+	mp := &MempoolPolicy{
+		chainID:              chainID,
+		accounts:             accounts,
+		validators:           validators,
+		maxPendingPerSigner:  conf.MaxPendingPerSigner,
+		rateLimit:            conf.RateLimit,
+		permissionRateLimits: make(map[permission.PermFlag]*RateLimit),
+		priorityThreshold:    conf.PriorityThreshold,
+		priorityAccounts:     make(map[crypto.Address]struct{}),
+		prioritiseRoot:       conf.PrioritiseRoot,
+		prioritiseValidators: conf.PrioritiseValidators,
+		priorityGasPrice:     conf.PriorityGasPrice,
+		buckets:              make(map[crypto.Address]*bucket),
+		pending:              make(map[crypto.Address]int),
+		now:                  time.Now,
+		logger:               logger.WithScope("MempoolPolicy"),
+	}
+	if conf.MaxPendingPerSigner < 0 || conf.PriorityThreshold < 0 {
+		return nil, fmt.Errorf("MaxPendingPerSigner and PriorityThreshold must not be negative")
+	}
+	err := checkRateLimit(conf.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+	for name, limit := range conf.PermissionRateLimits {
+		perm, err := permission.PermStringToFlag(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse permission for rate limit: %v", err)
+		}
+		err = checkRateLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+		mp.permissionRateLimits[perm] = limit
+	}
+	for _, address := range conf.PriorityAccounts {
+		mp.priorityAccounts[address] = struct{}{}
+	}
+	return mp, nil
+}
+
+func checkRateLimit(limit *RateLimit) error {
+	if limit != nil && (limit.Rate < 0 || limit.Burst < 1) {
+		return fmt.Errorf("rate limit must have a non-negative Rate and a Burst of at least 1 but has %v", *limit)
+	}
+	return nil
+}
+
+// Executor applies the policy before passing transactions to executor. Transactions being rechecked were admitted
+// once already so are only counted.
+func (mp *MempoolPolicy) Executor(executor execution.Executor, recheck bool) execution.Executor {
+	return execution.ExecutorFunc(func(txEnv *txs.Envelope) (*exec.TxExecution, error) {
+		// Leave transactions whose signers we cannot trust to be rejected by executor
+		if txEnv.Verify(mp.chainID) != nil {
+			return executor.Execute(txEnv)
+		}
+		mp.Lock()
+		defer mp.Unlock()
+		signers := signers(txEnv)
+		if !recheck {
+			err := mp.admit(txEnv, signers)
+			if err != nil {
+				mp.logger.TraceMsg("Transaction refused by mempool policy",
+					structure.TxHashKey, txEnv.Tx.Hash(),
+					structure.ErrorKey, err)
+				return nil, err
+			}
+		}
+		txe, err := executor.Execute(txEnv)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range signers {
+			mp.pending[address]++
+		}
+		mp.totalPending++
+		return txe, nil
+	})
+}
+
+// Reset forgets the pending transactions, to be called once a block has been committed. Rate limits carry over.
+func (mp *MempoolPolicy) Reset() {
+	mp.Lock()
+	defer mp.Unlock()
+	mp.pending = make(map[crypto.Address]int)
+	mp.totalPending = 0
+	// Full buckets are indistinguishable from new ones
+	now := mp.now()
+	for address, b := range mp.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(mp.buckets, address)
+		}
+	}
+}
+
+func (mp *MempoolPolicy) admit(txEnv *txs.Envelope, signers []crypto.Address) error {
+	if mp.maxPendingPerSigner > 0 {
+		for _, address := range signers {
+			if mp.pending[address] >= mp.maxPendingPerSigner {
+				return errors.Errorf(errors.Codes.TooManyPendingTxs,
+					"account %v already has %d transactions waiting to be included in a block", address,
+					mp.pending[address])
+			}
+		}
+	}
+	priority, err := mp.priority(txEnv, signers)
+	if err != nil {
+		return err
+	}
+	if priority {
+		return nil
+	}
+	if mp.priorityThreshold > 0 && mp.totalPending >= mp.priorityThreshold {
+		return errors.Errorf(errors.Codes.MempoolFull,
+			"%d transactions are waiting to be included in a block", mp.totalPending)
+	}
+	// Check every signer can afford the transaction before charging any of them for it
+	now := mp.now()
+	var charge []*bucket
+	for _, address := range signers {
+		b, err := mp.bucket(address, now)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			continue
+		}
+		b.refill(now)
+		if b.tokens < 1 {
+			return errors.Errorf(errors.Codes.RateLimited,
+				"account %v may submit at most %v transactions per second", address, b.limit.Rate)
+		}
+		charge = append(charge, b)
+	}
+	for _, b := range charge {
+		b.tokens--
+	}
+	return nil
+}
+
+// Transactions from a priority account, from root or validators if configured, or offering a high enough gas price
+// jump the queue and are not rate limited
+func (mp *MempoolPolicy) priority(txEnv *txs.Envelope, signers []crypto.Address) (bool, error) {
+	if callTx, ok := txEnv.Tx.Payload.(*payload.CallTx); ok && mp.priorityGasPrice > 0 &&
+		callTx.GasPrice >= mp.priorityGasPrice {
+		return true, nil
+	}
+	for _, address := range signers {
+		if _, ok := mp.priorityAccounts[address]; ok {
+			return true, nil
+		}
+		if mp.prioritiseValidators {
+			power, err := mp.validators.Validators(0).Power(address)
+			if err != nil {
+				return false, err
+			}
+			if power != nil && power.Cmp(big.NewInt(0)) > 0 {
+				return true, nil
+			}
+		}
+		if mp.prioritiseRoot {
+			root, err := mp.hasPermission(address, permission.Root)
+			if err != nil {
+				return false, err
+			}
+			if root {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Get the bucket limiting address, or nil if it is not limited
+func (mp *MempoolPolicy) bucket(address crypto.Address, now time.Time) (*bucket, error) {
+	if b, ok := mp.buckets[address]; ok {
+		return b, nil
+	}
+	limit := mp.rateLimit
+	var permLimit *RateLimit
+	for perm, rl := range mp.permissionRateLimits {
+		if !rl.generous(permLimit) {
+			continue
+		}
+		has, err := mp.hasPermission(address, perm)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			permLimit = rl
+		}
+	}
+	if permLimit != nil {
+		limit = permLimit
+	}
+	if limit == nil {
+		return nil, nil
+	}
+	b := &bucket{limit: limit, tokens: float64(limit.Burst), filled: now}
+	mp.buckets[address] = b
+	return b, nil
+}
+
+func (mp *MempoolPolicy) hasPermission(address crypto.Address, perm permission.PermFlag) (bool, error) {
+	acc, err := mp.accounts.GetAccount(address)
+	if err != nil || acc == nil {
+		return false, err
+	}
+	globalPerms, err := acmstate.GlobalAccountPermissions(mp.accounts)
+	if err != nil {
+		return false, err
+	}
+	has, err := acc.Permissions.Base.Compose(globalPerms.Base).Get(perm)
+	if err != nil {
+		// Permission is not set on the account or globally so is denied
+		return false, nil
+	}
+	return has, nil
+}
+
+func signers(txEnv *txs.Envelope) []crypto.Address {
+	inputs := txEnv.Tx.GetInputs()
+	addresses := make([]crypto.Address, len(inputs))
+	for i, input := range inputs {
+		addresses[i] = input.Address
+	}
+	return addresses
+}
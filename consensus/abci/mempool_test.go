@@ -0,0 +1,165 @@
+package abci
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/acm/validator"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/errors"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/permission"
+	"github.com/hyperledger/burrow/txs"
+	"github.com/hyperledger/burrow/txs/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mempoolChainID = "MempoolChain"
+
+var (
+	rootAccount      = acm.GeneratePrivateAccountFromSecret("root")
+	userAccount      = acm.GeneratePrivateAccountFromSecret("user")
+	validatorAccount = acm.GeneratePrivateAccountFromSecret("validator")
+)
+
+func TestMempoolPolicy_MaxPendingPerSigner(t *testing.T) {
+	mp, _ := newTestMempoolPolicy(t, &MempoolConfig{MaxPendingPerSigner: 2})
+	exe := mp.Executor(acceptAll, false)
+
+	_, err := exe.Execute(callTx(t, userAccount, 1, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, userAccount, 2, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, userAccount, 3, 0))
+	assertCode(t, errors.Codes.TooManyPendingTxs, err)
+	// Other signers are unaffected
+	_, err = exe.Execute(callTx(t, rootAccount, 1, 0))
+	require.NoError(t, err)
+
+	// Transactions left in the mempool after a commit are rechecked and counted again
+	mp.Reset()
+	_, err = mp.Executor(acceptAll, true).Execute(callTx(t, userAccount, 2, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, userAccount, 3, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, userAccount, 4, 0))
+	assertCode(t, errors.Codes.TooManyPendingTxs, err)
+}
+
+func TestMempoolPolicy_RateLimit(t *testing.T) {
+	mp, now := newTestMempoolPolicy(t, &MempoolConfig{
+		RateLimit: &RateLimit{Rate: 1, Burst: 1},
+		PermissionRateLimits: map[string]*RateLimit{
+			permission.RootString: {Rate: 2, Burst: 2},
+		},
+	})
+	exe := mp.Executor(acceptAll, false)
+
+	_, err := exe.Execute(callTx(t, userAccount, 1, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, userAccount, 2, 0))
+	assertCode(t, errors.Codes.RateLimited, err)
+
+	// Root has a more generous limit
+	_, err = exe.Execute(callTx(t, rootAccount, 1, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, rootAccount, 2, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, rootAccount, 3, 0))
+	assertCode(t, errors.Codes.RateLimited, err)
+
+	// Limits survive a commit but refill over time
+	mp.Reset()
+	_, err = exe.Execute(callTx(t, userAccount, 2, 0))
+	assertCode(t, errors.Codes.RateLimited, err)
+	*now = now.Add(time.Second)
+	_, err = exe.Execute(callTx(t, userAccount, 2, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, rootAccount, 3, 0))
+	require.NoError(t, err)
+}
+
+func TestMempoolPolicy_Priority(t *testing.T) {
+	mp, _ := newTestMempoolPolicy(t, &MempoolConfig{
+		RateLimit:            &RateLimit{Rate: 1, Burst: 1},
+		PriorityThreshold:    1,
+		PriorityAccounts:     []crypto.Address{rootAccount.GetAddress()},
+		PrioritiseValidators: true,
+		PriorityGasPrice:     5,
+	})
+	exe := mp.Executor(acceptAll, false)
+
+	_, err := exe.Execute(callTx(t, userAccount, 1, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, userAccount, 2, 4))
+	assertCode(t, errors.Codes.MempoolFull, err)
+	// Priority transactions are accepted and are not rate limited
+	_, err = exe.Execute(callTx(t, userAccount, 2, 5))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, rootAccount, 1, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, validatorAccount, 1, 0))
+	require.NoError(t, err)
+	_, err = exe.Execute(callTx(t, validatorAccount, 2, 0))
+	require.NoError(t, err)
+
+	mp.Reset()
+	_, err = exe.Execute(callTx(t, userAccount, 3, 0))
+	assertCode(t, errors.Codes.RateLimited, err)
+}
+
+func TestMempoolPolicy_RejectedByExecutor(t *testing.T) {
+	mp, _ := newTestMempoolPolicy(t, &MempoolConfig{MaxPendingPerSigner: 1})
+	rejectAll := execution.ExecutorFunc(func(txEnv *txs.Envelope) (*exec.TxExecution, error) {
+		return nil, errors.Errorf(errors.Codes.InvalidSequence, "rejected")
+	})
+	_, err := mp.Executor(rejectAll, false).Execute(callTx(t, userAccount, 1, 0))
+	assertCode(t, errors.Codes.InvalidSequence, err)
+	// Nothing was added to the mempool
+	_, err = mp.Executor(acceptAll, false).Execute(callTx(t, userAccount, 1, 0))
+	require.NoError(t, err)
+}
+
+var acceptAll = execution.ExecutorFunc(func(txEnv *txs.Envelope) (*exec.TxExecution, error) {
+	return exec.NewTxExecution(txEnv), nil
+})
+
+func newTestMempoolPolicy(t *testing.T, conf *MempoolConfig) (*MempoolPolicy, *time.Time) {
+	st := acmstate.NewMemoryState()
+	st.Accounts[rootAccount.GetAddress()] = &acm.Account{
+		Address:     rootAccount.GetAddress(),
+		Permissions: permission.AllAccountPermissions,
+	}
+	st.Accounts[userAccount.GetAddress()] = &acm.Account{Address: userAccount.GetAddress()}
+	validators := validator.NewSet()
+	validators.ChangePower(validatorAccount.GetPublicKey(), big.NewInt(1))
+	mp, err := NewMempoolPolicy(conf, mempoolChainID, st, validator.NewRing(validators, 1), logging.NewNoopLogger())
+	require.NoError(t, err)
+	now := time.Now()
+	mp.now = func() time.Time { return now }
+	return mp, &now
+}
+
+func callTx(t *testing.T, signer *acm.PrivateAccount, sequence, gasPrice uint64) *txs.Envelope {
+	address := crypto.Address{1}
+	txEnv := txs.Enclose(mempoolChainID, &payload.CallTx{
+		Input:    &payload.TxInput{Address: signer.GetAddress(), Amount: 1, Sequence: sequence},
+		Address:  &address,
+		GasLimit: 100,
+		GasPrice: gasPrice,
+	})
+	require.NoError(t, txEnv.Sign(signer))
+	return txEnv
+}
+
+func assertCode(t *testing.T, expected *errors.Code, err error) {
+	if assert.Error(t, err) {
+		assert.Equal(t, expected, errors.AsException(err).ErrorCode())
+	}
+}
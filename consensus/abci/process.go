@@ -24,6 +24,7 @@ type Process struct {
 	panic        func(error)
 	commitNeeded bool
 	txDecoder    txs.Decoder
+	policy       *MempoolPolicy
 	shutdownOnce sync.Once
 }
 
@@ -48,6 +49,11 @@ func NewProcess(committer execution.BatchCommitter, blockchain *bcm.Blockchain,
 	return p
 }
 
+// Provide a policy limiting the transactions accepted by CheckTx, which is reset on each commit
+func (p *Process) SetMempoolPolicy(policy *MempoolPolicy) {
+	p.policy = policy
+}
+
 func (p *Process) CheckTx(tx tmTypes.Tx, cb func(*types.Response), txInfo mempool.TxInfo) error {
 	const header = "DeliverTx"
 	p.committer.Lock()
@@ -58,7 +64,11 @@ func (p *Process) CheckTx(tx tmTypes.Tx, cb func(*types.Response), txInfo mempoo
 	// This means that the same sequence of transactions fed to no consensus mode can give rise to a state with additional
 	// invalid transactions in state. Since the state hash is non-deterministic based on when the commits happen it's not
 	// clear this is a problem. The underlying state will be compatible.
-	checkTx := ExecuteTx(header, p.committer, p.txDecoder, tx)
+	var committer execution.Executor = p.committer
+	if p.policy != nil {
+		committer = p.policy.Executor(p.committer, false)
+	}
+	checkTx := ExecuteTx(header, committer, p.txDecoder, tx)
 	cb(types.ToResponseCheckTx(checkTx))
 	p.commitNeeded = true
 	if p.ticker == nil {
@@ -112,6 +122,9 @@ func (p *Process) commit() error {
 	if err != nil {
 		return fmt.Errorf("%s could not Commit tx %v", errHeader, err)
 	}
+	if p.policy != nil {
+		p.policy.Reset()
+	}
 
 	// Maintain a basic hashed linked list, mixing in the appHash as we go
 	hasher := sha256.New()
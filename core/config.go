@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/go-kit/kit/log"
+	"github.com/hyperledger/burrow/acm/acmstate"
 	"github.com/hyperledger/burrow/config"
 	"github.com/hyperledger/burrow/consensus/abci"
 	"github.com/hyperledger/burrow/consensus/tendermint"
@@ -52,6 +53,19 @@ func (kern *Kernel) LoadExecutionOptionsFromConfig(conf *execution.ExecutionConf
 	return nil
 }
 
+// LoadMempoolFromConfig sets the limits on the transactions accepted by CheckTx
+func (kern *Kernel) LoadMempoolFromConfig(conf *abci.MempoolConfig) {
+	kern.mempoolConfig = conf
+}
+
+// Build a policy applying the configured mempool limits to accounts as they appear in accounts, or nil if unlimited
+func (kern *Kernel) mempoolPolicy(accounts acmstate.Reader) (*abci.MempoolPolicy, error) {
+	if kern.mempoolConfig == nil {
+		return nil, nil
+	}
+	return abci.NewMempoolPolicy(kern.mempoolConfig, kern.Blockchain.ChainID(), accounts, kern.State, kern.Logger)
+}
+
 // LoadTendermintFromConfig loads our consensus engine into the kernel
 func (kern *Kernel) LoadTendermintFromConfig(conf *config.BurrowConfig, privVal tmTypes.PrivValidator) (err error) {
 	if conf.Tendermint == nil || !conf.Tendermint.Enabled {
@@ -70,6 +84,13 @@ func (kern *Kernel) LoadTendermintFromConfig(conf *config.BurrowConfig, privVal
 
 	app := abci.NewApp(kern.info, kern.Blockchain, kern.State, kern.checker, kern.committer, kern.txCodec,
 		authorizedPeersProvider, kern.Panic, kern.Logger)
+	mempoolPolicy, err := kern.mempoolPolicy(kern.checker)
+	if err != nil {
+		return fmt.Errorf("could not build mempool policy: %v", err)
+	}
+	if mempoolPolicy != nil {
+		app.SetMempoolPolicy(mempoolPolicy)
+	}
 
 	// We could use this to provide/register our own metrics (though this will register them with us). Unfortunately
 	// Tendermint currently ignores the metrics passed unless its own server is turned on.
@@ -93,7 +114,7 @@ func (kern *Kernel) LoadTendermintFromConfig(conf *config.BurrowConfig, privVal
 
 // LoadKernelFromConfig builds and returns a Kernel based solely on the supplied configuration
 func LoadKernelFromConfig(conf *config.BurrowConfig) (*Kernel, error) {
-	kern, err := NewKernel(conf.BurrowDir)
+	kern, err := NewKernelWithDatabase(conf.BurrowDir, conf.Database)
 	if err != nil {
 		return nil, fmt.Errorf("could not create initial kernel: %v", err)
 	}
@@ -111,11 +132,17 @@ func LoadKernelFromConfig(conf *config.BurrowConfig) (*Kernel, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not add execution options: %v", err)
 	}
+	kern.LoadMempoolFromConfig(conf.Mempool)
 
 	err = kern.LoadState(conf.GenesisDoc)
 	if err != nil {
 		return nil, fmt.Errorf("could not load state: %v", err)
 	}
+	kern.State.SetPruning(conf.Pruning)
+	err = kern.State.SetEvents(conf.GenesisDoc.Params.PlainEventsHeight, conf.Events)
+	if err != nil {
+		return nil, fmt.Errorf("could not configure event store: %v", err)
+	}
 
 	if conf.ValidatorAddress == nil {
 		return nil, fmt.Errorf("Address must be set")
@@ -132,5 +159,8 @@ func LoadKernelFromConfig(conf *config.BurrowConfig) (*Kernel, error) {
 	}
 
 	kern.AddProcesses(DefaultProcessLaunchers(kern, conf.RPC, conf.Keys)...)
+	if conf.Snapshots != nil {
+		kern.AddProcesses(SnapshotLauncher(kern, conf.Snapshots, conf.Snapshots.Path(conf.BurrowDir)))
+	}
 	return kern, nil
 }
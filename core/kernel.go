@@ -19,17 +19,22 @@ import (
 
 	"github.com/go-kit/kit/log"
 	"github.com/hyperledger/burrow/bcm"
+	"github.com/hyperledger/burrow/consensus/abci"
 	"github.com/hyperledger/burrow/consensus/tendermint"
 	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/event"
 	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/evm"
+	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/execution/state"
+	"github.com/hyperledger/burrow/forensics"
 	"github.com/hyperledger/burrow/genesis"
 	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/logging/structure"
 	"github.com/hyperledger/burrow/process"
 	"github.com/hyperledger/burrow/rpc"
+	"github.com/hyperledger/burrow/snapshot"
 	"github.com/hyperledger/burrow/txs"
 	"github.com/streadway/simpleuuid"
 	"github.com/tendermint/tendermint/store"
@@ -42,7 +47,7 @@ const (
 	ServerShutdownTimeout  = 5000 * time.Millisecond
 	LoggingCallerDepth     = 5
 	AccountsRingMutexCount = 100
-	BurrowDBName           = "burrow_state"
+	BurrowDBName           = state.BurrowDBName
 )
 
 // Kernel is the root structure of Burrow
@@ -63,6 +68,7 @@ type Kernel struct {
 	exeOptions     []execution.Option
 	checker        execution.BatchExecutor
 	committer      execution.BatchCommitter
+	mempoolConfig  *abci.MempoolConfig
 	keyClient      keys.KeyClient
 	keyStore       *keys.KeyStore
 	info           string
@@ -75,9 +81,21 @@ type Kernel struct {
 
 // NewKernel initializes an empty kernel
 func NewKernel(dbDir string) (*Kernel, error) {
+	return NewKernelWithDatabase(dbDir, state.DefaultDatabaseConfig())
+}
+
+// NewKernelWithDatabase initializes an empty kernel whose state is opened with the backends given by conf
+func NewKernelWithDatabase(dbDir string, conf *state.DatabaseConfig) (*Kernel, error) {
 	if dbDir == "" {
 		return nil, fmt.Errorf("Burrow requires a database directory")
 	}
+	if conf == nil {
+		conf = state.DefaultDatabaseConfig()
+	}
+	database, err := conf.Open(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not open Burrow database: %v", err)
+	}
 	runID, err := simpleuuid.NewTime(time.Now()) // Create a random ID based on start time
 	return &Kernel{
 		Logger:         logging.NewNoopLogger(),
@@ -87,7 +105,7 @@ func NewKernel(dbDir string) (*Kernel, error) {
 		listeners:      make(map[string]net.Listener),
 		shutdownNotify: make(chan struct{}),
 		txCodec:        txs.NewProtobufCodec(),
-		database:       dbm.NewDB(BurrowDBName, dbm.GoLevelDBBackend, dbDir),
+		database:       database,
 	}, err
 }
 
@@ -196,6 +214,47 @@ func (kern *Kernel) LoadDump(genesisDoc *genesis.GenesisDoc, restoreFile string,
 	return nil
 }
 
+// LoadSnapshot restores Burrow's state at height from a snapshot in store. Tendermint's block store and state are not
+// included so the node can only carry on from height once they have been supplied for it.
+func (kern *Kernel) LoadSnapshot(genesisDoc *genesis.GenesisDoc, store *snapshot.Store, height uint64) (err error) {
+	var exists bool
+	if kern.Blockchain, exists, err = bcm.LoadOrNewBlockchain(kern.database, genesisDoc, kern.Logger); err != nil {
+		return fmt.Errorf("error creating or loading blockchain state: %v", err)
+	}
+
+	if exists {
+		return fmt.Errorf("existing state found, please remove before restoring")
+	}
+
+	manifest, err := store.Manifest(height)
+	if err != nil {
+		return err
+	}
+	if manifest.ChainID != genesisDoc.ChainID() {
+		return fmt.Errorf("snapshot is of chain %s but GenesisDoc is for chain %s", manifest.ChainID,
+			genesisDoc.ChainID())
+	}
+
+	kern.State, manifest, err = store.Restore(height, kern.database)
+	if err != nil {
+		return err
+	}
+
+	err = kern.Blockchain.CommitBlockAtHeight(manifest.BlockTime, nil, manifest.AppHash, height)
+	if err != nil {
+		return err
+	}
+	err = kern.Blockchain.CommitWithAppHash(manifest.AppHash)
+	if err != nil {
+		return fmt.Errorf("unable to commit %v", err)
+	}
+
+	kern.Logger.InfoMsg("State restored from snapshot",
+		"height", height,
+		"state_hash", kern.State.Hash())
+	return nil
+}
+
 // GetNodeView builds and returns a wrapper of our tendermint node
 func (kern *Kernel) GetNodeView() (*tendermint.NodeView, error) {
 	if kern.Node == nil {
@@ -204,6 +263,19 @@ func (kern *Kernel) GetNodeView() (*tendermint.NodeView, error) {
 	return tendermint.NewNodeView(kern.Node, kern.txCodec, kern.RunID)
 }
 
+// TraceTx re-executes a committed transaction against a cached copy of our state passing its execution to tracer
+func (kern *Kernel) TraceTx(height uint64, txHash []byte, tracer evm.Tracer) (*exec.TxExecution, error) {
+	nodeView, err := kern.GetNodeView()
+	if err != nil {
+		return nil, err
+	} else if nodeView == nil {
+		return nil, fmt.Errorf("cannot trace transactions without a Tendermint node")
+	}
+	genesisDoc := kern.Blockchain.GenesisDoc()
+	src := forensics.NewSourceFromBlockStore(kern.database, nodeView.BlockStore(), &genesisDoc)
+	return src.TraceTx(height, txHash, tracer, kern.exeOptions...)
+}
+
 // AddExecutionOptions extends our execution options
 func (kern *Kernel) AddExecutionOptions(opts ...execution.Option) {
 	kern.exeOptions = append(kern.exeOptions, opts...)
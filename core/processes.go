@@ -8,7 +8,9 @@ import (
 
 	"github.com/hyperledger/burrow/bcm"
 	"github.com/hyperledger/burrow/consensus/abci"
+	"github.com/hyperledger/burrow/event"
 	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/logging/structure"
 	"github.com/hyperledger/burrow/process"
@@ -22,6 +24,7 @@ import (
 	"github.com/hyperledger/burrow/rpc/rpcquery"
 	"github.com/hyperledger/burrow/rpc/rpctransact"
 	"github.com/hyperledger/burrow/rpc/web3"
+	"github.com/hyperledger/burrow/snapshot"
 	"github.com/hyperledger/burrow/txs"
 	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/version"
@@ -38,6 +41,7 @@ const (
 	InfoProcessName        = "rpcConfig/info"
 	GRPCProcessName        = "rpcConfig/GRPC"
 	MetricsProcessName     = "rpcConfig/metrics"
+	SnapshotProcessName    = "Snapshots"
 )
 
 func DefaultProcessLaunchers(kern *Kernel, rpcConfig *rpc.RPCConfig, keysConfig *keys.KeysConfig) []process.Launcher {
@@ -103,6 +107,13 @@ func NoConsensusLauncher(kern *Kernel) process.Launcher {
 			blockDuration := time.Duration(kern.timeoutFactor * float64(time.Second))
 			//proc := abci.NewProcess(kern.checker, kern.committer, kern.Blockchain, kern.txCodec, blockDuration, kern.Panic)
 			proc := abci.NewProcess(kern.committer, kern.Blockchain, kern.txCodec, blockDuration, kern.Panic)
+			mempoolPolicy, err := kern.mempoolPolicy(kern.committer)
+			if err != nil {
+				return nil, fmt.Errorf("could not build mempool policy: %v", err)
+			}
+			if mempoolPolicy != nil {
+				proc.SetMempoolPolicy(mempoolPolicy)
+			}
 			// Provide execution accounts against backend state since we will commit immediately
 			accounts := execution.NewAccounts(kern.committer, kern.keyClient, AccountsRingMutexCount)
 			// Elide consensus and use a CheckTx function that immediately commits any valid transaction
@@ -143,7 +154,7 @@ func TendermintLauncher(kern *Kernel) process.Launcher {
 			nodeRegState := kern.State
 			validatorState := kern.State
 			kern.Service = rpc.NewService(accountState, nameRegState, nodeRegState, kern.Blockchain, validatorState, nodeView, kern.Logger)
-			kern.EthService = rpc.NewEthService(accountState, eventsState, kern.Blockchain, validatorState, nodeView, kern.Transactor, kern.keyStore, kern.Logger)
+			kern.EthService = rpc.NewEthService(accountState, kern.State, eventsState, kern.Emitter, kern.Blockchain, validatorState, nodeView, kern.Transactor, kern, kern.keyStore, kern.Logger, kern.exeOptions...)
 
 			if err := kern.Node.Start(); err != nil {
 				return nil, fmt.Errorf("%s error starting Tendermint node: %v", errHeader, err)
@@ -168,6 +179,65 @@ func TendermintLauncher(kern *Kernel) process.Launcher {
 	}
 }
 
+// Take a snapshot of state at every height that is a multiple of the configured interval as it is committed
+func SnapshotLauncher(kern *Kernel, conf *snapshot.SnapshotConfig, directory string) process.Launcher {
+	return process.Launcher{
+		Name:    SnapshotProcessName,
+		Enabled: conf.Interval > 0,
+		Launch: func() (process.Process, error) {
+			store := snapshot.NewStore(directory)
+			subID := event.GenSubID()
+			// Blocks published while a snapshot is being written are dropped, so a snapshot is only skipped if the
+			// previous one took longer than the interval to write
+			blocks, err := kern.Emitter.Subscribe(context.Background(), subID, exec.QueryForBlockExecution(), 1)
+			if err != nil {
+				return nil, err
+			}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for msg := range blocks {
+					be := msg.(*exec.BlockExecution)
+					if be.Height%conf.Interval != 0 {
+						continue
+					}
+					// Without consensus there is no header and blocks are stamped with the time they are committed
+					blockTime := time.Now().UTC()
+					if be.Header != nil {
+						blockTime = be.Header.Time
+					}
+					logger := kern.Logger.With("height", be.Height)
+					manifest, err := store.Create(kern.State, kern.Blockchain.ChainID(), be.Height, blockTime,
+						conf.ChunkSize)
+					if err != nil {
+						logger.InfoMsg("Could not create snapshot", structure.ErrorKey, err)
+						continue
+					}
+					logger.InfoMsg("Created snapshot",
+						"app_hash", manifest.AppHash,
+						"chunks", len(manifest.ChunkHashes))
+					err = store.Prune(conf.Keep)
+					if err != nil {
+						logger.InfoMsg("Could not prune snapshots", structure.ErrorKey, err)
+					}
+				}
+			}()
+			return process.ShutdownFunc(func(ctx context.Context) error {
+				err := kern.Emitter.UnsubscribeAll(ctx, subID)
+				if err != nil {
+					return err
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-done:
+					return nil
+				}
+			}), nil
+		},
+	}
+}
+
 func StartupLauncher(kern *Kernel) process.Launcher {
 	return process.Launcher{
 		Name:    StartupProcessName,
@@ -320,7 +390,8 @@ func GRPCLauncher(kern *Kernel, conf *rpc.ServerConfig, keyConfig *keys.KeysConf
 
 			txCodec := txs.NewProtobufCodec()
 			rpctransact.RegisterTransactServer(grpcServer,
-				rpctransact.NewTransactServer(kern.State, kern.Blockchain, kern.Transactor, txCodec, kern.Logger))
+				rpctransact.NewTransactServer(kern.State, kern.Blockchain, kern.Transactor, txCodec, kern.Logger,
+					kern.exeOptions...))
 
 			rpcevents.RegisterExecutionEventsServer(grpcServer, rpcevents.NewExecutionEventsServer(kern.State,
 				kern.Emitter, kern.Blockchain, kern.Logger))
@@ -0,0 +1,17 @@
+package encoding
+
+import (
+	"math/big"
+)
+
+// GetEthChainID converts Burrow's string ChainID into a number usable where Ethereum expects a numeric chain ID
+// (for example by the CHAINID opcode). For compatibility with Ethereum tooling we first try to interpret the ChainID
+// as a decimal integer, falling back to treating the bytes of the string as a big-endian integer.
+func GetEthChainID(chainID string) *big.Int {
+	b := new(big.Int)
+	id, ok := b.SetString(chainID, 10)
+	if ok {
+		return id
+	}
+	return b.SetBytes([]byte(chainID))
+}
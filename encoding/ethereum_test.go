@@ -0,0 +1,16 @@
+package encoding
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEthChainID(t *testing.T) {
+	assert.Equal(t, big.NewInt(1), GetEthChainID("1"))
+	assert.Equal(t, big.NewInt(1337), GetEthChainID("1337"))
+	assert.Equal(t, new(big.Int).SetBytes([]byte("burrow-chain")), GetEthChainID("burrow-chain"))
+	// Must be stable
+	assert.Equal(t, GetEthChainID("burrow-chain"), GetEthChainID("burrow-chain"))
+}
@@ -30,6 +30,10 @@ func EncodeNumber(i uint64) string {
 	return AddPrefix(strconv.FormatUint(i, 16))
 }
 
+func EncodeBigInt(i *big.Int) string {
+	return AddPrefix(i.Text(16))
+}
+
 func DecodeToBytes(input string) ([]byte, error) {
 	input = RemovePrefix(input)
 	return hex.DecodeString(input)
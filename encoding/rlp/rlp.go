@@ -44,8 +44,8 @@ func encodeUint8(input uint8) ([]byte, error) {
 }
 
 func encodeUint64(i uint64) ([]byte, error) {
-	size := bits.Len64(i)/8 + 1
-	if size == 1 {
+	size := (bits.Len64(i) + 7) / 8
+	if size <= 1 {
 		return encodeUint8(uint8(i))
 	}
 	b := make([]byte, 8)
@@ -61,8 +61,9 @@ func encodeLength(n, offset int) []byte {
 	i := uint64(n)
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, i)
-	size := bits.Len64(i)/8 + 1
-	return append([]byte{uint8(0xb7 + len(string(n)))}, b[8-size:]...)
+	// Long lengths are prefixed by the length of their minimal big-endian encoding
+	size := (bits.Len64(i) + 7) / 8
+	return append([]byte{uint8(offset + 55 + size)}, b[8-size:]...)
 }
 
 func encodeString(input []byte) ([]byte, error) {
@@ -176,20 +177,20 @@ func decode(in []byte, out *fields) error {
 	return decode(in[length:], out)
 }
 
-func decodeLength(input []byte) (uint8, uint8, reflect.Kind, error) {
+func decodeLength(input []byte) (int, int, reflect.Kind, error) {
 	length := len(input)
 
 	if length == 0 {
 		return 0, 0, reflect.Invalid, ErrNoInput
 	}
 
-	prefix := input[0]
+	prefix := int(input[0])
 
 	if prefix <= 0x7f {
 		// single byte
 		return 0, 1, reflect.String, nil
 
-	} else if length > int(prefix-0x80) && prefix <= 0xb7 {
+	} else if prefix <= 0xb7 && length > prefix-0x80 {
 		// short string
 		strLen := prefix - 0x80
 		if strLen == 1 && uint8(input[1]) <= 0x7f {
@@ -197,63 +198,52 @@ func decodeLength(input []byte) (uint8, uint8, reflect.Kind, error) {
 		}
 		return 1, strLen + 1, reflect.String, nil
 
-	} else if length > int(prefix-0xb7) && prefix <= 0xbf {
+	} else if prefix <= 0xbf && length > prefix-0xb7 {
 		// long string
-		next, err := getLength(input[1 : (prefix-0xb7)+1])
+		lenOfStrLen := prefix - 0xb7
+		strLen, err := getLength(input[1 : lenOfStrLen+1])
 		if err != nil {
 			return 0, 0, reflect.Invalid, err
-		} else if length > int(prefix-0xb7+next) {
-			lenOfStrLen := prefix - 0xb7
-			if input[1] == 0 {
-				return 0, 0, reflect.Invalid, fmt.Errorf("multi-byte length must have no leading zero")
-			}
-			strLen, err := getLength(input[1 : lenOfStrLen+1])
-			if err != nil {
-				return 0, 0, reflect.Invalid, err
-			} else if strLen < 56 {
-				return 0, 0, reflect.Invalid, fmt.Errorf("length below 56 must be encoded in one byte")
-			}
-			return lenOfStrLen + 1, lenOfStrLen + strLen, reflect.String, nil
+		} else if length >= lenOfStrLen+1+strLen {
+			return lenOfStrLen + 1, lenOfStrLen + 1 + strLen, reflect.String, nil
 		}
 
-	} else if length > int(prefix-0xc0) && prefix <= 0xf7 {
+	} else if prefix <= 0xf7 && length > prefix-0xc0 {
 		// short list
 		lenOfList := prefix - 0xc0
 		return 1, lenOfList + 1, reflect.Slice, nil
 
-	} else if prefix <= 0xff && length > int(prefix-0xf7) {
+	} else if length > prefix-0xf7 {
 		// long list
-		lenOfListLen := (prefix - 0xf7) + 1
-		next, err := getLength(input[1:lenOfListLen])
+		lenOfListLen := prefix - 0xf7
+		listLen, err := getLength(input[1 : lenOfListLen+1])
 		if err != nil {
 			return 0, 0, reflect.Invalid, err
-		} else if length > int(prefix-0xf7+next) {
-			if input[1] == 0 {
-				return 0, 0, reflect.Invalid, fmt.Errorf("multi-byte length must have no leading zero")
-			}
-			listLen, err := getLength(input[1:lenOfListLen])
-			if err != nil {
-				return 0, 0, reflect.Invalid, err
-			} else if listLen < 56 {
-				return 0, 0, reflect.Invalid, fmt.Errorf("length below 56 must be encoded in one byte")
-			}
-			return lenOfListLen, lenOfListLen + listLen, reflect.Slice, nil
+		} else if length >= lenOfListLen+1+listLen {
+			return lenOfListLen + 1, lenOfListLen + 1 + listLen, reflect.Slice, nil
 		}
 	}
 
 	return 0, 0, reflect.Invalid, ErrInvalid
 }
 
-func getLength(data []byte) (uint8, error) {
-	length := len(data)
-	if length == 0 {
+// getLength reads the big-endian length that follows a long string or list prefix
+func getLength(data []byte) (int, error) {
+	if len(data) == 0 {
 		return 0, ErrNoInput
-	} else if length == 1 {
-		return uint8(data[0]), nil
-	} else {
-		next, err := getLength(data[0 : len(data)-1])
-		return uint8(data[len(data)-1]) + next, err
+	} else if len(data) > 4 {
+		return 0, fmt.Errorf("length of %d bytes is too long", len(data))
+	} else if data[0] == 0 {
+		return 0, fmt.Errorf("multi-byte length must have no leading zero")
+	}
+	length := 0
+	for _, b := range data {
+		length = length<<8 | int(b)
+	}
+	if length < 56 {
+		return 0, fmt.Errorf("length below 56 must be encoded in one byte")
 	}
+	return length, nil
 }
 
 func decodeStruct(in reflect.Value, fields [][]byte) error {
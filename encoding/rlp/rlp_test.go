@@ -100,6 +100,41 @@ func TestEncoding(t *testing.T) {
 		trial(t, tests)
 	})
 
+	t.Run("Uint", func(t *testing.T) {
+		var tests = []testCase{
+			{
+				uint64(0x7f),
+				[]byte{0x7f},
+				[]byte{0x7f},
+			},
+			{
+				uint64(0xff),
+				[]byte{0x81, 0xff},
+				[]byte{0xff},
+			},
+			{
+				uint64(0x0100),
+				[]byte{0x82, 0x01, 0x00},
+				[]byte{0x01, 0x00},
+			},
+		}
+
+		trial(t, tests)
+	})
+
+	t.Run("LongString", func(t *testing.T) {
+		long := make([]byte, 256)
+		var tests = []testCase{
+			{
+				long,
+				append([]byte{0xb9, 0x01, 0x00}, long...),
+				long,
+			},
+		}
+
+		trial(t, tests)
+	})
+
 	t.Run("List", func(t *testing.T) {
 		var tests = []testCase{
 			{
@@ -112,6 +147,11 @@ func TestEncoding(t *testing.T) {
 				[]byte{0xce, 0xc8, 0x83, byte('c'), byte('a'), byte('t'), 0x83, byte('d'), byte('o'), byte('g'), 0xc4, 0x83, byte('o'), byte('w'), byte('l')},
 				[][]byte{[]byte("cat"), []byte("dog"), []byte("owl")},
 			},
+			{
+				[]string{"Lorem ipsum dolor sit amet, consectetur adipisicing elit"},
+				append([]byte{0xf8, 0x3a, 0xb8, 0x38}, "Lorem ipsum dolor sit amet, consectetur adipisicing elit"...),
+				[][]byte{[]byte("Lorem ipsum dolor sit amet, consectetur adipisicing elit")},
+			},
 		}
 
 		trial(t, tests)
@@ -141,6 +141,29 @@ func (e *Expression) Evaluate(getTagValue func(tag string) (interface{}, bool))
 	return stack[0].match, nil
 }
 
+// Collect tag = 'value' conditions from a code with only conjunctions
+func (e *Expression) equalities() map[string]string {
+	equalities := make(map[string]string)
+	for i, in := range e.code {
+		switch in.op {
+		case OpOr:
+			return nil
+		case OpEqual:
+			// Operators follow their operands on the tape
+			if i < 2 {
+				continue
+			}
+			tag, value := e.code[i-2], e.code[i-1]
+			if tag.tag != nil && value.string != nil {
+				if _, ok := equalities[*tag.tag]; !ok {
+					equalities[*tag.tag] = *value.string
+				}
+			}
+		}
+	}
+	return equalities
+}
+
 func (e *Expression) explainf(fmt string, args ...interface{}) {
 	if e.explainer != nil {
 		e.explainer(fmt, args...)
@@ -106,6 +106,12 @@ func (q *PegQuery) MatchError() error {
 	return q.error
 }
 
+// Equalities returns the string values that tags must equal for the query to match. Conditions can only be required
+// when the query has no OR so if it does nothing is returned.
+func (q *PegQuery) Equalities() map[string]string {
+	return q.parser.equalities()
+}
+
 func (q *PegQuery) ExplainTo(explainer func(fmt string, args ...interface{})) {
 	q.parser.explainer = explainer
 }
@@ -82,6 +82,17 @@ func TestMatches(t *testing.T) {
 	}
 }
 
+func TestEqualities(t *testing.T) {
+	qry := MustParse("Address = 'ABCD' AND Log0 = 'EF01' AND Height > 10")
+	assert.Equal(t, map[string]string{"Address": "ABCD", "Log0": "EF01"}, qry.Equalities())
+
+	qry = MustParse("Address = 'ABCD' AND Height = 10")
+	assert.Equal(t, map[string]string{"Address": "ABCD"}, qry.Equalities())
+
+	qry = MustParse("Address = 'ABCD' OR Log0 = 'EF01'")
+	assert.Empty(t, qry.Equalities())
+}
+
 func TestMustParse(t *testing.T) {
 	assert.Panics(t, func() { MustParse("=") })
 	assert.NotPanics(t, func() { MustParse("tm.events.type='NewBlock'") })
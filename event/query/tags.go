@@ -62,6 +62,9 @@ func GetReflectDepth(rv reflect.Value, key string, maxDepth int) (interface{}, b
 		}
 		rv = rv.Elem()
 	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
 	keys := strings.SplitN(key, ".", 2)
 	field := rv.FieldByName(keys[0])
 	if field == zeroValue {
@@ -3,7 +3,9 @@ package execution
 import (
 	"fmt"
 
+	"github.com/hyperledger/burrow/acm/acmstate"
 	"github.com/hyperledger/burrow/execution/evm"
+	"github.com/hyperledger/burrow/execution/wasm"
 )
 
 type VMOption string
@@ -22,7 +24,14 @@ type ExecutionConfig struct {
 	CallStackMaxDepth        uint64
 	DataStackInitialCapacity uint64
 	DataStackMaxDepth        uint64
-	VMOptions                []VMOption `json:",omitempty" toml:",omitempty"`
+	// The maximum number of 64KiB pages of memory available to a WASM contract
+	WASMMaxMemoryPages uint64
+	VMOptions          []VMOption `json:",omitempty" toml:",omitempty"`
+	// The number of workers with which to execute each block's transactions speculatively in parallel before they are
+	// delivered. Transactions that read something changed by an earlier transaction in the block are executed again
+	// in order, so the resulting state is identical to sequential execution. Zero (the default) disables parallel
+	// execution.
+	ParallelWorkers int `json:",omitempty" toml:",omitempty"`
 }
 
 func DefaultExecutionConfig() *ExecutionConfig {
@@ -30,6 +39,7 @@ func DefaultExecutionConfig() *ExecutionConfig {
 		CallStackMaxDepth:        0, // Unlimited by default
 		DataStackInitialCapacity: evm.DataStackInitialCapacity,
 		DataStackMaxDepth:        0, // Unlimited by default
+		WASMMaxMemoryPages:       wasm.DefaultMaxMemoryPages,
 		TimeoutFactor:            0.33,
 	}
 }
@@ -42,6 +52,38 @@ func VMOptions(vmOptions evm.Options) func(*executor) {
 	}
 }
 
+func WASMOptions(wasmOptions wasm.Options) func(*executor) {
+	return func(exe *executor) {
+		exe.wasmOptions = wasmOptions
+	}
+}
+
+// ParallelExecution speculatively executes the transactions of each block with workers in parallel, only using the
+// results of those that read nothing written by an earlier transaction in the block
+func ParallelExecution(workers int) Option {
+	return func(exe *executor) {
+		exe.workers = workers
+		if workers > 0 {
+			exe.useTxCache()
+		}
+	}
+}
+
+// Give each transaction its own cache over a cache of the block's writes
+func (exe *executor) useTxCache() {
+	if exe.blockCache == nil {
+		exe.blockCache = exe.stateCache
+		exe.stateCache = acmstate.NewCache(exe.blockCache, acmstate.Named("TxCache"))
+	}
+}
+
+// VMTracer attaches tracer to the EVM, it must follow any VMOptions since those replace the EVM options wholesale
+func VMTracer(tracer evm.Tracer) func(*executor) {
+	return func(exe *executor) {
+		exe.vmOptions.Tracer = tracer
+	}
+}
+
 func (ec *ExecutionConfig) ExecutionOptions() ([]Option, error) {
 	var exeOptions []Option
 	vmOptions := evm.Options{
@@ -61,5 +103,15 @@ func (ec *ExecutionConfig) ExecutionOptions() ([]Option, error) {
 		}
 	}
 	exeOptions = append(exeOptions, VMOptions(vmOptions))
+	exeOptions = append(exeOptions, WASMOptions(wasm.Options{
+		MaxMemoryPages:    ec.WASMMaxMemoryPages,
+		CallStackMaxDepth: ec.CallStackMaxDepth,
+	}))
+	if ec.ParallelWorkers < 0 {
+		return nil, fmt.Errorf("ParallelWorkers must not be negative but is %d", ec.ParallelWorkers)
+	}
+	if ec.ParallelWorkers > 0 {
+		exeOptions = append(exeOptions, ParallelExecution(ec.ParallelWorkers))
+	}
 	return exeOptions, nil
 }
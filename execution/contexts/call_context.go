@@ -2,9 +2,11 @@ package contexts
 
 import (
 	"fmt"
+	"math/bits"
 
 	"github.com/hyperledger/burrow/acm"
 	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/execution/engine"
 	"github.com/hyperledger/burrow/execution/errors"
@@ -12,6 +14,7 @@ import (
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/execution/native"
 	"github.com/hyperledger/burrow/execution/wasm"
+	"github.com/hyperledger/burrow/genesis"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/logging/structure"
 	"github.com/hyperledger/burrow/txs/payload"
@@ -22,10 +25,12 @@ const GasLimit = uint64(1000000)
 
 type CallContext struct {
 	EVM           *evm.EVM
+	WVM           *wasm.WVM
 	State         acmstate.ReaderWriter
 	MetadataState acmstate.MetadataReaderWriter
 	Blockchain    engine.Blockchain
 	RunCall       bool
+	Fees          *genesis.FeeSchedule
 	Logger        *logging.Logger
 	tx            *payload.CallTx
 	txe           *exec.TxExecution
@@ -69,7 +74,11 @@ func (ctx *CallContext) Precheck() (*acm.Account, *acm.Account, error) {
 	}
 
 	// Fees are handle by the CallContext, values transfers (i.e. balances) are handled in the VM (or in Check())
-	err = inAcc.SubtractFromBalance(ctx.tx.Fee)
+	fee, err := ctx.maximumFee()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = inAcc.SubtractFromBalance(fee)
 	if err != nil {
 		return nil, nil, errors.Errorf(errors.Codes.InsufficientFunds,
 			"Input account does not have sufficient balance to cover input amount: %v", ctx.tx.Input)
@@ -117,12 +126,21 @@ func (ctx *CallContext) Check(inAcc *acm.Account, value uint64) error {
 	return nil
 }
 
-func (ctx *CallContext) Deliver(inAcc, outAcc *acm.Account, value uint64) error {
+func (ctx *CallContext) Deliver(inAcc, outAcc *acm.Account, value uint64) (err error) {
 	// VM call variables
 	createContract := ctx.tx.Address == nil
 	caller := inAcc.Address
 	txCache := acmstate.NewCache(ctx.State, acmstate.Named("TxCache"))
 	metaCache := acmstate.NewMetadataCache(ctx.MetadataState)
+	gas := ctx.tx.GasLimit
+
+	// Precheck took the maximum fee so however we exit we must refund the unused gas and charge for the rest
+	defer func() {
+		settleErr := ctx.settleFee(ctx.tx.GasLimit - gas)
+		if err == nil {
+			err = settleErr
+		}
+	}()
 
 	var callee crypto.Address
 	var code []byte
@@ -181,11 +199,20 @@ func (ctx *CallContext) Deliver(inAcc, outAcc *acm.Account, value uint64) error
 	ctx.Logger.Trace.Log("callee", callee)
 
 	var ret []byte
-	var err error
 	txHash := ctx.txe.Envelope.Tx.Hash()
-	gas := ctx.tx.GasLimit
+
+	params := engine.CallParams{
+		Origin:   caller,
+		Caller:   caller,
+		Callee:   callee,
+		Input:    ctx.tx.Data,
+		Value:    value,
+		Gas:      &gas,
+		GasPrice: ctx.tx.GasPrice,
+	}
+
 	if len(wcode) != 0 {
-		ret, err = wasm.RunWASM(txCache, callee, createContract, wcode, ctx.tx.Data)
+		ret, err = ctx.WVM.Execute(txCache, ctx.Blockchain, ctx.txe, params, wcode)
 		if err != nil {
 			// Failure. Charge the gas fee. The 'value' was otherwise not transferred.
 			ctx.Logger.InfoMsg("Error on WASM execution",
@@ -210,15 +237,6 @@ func (ctx *CallContext) Deliver(inAcc, outAcc *acm.Account, value uint64) error
 		ctx.EVM.SetNonce(txHash)
 		ctx.EVM.SetLogger(ctx.Logger.With(structure.TxHashKey, txHash))
 
-		params := engine.CallParams{
-			Origin: caller,
-			Caller: caller,
-			Callee: callee,
-			Input:  ctx.tx.Data,
-			Value:  value,
-			Gas:    &gas,
-		}
-
 		ret, err = ctx.EVM.Execute(txCache, ctx.Blockchain, ctx.txe, params, code)
 
 		if err != nil {
@@ -241,9 +259,10 @@ func (ctx *CallContext) Deliver(inAcc, outAcc *acm.Account, value uint64) error
 				return err
 			}
 		}
-		ctx.CallEvents(err)
 	}
-	ctx.txe.Return(ret, ctx.tx.GasLimit-gas)
+	ctx.CallEvents(err)
+	gasUsed := ctx.tx.GasLimit - gas
+	ctx.txe.Return(ret, gasUsed)
 	// Create a receipt from the ret and whether it erred.
 	ctx.Logger.TraceMsg("VM Call complete",
 		"caller", caller,
@@ -254,6 +273,56 @@ func (ctx *CallContext) Deliver(inAcc, outAcc *acm.Account, value uint64) error
 	return nil
 }
 
+// The fee taken from the input before execution: the flat fee of the CallTx and, when there is a fee schedule, the
+// price of all the gas it may use
+func (ctx *CallContext) maximumFee() (uint64, error) {
+	if ctx.Fees == nil {
+		return ctx.tx.Fee, nil
+	}
+	if ctx.tx.GasPrice < ctx.Fees.MinimumGasPrice {
+		return 0, errors.Errorf(errors.Codes.GasPriceTooLow,
+			"CallTx offers gas price %d but the minimum gas price is %d", ctx.tx.GasPrice, ctx.Fees.MinimumGasPrice)
+	}
+	overflow, gasFee := bits.Mul64(ctx.tx.GasLimit, ctx.tx.GasPrice)
+	if overflow != 0 {
+		return 0, errors.Errorf(errors.Codes.IntegerOverflow,
+			"price of gas limit %d at gas price %d overflows", ctx.tx.GasLimit, ctx.tx.GasPrice)
+	}
+	if binary.IsUint64SumOverflow(ctx.tx.Fee, gasFee) {
+		return 0, errors.Errorf(errors.Codes.IntegerOverflow,
+			"fee %d plus price of gas %d overflows", ctx.tx.Fee, gasFee)
+	}
+	return ctx.tx.Fee + gasFee, nil
+}
+
+// Return the price of the gas left unused to the input and record the fee it paid
+func (ctx *CallContext) settleFee(gasUsed uint64) error {
+	if ctx.Fees == nil {
+		return nil
+	}
+	refund := (ctx.tx.GasLimit - gasUsed) * ctx.tx.GasPrice
+	if refund > 0 {
+		inAcc, err := ctx.State.GetAccount(ctx.tx.Input.Address)
+		if err != nil {
+			return err
+		}
+		if inAcc == nil {
+			return errors.Errorf(errors.Codes.NonExistentAccount,
+				"cannot refund unused gas to input account %v", ctx.tx.Input.Address)
+		}
+		err = inAcc.AddToBalance(refund)
+		if err != nil {
+			return err
+		}
+		err = ctx.State.UpdateAccount(inAcc)
+		if err != nil {
+			return err
+		}
+	}
+	ctx.txe.Charge(ctx.tx.Fee + gasUsed*ctx.tx.GasPrice)
+	return nil
+}
+
 func (ctx *CallContext) CallEvents(err error) {
 	// Fire Events for sender and receiver a separate event will be fired from vm for each additional call
 	ctx.txe.Input(ctx.tx.Input.Address, errors.AsException(err))
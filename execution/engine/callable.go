@@ -8,6 +8,7 @@ import (
 )
 
 type Blockchain interface {
+	ChainID() string
 	LastBlockHeight() uint64
 	LastBlockTime() time.Time
 	BlockHash(height uint64) ([]byte, error)
@@ -21,6 +22,8 @@ type CallParams struct {
 	Input    []byte
 	Value    uint64
 	Gas      *uint64
+	// The price the transaction pays for each unit of gas
+	GasPrice uint64
 }
 
 // Effectively a contract, but can either represent a single function or a contract with multiple functions and a selector
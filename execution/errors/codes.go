@@ -49,6 +49,10 @@ type codes struct {
 	UnresolvedSymbols      *Code
 	InvalidContractCode    *Code
 	NonExistentAccount     *Code
+	GasPriceTooLow         *Code
+	RateLimited            *Code
+	TooManyPendingTxs      *Code
+	MempoolFull            *Code
 
 	// For lookup
 	codes []*Code
@@ -64,6 +64,10 @@ var Codes = codes{
 	UnresolvedSymbols:      code("code has unresolved symbols"),
 	InvalidContractCode:    code("contract being created with unexpected code"),
 	NonExistentAccount:     code("account does not exist"),
+	GasPriceTooLow:         code("gas price is below the minimum"),
+	RateLimited:            code("account has exceeded its rate limit for transactions"),
+	TooManyPendingTxs:      code("account has too many transactions waiting to be included in a block"),
+	MempoolFull:            code("mempool is only accepting priority transactions"),
 }
 
 func init() {
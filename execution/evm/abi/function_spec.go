@@ -2,7 +2,9 @@ package abi
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/hyperledger/burrow/event/query"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -52,6 +54,10 @@ func (f *FunctionSpec) String() string {
 		" returns " + argsToSignature(f.Outputs, true)
 }
 
+func (f *FunctionSpec) Get(key string) (interface{}, bool) {
+	return query.GetReflect(reflect.ValueOf(f), key)
+}
+
 func (fs FunctionID) Bytes() []byte {
 	return fs[:]
 }
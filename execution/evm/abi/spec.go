@@ -123,6 +123,15 @@ func (spec *Spec) GetEventAbi(id EventID, addresses crypto.Address) (*EventSpec,
 	return eventSpec, nil
 }
 
+func (spec *Spec) GetFunctionAbi(id FunctionID, address crypto.Address) (*FunctionSpec, error) {
+	for _, funcSpec := range spec.Functions {
+		if funcSpec.FunctionID == id {
+			return funcSpec, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find ABI for function with ID %X", id[:])
+}
+
 // Pack ABI encodes a function call. The fname specifies which function should called, if
 // it doesn't exist exist the fallback function will be called. If fname is the empty
 // string, the constructor is called. The arguments must be specified in args. The count
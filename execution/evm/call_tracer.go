@@ -0,0 +1,69 @@
+package evm
+
+import x "github.com/hyperledger/burrow/encoding/hex"
+
+// CallFrame is a node in the call tree in the format returned by geth's callTracer
+type CallFrame struct {
+	Type    string       `json:"type"`
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Value   string       `json:"value"`
+	Gas     string       `json:"gas"`
+	GasUsed string       `json:"gasUsed"`
+	Input   string       `json:"input"`
+	Output  string       `json:"output"`
+	Error   string       `json:"error,omitempty"`
+	Calls   []*CallFrame `json:"calls,omitempty"`
+}
+
+// CallTracer is a Tracer that records the tree of calls made during execution, ignoring individual instructions
+type CallTracer struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+var _ Tracer = &CallTracer{}
+
+func NewCallTracer() *CallTracer {
+	return new(CallTracer)
+}
+
+func (ct *CallTracer) Enter(call *TraceCall) {
+	frame := &CallFrame{
+		Type:  call.Op.Name(),
+		From:  x.EncodeBytes(call.Caller.Bytes()),
+		To:    x.EncodeBytes(call.Callee.Bytes()),
+		Value: x.EncodeNumber(call.Value),
+		Gas:   x.EncodeNumber(call.Gas),
+		Input: x.EncodeBytes(call.Input),
+	}
+	if len(ct.stack) == 0 {
+		ct.root = frame
+	} else {
+		parent := ct.stack[len(ct.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	ct.stack = append(ct.stack, frame)
+}
+
+// Step is a no-op since only calls are recorded
+func (ct *CallTracer) Step(step *TraceStep) {
+}
+
+func (ct *CallTracer) Exit(ret *TraceReturn) {
+	if len(ct.stack) == 0 {
+		return
+	}
+	frame := ct.stack[len(ct.stack)-1]
+	ct.stack = ct.stack[:len(ct.stack)-1]
+	frame.GasUsed = x.EncodeNumber(ret.GasUsed)
+	frame.Output = x.EncodeBytes(ret.Output)
+	if ret.Err != nil {
+		frame.Error = ret.Err.Error()
+	}
+}
+
+// Result returns the top-level call frame or nil if no call has been entered
+func (ct *CallTracer) Result() *CallFrame {
+	return ct.root
+}
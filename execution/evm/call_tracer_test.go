@@ -0,0 +1,55 @@
+package evm
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/acm/acmstate"
+	x "github.com/hyperledger/burrow/encoding/hex"
+	"github.com/hyperledger/burrow/execution/engine"
+	. "github.com/hyperledger/burrow/execution/evm/asm"
+	. "github.com/hyperledger/burrow/execution/evm/asm/bc"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTracer(t *testing.T) {
+	tracer := NewCallTracer()
+	vm := New(Options{Tracer: tracer})
+	st := acmstate.NewMemoryState()
+
+	reverter := makeAccountWithCode(t, st, "reverter", MustSplice(PUSH1, 0x00, PUSH1, 0x00, REVERT))
+	callee := makeAccountWithCode(t, st, "callee", MustSplice(PUSH1, 0x01, PUSH1, 0x00, MSTORE, PUSH1, 0x20,
+		PUSH1, 0x00, RETURN))
+	bytecode := MustSplice(
+		PUSH1, 0x20, PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH20, callee, PUSH2, 0x01, 0x00, STATICCALL,
+		PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH20, reverter, PUSH2, 0x01, 0x00, CALL,
+		STOP)
+	// Code run at an account that already holds it is a call rather than contract creation
+	caller := makeAccountWithCode(t, st, "caller", bytecode)
+
+	var gas uint64 = 100000
+	_, err := vm.Execute(st, new(blockchain), exec.NewNoopEventSink(), engine.CallParams{
+		Caller: caller,
+		Callee: caller,
+		Gas:    &gas,
+	}, bytecode)
+	require.NoError(t, err)
+
+	root := tracer.Result()
+	require.NotNil(t, root)
+	assert.Equal(t, "CALL", root.Type)
+	assert.Equal(t, "0x186a0", root.Gas)
+	assert.Empty(t, root.Error)
+	require.Len(t, root.Calls, 2)
+
+	static := root.Calls[0]
+	assert.Equal(t, "STATICCALL", static.Type)
+	assert.Equal(t, x.EncodeBytes(callee.Bytes()), static.To)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", static.Output)
+	assert.Empty(t, static.Calls)
+
+	reverted := root.Calls[1]
+	assert.Equal(t, "CALL", reverted.Type)
+	assert.NotEmpty(t, reverted.Error)
+}
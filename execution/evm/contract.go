@@ -12,7 +12,7 @@ import (
 	"github.com/hyperledger/burrow/acm/acmstate"
 	. "github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/crypto"
-
+	"github.com/hyperledger/burrow/encoding"
 	"github.com/hyperledger/burrow/execution/engine"
 	"github.com/hyperledger/burrow/execution/errors"
 	"github.com/hyperledger/burrow/execution/evm/abi"
@@ -57,14 +57,21 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 	// Provide stack and memory storage - passing in the callState as an error provider
 	stack := NewStack(maybe, c.options.DataStackInitialCapacity, c.options.DataStackMaxDepth, params.Gas)
 	memory := c.options.MemoryProvider(maybe)
+	// Report each instruction to any tracer (tracer is nil and memory unwrapped when not tracing)
+	tracer, memory := c.newStepTracer(memory, params.Gas)
+	defer func() {
+		tracer.end(maybe.Error())
+	}()
 
 	for {
+		tracer.end(maybe.Error())
 		// Check for any error in this frame.
 		if maybe.Error() != nil {
 			return nil, maybe.Error()
 		}
 
 		var op = codeGetOp(c.code, pc)
+		tracer.begin(pc, op, st.CallFrame.CallStackDepth(), params.Callee, stack)
 		c.debugf("(pc) %-3d (op) %-14s (st) %-4d (gas) %d", pc, op.String(), stack.Len(), *params.Gas)
 		// Use BaseOp gas.
 		maybe.PushError(useGasNegative(params.Gas, native.GasBaseOp))
@@ -384,8 +391,8 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 			c.debugf(" => [%v, %v, %v] %X\n", memOff, codeOff, length, data)
 
 		case GASPRICE_DEPRECATED: // 0x3A
-			stack.Push(Zero256)
-			c.debugf(" => %v (GASPRICE IS DEPRECATED)\n", Zero256)
+			stack.Push64(params.GasPrice)
+			c.debugf(" => %v\n", params.GasPrice)
 
 		case EXTCODESIZE: // 0x3B
 			address := stack.PopAddress()
@@ -396,6 +403,9 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 				c.debugf(" => 0\n")
 			} else {
 				length := uint64(len(acc.EVMCode))
+				if length == 0 && c.options.WASMCodeSize {
+					length = uint64(len(acc.WASMCode))
+				}
 				stack.Push64(length)
 				c.debugf(" => %d\n", length)
 			}
@@ -485,6 +495,16 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 			stack.Push64(*params.Gas)
 			c.debugf(" => %v\n", *params.Gas)
 
+		case CHAINID: // 0x46
+			id := encoding.GetEthChainID(st.Blockchain.ChainID())
+			stack.PushBigInt(id)
+			c.debugf(" => %X\n", id)
+
+		case SELFBALANCE: // 0x47
+			balance := mustGetAccount(st.CallFrame, maybe, params.Callee).Balance
+			stack.Push64(balance)
+			c.debugf(" => %v (%v)\n", balance, params.Callee)
+
 		case POP: // 0x50
 			popped := stack.Pop()
 			c.debugf(" => 0x%v\n", popped)
@@ -511,12 +531,14 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 			loc := stack.Pop()
 			data := LeftPadWord256(maybe.Bytes(st.CallFrame.GetStorage(params.Callee, loc)))
 			stack.Push(data)
+			tracer.storage(&StorageSlot{Key: loc, Value: data}, nil)
 			c.debugf("%v {0x%v = 0x%v}\n", params.Callee, loc, data)
 
 		case SSTORE: // 0x55
 			loc, data := stack.Pop(), stack.Pop()
 			maybe.PushError(useGasNegative(params.Gas, native.GasStorageUpdate))
 			maybe.PushError(st.CallFrame.SetStorage(params.Callee, loc, data.Bytes()))
+			tracer.storage(nil, &StorageSlot{Key: loc, Value: data})
 			c.debugf("%v {%v := %v}\n", params.Callee, loc, data)
 
 		case JUMP: // 0x56
@@ -620,20 +642,25 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 
 			// Run the input to get the contract code.
 			// NOTE: no need to copy 'input' as per Call contract.
+			createParams := engine.CallParams{
+				Origin:   params.Origin,
+				Caller:   params.Callee,
+				Callee:   newAccountAddress,
+				Input:    input,
+				Value:    contractValue,
+				Gas:      params.Gas,
+				GasPrice: params.GasPrice,
+			}
+			gasBefore := *params.Gas
+			c.traceEnter(op, st.CallFrame.CallStackDepth()+1, createParams)
 			ret, callErr := c.Contract(input).Call(
 				engine.State{
 					CallFrame:  childCallFrame,
 					Blockchain: st.Blockchain,
 					EventSink:  st.EventSink,
 				},
-				engine.CallParams{
-					Origin: params.Origin,
-					Caller: params.Callee,
-					Callee: newAccountAddress,
-					Input:  input,
-					Value:  contractValue,
-					Gas:    params.Gas,
-				})
+				createParams)
+			c.traceExit(st.CallFrame.CallStackDepth()+1, ret, gasBefore-*params.Gas, callErr)
 			if callErr != nil {
 				stack.Push(Zero256)
 				// Note we both set the return buffer and return the result normally in order to service the error to
@@ -712,10 +739,11 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 			// Setup callee params for call type
 
 			calleeParams := engine.CallParams{
-				Origin: params.Origin,
-				Input:  memory.Read(inOffset, inSize),
-				Value:  value,
-				Gas:    &gasLimit,
+				Origin:   params.Origin,
+				Input:    memory.Read(inOffset, inSize),
+				Value:    value,
+				Gas:      &gasLimit,
+				GasPrice: params.GasPrice,
 			}
 
 			// Set up the caller/callee context
@@ -772,7 +800,10 @@ func (c *Contract) execute(st engine.State, params engine.CallParams) ([]byte, e
 			}
 
 			var callErr error
+			gasBefore := gasLimit
+			c.traceEnter(op, childCallFrame.CallStackDepth(), calleeParams)
 			returnData, callErr = c.Dispatch(acc).Call(childState, calleeParams)
+			c.traceExit(childCallFrame.CallStackDepth(), returnData, gasBefore-*calleeParams.Gas, callErr)
 
 			if callErr == nil {
 				// Sync error is a hard stop
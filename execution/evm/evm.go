@@ -8,8 +8,10 @@ import (
 
 	"github.com/hyperledger/burrow/acm"
 	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/execution/engine"
 	"github.com/hyperledger/burrow/execution/errors"
+	"github.com/hyperledger/burrow/execution/evm/asm"
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/execution/native"
 	"github.com/hyperledger/burrow/logging"
@@ -41,7 +43,12 @@ type Options struct {
 	CallStackMaxDepth        uint64
 	DataStackInitialCapacity uint64
 	DataStackMaxDepth        uint64
-	Logger                   *logging.Logger
+	// Receives structured execution events when set
+	Tracer Tracer
+	// Have EXTCODESIZE give the size of an account's WASM code when it has no EVM code, so that Solidity will call
+	// contracts hosted by the WVM
+	WASMCodeSize bool
+	Logger       *logging.Logger
 }
 
 func New(options Options) *EVM {
@@ -84,7 +91,12 @@ func (vm *EVM) Execute(st acmstate.ReaderWriter, blockchain engine.Blockchain, e
 		EventSink:  eventSink,
 	}
 
+	gas := *params.Gas
+	if vm.options.Tracer != nil {
+		vm.traceEnter(topLevelOp(st, params.Callee, code), 0, params)
+	}
 	output, err := vm.Contract(code).Call(state, params)
+	vm.traceExit(0, output, gas-*params.Gas, err)
 	if err == nil {
 		// Only sync back when there was no exception
 		err = state.CallFrame.Sync()
@@ -125,6 +137,18 @@ func (vm *EVM) Contract(code []byte) *Contract {
 	}
 }
 
+// Infer whether the top-level call is a contract creation for the benefit of tracers
+func topLevelOp(st acmstate.Reader, callee crypto.Address, code []byte) asm.OpCode {
+	if len(code) == 0 {
+		return asm.CALL
+	}
+	acc, err := st.GetAccount(callee)
+	if err != nil || acc == nil || len(acc.EVMCode) == 0 {
+		return asm.CREATE
+	}
+	return asm.CALL
+}
+
 func (vm *EVM) debugf(format string, a ...interface{}) {
 	if vm.options.DebugOpcodes {
 		fmt.Printf(format, a...)
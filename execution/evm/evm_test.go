@@ -1333,6 +1333,52 @@ func TestEVM(t *testing.T) {
 		assert.Equal(t, hex.MustDecodeString("010da270094b5199d3e54f89afe4c66cdd658dd8111a41998714227e14e171bd"), output)
 	})
 
+	t.Run("ChainID", func(t *testing.T) {
+		st := acmstate.NewMemoryState()
+		blockchain := &blockchain{chainID: "burrow-test-chain"}
+		eventSink := exec.NewNoopEventSink()
+		account1 := newAccount(t, st, "1")
+		account2 := newAccount(t, st, "101")
+
+		var gas uint64 = 100000
+		params := engine.CallParams{
+			Caller: account1,
+			Callee: account2,
+			Gas:    &gas,
+		}
+		bytecode := MustSplice(CHAINID, return1())
+
+		output, err := vm.Execute(st, blockchain, eventSink, params, bytecode)
+		require.NoError(t, err)
+		assert.Equal(t, LeftPadBytes([]byte("burrow-test-chain"), 32), output)
+
+		// Numeric chain IDs are interpreted as such for compatibility with Ethereum tooling
+		blockchain.chainID = "1337"
+		output, err = vm.Execute(st, blockchain, eventSink, params, bytecode)
+		require.NoError(t, err)
+		assert.Equal(t, Uint64ToWord256(1337).Bytes(), output)
+	})
+
+	t.Run("SelfBalance", func(t *testing.T) {
+		st := acmstate.NewMemoryState()
+		account1 := newAccount(t, st, "1")
+		account2 := newAccount(t, st, "101")
+		addToBalance(t, st, account2, 1234)
+
+		var gas uint64 = 100000
+
+		bytecode := MustSplice(SELFBALANCE, return1())
+		output, err := call(vm, st, account1, account2, bytecode, nil, &gas)
+		require.NoError(t, err)
+		assert.Equal(t, Uint64ToWord256(1234).Bytes(), output)
+
+		// SELFBALANCE and BALANCE(ADDRESS) agree
+		bytecode = MustSplice(ADDRESS, BALANCE, SELFBALANCE, EQ, return1())
+		output, err = call(vm, st, account1, account2, bytecode, nil, &gas)
+		require.NoError(t, err)
+		assert.Equal(t, One256.Bytes(), output)
+	})
+
 	// Tests logs and events.
 	t.Run("TestLogEvents", func(t *testing.T) {
 		expectedData := []byte{0x10}
@@ -1489,10 +1535,15 @@ func TestEVM(t *testing.T) {
 }
 
 type blockchain struct {
+	chainID     string
 	blockHeight uint64
 	blockTime   time.Time
 }
 
+func (b *blockchain) ChainID() string {
+	return b.chainID
+}
+
 func (b *blockchain) LastBlockHeight() uint64 {
 	return b.blockHeight
 }
@@ -135,6 +135,13 @@ func (st *Stack) Peek() Word256 {
 	return st.slice[st.ptr-1]
 }
 
+// Not an opcode, costs no gas. Returns a copy of the stack ordered from bottom to top.
+func (st *Stack) Copy() []Word256 {
+	words := make([]Word256, st.ptr)
+	copy(words, st.slice[:st.ptr])
+	return words
+}
+
 func (st *Stack) Print(n int) {
 	fmt.Println("### stack ###")
 	if st.ptr > 0 {
@@ -0,0 +1,168 @@
+package evm
+
+import (
+	"encoding/hex"
+
+	. "github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+)
+
+// StructLog is a single instruction-level record in the format returned by geth's debug_traceTransaction
+type StructLog struct {
+	PC      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   uint64            `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack"`
+	Memory  []string          `json:"memory"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// StructLogResult is the result of a traced execution in the format returned by geth's debug_traceTransaction
+type StructLogResult struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+type StructLoggerConfig struct {
+	DisableStack   bool
+	DisableMemory  bool
+	DisableStorage bool
+	// Stop recording after this many instructions (0 for no limit)
+	Limit int
+}
+
+// StructLogger is a Tracer that records a StructLog for each instruction executed
+type StructLogger struct {
+	config StructLoggerConfig
+	logs   []StructLog
+	// Reconstructed memory for each active call frame indexed by depth
+	memories [][]byte
+	// Storage accessed so far by account
+	storage map[crypto.Address]map[Word256]Word256
+	// Index of the log reserved for the CALL/CREATE instruction that established the frame at each depth, the EVM
+	// only reports an instruction once it completes, which for calls is after all of the instructions of the child
+	pending map[uint64]int
+	result  StructLogResult
+}
+
+var _ Tracer = &StructLogger{}
+
+func NewStructLogger(config StructLoggerConfig) *StructLogger {
+	return &StructLogger{
+		config:  config,
+		storage: make(map[crypto.Address]map[Word256]Word256),
+		pending: make(map[uint64]int),
+	}
+}
+
+func (sl *StructLogger) Enter(call *TraceCall) {
+	if call.Depth > 0 && !sl.full() {
+		sl.pending[call.Depth-1] = len(sl.logs)
+		sl.logs = append(sl.logs, StructLog{})
+	}
+	sl.memories = append(sl.memories, nil)
+}
+
+func (sl *StructLogger) Step(step *TraceStep) {
+	log := StructLog{
+		PC:      step.PC,
+		Op:      step.Op.Name(),
+		Gas:     step.Gas,
+		GasCost: step.GasCost,
+		// Geth counts depth from 1
+		Depth: step.Depth + 1,
+	}
+	if step.Err != nil {
+		log.Error = step.Err.Error()
+	}
+	if !sl.config.DisableStack {
+		log.Stack = make([]string, len(step.Stack))
+		for i, word := range step.Stack {
+			log.Stack[i] = hex.EncodeToString(word[:])
+		}
+	}
+	memory := sl.memory()
+	if !sl.config.DisableMemory {
+		log.Memory = make([]string, 0, len(*memory)/Word256Bytes)
+		for i := 0; i < len(*memory); i += Word256Bytes {
+			log.Memory = append(log.Memory, hex.EncodeToString((*memory)[i:i+Word256Bytes]))
+		}
+	}
+	// Memory in each log is the memory before the instruction executed so apply writes afterwards
+	for _, write := range step.MemoryWrites {
+		end := write.Offset + uint64(len(write.Data))
+		if uint64(len(*memory)) < end {
+			// Grow in whole words
+			size := (end + Word256Bytes - 1) / Word256Bytes * Word256Bytes
+			*memory = append(*memory, make([]byte, size-uint64(len(*memory)))...)
+		}
+		copy((*memory)[write.Offset:end], write.Data)
+	}
+	if !sl.config.DisableStorage && (step.StorageRead != nil || step.StorageWrite != nil) {
+		log.Storage = sl.recordStorage(step)
+	}
+	if idx, ok := sl.pending[step.Depth]; ok {
+		delete(sl.pending, step.Depth)
+		sl.logs[idx] = log
+		return
+	}
+	if !sl.full() {
+		sl.logs = append(sl.logs, log)
+	}
+}
+
+func (sl *StructLogger) Exit(ret *TraceReturn) {
+	if len(sl.memories) > 0 {
+		sl.memories = sl.memories[:len(sl.memories)-1]
+	}
+	if ret.Depth == 0 {
+		sl.result.Gas = ret.GasUsed
+		sl.result.Failed = ret.Err != nil
+		sl.result.ReturnValue = hex.EncodeToString(ret.Output)
+	}
+}
+
+// Result returns the logs recorded so far along with the outcome of the top-level call
+func (sl *StructLogger) Result() *StructLogResult {
+	result := sl.result
+	result.StructLogs = sl.logs
+	if result.StructLogs == nil {
+		result.StructLogs = []StructLog{}
+	}
+	return &result
+}
+
+func (sl *StructLogger) full() bool {
+	return sl.config.Limit > 0 && len(sl.logs) >= sl.config.Limit
+}
+
+func (sl *StructLogger) memory() *[]byte {
+	if len(sl.memories) == 0 {
+		// Tolerate being attached mid-execution
+		sl.memories = append(sl.memories, nil)
+	}
+	return &sl.memories[len(sl.memories)-1]
+}
+
+func (sl *StructLogger) recordStorage(step *TraceStep) map[string]string {
+	slots, ok := sl.storage[step.Address]
+	if !ok {
+		slots = make(map[Word256]Word256)
+		sl.storage[step.Address] = slots
+	}
+	for _, slot := range []*StorageSlot{step.StorageRead, step.StorageWrite} {
+		if slot != nil {
+			slots[slot.Key] = slot.Value
+		}
+	}
+	storage := make(map[string]string, len(slots))
+	for key, value := range slots {
+		storage[hex.EncodeToString(key[:])] = hex.EncodeToString(value[:])
+	}
+	return storage
+}
@@ -0,0 +1,151 @@
+package evm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/burrow/acm/acmstate"
+	. "github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/execution/engine"
+	. "github.com/hyperledger/burrow/execution/evm/asm"
+	. "github.com/hyperledger/burrow/execution/evm/asm/bc"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTracer struct {
+	events []interface{}
+}
+
+func (rt *recordingTracer) Enter(call *TraceCall) {
+	rt.events = append(rt.events, call)
+}
+
+func (rt *recordingTracer) Step(step *TraceStep) {
+	rt.events = append(rt.events, step)
+}
+
+func (rt *recordingTracer) Exit(ret *TraceReturn) {
+	rt.events = append(rt.events, ret)
+}
+
+func TestTracer(t *testing.T) {
+	tracer := new(recordingTracer)
+	vm := New(Options{Tracer: tracer})
+	st := acmstate.NewMemoryState()
+
+	ret := "returned"
+	callee := makeAccountWithCode(t, st, "callee",
+		MustSplice(PUSH32, RightPadWord256([]byte(ret)), PUSH1, 0x00, MSTORE, PUSH1, len(ret), PUSH1, 0x00, RETURN))
+	caller := newAccount(t, st, "caller")
+
+	bytecode := MustSplice(PUSH1, 0x2A, PUSH1, 0x01, SSTORE,
+		PUSH1, len(ret), PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH20, callee, PUSH2, 0x01, 0x00, CALL,
+		STOP)
+
+	var gas uint64 = 100000
+	_, err := vm.Execute(st, new(blockchain), exec.NewNoopEventSink(), engine.CallParams{
+		Caller: caller,
+		Callee: caller,
+		Gas:    &gas,
+	}, bytecode)
+	require.NoError(t, err)
+
+	require.IsType(t, &TraceCall{}, tracer.events[0])
+	enter := tracer.events[0].(*TraceCall)
+	assert.Equal(t, uint64(0), enter.Depth)
+	assert.Equal(t, caller, enter.Callee)
+
+	var steps []*TraceStep
+	var calls []*TraceCall
+	var returns []*TraceReturn
+	for _, ev := range tracer.events {
+		switch e := ev.(type) {
+		case *TraceStep:
+			steps = append(steps, e)
+		case *TraceCall:
+			calls = append(calls, e)
+		case *TraceReturn:
+			returns = append(returns, e)
+		}
+	}
+	require.Len(t, calls, 2)
+	require.Len(t, returns, 2)
+	assert.Equal(t, CALL, calls[1].Op)
+	assert.Equal(t, uint64(1), calls[1].Depth)
+	assert.Equal(t, callee, calls[1].Callee)
+	assert.Equal(t, []byte(ret), returns[0].Output)
+	assert.Equal(t, uint64(0), returns[1].Depth)
+
+	// SSTORE is the third instruction and sees both its operands on the stack
+	sstore := steps[2]
+	assert.Equal(t, SSTORE, sstore.Op)
+	assert.Equal(t, []Word256{Int64ToWord256(0x2A), Int64ToWord256(0x01)}, sstore.Stack)
+	require.NotNil(t, sstore.StorageWrite)
+	assert.Equal(t, Int64ToWord256(0x01), sstore.StorageWrite.Key)
+	assert.Equal(t, Int64ToWord256(0x2A), sstore.StorageWrite.Value)
+	assert.True(t, sstore.GasCost > 0)
+
+	for _, step := range steps {
+		if step.Op == MSTORE {
+			assert.Equal(t, uint64(1), step.Depth)
+			require.Len(t, step.MemoryWrites, 1)
+			assert.Equal(t, RightPadBytes([]byte(ret), 32), step.MemoryWrites[0].Data)
+		}
+	}
+}
+
+func TestStructLogger(t *testing.T) {
+	logger := NewStructLogger(StructLoggerConfig{})
+	vm := New(Options{Tracer: logger})
+	st := acmstate.NewMemoryState()
+
+	callee := makeAccountWithCode(t, st, "callee", MustSplice(PUSH1, 0x01, PUSH1, 0x00, MSTORE, STOP))
+	caller := newAccount(t, st, "caller")
+
+	bytecode := MustSplice(PUSH1, 0x2A, PUSH1, 0x01, SSTORE,
+		PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH1, 0x00, PUSH20, callee, PUSH2, 0x01, 0x00, CALL,
+		PUSH1, 0x20, PUSH1, 0x00, RETURN)
+
+	var gas uint64 = 100000
+	output, err := vm.Execute(st, new(blockchain), exec.NewNoopEventSink(), engine.CallParams{
+		Caller: caller,
+		Callee: caller,
+		Gas:    &gas,
+	}, bytecode)
+	require.NoError(t, err)
+
+	result := logger.Result()
+	assert.False(t, result.Failed)
+	assert.Equal(t, 100000-gas, result.Gas)
+	assert.Len(t, output, 32)
+
+	var ops []string
+	var depths []uint64
+	for _, log := range result.StructLogs {
+		ops = append(ops, log.Op)
+		depths = append(depths, log.Depth)
+	}
+	// Geth ordering: the CALL is logged before the instructions of the callee
+	assert.Equal(t, []string{"PUSH1", "PUSH1", "SSTORE", "PUSH1", "PUSH1", "PUSH1", "PUSH1", "PUSH1", "PUSH20",
+		"PUSH2", "CALL", "PUSH1", "PUSH1", "MSTORE", "STOP", "PUSH1", "PUSH1", "RETURN"}, ops)
+	assert.Equal(t, []uint64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 1, 1, 1}, depths)
+
+	sstore := result.StructLogs[2]
+	assert.Equal(t, map[string]string{
+		"0000000000000000000000000000000000000000000000000000000000000001": "000000000000000000000000000000000000000000000000000000000000002a",
+	}, sstore.Storage)
+
+	// Memory reflects the state before each instruction
+	stop := result.StructLogs[14]
+	assert.Equal(t, []string{"0000000000000000000000000000000000000000000000000000000000000001"}, stop.Memory)
+	mstore := result.StructLogs[13]
+	assert.Empty(t, mstore.Memory)
+	// The caller's memory is untouched by the callee
+	assert.Empty(t, result.StructLogs[15].Memory)
+
+	bs, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), `"structLogs":[{"pc":0,"op":"PUSH1"`)
+}
@@ -0,0 +1,182 @@
+package evm
+
+import (
+	"math/big"
+
+	. "github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/engine"
+	"github.com/hyperledger/burrow/execution/evm/asm"
+)
+
+// A Tracer receives structured events from the EVM as it executes and can be set on Options to build debuggers,
+// profilers, and the like. Events are delivered synchronously in execution order. The EVM does not retain the events
+// it passes to a Tracer so they may be kept, but they must not be modified.
+type Tracer interface {
+	// Enter is called when a new call frame is entered, including the top-level frame established by EVM.Execute
+	Enter(call *TraceCall)
+	// Step is called once each instruction has been executed
+	Step(step *TraceStep)
+	// Exit is called when the call frame most recently entered returns
+	Exit(ret *TraceReturn)
+}
+
+type TraceCall struct {
+	// The instruction that established the frame - one of CALL, CALLCODE, DELEGATECALL, STATICCALL, CREATE, or
+	// CREATE2. The top-level frame is reported as CREATE when the callee has no code of its own and CALL otherwise.
+	Op asm.OpCode
+	// Call stack depth of the new frame (the top-level frame has depth 0)
+	Depth  uint64
+	Caller crypto.Address
+	Callee crypto.Address
+	Input  []byte
+	Value  uint64
+	// Gas made available to the frame
+	Gas uint64
+}
+
+type TraceStep struct {
+	// Program counter of the instruction
+	PC uint64
+	Op asm.OpCode
+	// Gas remaining before the instruction was executed
+	Gas uint64
+	// Gas consumed by the instruction, for calls this includes any gas consumed by the child frame
+	GasCost uint64
+	// Call stack depth of the frame executing the instruction
+	Depth uint64
+	// Account whose storage the frame is operating on
+	Address crypto.Address
+	// Data stack before the instruction was executed, ordered from bottom to top
+	Stack []Word256
+	// Memory written by the instruction, in the order it was written
+	MemoryWrites []MemoryWrite
+	// Storage read by SLOAD
+	StorageRead *StorageSlot
+	// Storage written by SSTORE
+	StorageWrite *StorageSlot
+	// Any error that caused the frame to halt at this instruction
+	Err error
+}
+
+type TraceReturn struct {
+	Depth   uint64
+	Output  []byte
+	GasUsed uint64
+	Err     error
+}
+
+type MemoryWrite struct {
+	Offset uint64
+	Data   []byte
+}
+
+type StorageSlot struct {
+	Key   Word256
+	Value Word256
+}
+
+// Wraps the frame's memory to capture the writes made by each instruction
+type tracingMemory struct {
+	Memory
+	writes []MemoryWrite
+}
+
+func (mem *tracingMemory) Write(offset *big.Int, value []byte) {
+	mem.Memory.Write(offset, value)
+	if offset.IsUint64() {
+		data := make([]byte, len(value))
+		copy(data, value)
+		mem.writes = append(mem.writes, MemoryWrite{Offset: offset.Uint64(), Data: data})
+	}
+}
+
+func (mem *tracingMemory) flush() []MemoryWrite {
+	writes := mem.writes
+	mem.writes = nil
+	return writes
+}
+
+// Accumulates the TraceStep for the instruction currently being executed
+type stepTracer struct {
+	tracer Tracer
+	memory *tracingMemory
+	gas    *uint64
+	step   *TraceStep
+}
+
+func (c *Contract) newStepTracer(memory Memory, gas *uint64) (*stepTracer, Memory) {
+	if c.options.Tracer == nil {
+		return nil, memory
+	}
+	mem := &tracingMemory{Memory: memory}
+	return &stepTracer{
+		tracer: c.options.Tracer,
+		memory: mem,
+		gas:    gas,
+	}, mem
+}
+
+func (st *stepTracer) begin(pc uint64, op asm.OpCode, depth uint64, address crypto.Address, stack *Stack) {
+	if st == nil {
+		return
+	}
+	st.step = &TraceStep{
+		PC:      pc,
+		Op:      op,
+		Gas:     *st.gas,
+		Depth:   depth,
+		Address: address,
+		Stack:   stack.Copy(),
+	}
+}
+
+func (st *stepTracer) storage(read, write *StorageSlot) {
+	if st == nil || st.step == nil {
+		return
+	}
+	st.step.StorageRead = read
+	st.step.StorageWrite = write
+}
+
+// Deliver any pending step to the tracer
+func (st *stepTracer) end(err error) {
+	if st == nil || st.step == nil {
+		return
+	}
+	step := st.step
+	st.step = nil
+	if step.Gas > *st.gas {
+		step.GasCost = step.Gas - *st.gas
+	}
+	step.MemoryWrites = st.memory.flush()
+	step.Err = err
+	st.tracer.Step(step)
+}
+
+func (vm *EVM) traceEnter(op asm.OpCode, depth uint64, params engine.CallParams) {
+	if vm.options.Tracer == nil {
+		return
+	}
+	vm.options.Tracer.Enter(&TraceCall{
+		Op:     op,
+		Depth:  depth,
+		Caller: params.Caller,
+		Callee: params.Callee,
+		Input:  params.Input,
+		Value:  params.Value,
+		Gas:    *params.Gas,
+	})
+}
+
+func (vm *EVM) traceExit(depth uint64, output []byte, gasUsed uint64, err error) {
+	if vm.options.Tracer == nil {
+		return
+	}
+	vm.options.Tracer.Exit(&TraceReturn{
+		Depth:   depth,
+		Output:  output,
+		GasUsed: gasUsed,
+		Err:     err,
+	})
+}
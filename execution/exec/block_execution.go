@@ -25,7 +25,8 @@ func (be *BlockExecution) StreamEvents() []*StreamEvent {
 	}
 	return append(ses, &StreamEvent{
 		EndBlock: &EndBlock{
-			Height: be.Height,
+			Height:    be.Height,
+			StateDiff: be.StateDiff,
 		},
 	})
 }
@@ -225,10 +225,12 @@ func (*BeginBlock) XXX_MessageName() string {
 }
 
 type EndBlock struct {
-	Height               uint64   `protobuf:"varint,1,opt,name=Height,proto3" json:"Height,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Height uint64 `protobuf:"varint,1,opt,name=Height,proto3" json:"Height,omitempty"`
+	// Changes made to account state outside of the block's transactions, such as the payment of fees
+	StateDiff            *StateDiff `protobuf:"bytes,2,opt,name=StateDiff,proto3" json:"StateDiff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *EndBlock) Reset()         { *m = EndBlock{} }
@@ -267,6 +269,13 @@ func (m *EndBlock) GetHeight() uint64 {
 	return 0
 }
 
+func (m *EndBlock) GetStateDiff() *StateDiff {
+	if m != nil {
+		return m.StateDiff
+	}
+	return nil
+}
+
 func (*EndBlock) XXX_MessageName() string {
 	return "exec.EndBlock"
 }
@@ -276,10 +285,12 @@ type BeginTx struct {
 	// Result of tx execution
 	Result *Result `protobuf:"bytes,2,opt,name=Result,proto3" json:"Result,omitempty"`
 	// If tx execution was an exception
-	Exception            *errors.Exception `protobuf:"bytes,4,opt,name=Exception,proto3" json:"Exception,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Exception *errors.Exception `protobuf:"bytes,4,opt,name=Exception,proto3" json:"Exception,omitempty"`
+	// The changes made to account state, if recording of state diffs is enabled
+	StateDiff            *StateDiff `protobuf:"bytes,5,opt,name=StateDiff,proto3" json:"StateDiff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *BeginTx) Reset()         { *m = BeginTx{} }
@@ -332,6 +343,13 @@ func (m *BeginTx) GetException() *errors.Exception {
 	return nil
 }
 
+func (m *BeginTx) GetStateDiff() *StateDiff {
+	if m != nil {
+		return m.StateDiff
+	}
+	return nil
+}
+
 func (*BeginTx) XXX_MessageName() string {
 	return "exec.BeginTx"
 }
@@ -456,12 +474,14 @@ func (*TxHeader) XXX_MessageName() string {
 
 type BlockExecution struct {
 	// The height of this block
-	Height               uint64         `protobuf:"varint,1,opt,name=Height,proto3" json:"Height,omitempty"`
-	Header               *types.Header  `protobuf:"bytes,2,opt,name=Header,proto3" json:"Header,omitempty"`
-	TxExecutions         []*TxExecution `protobuf:"bytes,3,rep,name=TxExecutions,proto3" json:"TxExecutions,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Height       uint64         `protobuf:"varint,1,opt,name=Height,proto3" json:"Height,omitempty"`
+	Header       *types.Header  `protobuf:"bytes,2,opt,name=Header,proto3" json:"Header,omitempty"`
+	TxExecutions []*TxExecution `protobuf:"bytes,3,rep,name=TxExecutions,proto3" json:"TxExecutions,omitempty"`
+	// Changes made to account state outside of the block's transactions, such as the payment of fees
+	StateDiff            *StateDiff `protobuf:"bytes,4,opt,name=StateDiff,proto3" json:"StateDiff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *BlockExecution) Reset()         { *m = BlockExecution{} }
@@ -514,6 +534,13 @@ func (m *BlockExecution) GetTxExecutions() []*TxExecution {
 	return nil
 }
 
+func (m *BlockExecution) GetStateDiff() *StateDiff {
+	if m != nil {
+		return m.StateDiff
+	}
+	return nil
+}
+
 func (*BlockExecution) XXX_MessageName() string {
 	return "exec.BlockExecution"
 }
@@ -588,10 +615,12 @@ type TxExecution struct {
 	// If execution was an exception
 	Exception *errors.Exception `protobuf:"bytes,10,opt,name=Exception,proto3" json:"Exception,omitempty"`
 	// A proposal may contain other transactions
-	TxExecutions         []*TxExecution `protobuf:"bytes,11,rep,name=TxExecutions,proto3" json:"TxExecutions,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	TxExecutions []*TxExecution `protobuf:"bytes,11,rep,name=TxExecutions,proto3" json:"TxExecutions,omitempty"`
+	// The changes made to account state, if recording of state diffs is enabled
+	StateDiff            *StateDiff `protobuf:"bytes,12,opt,name=StateDiff,proto3" json:"StateDiff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *TxExecution) Reset()         { *m = TxExecution{} }
@@ -658,6 +687,13 @@ func (m *TxExecution) GetTxExecutions() []*TxExecution {
 	return nil
 }
 
+func (m *TxExecution) GetStateDiff() *StateDiff {
+	if m != nil {
+		return m.StateDiff
+	}
+	return nil
+}
+
 func (*TxExecution) XXX_MessageName() string {
 	return "exec.TxExecution"
 }
@@ -926,10 +962,12 @@ type Result struct {
 	// Name entry created
 	NameEntry *names.Entry `protobuf:"bytes,3,opt,name=NameEntry,proto3" json:"NameEntry,omitempty"`
 	// Permission update performed
-	PermArgs             *permission.PermArgs `protobuf:"bytes,4,opt,name=PermArgs,proto3" json:"PermArgs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	PermArgs *permission.PermArgs `protobuf:"bytes,4,opt,name=PermArgs,proto3" json:"PermArgs,omitempty"`
+	// Fee paid by the input for the transaction when the chain has a fee schedule
+	Fee                  uint64   `protobuf:"varint,5,opt,name=Fee,proto3" json:"Fee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Result) Reset()         { *m = Result{} }
@@ -989,6 +1027,13 @@ func (m *Result) GetPermArgs() *permission.PermArgs {
 	return nil
 }
 
+func (m *Result) GetFee() uint64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
 func (*Result) XXX_MessageName() string {
 	return "exec.Result"
 }
@@ -1284,6 +1329,344 @@ func (m *CallData) GetGas() uint64 {
 func (*CallData) XXX_MessageName() string {
 	return "exec.CallData"
 }
+
+// StateDiff describes how a transaction changed the accounts it touched
+type StateDiff struct {
+	// In order of address
+	Accounts             []*AccountDiff `protobuf:"bytes,1,rep,name=Accounts,proto3" json:"Accounts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *StateDiff) Reset()         { *m = StateDiff{} }
+func (m *StateDiff) String() string { return proto.CompactTextString(m) }
+func (*StateDiff) ProtoMessage()    {}
+func (*StateDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d737c7315c25422, []int{20}
+}
+func (m *StateDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *StateDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateDiff.Merge(m, src)
+}
+func (m *StateDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateDiff proto.InternalMessageInfo
+
+func (m *StateDiff) GetAccounts() []*AccountDiff {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+func (*StateDiff) XXX_MessageName() string {
+	return "exec.StateDiff"
+}
+
+type AccountDiff struct {
+	Address github_com_hyperledger_burrow_crypto.Address `protobuf:"bytes,1,opt,name=Address,proto3,customtype=github.com/hyperledger/burrow/crypto.Address" json:"Address"`
+	// Whether the account did not exist before the transaction
+	Created bool `protobuf:"varint,2,opt,name=Created,proto3" json:"Created,omitempty"`
+	// Whether the account was removed by the transaction
+	Removed bool `protobuf:"varint,3,opt,name=Removed,proto3" json:"Removed,omitempty"`
+	// Set if the balance changed
+	Balance *BalanceDiff `protobuf:"bytes,4,opt,name=Balance,proto3" json:"Balance,omitempty"`
+	// Set if the code changed
+	Code *CodeDiff `protobuf:"bytes,5,opt,name=Code,proto3" json:"Code,omitempty"`
+	// Set if the permissions changed
+	Permissions *PermissionsDiff `protobuf:"bytes,6,opt,name=Permissions,proto3" json:"Permissions,omitempty"`
+	// In order of key
+	Storage              []*StorageDiff `protobuf:"bytes,7,rep,name=Storage,proto3" json:"Storage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AccountDiff) Reset()         { *m = AccountDiff{} }
+func (m *AccountDiff) String() string { return proto.CompactTextString(m) }
+func (*AccountDiff) ProtoMessage()    {}
+func (*AccountDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d737c7315c25422, []int{21}
+}
+func (m *AccountDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AccountDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *AccountDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountDiff.Merge(m, src)
+}
+func (m *AccountDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *AccountDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountDiff proto.InternalMessageInfo
+
+func (m *AccountDiff) GetCreated() bool {
+	if m != nil {
+		return m.Created
+	}
+	return false
+}
+
+func (m *AccountDiff) GetRemoved() bool {
+	if m != nil {
+		return m.Removed
+	}
+	return false
+}
+
+func (m *AccountDiff) GetBalance() *BalanceDiff {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *AccountDiff) GetCode() *CodeDiff {
+	if m != nil {
+		return m.Code
+	}
+	return nil
+}
+
+func (m *AccountDiff) GetPermissions() *PermissionsDiff {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
+func (m *AccountDiff) GetStorage() []*StorageDiff {
+	if m != nil {
+		return m.Storage
+	}
+	return nil
+}
+
+func (*AccountDiff) XXX_MessageName() string {
+	return "exec.AccountDiff"
+}
+
+type BalanceDiff struct {
+	Before               uint64   `protobuf:"varint,1,opt,name=Before,proto3" json:"Before,omitempty"`
+	After                uint64   `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalanceDiff) Reset()         { *m = BalanceDiff{} }
+func (m *BalanceDiff) String() string { return proto.CompactTextString(m) }
+func (*BalanceDiff) ProtoMessage()    {}
+func (*BalanceDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d737c7315c25422, []int{22}
+}
+func (m *BalanceDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BalanceDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *BalanceDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceDiff.Merge(m, src)
+}
+func (m *BalanceDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *BalanceDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceDiff proto.InternalMessageInfo
+
+func (m *BalanceDiff) GetBefore() uint64 {
+	if m != nil {
+		return m.Before
+	}
+	return 0
+}
+
+func (m *BalanceDiff) GetAfter() uint64 {
+	if m != nil {
+		return m.After
+	}
+	return 0
+}
+
+func (*BalanceDiff) XXX_MessageName() string {
+	return "exec.BalanceDiff"
+}
+
+type CodeDiff struct {
+	Before               github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,1,opt,name=Before,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"Before"`
+	After                github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,2,opt,name=After,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"After"`
+	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
+	XXX_unrecognized     []byte                                        `json:"-"`
+	XXX_sizecache        int32                                         `json:"-"`
+}
+
+func (m *CodeDiff) Reset()         { *m = CodeDiff{} }
+func (m *CodeDiff) String() string { return proto.CompactTextString(m) }
+func (*CodeDiff) ProtoMessage()    {}
+func (*CodeDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d737c7315c25422, []int{23}
+}
+func (m *CodeDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CodeDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *CodeDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CodeDiff.Merge(m, src)
+}
+func (m *CodeDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *CodeDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_CodeDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CodeDiff proto.InternalMessageInfo
+
+func (*CodeDiff) XXX_MessageName() string {
+	return "exec.CodeDiff"
+}
+
+type PermissionsDiff struct {
+	Before               permission.AccountPermissions `protobuf:"bytes,1,opt,name=Before,proto3" json:"Before"`
+	After                permission.AccountPermissions `protobuf:"bytes,2,opt,name=After,proto3" json:"After"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *PermissionsDiff) Reset()         { *m = PermissionsDiff{} }
+func (m *PermissionsDiff) String() string { return proto.CompactTextString(m) }
+func (*PermissionsDiff) ProtoMessage()    {}
+func (*PermissionsDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d737c7315c25422, []int{24}
+}
+func (m *PermissionsDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PermissionsDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *PermissionsDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PermissionsDiff.Merge(m, src)
+}
+func (m *PermissionsDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *PermissionsDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_PermissionsDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PermissionsDiff proto.InternalMessageInfo
+
+func (m *PermissionsDiff) GetBefore() permission.AccountPermissions {
+	if m != nil {
+		return m.Before
+	}
+	return permission.AccountPermissions{}
+}
+
+func (m *PermissionsDiff) GetAfter() permission.AccountPermissions {
+	if m != nil {
+		return m.After
+	}
+	return permission.AccountPermissions{}
+}
+
+func (*PermissionsDiff) XXX_MessageName() string {
+	return "exec.PermissionsDiff"
+}
+
+type StorageDiff struct {
+	Key github_com_hyperledger_burrow_binary.Word256 `protobuf:"bytes,1,opt,name=Key,proto3,customtype=github.com/hyperledger/burrow/binary.Word256" json:"Key"`
+	// Empty if the key was not set
+	Before github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,2,opt,name=Before,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"Before"`
+	// Empty if the key was deleted
+	After                github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,3,opt,name=After,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"After"`
+	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
+	XXX_unrecognized     []byte                                        `json:"-"`
+	XXX_sizecache        int32                                         `json:"-"`
+}
+
+func (m *StorageDiff) Reset()         { *m = StorageDiff{} }
+func (m *StorageDiff) String() string { return proto.CompactTextString(m) }
+func (*StorageDiff) ProtoMessage()    {}
+func (*StorageDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d737c7315c25422, []int{25}
+}
+func (m *StorageDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StorageDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *StorageDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageDiff.Merge(m, src)
+}
+func (m *StorageDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *StorageDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageDiff proto.InternalMessageInfo
+
+func (*StorageDiff) XXX_MessageName() string {
+	return "exec.StorageDiff"
+}
 func init() {
 	proto.RegisterType((*StreamEvents)(nil), "exec.StreamEvents")
 	golang_proto.RegisterType((*StreamEvents)(nil), "exec.StreamEvents")
@@ -1325,93 +1708,122 @@ func init() {
 	golang_proto.RegisterType((*OutputEvent)(nil), "exec.OutputEvent")
 	proto.RegisterType((*CallData)(nil), "exec.CallData")
 	golang_proto.RegisterType((*CallData)(nil), "exec.CallData")
+	proto.RegisterType((*StateDiff)(nil), "exec.StateDiff")
+	golang_proto.RegisterType((*StateDiff)(nil), "exec.StateDiff")
+	proto.RegisterType((*AccountDiff)(nil), "exec.AccountDiff")
+	golang_proto.RegisterType((*AccountDiff)(nil), "exec.AccountDiff")
+	proto.RegisterType((*BalanceDiff)(nil), "exec.BalanceDiff")
+	golang_proto.RegisterType((*BalanceDiff)(nil), "exec.BalanceDiff")
+	proto.RegisterType((*CodeDiff)(nil), "exec.CodeDiff")
+	golang_proto.RegisterType((*CodeDiff)(nil), "exec.CodeDiff")
+	proto.RegisterType((*PermissionsDiff)(nil), "exec.PermissionsDiff")
+	golang_proto.RegisterType((*PermissionsDiff)(nil), "exec.PermissionsDiff")
+	proto.RegisterType((*StorageDiff)(nil), "exec.StorageDiff")
+	golang_proto.RegisterType((*StorageDiff)(nil), "exec.StorageDiff")
 }
 
 func init() { proto.RegisterFile("exec.proto", fileDescriptor_4d737c7315c25422) }
 func init() { golang_proto.RegisterFile("exec.proto", fileDescriptor_4d737c7315c25422) }
 
 var fileDescriptor_4d737c7315c25422 = []byte{
-	// 1275 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0xcf, 0x93, 0x13, 0xc5,
-	0x17, 0x67, 0x92, 0xc9, 0xaf, 0x4e, 0x96, 0x2f, 0x74, 0xf1, 0xa5, 0x52, 0x1c, 0x12, 0x1c, 0x14,
-	0x11, 0x61, 0x42, 0xad, 0xa2, 0x16, 0x56, 0x59, 0x12, 0x36, 0xc2, 0x0a, 0x82, 0x36, 0x01, 0x4b,
-	0x4b, 0x0f, 0x93, 0x99, 0x66, 0x32, 0x45, 0x32, 0x33, 0x35, 0xd3, 0xc1, 0xc9, 0x3f, 0xe0, 0xc1,
-	0xf2, 0xa0, 0x37, 0xbc, 0x58, 0xfc, 0x1f, 0x5e, 0x3c, 0xee, 0x4d, 0x8e, 0x16, 0x87, 0x68, 0x2d,
-	0x7f, 0x81, 0xe5, 0xc9, 0x3d, 0x59, 0xdd, 0xfd, 0x7a, 0xd2, 0x23, 0xcb, 0x2e, 0x65, 0xf6, 0xe0,
-	0x25, 0xd5, 0xef, 0xbd, 0x4f, 0xbf, 0xbc, 0x7e, 0xef, 0xf3, 0xde, 0x1b, 0x84, 0x68, 0x46, 0x5d,
-	0x3b, 0x4e, 0x22, 0x16, 0x61, 0x93, 0x9f, 0x4f, 0x9c, 0xf7, 0x03, 0x36, 0x9e, 0x8d, 0x6c, 0x37,
-	0x9a, 0xf6, 0xfc, 0xc8, 0x8f, 0x7a, 0xc2, 0x38, 0x9a, 0xdd, 0x13, 0x92, 0x10, 0xc4, 0x49, 0x5e,
-	0x3a, 0xf1, 0xb6, 0x06, 0x67, 0x34, 0xf4, 0x68, 0x32, 0x0d, 0x42, 0xa6, 0x1f, 0x9d, 0x91, 0x1b,
-	0xf4, 0xd8, 0x3c, 0xa6, 0xa9, 0xfc, 0x85, 0x8b, 0x5d, 0x3f, 0x8a, 0xfc, 0x09, 0x5d, 0xba, 0x67,
-	0xc1, 0x94, 0xa6, 0xcc, 0x99, 0xc6, 0x00, 0x68, 0xd1, 0x24, 0x89, 0x12, 0x05, 0x6f, 0x86, 0xce,
-	0x34, 0xbf, 0xdb, 0x60, 0x99, 0x3a, 0x1e, 0x89, 0xf9, 0xdf, 0xa4, 0x69, 0x10, 0x85, 0xa0, 0x41,
-	0x69, 0xac, 0x9e, 0x64, 0x0d, 0x50, 0xeb, 0x36, 0x4b, 0xa8, 0x33, 0x1d, 0x3c, 0xa0, 0x21, 0x4b,
-	0xf1, 0xc5, 0xa2, 0xdc, 0x36, 0x4e, 0x96, 0xcf, 0x34, 0xd7, 0x8f, 0xda, 0x22, 0x0b, 0x9a, 0x85,
-	0x14, 0x60, 0xd6, 0x4f, 0x25, 0xd4, 0xd4, 0x14, 0xf8, 0x02, 0x42, 0x7d, 0xea, 0x07, 0x61, 0x7f,
-	0x12, 0xb9, 0xf7, 0xdb, 0xc6, 0x49, 0xe3, 0x4c, 0x73, 0xfd, 0x88, 0x74, 0xb2, 0xd4, 0x13, 0x0d,
-	0x83, 0x5f, 0x45, 0x35, 0x21, 0x0d, 0xb3, 0x76, 0x49, 0xc0, 0xd7, 0x34, 0xf8, 0x30, 0x23, 0xca,
-	0x8a, 0x3f, 0x43, 0xf5, 0x41, 0xf8, 0x80, 0x4e, 0xa2, 0x98, 0xb6, 0xcb, 0x80, 0xe4, 0xaf, 0x55,
-	0xca, 0xbe, 0xfd, 0x64, 0xd1, 0x3d, 0xab, 0x25, 0x7d, 0x3c, 0x8f, 0x69, 0x32, 0xa1, 0x9e, 0x4f,
-	0x93, 0xde, 0x68, 0x96, 0x24, 0xd1, 0x57, 0x3d, 0x1d, 0x4f, 0x72, 0x77, 0xf8, 0x25, 0x54, 0x11,
-	0xe1, 0xb7, 0x4d, 0xe1, 0xb7, 0x29, 0x23, 0x90, 0xef, 0x95, 0x16, 0x01, 0x09, 0xbd, 0x61, 0xd6,
-	0xae, 0x14, 0x20, 0x5c, 0x45, 0xa4, 0x05, 0x9f, 0xe5, 0x01, 0x7a, 0xf2, 0xe5, 0x55, 0x81, 0x3a,
-	0x9c, 0xa3, 0xe4, 0xbb, 0x73, 0xfb, 0x25, 0x73, 0xeb, 0x51, 0xd7, 0xb0, 0xae, 0xeb, 0xd9, 0xc2,
-	0xc7, 0x51, 0xf5, 0x1a, 0x0d, 0xfc, 0x31, 0x13, 0x79, 0x33, 0x09, 0x48, 0xf8, 0x15, 0xae, 0x77,
-	0x3c, 0x9a, 0xe4, 0x09, 0x92, 0x6c, 0x91, 0x4a, 0x02, 0x46, 0xcb, 0x5a, 0xfe, 0xfd, 0xf3, 0x5c,
-	0x59, 0xdf, 0x1a, 0x79, 0xb6, 0x79, 0xb8, 0xc3, 0x0c, 0x1c, 0x1b, 0x7a, 0xb8, 0x4a, 0x4b, 0x72,
-	0x3b, 0x7e, 0x19, 0x55, 0x09, 0x4d, 0x67, 0x13, 0x06, 0x21, 0xb4, 0x24, 0x52, 0xea, 0x08, 0xd8,
-	0x70, 0x0f, 0x35, 0x06, 0x99, 0x4b, 0x63, 0x16, 0x44, 0x21, 0xa4, 0xf2, 0xa8, 0x0d, 0x5c, 0xcd,
-	0x0d, 0x64, 0x89, 0xb1, 0xee, 0x42, 0x52, 0xf1, 0x47, 0xa8, 0x3a, 0xcc, 0xae, 0x39, 0xe9, 0x58,
-	0x54, 0xb6, 0xd5, 0xbf, 0xb8, 0xb5, 0xe8, 0x1e, 0x7a, 0xb2, 0xe8, 0x9e, 0xdf, 0xbb, 0x9c, 0xa3,
-	0x20, 0x74, 0x92, 0xb9, 0x7d, 0x8d, 0x66, 0xfd, 0x39, 0xa3, 0x29, 0x01, 0x27, 0xd6, 0x5f, 0xc6,
-	0xf2, 0x6d, 0xf8, 0x43, 0xee, 0x7b, 0x38, 0x8f, 0xa9, 0x78, 0xe5, 0x5a, 0x7f, 0x7d, 0x67, 0xd1,
-	0xb5, 0xf7, 0xa5, 0x49, 0x2f, 0x76, 0xe6, 0x93, 0xc8, 0xf1, 0x6c, 0x7e, 0x93, 0x80, 0x07, 0x2d,
-	0xce, 0xd2, 0x01, 0xc4, 0xa9, 0x95, 0xa9, 0x5c, 0xa8, 0xf8, 0x31, 0x54, 0xd9, 0x0c, 0x3d, 0x9a,
-	0x89, 0x24, 0x9a, 0x44, 0x0a, 0xbc, 0x08, 0xb7, 0x92, 0xc0, 0x0f, 0x42, 0xe0, 0x20, 0x14, 0x41,
-	0xea, 0x08, 0xd8, 0xac, 0xaf, 0x0d, 0x74, 0x58, 0x90, 0x60, 0x90, 0x51, 0x77, 0xc6, 0xd3, 0xbc,
-	0x22, 0xb1, 0xf8, 0x68, 0x18, 0x66, 0xb9, 0xb7, 0xb4, 0x5d, 0xd6, 0x47, 0x83, 0x66, 0x21, 0x05,
-	0x98, 0xf5, 0x3e, 0x3a, 0xac, 0xc9, 0xd7, 0xe9, 0xfc, 0xb9, 0x71, 0x1c, 0x47, 0xd5, 0x5b, 0xf7,
-	0xee, 0xa5, 0x54, 0xb2, 0xcb, 0x24, 0x20, 0x59, 0x7f, 0x94, 0x50, 0x53, 0x73, 0x81, 0xcf, 0xe5,
-	0xf1, 0xee, 0xca, 0xd7, 0xbe, 0xf9, 0x78, 0xd1, 0x35, 0xf2, 0xb0, 0xf5, 0x79, 0x51, 0x3d, 0xd8,
-	0x79, 0x71, 0x0a, 0x55, 0x61, 0x4c, 0xd6, 0x44, 0x2e, 0x0a, 0x03, 0x03, 0x4c, 0x5a, 0xcf, 0xd4,
-	0xf7, 0xe8, 0x99, 0xd3, 0xa8, 0x46, 0xa8, 0x4b, 0x83, 0x98, 0xb5, 0x1b, 0x00, 0xe3, 0x7f, 0x0a,
-	0x3a, 0xa2, 0x8c, 0xc5, 0xde, 0x42, 0xfb, 0xf7, 0xd6, 0x33, 0x55, 0x6b, 0xbe, 0x58, 0xd5, 0xbe,
-	0x31, 0x14, 0xcb, 0x70, 0x1b, 0xd5, 0xae, 0x8c, 0x9d, 0x20, 0xdc, 0xdc, 0x10, 0xf9, 0x6e, 0x10,
-	0x25, 0x6a, 0x85, 0x2c, 0xed, 0xce, 0xdb, 0xb2, 0xce, 0xdb, 0x77, 0x90, 0x39, 0x0c, 0xa6, 0x14,
-	0x26, 0xc2, 0x09, 0x5b, 0xae, 0x37, 0x5b, 0xad, 0x37, 0x7b, 0xa8, 0xd6, 0x5b, 0xbf, 0xce, 0xdb,
-	0xe9, 0xbb, 0xdf, 0xba, 0x06, 0x11, 0x37, 0xac, 0x5f, 0x4a, 0xaa, 0xe2, 0xff, 0xe5, 0x2e, 0x7e,
-	0x1d, 0x35, 0x44, 0xc9, 0x45, 0x74, 0x65, 0x11, 0xdd, 0xda, 0xce, 0xa2, 0xbb, 0x54, 0x92, 0xe5,
-	0x91, 0x27, 0x55, 0x08, 0x9b, 0x1b, 0x22, 0x1f, 0x0d, 0xa2, 0x44, 0x2d, 0xa9, 0x95, 0xdd, 0x93,
-	0x5a, 0xd5, 0x93, 0x5a, 0xe0, 0x43, 0x6d, 0x7f, 0x3e, 0x5c, 0x32, 0x1f, 0x3e, 0xea, 0x1e, 0xb2,
-	0xbe, 0x2f, 0xc1, 0xaa, 0xe3, 0xf4, 0x2c, 0x34, 0x13, 0xd0, 0xf3, 0x1f, 0xbd, 0x7f, 0x9a, 0xff,
-	0x79, 0x3c, 0x53, 0x73, 0x1f, 0x56, 0xb9, 0x50, 0xc1, 0x7a, 0x14, 0x67, 0xfc, 0x1a, 0xaa, 0xde,
-	0x9a, 0x31, 0x0e, 0x2c, 0xab, 0x58, 0xc4, 0x6c, 0x12, 0x3a, 0xe8, 0x0b, 0x29, 0xe0, 0x53, 0xc8,
-	0xbc, 0xe2, 0x4c, 0x26, 0x40, 0x87, 0xff, 0x49, 0x20, 0xd7, 0x48, 0x98, 0x30, 0xe2, 0x93, 0xa8,
-	0x7c, 0x23, 0xf2, 0x61, 0xd0, 0x41, 0x9f, 0xdf, 0x88, 0x7c, 0x09, 0xe1, 0x26, 0xfc, 0x1e, 0x5a,
-	0xbb, 0x1a, 0x3d, 0xa0, 0x49, 0x78, 0xd9, 0x75, 0xa3, 0x59, 0xc8, 0xa0, 0xc7, 0xdb, 0x12, 0x5b,
-	0x30, 0xc9, 0x5b, 0x45, 0xf8, 0xa5, 0x3a, 0xcf, 0x87, 0xd8, 0xc2, 0x0f, 0x0d, 0xd5, 0xa9, 0xbc,
-	0x06, 0x84, 0xb2, 0x59, 0x12, 0x8a, 0xa4, 0xb4, 0x08, 0x48, 0xbc, 0x6a, 0x57, 0x9d, 0xf4, 0x4e,
-	0x4a, 0x3d, 0x60, 0xbc, 0x12, 0xf1, 0x59, 0xd4, 0xb8, 0xe9, 0x4c, 0xe9, 0x20, 0x64, 0xc9, 0x1c,
-	0xde, 0xde, 0xb2, 0xe5, 0x17, 0x99, 0xd0, 0x91, 0xa5, 0x19, 0x5f, 0x40, 0xf5, 0x8f, 0x69, 0x32,
-	0xbd, 0x9c, 0xf8, 0x29, 0xbc, 0xfe, 0x98, 0xad, 0x7d, 0xa4, 0x29, 0x1b, 0xc9, 0x51, 0xd6, 0x9f,
-	0x06, 0xaa, 0xab, 0x67, 0xe3, 0x9b, 0xa8, 0x76, 0xd9, 0xf3, 0x12, 0x9a, 0xa6, 0x32, 0xba, 0xfe,
-	0x9b, 0xc0, 0xdb, 0x73, 0x7b, 0xf3, 0xd6, 0x4d, 0xe6, 0x31, 0x8b, 0x6c, 0xb8, 0x4b, 0x94, 0x13,
-	0xbc, 0x89, 0xcc, 0x0d, 0x87, 0x39, 0xab, 0x35, 0x81, 0x70, 0x81, 0x6f, 0xa0, 0xea, 0x30, 0x8a,
-	0x03, 0x57, 0x2e, 0x87, 0x17, 0x8e, 0x0c, 0x9c, 0x7d, 0x1a, 0x25, 0xde, 0xfa, 0xc5, 0xb7, 0x08,
-	0xf8, 0xb0, 0x7e, 0x2c, 0xa1, 0x46, 0x4e, 0x08, 0x7c, 0x06, 0xd5, 0xb9, 0x20, 0xba, 0xab, 0x22,
-	0xba, 0xab, 0xb5, 0xb3, 0xe8, 0xe6, 0x3a, 0x92, 0x9f, 0xf8, 0x17, 0x0d, 0x3f, 0x8b, 0x47, 0x15,
-	0x36, 0x84, 0xd2, 0x92, 0xdc, 0xce, 0x23, 0x86, 0x65, 0x5a, 0x5a, 0x21, 0x97, 0x6a, 0x54, 0x76,
-	0x10, 0xba, 0xcd, 0x1c, 0xf7, 0xfe, 0x06, 0x8d, 0xd9, 0x18, 0xa6, 0x9f, 0xa6, 0xe1, 0x13, 0x07,
-	0x78, 0x65, 0xae, 0x34, 0x71, 0xa4, 0x13, 0xeb, 0x13, 0x84, 0x9f, 0x25, 0x38, 0x7e, 0x17, 0xad,
-	0x81, 0x7c, 0x27, 0xf6, 0x1c, 0x46, 0x21, 0x07, 0xff, 0xb7, 0xc5, 0x67, 0xff, 0x90, 0x4e, 0xe3,
-	0x89, 0xc3, 0x28, 0x40, 0x48, 0x11, 0x6b, 0x7d, 0x81, 0xd0, 0xb2, 0xab, 0x0f, 0x9a, 0x6a, 0xd6,
-	0x97, 0xa8, 0xa9, 0x8d, 0x82, 0x03, 0x77, 0xff, 0x43, 0x09, 0x15, 0x2a, 0xcb, 0xcf, 0x30, 0xd8,
-	0xfe, 0x75, 0x65, 0xa5, 0x8f, 0xdc, 0x1b, 0x5d, 0x8d, 0x27, 0xd2, 0x47, 0xde, 0x72, 0xe5, 0xd5,
-	0x5b, 0xee, 0x18, 0xaa, 0xdc, 0x75, 0x26, 0x33, 0xaa, 0xbe, 0x11, 0x85, 0x80, 0x8f, 0xa0, 0xf2,
-	0x55, 0x27, 0x85, 0x0d, 0xc2, 0x8f, 0xfd, 0x0f, 0xb6, 0xb6, 0x3b, 0xc6, 0xe3, 0xed, 0x8e, 0xf1,
-	0xeb, 0x76, 0xc7, 0xf8, 0x7d, 0xbb, 0x63, 0xfc, 0xfc, 0xb4, 0x63, 0x6c, 0x3d, 0xed, 0x18, 0x9f,
-	0xef, 0xf3, 0x04, 0xaa, 0x3e, 0x0a, 0xc4, 0x69, 0x54, 0x15, 0xfb, 0xfa, 0x8d, 0xbf, 0x03, 0x00,
-	0x00, 0xff, 0xff, 0xf5, 0x7c, 0x70, 0x97, 0x18, 0x0f, 0x00, 0x00,
+	// 1543 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0xbf, 0x73, 0x1b, 0xc5,
+	0x17, 0xcf, 0xe9, 0x4e, 0xb2, 0xf4, 0x24, 0xe7, 0xc7, 0x4e, 0x92, 0xd1, 0xa4, 0x90, 0xfc, 0xbd,
+	0xe4, 0x1b, 0x42, 0x12, 0x9f, 0x33, 0x86, 0x10, 0xc6, 0x61, 0x18, 0xac, 0xd8, 0x71, 0x8c, 0x4d,
+	0x7e, 0xac, 0x95, 0x30, 0x30, 0x50, 0x9c, 0x75, 0x2b, 0xf9, 0x26, 0xd2, 0xdd, 0xcd, 0xdd, 0xca,
+	0x48, 0xff, 0x02, 0x34, 0xd0, 0x41, 0xc3, 0xa4, 0xa2, 0xa0, 0xa7, 0x4a, 0x43, 0xe9, 0x8e, 0x94,
+	0x4c, 0x0a, 0xc1, 0x38, 0x7f, 0x01, 0x43, 0x45, 0x2a, 0x66, 0x7f, 0x9d, 0xf6, 0x4c, 0x12, 0x07,
+	0xcb, 0x05, 0xcd, 0xcd, 0xbe, 0xf7, 0x3e, 0xfb, 0xee, 0xfd, 0xde, 0x5d, 0x00, 0x32, 0x20, 0x2d,
+	0x27, 0x8a, 0x43, 0x1a, 0x22, 0x8b, 0xad, 0xcf, 0xcc, 0x76, 0x7c, 0xba, 0xd5, 0xdf, 0x74, 0x5a,
+	0x61, 0x6f, 0xae, 0x13, 0x76, 0xc2, 0x39, 0x2e, 0xdc, 0xec, 0xb7, 0x39, 0xc5, 0x09, 0xbe, 0x12,
+	0x9b, 0xce, 0x5c, 0xd3, 0xe0, 0x94, 0x04, 0x1e, 0x89, 0x7b, 0x7e, 0x40, 0xf5, 0xa5, 0xbb, 0xd9,
+	0xf2, 0xe7, 0xe8, 0x30, 0x22, 0x89, 0xf8, 0xca, 0x8d, 0xf5, 0x4e, 0x18, 0x76, 0xba, 0x64, 0xac,
+	0x9e, 0xfa, 0x3d, 0x92, 0x50, 0xb7, 0x17, 0x49, 0x40, 0x85, 0xc4, 0x71, 0x18, 0x2b, 0x78, 0x39,
+	0x70, 0x7b, 0xe9, 0xde, 0x12, 0x1d, 0xa8, 0xe5, 0xf1, 0x88, 0xfd, 0x26, 0x49, 0xfc, 0x30, 0x90,
+	0x1c, 0x48, 0x22, 0xe5, 0x92, 0xbd, 0x0c, 0x95, 0x0d, 0x1a, 0x13, 0xb7, 0xb7, 0xbc, 0x4d, 0x02,
+	0x9a, 0xa0, 0xab, 0x59, 0xba, 0x6a, 0xcc, 0x98, 0x17, 0xca, 0xf3, 0x27, 0x1c, 0x1e, 0x05, 0x4d,
+	0x82, 0x33, 0x30, 0xfb, 0x71, 0x0e, 0xca, 0x1a, 0x03, 0x5d, 0x01, 0x68, 0x90, 0x8e, 0x1f, 0x34,
+	0xba, 0x61, 0xeb, 0x61, 0xd5, 0x98, 0x31, 0x2e, 0x94, 0xe7, 0x8f, 0x0b, 0x25, 0x63, 0x3e, 0xd6,
+	0x30, 0xe8, 0x0d, 0x98, 0xe2, 0x54, 0x73, 0x50, 0xcd, 0x71, 0xf8, 0xb4, 0x06, 0x6f, 0x0e, 0xb0,
+	0x92, 0xa2, 0x4f, 0xa0, 0xb8, 0x1c, 0x6c, 0x93, 0x6e, 0x18, 0x91, 0xaa, 0x29, 0x91, 0xcc, 0x5b,
+	0xc5, 0x6c, 0x38, 0x4f, 0x47, 0xf5, 0x8b, 0x5a, 0xd0, 0xb7, 0x86, 0x11, 0x89, 0xbb, 0xc4, 0xeb,
+	0x90, 0x78, 0x6e, 0xb3, 0x1f, 0xc7, 0xe1, 0x17, 0x73, 0x3a, 0x1e, 0xa7, 0xea, 0xd0, 0xff, 0x20,
+	0xcf, 0xcd, 0xaf, 0x5a, 0x5c, 0x6f, 0x59, 0x58, 0x20, 0xfc, 0x15, 0x12, 0x0e, 0x09, 0xbc, 0xe6,
+	0xa0, 0x9a, 0xcf, 0x40, 0x18, 0x0b, 0x0b, 0x09, 0xba, 0xc8, 0x0c, 0xf4, 0x84, 0xe7, 0x05, 0x8e,
+	0x3a, 0x9a, 0xa2, 0x84, 0xdf, 0xa9, 0x7c, 0xc1, 0xda, 0x79, 0x54, 0x37, 0xec, 0x35, 0x3d, 0x5a,
+	0xe8, 0x34, 0x14, 0x6e, 0x11, 0xbf, 0xb3, 0x45, 0x79, 0xdc, 0x2c, 0x2c, 0x29, 0xf4, 0x7f, 0xc6,
+	0x77, 0x3d, 0x12, 0xa7, 0x01, 0x12, 0xd5, 0x22, 0x98, 0x58, 0x0a, 0xed, 0x7b, 0xe3, 0xdf, 0xbf,
+	0x54, 0xd5, 0x2c, 0x94, 0x36, 0xa8, 0x4b, 0xc9, 0x92, 0xdf, 0x6e, 0x4b, 0x6d, 0xc7, 0x54, 0x8a,
+	0x25, 0x1b, 0x8f, 0x11, 0xf6, 0x63, 0x23, 0x4d, 0x0e, 0xf3, 0xae, 0x39, 0x90, 0x76, 0x18, 0xba,
+	0x77, 0x8a, 0x8b, 0x53, 0x39, 0x3a, 0x07, 0x05, 0x4c, 0x92, 0x7e, 0x97, 0xca, 0x7f, 0x54, 0x04,
+	0x52, 0xf0, 0xb0, 0x94, 0xa1, 0x39, 0x28, 0x2d, 0x0f, 0x5a, 0x24, 0xa2, 0x7e, 0x18, 0xc8, 0xc8,
+	0x9f, 0x70, 0x64, 0x69, 0xa7, 0x02, 0x3c, 0xc6, 0x64, 0xad, 0xcf, 0xef, 0x6b, 0xfd, 0x03, 0x99,
+	0x32, 0xf4, 0x11, 0x14, 0x9a, 0x83, 0x5b, 0x6e, 0xb2, 0xc5, 0xeb, 0xa6, 0xd2, 0xb8, 0xba, 0x33,
+	0xaa, 0x1f, 0x79, 0x3a, 0xaa, 0xcf, 0xbe, 0xba, 0x58, 0x36, 0xfd, 0xc0, 0x8d, 0x87, 0xce, 0x2d,
+	0x32, 0x68, 0x0c, 0x29, 0x49, 0xb0, 0x54, 0x62, 0xff, 0x65, 0x8c, 0x43, 0x81, 0x3e, 0x64, 0xba,
+	0x9b, 0xc3, 0x88, 0xf0, 0xa0, 0x4c, 0x37, 0xe6, 0x9f, 0x8f, 0xea, 0xce, 0xbe, 0x45, 0x38, 0x17,
+	0xb9, 0xc3, 0x6e, 0xe8, 0x7a, 0x0e, 0xdb, 0x89, 0xa5, 0x06, 0xcd, 0xce, 0xdc, 0x21, 0xd8, 0xa9,
+	0x15, 0x81, 0x99, 0x29, 0x82, 0x93, 0x90, 0x5f, 0x0d, 0x3c, 0x32, 0xe0, 0x31, 0xb7, 0xb0, 0x20,
+	0x58, 0xce, 0xee, 0xc4, 0x7e, 0xc7, 0x0f, 0xaa, 0x79, 0x3d, 0x67, 0x82, 0x87, 0xa5, 0xcc, 0xfe,
+	0xc9, 0x80, 0xa3, 0xbc, 0xc4, 0x96, 0x07, 0xa4, 0xd5, 0xe7, 0x59, 0x99, 0xac, 0x6c, 0xd9, 0xe0,
+	0x69, 0x0e, 0x52, 0x6d, 0x49, 0xd5, 0xd4, 0x07, 0x8f, 0x26, 0xc1, 0x19, 0x58, 0xb6, 0x16, 0xac,
+	0x7d, 0x6b, 0xe1, 0x03, 0x38, 0xaa, 0x6d, 0x5f, 0x23, 0xc3, 0x97, 0x9a, 0x7d, 0x1a, 0x0a, 0x77,
+	0xda, 0xed, 0x84, 0x88, 0xda, 0xb5, 0xb0, 0xa4, 0xec, 0x47, 0x26, 0x94, 0x35, 0x15, 0xe8, 0x72,
+	0xea, 0xde, 0x0b, 0xbb, 0xa1, 0x61, 0x3d, 0x19, 0xd5, 0x8d, 0xd4, 0x4b, 0x7d, 0x78, 0x15, 0x0e,
+	0x77, 0x78, 0x9d, 0x85, 0x82, 0x9c, 0xd9, 0x53, 0x33, 0xa6, 0x36, 0x9a, 0x18, 0x0f, 0x4b, 0x91,
+	0xd6, 0x91, 0xc5, 0x57, 0x74, 0xe4, 0x79, 0x98, 0xc2, 0xa4, 0x45, 0xfc, 0x88, 0x56, 0x4b, 0x12,
+	0xc6, 0x7e, 0x2a, 0x79, 0x58, 0x09, 0xb3, 0x9d, 0x0b, 0xaf, 0xd1, 0xb9, 0x7b, 0x93, 0x5c, 0x3e,
+	0x40, 0x92, 0x2b, 0xfb, 0x26, 0xf9, 0x4b, 0x43, 0xd5, 0x30, 0xaa, 0xc2, 0xd4, 0x8d, 0x2d, 0xd7,
+	0x0f, 0x56, 0x97, 0x78, 0x7a, 0x4a, 0x58, 0x91, 0x5a, 0xde, 0x73, 0x2f, 0xee, 0x0a, 0x53, 0xef,
+	0x8a, 0x77, 0xc1, 0x6a, 0xfa, 0x3d, 0x22, 0x2b, 0xec, 0x8c, 0x23, 0x8e, 0x66, 0x47, 0x1d, 0xcd,
+	0x4e, 0x53, 0x1d, 0xcd, 0x8d, 0x22, 0x6b, 0xd6, 0xaf, 0x7f, 0xab, 0x1b, 0x98, 0xef, 0xb0, 0x7f,
+	0xc9, 0x41, 0xe1, 0xbf, 0x3f, 0x23, 0x2e, 0x41, 0x89, 0x57, 0x08, 0xb7, 0xce, 0xe4, 0xd6, 0x4d,
+	0x3f, 0x1f, 0xd5, 0xc7, 0x4c, 0x3c, 0x5e, 0xb2, 0xa0, 0x72, 0x62, 0x75, 0x89, 0xc7, 0xa3, 0x84,
+	0x15, 0xa9, 0x05, 0x35, 0xff, 0xe2, 0xa0, 0x16, 0xf4, 0xa0, 0x66, 0xca, 0x67, 0x6a, 0xff, 0xf2,
+	0x59, 0xb0, 0xbe, 0x7d, 0x54, 0x3f, 0x62, 0x7f, 0x93, 0x93, 0xc7, 0x34, 0x3a, 0xa7, 0x42, 0x5b,
+	0x35, 0xf4, 0x6a, 0xde, 0x33, 0x59, 0xce, 0xb3, 0x9f, 0x47, 0x7d, 0x75, 0x08, 0xc9, 0x6b, 0x08,
+	0x67, 0xc9, 0xa3, 0x9d, 0xaf, 0xd1, 0x9b, 0x50, 0xb8, 0xd3, 0xa7, 0x0c, 0x68, 0x2a, 0x5b, 0xf8,
+	0xe4, 0xeb, 0xd3, 0x14, 0x29, 0x01, 0xe8, 0x2c, 0x58, 0x37, 0xdc, 0x6e, 0x37, 0x3b, 0x70, 0x18,
+	0x47, 0xc0, 0xb8, 0x10, 0xcd, 0x80, 0xb9, 0x1e, 0x76, 0xaa, 0x79, 0x7d, 0x2c, 0xac, 0x87, 0x1d,
+	0x01, 0x61, 0x22, 0xf4, 0x3e, 0x4c, 0xaf, 0x84, 0xdb, 0x24, 0x0e, 0x16, 0x5b, 0xad, 0xb0, 0x1f,
+	0x50, 0x39, 0x12, 0xaa, 0x02, 0x9b, 0x11, 0x89, 0x5d, 0x59, 0xf8, 0x42, 0x91, 0xc5, 0x83, 0xdf,
+	0x20, 0x7e, 0x34, 0x54, 0x63, 0xb3, 0x1c, 0x60, 0x42, 0xfb, 0x71, 0xc0, 0x83, 0x52, 0xc1, 0x92,
+	0x62, 0x59, 0x5b, 0x71, 0x93, 0xfb, 0x09, 0xf1, 0x64, 0xc5, 0x2b, 0x12, 0x5d, 0x84, 0xd2, 0x6d,
+	0xb7, 0x47, 0x96, 0x03, 0x1a, 0x0f, 0xa5, 0xef, 0x15, 0x47, 0xdc, 0x26, 0x39, 0x0f, 0x8f, 0xc5,
+	0xe8, 0x0a, 0x14, 0xef, 0x92, 0xb8, 0xb7, 0x18, 0x77, 0x12, 0xe9, 0xfd, 0x49, 0x47, 0xbb, 0x60,
+	0x2a, 0x19, 0x4e, 0x51, 0xe8, 0x38, 0x98, 0x37, 0x09, 0x91, 0x05, 0xc1, 0x96, 0xf6, 0x9f, 0x06,
+	0x14, 0x55, 0x20, 0xd0, 0x6d, 0x98, 0x5a, 0xf4, 0xbc, 0x98, 0x24, 0x89, 0xb0, 0xb7, 0xf1, 0xb6,
+	0xac, 0xe4, 0xcb, 0xaf, 0xae, 0xe4, 0x56, 0x3c, 0x8c, 0x68, 0xe8, 0xc8, 0xbd, 0x58, 0x29, 0x41,
+	0xab, 0x60, 0x2d, 0xb9, 0xd4, 0x9d, 0xac, 0x2d, 0xb8, 0x0a, 0xb4, 0x0e, 0x85, 0x66, 0x18, 0xf9,
+	0x2d, 0x71, 0x18, 0xbd, 0xb6, 0x65, 0x52, 0xd9, 0xc7, 0x61, 0xec, 0xcd, 0x5f, 0x7d, 0x07, 0x4b,
+	0x1d, 0xf6, 0xf7, 0x39, 0x28, 0xa5, 0x25, 0x82, 0x2e, 0x40, 0x91, 0x11, 0xbc, 0xdf, 0xf2, 0xbc,
+	0xdf, 0x2a, 0xcf, 0x47, 0xf5, 0x94, 0x87, 0xd3, 0x15, 0xbb, 0x70, 0xb1, 0x35, 0x77, 0x2a, 0x73,
+	0xc4, 0x28, 0x2e, 0x4e, 0xe5, 0x68, 0x5d, 0x0d, 0x3e, 0xe9, 0xfe, 0xc1, 0x62, 0xa9, 0x86, 0x67,
+	0x0d, 0x60, 0x83, 0xba, 0xad, 0x87, 0x4b, 0x24, 0xa2, 0x5b, 0x72, 0x1e, 0x6a, 0x1c, 0x36, 0x83,
+	0x64, 0xa5, 0x59, 0x13, 0xcd, 0x20, 0xa1, 0xc4, 0xbe, 0x07, 0xe8, 0x9f, 0x25, 0x8f, 0xae, 0xc3,
+	0xb4, 0xa4, 0xef, 0x47, 0x9e, 0x4b, 0x89, 0x8c, 0xc1, 0x29, 0x87, 0x3f, 0x62, 0x9a, 0xa4, 0x17,
+	0x75, 0x5d, 0x4a, 0x24, 0x04, 0x67, 0xb1, 0xf6, 0x67, 0x00, 0xe3, 0x3e, 0x3f, 0xec, 0x52, 0xb3,
+	0x3f, 0x87, 0xb2, 0x36, 0x1c, 0x0e, 0x5d, 0xfd, 0x77, 0x39, 0xc8, 0x64, 0x96, 0xad, 0x49, 0x3c,
+	0x91, 0x6e, 0xa9, 0x23, 0xd5, 0x46, 0x26, 0xab, 0x13, 0xa1, 0x23, 0x6d, 0x39, 0x73, 0xf2, 0x96,
+	0x3b, 0x09, 0xf9, 0x07, 0x6e, 0xb7, 0x4f, 0xd4, 0x9d, 0x94, 0x13, 0x6c, 0x84, 0xac, 0xb8, 0x89,
+	0x1a, 0x21, 0x2b, 0x6e, 0x62, 0x2f, 0x68, 0x37, 0x02, 0x34, 0x0b, 0x45, 0x99, 0xf6, 0x3d, 0xef,
+	0x55, 0xc9, 0x65, 0x20, 0x9c, 0x42, 0xec, 0x9d, 0x1c, 0x94, 0x35, 0xc9, 0xa1, 0x4f, 0x20, 0x76,
+	0xe7, 0x88, 0x89, 0x4b, 0xe5, 0xa0, 0x2d, 0x62, 0x45, 0x32, 0x09, 0x26, 0xbd, 0x70, 0x9b, 0x78,
+	0x3c, 0x56, 0x45, 0xac, 0x48, 0x74, 0x09, 0xa6, 0x1a, 0x6e, 0xd7, 0x0d, 0x5a, 0x64, 0xfc, 0x02,
+	0xe2, 0xaf, 0x5f, 0xc1, 0xe4, 0x1e, 0x28, 0x04, 0xb2, 0xc1, 0xba, 0x11, 0x7a, 0x24, 0x7b, 0xb2,
+	0x30, 0x0e, 0x87, 0x71, 0x19, 0xba, 0x06, 0xe5, 0xbb, 0xe9, 0x58, 0x4e, 0xe4, 0xc1, 0x72, 0x4a,
+	0x40, 0x35, 0x01, 0xdf, 0xa1, 0x23, 0x99, 0x25, 0x1b, 0x34, 0x8c, 0xdd, 0x0e, 0x91, 0xf7, 0xc8,
+	0xf4, 0xed, 0xcf, 0x99, 0xc2, 0x12, 0x49, 0xd8, 0xd7, 0xa1, 0xac, 0x59, 0xc8, 0x8e, 0x9e, 0x06,
+	0x69, 0x87, 0x31, 0x51, 0x77, 0x69, 0x41, 0xb1, 0xac, 0x2e, 0xb6, 0xa9, 0x7c, 0x01, 0x58, 0x58,
+	0x10, 0xf6, 0x0f, 0x06, 0x14, 0x95, 0xd5, 0x6c, 0x96, 0x68, 0x5b, 0x0f, 0x3e, 0x4b, 0xe4, 0x1f,
+	0xd7, 0xf4, 0x3f, 0x1e, 0x58, 0x9b, 0x34, 0xf4, 0x2b, 0x03, 0x8e, 0xed, 0x89, 0x19, 0x7a, 0x2f,
+	0x63, 0x6f, 0x79, 0xbe, 0xa6, 0x9f, 0x82, 0xb2, 0xba, 0xb4, 0x3d, 0x0d, 0x8b, 0x59, 0x90, 0x9a,
+	0xb7, 0xa0, 0x9b, 0xf7, 0xba, 0x9b, 0xa5, 0x35, 0x7f, 0x18, 0x50, 0x96, 0xf1, 0xe7, 0x96, 0xdc,
+	0x04, 0x73, 0x8d, 0x0c, 0xff, 0x5d, 0xe9, 0xee, 0x39, 0xa2, 0x98, 0x02, 0x2d, 0x03, 0xb9, 0x43,
+	0xcd, 0x80, 0x39, 0x79, 0x06, 0x1a, 0x37, 0x77, 0x76, 0x6b, 0xc6, 0x93, 0xdd, 0x9a, 0xf1, 0xeb,
+	0x6e, 0xcd, 0xf8, 0x7d, 0xb7, 0x66, 0xfc, 0xfc, 0xac, 0x66, 0xec, 0x3c, 0xab, 0x19, 0x9f, 0xee,
+	0xe3, 0x28, 0x51, 0x8f, 0x08, 0xbe, 0xda, 0x2c, 0xf0, 0x0b, 0xfb, 0x5b, 0x7f, 0x0f, 0x00, 0x09,
+	0xa7, 0x2b, 0x4d, 0xd5, 0x13, 0x00, 0x00,
 }
 
 func (m *StreamEvents) Marshal() (dAtA []byte, err error) {
@@ -1622,6 +2034,18 @@ func (m *EndBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.StateDiff != nil {
+		{
+			size, err := m.StateDiff.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.Height != 0 {
 		i = encodeVarintExec(dAtA, i, uint64(m.Height))
 		i--
@@ -1654,6 +2078,18 @@ func (m *BeginTx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.StateDiff != nil {
+		{
+			size, err := m.StateDiff.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
 	if m.Exception != nil {
 		{
 			size, err := m.Exception.MarshalToSizedBuffer(dAtA[:i])
@@ -1818,6 +2254,18 @@ func (m *BlockExecution) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.StateDiff != nil {
+		{
+			size, err := m.StateDiff.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
 	if len(m.TxExecutions) > 0 {
 		for iNdEx := len(m.TxExecutions) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1913,7 +2361,19 @@ func (m *TxExecution) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.TxExecutions) > 0 {
+	if m.StateDiff != nil {
+		{
+			size, err := m.StateDiff.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	if len(m.TxExecutions) > 0 {
 		for iNdEx := len(m.TxExecutions) - 1; iNdEx >= 0; iNdEx-- {
 			{
 				size, err := m.TxExecutions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
@@ -2028,12 +2488,12 @@ func (m *Origin) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	n18, err18 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
-	if err18 != nil {
-		return 0, err18
+	n22, err22 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err22 != nil {
+		return 0, err22
 	}
-	i -= n18
-	i = encodeVarintExec(dAtA, i, uint64(n18))
+	i -= n22
+	i = encodeVarintExec(dAtA, i, uint64(n22))
 	i--
 	dAtA[i] = 0x22
 	if m.Index != 0 {
@@ -2255,6 +2715,11 @@ func (m *Result) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Fee != 0 {
+		i = encodeVarintExec(dAtA, i, uint64(m.Fee))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.PermArgs != nil {
 		{
 			size, err := m.PermArgs.MarshalToSizedBuffer(dAtA[:i])
@@ -2604,116 +3069,456 @@ func (m *CallData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintExec(dAtA []byte, offset int, v uint64) int {
-	offset -= sovExec(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *StateDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *StreamEvents) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *StateDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.StreamEvents) > 0 {
-		for _, e := range m.StreamEvents {
-			l = e.Size()
-			n += 1 + l + sovExec(uint64(l))
-		}
-	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	if len(m.Accounts) > 0 {
+		for iNdEx := len(m.Accounts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Accounts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintExec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *StreamEvent) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *AccountDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *AccountDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AccountDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.BeginBlock != nil {
-		l = m.BeginBlock.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.BeginTx != nil {
-		l = m.BeginTx.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if len(m.Storage) > 0 {
+		for iNdEx := len(m.Storage) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Storage[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintExec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
 	}
-	if m.Envelope != nil {
-		l = m.Envelope.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.Permissions != nil {
+		{
+			size, err := m.Permissions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
 	}
-	if m.Event != nil {
-		l = m.Event.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.Code != nil {
+		{
+			size, err := m.Code.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
 	}
-	if m.EndTx != nil {
-		l = m.EndTx.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.Balance != nil {
+		{
+			size, err := m.Balance.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintExec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	if m.EndBlock != nil {
-		l = m.EndBlock.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.Removed {
+		i--
+		if m.Removed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if m.Created {
+		i--
+		if m.Created {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	{
+		size := m.Address.Size()
+		i -= size
+		if _, err := m.Address.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *BeginBlock) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *BalanceDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *BalanceDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BalanceDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Height != 0 {
-		n += 1 + sovExec(uint64(m.Height))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Header != nil {
-		l = m.Header.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.After != 0 {
+		i = encodeVarintExec(dAtA, i, uint64(m.After))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if m.Before != 0 {
+		i = encodeVarintExec(dAtA, i, uint64(m.Before))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *EndBlock) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *CodeDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *CodeDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CodeDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Height != 0 {
-		n += 1 + sovExec(uint64(m.Height))
-	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	{
+		size := m.After.Size()
+		i -= size
+		if _, err := m.After.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size := m.Before.Size()
+		i -= size
+		if _, err := m.Before.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *BeginTx) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PermissionsDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *PermissionsDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PermissionsDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.TxHeader != nil {
-		l = m.TxHeader.Size()
-		n += 1 + l + sovExec(uint64(l))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Result != nil {
+	{
+		size, err := m.After.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.Before.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *StorageDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StorageDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StorageDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	{
+		size := m.After.Size()
+		i -= size
+		if _, err := m.After.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	{
+		size := m.Before.Size()
+		i -= size
+		if _, err := m.Before.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size := m.Key.Size()
+		i -= size
+		if _, err := m.Key.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintExec(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintExec(dAtA []byte, offset int, v uint64) int {
+	offset -= sovExec(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *StreamEvents) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.StreamEvents) > 0 {
+		for _, e := range m.StreamEvents {
+			l = e.Size()
+			n += 1 + l + sovExec(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StreamEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BeginBlock != nil {
+		l = m.BeginBlock.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.BeginTx != nil {
+		l = m.BeginTx.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.Envelope != nil {
+		l = m.Envelope.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.Event != nil {
+		l = m.Event.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.EndTx != nil {
+		l = m.EndTx.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.EndBlock != nil {
+		l = m.EndBlock.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *BeginBlock) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovExec(uint64(m.Height))
+	}
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *EndBlock) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovExec(uint64(m.Height))
+	}
+	if m.StateDiff != nil {
+		l = m.StateDiff.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *BeginTx) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TxHeader != nil {
+		l = m.TxHeader.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.Result != nil {
 		l = m.Result.Size()
 		n += 1 + l + sovExec(uint64(l))
 	}
@@ -2721,6 +3526,10 @@ func (m *BeginTx) Size() (n int) {
 		l = m.Exception.Size()
 		n += 1 + l + sovExec(uint64(l))
 	}
+	if m.StateDiff != nil {
+		l = m.StateDiff.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2787,6 +3596,10 @@ func (m *BlockExecution) Size() (n int) {
 			n += 1 + l + sovExec(uint64(l))
 		}
 	}
+	if m.StateDiff != nil {
+		l = m.StateDiff.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2849,6 +3662,10 @@ func (m *TxExecution) Size() (n int) {
 			n += 1 + l + sovExec(uint64(l))
 		}
 	}
+	if m.StateDiff != nil {
+		l = m.StateDiff.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2970,6 +3787,9 @@ func (m *Result) Size() (n int) {
 		l = m.PermArgs.Size()
 		n += 1 + l + sovExec(uint64(l))
 	}
+	if m.Fee != 0 {
+		n += 1 + sovExec(uint64(m.Fee))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3092,67 +3912,191 @@ func (m *CallData) Size() (n int) {
 	return n
 }
 
-func sovExec(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozExec(x uint64) (n int) {
-	return sovExec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *StreamEvent) GetValue() interface{} {
-	if this.BeginBlock != nil {
-		return this.BeginBlock
-	}
-	if this.BeginTx != nil {
-		return this.BeginTx
-	}
-	if this.Envelope != nil {
-		return this.Envelope
-	}
-	if this.Event != nil {
-		return this.Event
+func (m *StateDiff) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	if this.EndTx != nil {
-		return this.EndTx
+	var l int
+	_ = l
+	if len(m.Accounts) > 0 {
+		for _, e := range m.Accounts {
+			l = e.Size()
+			n += 1 + l + sovExec(uint64(l))
+		}
 	}
-	if this.EndBlock != nil {
-		return this.EndBlock
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
-	return nil
+	return n
 }
 
-func (this *StreamEvent) SetValue(value interface{}) bool {
-	switch vt := value.(type) {
-	case *BeginBlock:
-		this.BeginBlock = vt
-	case *BeginTx:
-		this.BeginTx = vt
-	case *github_com_hyperledger_burrow_txs.Envelope:
-		this.Envelope = vt
-	case *Event:
-		this.Event = vt
-	case *EndTx:
-		this.EndTx = vt
-	case *EndBlock:
-		this.EndBlock = vt
-	default:
-		this.Event = new(Event)
-		if set := this.Event.SetValue(value); set {
-			return true
-		}
-		this.Event = nil
-		return false
+func (m *AccountDiff) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	return true
-}
-func (this *Event) GetValue() interface{} {
-	if this.Header != nil {
-		return this.Header
+	var l int
+	_ = l
+	l = m.Address.Size()
+	n += 1 + l + sovExec(uint64(l))
+	if m.Created {
+		n += 2
 	}
-	if this.Input != nil {
-		return this.Input
+	if m.Removed {
+		n += 2
 	}
-	if this.Output != nil {
-		return this.Output
+	if m.Balance != nil {
+		l = m.Balance.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.Code != nil {
+		l = m.Code.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if m.Permissions != nil {
+		l = m.Permissions.Size()
+		n += 1 + l + sovExec(uint64(l))
+	}
+	if len(m.Storage) > 0 {
+		for _, e := range m.Storage {
+			l = e.Size()
+			n += 1 + l + sovExec(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *BalanceDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Before != 0 {
+		n += 1 + sovExec(uint64(m.Before))
+	}
+	if m.After != 0 {
+		n += 1 + sovExec(uint64(m.After))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CodeDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Before.Size()
+	n += 1 + l + sovExec(uint64(l))
+	l = m.After.Size()
+	n += 1 + l + sovExec(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PermissionsDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Before.Size()
+	n += 1 + l + sovExec(uint64(l))
+	l = m.After.Size()
+	n += 1 + l + sovExec(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StorageDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Key.Size()
+	n += 1 + l + sovExec(uint64(l))
+	l = m.Before.Size()
+	n += 1 + l + sovExec(uint64(l))
+	l = m.After.Size()
+	n += 1 + l + sovExec(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovExec(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozExec(x uint64) (n int) {
+	return sovExec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *StreamEvent) GetValue() interface{} {
+	if this.BeginBlock != nil {
+		return this.BeginBlock
+	}
+	if this.BeginTx != nil {
+		return this.BeginTx
+	}
+	if this.Envelope != nil {
+		return this.Envelope
+	}
+	if this.Event != nil {
+		return this.Event
+	}
+	if this.EndTx != nil {
+		return this.EndTx
+	}
+	if this.EndBlock != nil {
+		return this.EndBlock
+	}
+	return nil
+}
+
+func (this *StreamEvent) SetValue(value interface{}) bool {
+	switch vt := value.(type) {
+	case *BeginBlock:
+		this.BeginBlock = vt
+	case *BeginTx:
+		this.BeginTx = vt
+	case *github_com_hyperledger_burrow_txs.Envelope:
+		this.Envelope = vt
+	case *Event:
+		this.Event = vt
+	case *EndTx:
+		this.EndTx = vt
+	case *EndBlock:
+		this.EndBlock = vt
+	default:
+		this.Event = new(Event)
+		if set := this.Event.SetValue(value); set {
+			return true
+		}
+		this.Event = nil
+		return false
+	}
+	return true
+}
+func (this *Event) GetValue() interface{} {
+	if this.Header != nil {
+		return this.Header
+	}
+	if this.Input != nil {
+		return this.Input
+	}
+	if this.Output != nil {
+		return this.Output
 	}
 	if this.Call != nil {
 		return this.Call
@@ -3700,6 +4644,42 @@ func (m *EndBlock) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateDiff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StateDiff == nil {
+				m.StateDiff = &StateDiff{}
+			}
+			if err := m.StateDiff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -3862,6 +4842,42 @@ func (m *BeginTx) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateDiff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StateDiff == nil {
+				m.StateDiff = &StateDiff{}
+			}
+			if err := m.StateDiff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -4272,6 +5288,42 @@ func (m *BlockExecution) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateDiff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StateDiff == nil {
+				m.StateDiff = &StateDiff{}
+			}
+			if err := m.StateDiff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -4666,6 +5718,42 @@ func (m *TxExecution) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateDiff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StateDiff == nil {
+				m.StateDiff = &StateDiff{}
+			}
+			if err := m.StateDiff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -5503,6 +6591,25 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fee", wireType)
+			}
+			m.Fee = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Fee |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -5702,19 +6809,829 @@ func (m *CallEvent) Unmarshal(dAtA []byte) error {
 			if b < 0x80 {
 				break
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: CallEvent: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CallEvent: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CallEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CallEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CallData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CallData == nil {
+				m.CallData = &CallData{}
+			}
+			if err := m.CallData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Origin.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StackDepth", wireType)
+			}
+			m.StackDepth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StackDepth |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Return", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Return.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CallType", wireType)
+			}
+			m.CallType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CallType |= CallType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GovernAccountEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GovernAccountEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GovernAccountEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AccountUpdate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AccountUpdate == nil {
+				m.AccountUpdate = &spec.TemplateAccount{}
+			}
+			if err := m.AccountUpdate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InputEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InputEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InputEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Address.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OutputEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OutputEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OutputEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Address.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CallData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CallData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CallData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Caller", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Caller.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Callee", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Callee.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			m.Value = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Value |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gas", wireType)
+			}
+			m.Gas = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Gas |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StateDiff) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StateDiff: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StateDiff: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Accounts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Accounts = append(m.Accounts, &AccountDiff{})
+			if err := m.Accounts[len(m.Accounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthExec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AccountDiff) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AccountDiff: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AccountDiff: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Address.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Created = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Removed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Removed = bool(v != 0)
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CallData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Balance", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5741,18 +7658,18 @@ func (m *CallEvent) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.CallData == nil {
-				m.CallData = &CallData{}
+			if m.Balance == nil {
+				m.Balance = &BalanceDiff{}
 			}
-			if err := m.CallData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Balance.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowExec
@@ -5762,49 +7679,33 @@ func (m *CallEvent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthExec
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthExec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Origin.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Code == nil {
+				m.Code = &CodeDiff{}
+			}
+			if err := m.Code.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StackDepth", wireType)
-			}
-			m.StackDepth = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowExec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.StackDepth |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Return", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Permissions", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowExec
@@ -5814,30 +7715,33 @@ func (m *CallEvent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthExec
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthExec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Return.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Permissions == nil {
+				m.Permissions = &PermissionsDiff{}
+			}
+			if err := m.Permissions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CallType", wireType)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Storage", wireType)
 			}
-			m.CallType = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowExec
@@ -5847,11 +7751,26 @@ func (m *CallEvent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CallType |= CallType(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Storage = append(m.Storage, &StorageDiff{})
+			if err := m.Storage[len(m.Storage)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -5877,7 +7796,7 @@ func (m *CallEvent) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GovernAccountEvent) Unmarshal(dAtA []byte) error {
+func (m *BalanceDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5900,17 +7819,17 @@ func (m *GovernAccountEvent) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GovernAccountEvent: wiretype end group for non-group")
+			return fmt.Errorf("proto: BalanceDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GovernAccountEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BalanceDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AccountUpdate", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Before", wireType)
 			}
-			var msglen int
+			m.Before = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowExec
@@ -5920,28 +7839,30 @@ func (m *GovernAccountEvent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Before |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthExec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthExec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.AccountUpdate == nil {
-				m.AccountUpdate = &spec.TemplateAccount{}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
 			}
-			if err := m.AccountUpdate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.After = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.After |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -5967,7 +7888,7 @@ func (m *GovernAccountEvent) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *InputEvent) Unmarshal(dAtA []byte) error {
+func (m *CodeDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5990,15 +7911,15 @@ func (m *InputEvent) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: InputEvent: wiretype end group for non-group")
+			return fmt.Errorf("proto: CodeDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: InputEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CodeDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Before", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -6025,7 +7946,40 @@ func (m *InputEvent) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Address.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Before.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.After.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6054,7 +8008,7 @@ func (m *InputEvent) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *OutputEvent) Unmarshal(dAtA []byte) error {
+func (m *PermissionsDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6077,17 +8031,17 @@ func (m *OutputEvent) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: OutputEvent: wiretype end group for non-group")
+			return fmt.Errorf("proto: PermissionsDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: OutputEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PermissionsDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Before", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowExec
@@ -6097,22 +8051,55 @@ func (m *OutputEvent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthExec
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthExec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Address.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Before.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.After.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6141,7 +8128,7 @@ func (m *OutputEvent) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CallData) Unmarshal(dAtA []byte) error {
+func (m *StorageDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6164,15 +8151,15 @@ func (m *CallData) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CallData: wiretype end group for non-group")
+			return fmt.Errorf("proto: StorageDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CallData: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StorageDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Caller", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -6199,13 +8186,13 @@ func (m *CallData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Caller.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Key.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Callee", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Before", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -6232,13 +8219,13 @@ func (m *CallData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Callee.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Before.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -6265,48 +8252,10 @@ func (m *CallData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.After.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
-			}
-			m.Value = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowExec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Value |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Gas", wireType)
-			}
-			m.Gas = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowExec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Gas |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExec(dAtA[iNdEx:])
@@ -0,0 +1,66 @@
+package exec
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/permission"
+)
+
+// NewStateDiff describes the balance, code, permission, and storage changes among changes. Accounts whose only change
+// was to some other field (such as the sequence number of a signer) are omitted.
+func NewStateDiff(changes []*acmstate.AccountChange) *StateDiff {
+	diff := new(StateDiff)
+	for _, change := range changes {
+		accountDiff := newAccountDiff(change)
+		if accountDiff != nil {
+			diff.Accounts = append(diff.Accounts, accountDiff)
+		}
+	}
+	return diff
+}
+
+func newAccountDiff(change *acmstate.AccountChange) *AccountDiff {
+	before, after := change.Before, change.After
+	diff := &AccountDiff{
+		Address: change.Address,
+		Created: before == nil && after != nil,
+		Removed: before != nil && after == nil,
+	}
+	if before == nil {
+		before = new(acm.Account)
+	}
+	if after == nil {
+		after = new(acm.Account)
+	}
+	if before.Balance != after.Balance {
+		diff.Balance = &BalanceDiff{Before: before.Balance, After: after.Balance}
+	}
+	if !bytes.Equal(before.Code(), after.Code()) {
+		diff.Code = &CodeDiff{Before: before.Code(), After: after.Code()}
+	}
+	if !equalPermissions(before.Permissions, after.Permissions) {
+		diff.Permissions = &PermissionsDiff{Before: before.Permissions, After: after.Permissions}
+	}
+	for _, sc := range change.Storage {
+		diff.Storage = append(diff.Storage, &StorageDiff{Key: sc.Key, Before: sc.Before, After: sc.After})
+	}
+	if !diff.Created && !diff.Removed && diff.Balance == nil && diff.Code == nil && diff.Permissions == nil &&
+		len(diff.Storage) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func equalPermissions(a, b permission.AccountPermissions) bool {
+	if a.Base.Perms != b.Base.Perms || a.Base.SetBit != b.Base.SetBit || len(a.Roles) != len(b.Roles) {
+		return false
+	}
+	for i, role := range a.Roles {
+		if b.Roles[i] != role {
+			return false
+		}
+	}
+	return true
+}
@@ -95,6 +95,7 @@ func (ba *BlockAccumulator) Consume(ev *StreamEvent) (*BlockExecution, error) {
 			ba.block.TxExecutions = append(ba.block.TxExecutions, txe)
 		}
 	case ev.EndBlock != nil:
+		ba.block.StateDiff = ev.EndBlock.StateDiff
 		return ba.block, nil
 	}
 	return nil, nil
@@ -168,5 +169,6 @@ func initTx(beginTx *BeginTx) *TxExecution {
 		TxHeader:  beginTx.TxHeader,
 		Result:    beginTx.Result,
 		Exception: beginTx.Exception,
+		StateDiff: beginTx.StateDiff,
 	}
 }
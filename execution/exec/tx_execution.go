@@ -42,6 +42,7 @@ func (txe *TxExecution) StreamEvents() []*StreamEvent {
 				TxHeader:  txe.TxHeader,
 				Exception: txe.Exception,
 				Result:    txe.Result,
+				StateDiff: txe.StateDiff,
 			},
 		},
 		&StreamEvent{
@@ -175,6 +176,22 @@ func (txe *TxExecution) Return(returnValue []byte, gasUsed uint64) {
 	txe.Result.GasUsed = gasUsed
 }
 
+func (txe *TxExecution) Charge(fee uint64) {
+	if txe.Result == nil {
+		txe.Result = &Result{}
+	}
+	txe.Result.Fee = fee
+}
+
+// Fees sums the fees charged to this execution and to any it contains
+func (txe *TxExecution) Fees() uint64 {
+	fees := txe.Result.GetFee()
+	for _, child := range txe.TxExecutions {
+		fees += child.Fees()
+	}
+	return fees
+}
+
 func (txe *TxExecution) Name(entry *names.Entry) {
 	if txe.Result == nil {
 		txe.Result = &Result{}
@@ -21,9 +21,11 @@ import (
 	"github.com/hyperledger/burrow/execution/evm"
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/execution/names"
+	"github.com/hyperledger/burrow/execution/native"
 	"github.com/hyperledger/burrow/execution/proposal"
 	"github.com/hyperledger/burrow/execution/registry"
 	"github.com/hyperledger/burrow/execution/state"
+	"github.com/hyperledger/burrow/execution/wasm"
 	"github.com/hyperledger/burrow/genesis"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/logging/structure"
@@ -83,21 +85,40 @@ type executor struct {
 	proposalRegCache *proposal.Cache
 	validatorCache   *validator.Cache
 	emitter          *event.Emitter
+	blockchain       engine.Blockchain
 	block            *exec.BlockExecution
 	logger           *logging.Logger
 	vmOptions        evm.Options
+	wasmOptions      wasm.Options
 	contexts         map[payload.Type]contexts.Context
+	// When recording state diffs or executing in parallel stateCache holds the current transaction's writes and is
+	// flushed into blockCache
+	blockCache       *acmstate.Cache
+	recordStateDiffs bool
+	// The number of workers executing transactions speculatively in parallel, each with its own engines
+	workers       int
+	workerEngines []*engines
+	// Speculative executions of the current block's transactions yet to be delivered
+	speculations map[string]*speculation
+	// What the block's transactions have changed so far, against which speculations are validated
+	blockWrites *accessSet
 }
 
 type Params struct {
 	ChainID           string
 	ProposalThreshold uint64
+	Fees              *genesis.FeeSchedule
+	StateDiffs        bool
+	WASMCodeSize      bool
 }
 
 func ParamsFromGenesis(genesisDoc *genesis.GenesisDoc) Params {
 	return Params{
 		ChainID:           genesisDoc.ChainID(),
 		ProposalThreshold: genesisDoc.Params.ProposalThreshold,
+		Fees:              genesisDoc.Params.Fees,
+		StateDiffs:        genesisDoc.Params.StateDiffs,
+		WASMCodeSize:      genesisDoc.Params.WASMCodeSize,
 	}
 }
 
@@ -114,9 +135,12 @@ func NewBatchChecker(backend ExecutorState, params Params, blockchain engine.Blo
 func NewBatchCommitter(backend ExecutorState, params Params, blockchain engine.Blockchain, emitter *event.Emitter,
 	logger *logging.Logger, options ...Option) BatchCommitter {
 
-	return newExecutor("CommitCache", true, params, backend, blockchain, emitter,
+	exe := newExecutor("CommitCache", true, params, backend, blockchain, emitter,
 		logger.WithScope("NewBatchCommitter"), options...)
-
+	if len(exe.workerEngines) > 0 {
+		return parallelCommitter{exe}
+	}
+	return exe
 }
 
 func newExecutor(name string, runCall bool, params Params, backend ExecutorState, blockchain engine.Blockchain,
@@ -132,6 +156,7 @@ func newExecutor(name string, runCall bool, params Params, backend ExecutorState
 		proposalRegCache: proposal.NewCache(backend),
 		validatorCache:   validator.NewCache(backend),
 		emitter:          emitter,
+		blockchain:       blockchain,
 		block: &exec.BlockExecution{
 			Height: blockchain.LastBlockHeight() + 1,
 		},
@@ -140,14 +165,29 @@ func newExecutor(name string, runCall bool, params Params, backend ExecutorState
 	for _, option := range options {
 		option(exe)
 	}
+	if params.StateDiffs {
+		exe.recordStateDiffs = true
+		exe.useTxCache()
+	}
+	exe.vmOptions.WASMCodeSize = params.WASMCodeSize
+	vm, wvm := newEngines(exe.vmOptions, exe.wasmOptions)
+	for i := 0; i < exe.workers; i++ {
+		workerVM, workerWVM := newEngines(exe.vmOptions, exe.wasmOptions)
+		exe.workerEngines = append(exe.workerEngines, &engines{vm: workerVM, wvm: workerWVM})
+	}
+	if exe.workers > 0 {
+		exe.blockWrites = newAccessSet()
+	}
 
 	baseContexts := map[payload.Type]contexts.Context{
 		payload.TypeCall: &contexts.CallContext{
-			EVM:           evm.New(exe.vmOptions),
+			EVM:           vm,
+			WVM:           wvm,
 			Blockchain:    blockchain,
 			State:         exe.stateCache,
 			MetadataState: exe.metadataCache,
 			RunCall:       runCall,
+			Fees:          params.Fees,
 			Logger:        exe.logger,
 		},
 		payload.TypeSend: &contexts.SendContext{
@@ -205,6 +245,28 @@ func newExecutor(name string, runCall bool, params Params, backend ExecutorState
 	return exe
 }
 
+// The engine options of an executor with params and options, so that simulations run as the executor would
+func engineOptions(params Params, options []Option) (evm.Options, wasm.Options) {
+	exe := new(executor)
+	for _, option := range options {
+		option(exe)
+	}
+	exe.vmOptions.WASMCodeSize = params.WASMCodeSize
+	return exe.vmOptions, exe.wasmOptions
+}
+
+// Make an EVM and a WVM sharing natives and connected so that each can call contracts hosted by the other
+func newEngines(vmOptions evm.Options, wasmOptions wasm.Options) (*evm.EVM, *wasm.WVM) {
+	if vmOptions.Natives == nil {
+		vmOptions.Natives = native.MustDefaultNatives()
+	}
+	wasmOptions.Natives = vmOptions.Natives
+	vm := evm.New(vmOptions)
+	wvm := wasm.New(wasmOptions)
+	engine.Connect(vm, wvm, vmOptions.Natives)
+	return vm, wvm
+}
+
 func (exe *executor) AddContext(ty payload.Type, ctx contexts.Context) *executor {
 	exe.contexts[ty] = ctx
 	return exe
@@ -227,6 +289,11 @@ func (exe *executor) Execute(txEnv *txs.Envelope) (txe *exec.TxExecution, err er
 
 	logger.InfoMsg("Executing transaction", "tx", txEnv.String())
 
+	if spec := exe.takeSpeculation(txEnv); spec != nil {
+		logger.TraceMsg("Using speculative execution")
+		return exe.applySpeculation(spec)
+	}
+
 	// Verify transaction signature against inputs
 	err = txEnv.Verify(exe.params.ChainID)
 	if err != nil {
@@ -243,26 +310,17 @@ func (exe *executor) Execute(txEnv *txs.Envelope) (txe *exec.TxExecution, err er
 					debug.Stack())
 			}
 		}()
-
-		err = exe.validateInputsAndStorePublicKeys(txEnv)
-		if err != nil {
-			logger.InfoMsg("Transaction validate failed", structure.ErrorKey, err)
-			txe.PushError(err)
-			return nil, err
+		if exe.blockCache != nil {
+			defer func() {
+				diffErr := exe.flushTxCache(txe)
+				if err == nil {
+					err = diffErr
+				}
+			}()
 		}
 
-		err = txExecutor.Execute(txe, txe.Envelope.Tx.Payload)
+		err = exe.execute(exe.stateCache, txExecutor, txe, logger)
 		if err != nil {
-			logger.InfoMsg("Transaction execution failed", structure.ErrorKey, err)
-			txe.PushError(err)
-			return nil, err
-		}
-
-		// Increment sequence numbers for Tx inputs
-		err = exe.updateSequenceNumbers(txEnv)
-		if err != nil {
-			logger.InfoMsg("Updating sequences failed", structure.ErrorKey, err)
-			txe.PushError(err)
 			return nil, err
 		}
 		// Return execution for this tx
@@ -271,14 +329,65 @@ func (exe *executor) Execute(txEnv *txs.Envelope) (txe *exec.TxExecution, err er
 	return nil, fmt.Errorf("unknown transaction type: %v", txEnv.Tx.Type())
 }
 
+// Validate and run the transaction of txe against st using txExecutor
+func (exe *executor) execute(st acmstate.ReaderWriter, txExecutor contexts.Context, txe *exec.TxExecution,
+	logger *logging.Logger) error {
+
+	err := exe.validateInputsAndStorePublicKeys(st, txe.Envelope)
+	if err != nil {
+		logger.InfoMsg("Transaction validate failed", structure.ErrorKey, err)
+		txe.PushError(err)
+		return err
+	}
+
+	err = txExecutor.Execute(txe, txe.Envelope.Tx.Payload)
+	if err != nil {
+		logger.InfoMsg("Transaction execution failed", structure.ErrorKey, err)
+		txe.PushError(err)
+		return err
+	}
+
+	// Increment sequence numbers for Tx inputs
+	err = exe.updateSequenceNumbers(st, txe.Envelope)
+	if err != nil {
+		logger.InfoMsg("Updating sequences failed", structure.ErrorKey, err)
+		txe.PushError(err)
+		return err
+	}
+	return nil
+}
+
+// Record the changes made by the transaction and merge them into the block's cache
+func (exe *executor) flushTxCache(txe *exec.TxExecution) error {
+	changes, err := exe.stateCache.Changes()
+	if err != nil {
+		return err
+	}
+	if exe.recordStateDiffs {
+		txe.StateDiff = exec.NewStateDiff(changes)
+	}
+	if exe.blockWrites != nil {
+		exe.blockWrites.addChanges(changes)
+	}
+	return exe.stateCache.Flush(exe.blockCache, exe.blockCache)
+}
+
+// The cache holding the writes of the whole block, including any made outside of a transaction
+func (exe *executor) blockStateCache() (*acmstate.Cache, error) {
+	if exe.blockCache == nil {
+		return exe.stateCache, nil
+	}
+	return exe.blockCache, exe.stateCache.Flush(exe.blockCache, exe.blockCache)
+}
+
 // Validate inputs, check sequence numbers and capture public keys
-func (exe *executor) validateInputsAndStorePublicKeys(txEnv *txs.Envelope) error {
+func (exe *executor) validateInputsAndStorePublicKeys(st acmstate.ReaderWriter, txEnv *txs.Envelope) error {
 	for s, in := range txEnv.Tx.GetInputs() {
-		err := exe.updateSignatory(txEnv.Signatories[s])
+		err := exe.updateSignatory(st, txEnv.Signatories[s])
 		if err != nil {
 			return fmt.Errorf("failed to update public key for input %X: %v", in.Address, err)
 		}
-		acc, err := exe.stateCache.GetAccount(in.Address)
+		acc, err := st.GetAccount(in.Address)
 		if err != nil {
 			return err
 		}
@@ -300,7 +409,7 @@ func (exe *executor) validateInputsAndStorePublicKeys(txEnv *txs.Envelope) error
 			return errors.Codes.InsufficientFunds
 		}
 		// Check for Input permission
-		globalPerms, err := acmstate.GlobalAccountPermissions(exe.stateCache)
+		globalPerms, err := acmstate.GlobalAccountPermissions(st)
 		if err != nil {
 			return err
 		}
@@ -315,10 +424,10 @@ func (exe *executor) validateInputsAndStorePublicKeys(txEnv *txs.Envelope) error
 	return nil
 }
 
-func (exe *executor) updateSignatory(sig txs.Signatory) error {
+func (exe *executor) updateSignatory(st acmstate.ReaderWriter, sig txs.Signatory) error {
 	// pointer dereferences are safe since txEnv.Validate() is run by
 	// txEnv.Verify() above which checks they are non-nil
-	acc, err := exe.stateCache.GetAccount(*sig.Address)
+	acc, err := st.GetAccount(*sig.Address)
 	if err != nil {
 		return fmt.Errorf("error getting account on which to set public key: %v", *sig.Address)
 	} else if acc == nil {
@@ -330,7 +439,7 @@ func (exe *executor) updateSignatory(sig txs.Signatory) error {
 			acc.Address, sig.PublicKey)
 	}
 	acc.PublicKey = *sig.PublicKey
-	return exe.stateCache.UpdateAccount(acc)
+	return st.UpdateAccount(acc)
 }
 
 // Commit the current state - optionally pass in the tendermint ABCI header for that to be included with the BeginBlock
@@ -355,7 +464,15 @@ func (exe *executor) Commit(header *abciTypes.Header) (stateHash []byte, err err
 	// that nothing in the downstream commit process could have failed. At worst we go back one block.
 	hash, version, err := exe.state.Update(func(ws state.Updatable) error {
 		// flush the caches
-		err := exe.stateCache.Flush(ws, exe.state)
+		stateCache, err := exe.blockStateCache()
+		if err != nil {
+			return err
+		}
+		err = exe.payFees(stateCache, blockExecution)
+		if err != nil {
+			return err
+		}
+		err = stateCache.Flush(ws, exe.state)
 		if err != nil {
 			return err
 		}
@@ -393,17 +510,69 @@ func (exe *executor) Commit(header *abciTypes.Header) (stateHash []byte, err err
 		return nil, fmt.Errorf("expected height at state tree version %d is %d but actual height is %d",
 			version, expectedHeight, height)
 	}
+	exe.resetSpeculation()
 	// Now state is fully committed publish events (this should be the last thing we do)
 	exe.publishBlock(blockExecution)
 	return hash, nil
 }
 
+// Credit the fees charged to the block's transactions to the treasury or, failing that, the proposer of the block.
+// Without either, as when running without consensus, the fees are burnt.
+func (exe *executor) payFees(st acmstate.ReaderWriter, blockExecution *exec.BlockExecution) error {
+	if exe.params.Fees == nil {
+		return nil
+	}
+	var fees uint64
+	for _, txe := range blockExecution.TxExecutions {
+		fees += txe.Fees()
+	}
+	recipient := exe.params.Fees.Treasury
+	if recipient == nil && blockExecution.Header != nil && len(blockExecution.Header.ProposerAddress) > 0 {
+		proposer, err := crypto.AddressFromBytes(blockExecution.Header.ProposerAddress)
+		if err != nil {
+			return fmt.Errorf("could not get address of block proposer to pay fees: %v", err)
+		}
+		recipient = &proposer
+	}
+	if fees == 0 || recipient == nil {
+		return nil
+	}
+	before, err := st.GetAccount(*recipient)
+	if err != nil {
+		return err
+	}
+	acc := &acm.Account{Address: *recipient}
+	if before != nil {
+		acc = before.Copy()
+	}
+	err = acc.AddToBalance(fees)
+	if err != nil {
+		return err
+	}
+	exe.logger.TraceMsg("Paying fees",
+		"height", blockExecution.Height,
+		"recipient", *recipient,
+		"fees", fees)
+	// The payment happens outside of any transaction so we record it against the block
+	blockExecution.StateDiff = exec.NewStateDiff([]*acmstate.AccountChange{{
+		Address: *recipient,
+		Before:  before,
+		After:   acc,
+	}})
+	return st.UpdateAccount(acc)
+}
+
 func (exe *executor) Reset() error {
 	// As with Commit() we do not take the write lock here
 	exe.stateCache.Reset(exe.state)
+	if exe.blockCache != nil {
+		exe.blockCache.Reset(exe.state)
+		exe.stateCache.Reset(exe.blockCache)
+	}
 	exe.nameRegCache.Reset(exe.state)
 	exe.proposalRegCache.Reset(exe.state)
 	exe.validatorCache.Reset(exe.state)
+	exe.resetSpeculation()
 	return nil
 }
 
@@ -447,9 +616,9 @@ func (exe *executor) finaliseBlockExecution(header *abciTypes.Header) (*exec.Blo
 }
 
 // update sequence numbers
-func (exe *executor) updateSequenceNumbers(txEnv *txs.Envelope) error {
+func (exe *executor) updateSequenceNumbers(st acmstate.ReaderWriter, txEnv *txs.Envelope) error {
 	for _, sig := range txEnv.Signatories {
-		acc, err := exe.stateCache.GetAccount(*sig.Address)
+		acc, err := st.GetAccount(*sig.Address)
 		if err != nil {
 			return fmt.Errorf("error getting account on which to set public key: %v", *sig.Address)
 		}
@@ -462,7 +631,7 @@ func (exe *executor) updateSequenceNumbers(txEnv *txs.Envelope) error {
 			"new_sequence", acc.Sequence+1)
 
 		acc.Sequence++
-		err = exe.stateCache.UpdateAccount(acc)
+		err = st.UpdateAccount(acc)
 		if err != nil {
 			return fmt.Errorf("error updating account after incrementing sequence: %v", err)
 		}
@@ -1187,6 +1187,224 @@ func TestContractSend(t *testing.T) {
 	assert.Equal(t, sendAmt, accNonExistent.Balance, "value should have been transferred")
 }
 
+func TestStateDiffs(t *testing.T) {
+	st, privAccounts := makeGenesisState(3, 1)
+	acc0 := getAccount(t, st, privAccounts[0].GetAddress())
+	acc1 := getAccount(t, st, privAccounts[1].GetAddress())
+	addressNonExistent := newAddress("nobody")
+
+	tx := payload.NewSendTx()
+	tx.AddInputWithSequence(privAccounts[0].GetPublicKey(), 3, acc0.Sequence+1)
+	tx.AddOutput(acc1.Address, 1)
+	tx.AddOutput(addressNonExistent, 2)
+	txEnv := txs.Enclose(testChainID, tx)
+	require.NoError(t, txEnv.Sign(privAccounts[0]))
+
+	params := ParamsFromGenesis(testGenesisDoc)
+	params.StateDiffs = true
+	exe := makeExecutorWithParams(st, params)
+	txe, err := exe.Execute(txEnv)
+	require.NoError(t, err)
+	_, err = exe.Commit(nil)
+	require.NoError(t, err)
+
+	diffs := make(map[crypto.Address]*exec.AccountDiff)
+	for _, diff := range txe.StateDiff.Accounts {
+		diffs[diff.Address] = diff
+	}
+	require.Len(t, diffs, 3)
+	assert.Equal(t, &exec.BalanceDiff{Before: acc0.Balance, After: acc0.Balance - 3}, diffs[acc0.Address].Balance)
+	assert.Equal(t, &exec.BalanceDiff{Before: acc1.Balance, After: acc1.Balance + 1}, diffs[acc1.Address].Balance)
+	assert.False(t, diffs[acc1.Address].Created)
+	assert.True(t, diffs[addressNonExistent].Created)
+	assert.Equal(t, uint64(2), diffs[addressNonExistent].Balance.After)
+
+	// Each transaction's writes still reach the state
+	assert.Equal(t, acc1.Balance+1, getAccount(t, st, acc1.Address).Balance)
+	assert.Equal(t, uint64(2), getAccount(t, st, addressNonExistent).Balance)
+}
+
+func TestCallFees(t *testing.T) {
+	st, privAccounts := makeGenesisState(2, 1)
+	counter := newAddress("counter")
+	code := bc.MustSplice(PUSH1, 0, SLOAD, PUSH1, 1, ADD, PUSH1, 0, SSTORE, STOP)
+	makeExecutor(st).updateAccounts(t, &acm.Account{Address: counter, EVMCode: code})
+	acc0 := getAccount(t, st, privAccounts[0].GetAddress())
+	treasury := newAddress("treasury")
+
+	call := func(gasPrice uint64) *txs.Envelope {
+		txEnv := txs.Enclose(testChainID, &payload.CallTx{
+			Input:    &payload.TxInput{Address: acc0.Address, Amount: 5, Sequence: acc0.Sequence + 1},
+			Address:  &counter,
+			GasLimit: 100,
+			GasPrice: gasPrice,
+			Fee:      5,
+		})
+		require.NoError(t, txEnv.Sign(privAccounts[0]))
+		return txEnv
+	}
+
+	params := ParamsFromGenesis(testGenesisDoc)
+	params.Fees = &genesis.FeeSchedule{MinimumGasPrice: 2, Treasury: &treasury}
+	exeState := copyState(t, st)
+	exe := makeExecutorWithParams(exeState, params)
+
+	_, err := exe.Execute(call(1))
+	assertErrorCode(t, errors.Codes.GasPriceTooLow, err)
+
+	txe, err := exe.Execute(call(3))
+	require.NoError(t, err)
+	require.Nil(t, txe.Exception)
+	gasUsed := txe.Result.GasUsed
+	require.NotZero(t, gasUsed)
+	fee := 5 + gasUsed*3
+	assert.Equal(t, fee, txe.Result.Fee)
+	// Only the gas used is paid for
+	assert.Equal(t, acc0.Balance-fee, exe.getAccount(t, acc0.Address).Balance)
+	_, err = exe.Commit(nil)
+	require.NoError(t, err)
+	assert.Equal(t, fee, exe.getAccount(t, treasury).Balance)
+	// The payment is recorded against the block
+	diff := lastBlockStateDiff(t, exeState)
+	require.Len(t, diff.Accounts, 1)
+	assert.Equal(t, treasury, diff.Accounts[0].Address)
+	assert.True(t, diff.Accounts[0].Created)
+	assert.Equal(t, &exec.BalanceDiff{Before: 0, After: fee}, diff.Accounts[0].Balance)
+
+	// Without a treasury the proposer is paid
+	proposer := newAddress("proposer")
+	params.Fees = &genesis.FeeSchedule{MinimumGasPrice: 2}
+	exeState = copyState(t, st)
+	exe = makeExecutorWithParams(exeState, params)
+	_, err = exe.Execute(call(3))
+	require.NoError(t, err)
+	_, err = exe.Commit(&types.Header{Height: int64(exe.block.Height), ProposerAddress: proposer.Bytes()})
+	require.NoError(t, err)
+	assert.Equal(t, fee, exe.getAccount(t, proposer).Balance)
+	diff = lastBlockStateDiff(t, exeState)
+	require.Len(t, diff.Accounts, 1)
+	assert.Equal(t, proposer, diff.Accounts[0].Address)
+}
+
+// Returns the state diff recorded against the last block committed to st
+func lastBlockStateDiff(t *testing.T, st *state.State) *exec.StateDiff {
+	var diff *exec.StateDiff
+	err := st.IterateStreamEvents(nil, nil, func(ev *exec.StreamEvent) error {
+		if ev.EndBlock != nil {
+			diff = ev.EndBlock.StateDiff
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	return diff
+}
+
+func TestCallFeesOnFailedCreate(t *testing.T) {
+	st, privAccounts := makeGenesisState(2, 1)
+	acc0 := getAccount(t, st, privAccounts[0].GetAddress())
+	treasury := newAddress("treasury")
+
+	// The init code removes the new contract so it cannot be given code
+	txEnv := txs.Enclose(testChainID, &payload.CallTx{
+		Input:    &payload.TxInput{Address: acc0.Address, Amount: 5, Sequence: acc0.Sequence + 1},
+		Data:     bc.MustSplice(PUSH1, 0, SELFDESTRUCT),
+		GasLimit: 100,
+		GasPrice: 3,
+		Fee:      5,
+	})
+	require.NoError(t, txEnv.Sign(privAccounts[0]))
+
+	params := ParamsFromGenesis(testGenesisDoc)
+	params.Fees = &genesis.FeeSchedule{MinimumGasPrice: 2, Treasury: &treasury}
+	exe := makeExecutorWithParams(copyState(t, st), params)
+
+	_, err := exe.Execute(txEnv)
+	assertErrorCode(t, errors.Codes.NonExistentAccount, err)
+	// The unused gas is still refunded and the rest paid to the treasury
+	require.Len(t, exe.block.TxExecutions, 1)
+	fee := exe.block.TxExecutions[0].Result.GetFee()
+	assert.True(t, fee > 5 && fee < 5+100*3, "fee %d should be for the gas used", fee)
+	assert.Equal(t, acc0.Balance-fee, exe.getAccount(t, acc0.Address).Balance)
+	_, err = exe.Commit(nil)
+	require.NoError(t, err)
+	assert.Equal(t, fee, exe.getAccount(t, treasury).Balance)
+}
+
+func TestParallelExecution(t *testing.T) {
+	st, privAccounts := makeGenesisState(7, 1)
+	counter := newAddress("counter")
+	// Increments the value at storage key zero
+	code := bc.MustSplice(PUSH1, 0, SLOAD, PUSH1, 1, ADD, PUSH1, 0, SSTORE, STOP)
+	makeExecutor(st).updateAccounts(t, &acm.Account{Address: counter, EVMCode: code})
+
+	sign := func(tx payload.Payload, signer *acm.PrivateAccount) *txs.Envelope {
+		txEnv := txs.Enclose(testChainID, tx)
+		require.NoError(t, txEnv.Sign(signer))
+		return txEnv
+	}
+	sequence := func(i int) uint64 {
+		return getAccount(t, st, privAccounts[i].GetAddress()).Sequence + 1
+	}
+	send := func(from, to int, sequence uint64) *txs.Envelope {
+		tx := payload.NewSendTx()
+		require.NoError(t, tx.AddInputWithSequence(privAccounts[from].GetPublicKey(), 10, sequence))
+		require.NoError(t, tx.AddOutput(privAccounts[to].GetAddress(), 10))
+		return sign(tx, privAccounts[from])
+	}
+	call := func(from int, sequence uint64) *txs.Envelope {
+		return sign(&payload.CallTx{
+			Input:    &payload.TxInput{Address: privAccounts[from].GetAddress(), Sequence: sequence},
+			Address:  &counter,
+			GasLimit: 100,
+		}, privAccounts[from])
+	}
+	txEnvs := []*txs.Envelope{
+		send(0, 1, sequence(0)),
+		call(2, sequence(2)),
+		// Reads the storage written by the previous call
+		call(3, sequence(3)),
+		send(4, 5, sequence(4)),
+		// Reads accounts written by the first and previous sends
+		send(1, 4, sequence(1)),
+		// Fails with the same error whether executed speculatively or not
+		send(6, 0, sequence(6)+1),
+	}
+
+	execute := func(exe *testExecutor) ([]byte, []*exec.TxExecution, []error) {
+		var errs []error
+		for _, txEnv := range txEnvs {
+			_, err := exe.Execute(txEnv)
+			errs = append(errs, err)
+		}
+		txes := exe.block.TxExecutions
+		appHash, err := exe.Commit(nil)
+		require.NoError(t, err)
+		return appHash, txes, errs
+	}
+
+	sequential := makeExecutor(copyState(t, st))
+	expectedHash, expectedTxes, expectedErrs := execute(sequential)
+
+	parallel := makeExecutor(copyState(t, st), ParallelExecution(3))
+	require.NoError(t, parallelCommitter{parallel.executor}.Speculate(txEnvs))
+	require.Len(t, parallel.speculations, len(txEnvs))
+	key, err := speculationKey(txEnvs[1])
+	require.NoError(t, err)
+	independent := parallel.speculations[key]
+	appHash, txes, errs := execute(parallel)
+
+	assert.Equal(t, expectedHash, appHash)
+	assert.Equal(t, expectedTxes, txes)
+	assert.Equal(t, expectedErrs, errs)
+	assert.Error(t, errs[len(errs)-1])
+	// The speculation was used rather than executing again
+	assert.True(t, independent.txe == txes[1])
+	value, err := parallel.GetStorage(counter, Zero256)
+	require.NoError(t, err)
+	assert.Equal(t, LeftPadBytes([]byte{2}, 32), value)
+}
+
 func TestMerklePanic(t *testing.T) {
 	st, privAccounts := makeGenesisState(3, 1)
 
@@ -1538,7 +1756,11 @@ type testExecutor struct {
 	*bcm.Blockchain
 }
 
-func makeExecutor(state *state.State) *testExecutor {
+func makeExecutor(state *state.State, options ...Option) *testExecutor {
+	return makeExecutorWithParams(state, ParamsFromGenesis(testGenesisDoc), options...)
+}
+
+func makeExecutorWithParams(state *state.State, params Params, options ...Option) *testExecutor {
 	blockchain := newBlockchain(testGenesisDoc)
 	err := blockchain.CommitBlockAtHeight(time.Now(), []byte("hashily"), state.Hash(), HeightAtVersion(state.Version()))
 	if err != nil {
@@ -1546,8 +1768,7 @@ func makeExecutor(state *state.State) *testExecutor {
 	}
 	return &testExecutor{
 		Blockchain: blockchain,
-		executor: newExecutor("makeExecutorCache", true, ParamsFromGenesis(testGenesisDoc), state,
-			blockchain, nil, logger),
+		executor:   newExecutor("makeExecutorCache", true, params, state, blockchain, nil, logger, options...),
 	}
 }
 
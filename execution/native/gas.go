@@ -7,6 +7,7 @@ const (
 	GasSha3          uint64 = 1
 	GasGetAccount    uint64 = 1
 	GasStorageUpdate uint64 = 1
+	GasStorageRead   uint64 = 1
 	GasCreateAccount uint64 = 1
 
 	GasBaseOp  uint64 = 0 // TODO: make this 1
@@ -6,7 +6,9 @@ import (
 	"github.com/hyperledger/burrow/bcm"
 	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/execution/contexts"
+	"github.com/hyperledger/burrow/execution/errors"
 	"github.com/hyperledger/burrow/execution/evm"
+	"github.com/hyperledger/burrow/execution/evm/abi"
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/txs"
@@ -14,13 +16,88 @@ import (
 )
 
 // Run a contract's code on an isolated and unpersisted state
-// Cannot be used to create new contracts
+// Cannot be used to create new contracts. The engines are configured by options as an executor's would be.
 func CallSim(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress, address crypto.Address, data []byte,
-	logger *logging.Logger) (*exec.TxExecution, error) {
+	logger *logging.Logger, options ...Option) (*exec.TxExecution, error) {
+
+	return TraceCallSim(reader, blockchain, fromAddress, address, data, nil, logger, options...)
+}
+
+// Run a contract's code on an isolated and unpersisted state passing the execution to tracer (if non-nil)
+func TraceCallSim(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress, address crypto.Address,
+	data []byte, tracer evm.Tracer, logger *logging.Logger, options ...Option) (*exec.TxExecution, error) {
+
+	return simulate(reader, blockchain, fromAddress, &address, data, 0, contexts.GasLimit, tracer, logger, options)
+}
+
+// EstimateGas finds the least gas limit with which a call to address (or contract creation if address is nil) sending
+// value succeeds by binary search between the gas used by a simulation with gasCap and gasCap itself. If the call fails even with
+// gasCap the exception is returned, including the revert reason if the contract gave one.
+func EstimateGas(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress crypto.Address,
+	address *crypto.Address, data []byte, value, gasCap uint64, logger *logging.Logger, options ...Option) (uint64, error) {
+
+	run := func(gasLimit uint64) (*exec.TxExecution, error) {
+		return simulate(reader, blockchain, fromAddress, address, data, value, gasLimit, nil, logger, options)
+	}
+
+	txe, err := run(gasCap)
+	if err != nil {
+		return 0, err
+	}
+	if txe.Exception != nil {
+		return 0, simulationError(txe)
+	}
+
+	// Execution is deterministic so any limit below the gas used when unconstrained would run out, and that amount
+	// is usually sufficient in itself so try it before searching
+	lo := txe.Result.GetGasUsed()
+	txe, err = run(lo)
+	if err != nil {
+		return 0, err
+	}
+	if txe.Exception == nil {
+		return lo, nil
+	}
+	// Invariant: lo fails and hi succeeds
+	hi := gasCap
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		txe, err = run(mid)
+		if err != nil {
+			return 0, err
+		}
+		if txe.Exception == nil {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+func simulationError(txe *exec.TxExecution) error {
+	if txe.Exception.ErrorCode() == errors.Codes.ExecutionReverted {
+		reason, err := abi.UnpackRevert(txe.Result.GetReturn())
+		if err == nil && reason != nil {
+			return errors.Errorf(errors.Codes.ExecutionReverted, "with reason '%s'", *reason)
+		}
+		return errors.Codes.ExecutionReverted
+	}
+	return txe.Exception.AsError()
+}
+
+func simulate(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress crypto.Address,
+	address *crypto.Address, data []byte, value, gasLimit uint64, tracer evm.Tracer,
+	logger *logging.Logger, options []Option) (*exec.TxExecution, error) {
 
 	cache := acmstate.NewCache(reader)
+	genesisDoc := blockchain.GenesisDoc()
+	vmOptions, wasmOptions := engineOptions(ParamsFromGenesis(&genesisDoc), options)
+	vmOptions.Tracer = tracer
+	vm, wvm := newEngines(vmOptions, wasmOptions)
 	exe := contexts.CallContext{
-		EVM:           evm.Default(),
+		EVM:           vm,
+		WVM:           wvm,
 		RunCall:       true,
 		State:         cache,
 		MetadataState: acmstate.NewMemoryState(),
@@ -31,10 +108,11 @@ func CallSim(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress,
 	txe := exec.NewTxExecution(txs.Enclose(blockchain.ChainID(), &payload.CallTx{
 		Input: &payload.TxInput{
 			Address: fromAddress,
+			Amount:  value,
 		},
-		Address:  &address,
+		Address:  address,
 		Data:     data,
-		GasLimit: contexts.GasLimit,
+		GasLimit: gasLimit,
 	}))
 
 	// Set height for downstream synchronisation purposes
@@ -49,7 +127,7 @@ func CallSim(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress,
 // Run the given code on an isolated and unpersisted state
 // Cannot be used to create new contracts.
 func CallCodeSim(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddress, address crypto.Address, code, data []byte,
-	logger *logging.Logger) (*exec.TxExecution, error) {
+	logger *logging.Logger, options ...Option) (*exec.TxExecution, error) {
 
 	// Attach code to target account (overwriting target)
 	cache := acmstate.NewCache(reader)
@@ -61,5 +139,5 @@ func CallCodeSim(reader acmstate.Reader, blockchain bcm.BlockchainInfo, fromAddr
 	if err != nil {
 		return nil, err
 	}
-	return CallSim(cache, blockchain, fromAddress, address, data, logger)
+	return CallSim(cache, blockchain, fromAddress, address, data, logger, options...)
 }
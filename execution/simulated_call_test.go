@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/execution/errors"
+	"github.com/hyperledger/burrow/execution/evm/abi"
+	. "github.com/hyperledger/burrow/execution/evm/asm"
+	"github.com/hyperledger/burrow/execution/evm/asm/bc"
+	"github.com/hyperledger/burrow/execution/solidity"
+	"github.com/hyperledger/burrow/execution/state"
+	"github.com/hyperledger/burrow/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestEstimateGas(t *testing.T) {
+	genDoc := newBaseGenDoc(permission.ZeroAccountPermissions, permission.ZeroAccountPermissions)
+	genDoc.Accounts[0].Permissions.Base.Set(permission.Call, true)
+	genDoc.Accounts[0].Permissions.Base.Set(permission.CreateContract, true)
+	st, err := state.MakeGenesisState(dbm.NewMemDB(), &genDoc)
+	require.NoError(t, err)
+	err = st.InitialCommit()
+	require.NoError(t, err)
+	blockchain := newBlockchain(testGenesisDoc)
+	caller := users[0].GetAddress()
+
+	cache := acmstate.NewCache(st)
+	storer := users[5].GetAddress()
+	storeCode := bc.MustSplice(PUSH1, 0x2A, PUSH1, 0x01, SSTORE, STOP)
+	require.NoError(t, cache.UpdateAccount(&acm.Account{Address: storer, EVMCode: storeCode}))
+	reverter := users[6].GetAddress()
+	require.NoError(t, cache.UpdateAccount(&acm.Account{Address: reverter, EVMCode: solidity.DeployedBytecode_Revert}))
+	// Like a payable function that requires some value
+	payable := users[7].GetAddress()
+	payableCode := bc.MustSplice(CALLVALUE, PUSH1, 0x08, JUMPI, PUSH1, 0x00, DUP1, REVERT, JUMPDEST, STOP)
+	require.NoError(t, cache.UpdateAccount(&acm.Account{Address: payable, EVMCode: payableCode}))
+
+	t.Run("Call", func(t *testing.T) {
+		gas, err := EstimateGas(cache, blockchain, caller, &storer, nil, 0, 100000, logger)
+		require.NoError(t, err)
+		assert.True(t, gas > 0)
+
+		txe, err := simulate(cache, blockchain, caller, &storer, nil, 0, gas, nil, logger, nil)
+		require.NoError(t, err)
+		assert.Nil(t, txe.Exception)
+
+		txe, err = simulate(cache, blockchain, caller, &storer, nil, 0, gas-1, nil, logger, nil)
+		require.NoError(t, err)
+		assert.Equal(t, errors.Codes.InsufficientGas, errors.GetCode(txe.Exception))
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		gas, err := EstimateGas(cache, blockchain, caller, nil, solidity.Bytecode_Revert, 0, 1000000, logger)
+		require.NoError(t, err)
+		assert.True(t, gas > 0)
+	})
+
+	t.Run("Revert", func(t *testing.T) {
+		spec, err := abi.ReadSpec(solidity.Abi_Revert)
+		require.NoError(t, err)
+		data, _, err := spec.Pack("RevertAt", 0)
+		require.NoError(t, err)
+		_, err = EstimateGas(cache, blockchain, caller, &reverter, data, 0, 100000, logger)
+		require.Error(t, err)
+		assert.Equal(t, errors.Codes.ExecutionReverted, errors.GetCode(err))
+		assert.Contains(t, err.Error(), "I have reverted")
+	})
+
+	t.Run("Value", func(t *testing.T) {
+		_, err := EstimateGas(cache, blockchain, caller, &payable, nil, 0, 100000, logger)
+		require.Error(t, err)
+		assert.Equal(t, errors.Codes.ExecutionReverted, errors.GetCode(err))
+
+		gas, err := EstimateGas(cache, blockchain, caller, &payable, nil, 1, 100000, logger)
+		require.NoError(t, err)
+		assert.True(t, gas > 0)
+	})
+
+	t.Run("GasCap", func(t *testing.T) {
+		_, err := EstimateGas(cache, blockchain, caller, &storer, nil, 0, 1, logger)
+		require.Error(t, err)
+		assert.Equal(t, errors.Codes.InsufficientGas, errors.GetCode(err))
+	})
+}
@@ -0,0 +1,341 @@
+package execution
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/contexts"
+	"github.com/hyperledger/burrow/execution/evm"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/execution/wasm"
+	"github.com/hyperledger/burrow/logging/structure"
+	"github.com/hyperledger/burrow/txs"
+	"github.com/hyperledger/burrow/txs/payload"
+)
+
+// Speculator executes the transactions of a block before they are delivered so that Execute can use the results in
+// place of executing them again
+type Speculator interface {
+	// Speculate executes txEnvs, the transactions of the block about to be delivered, in parallel against the state
+	// at the start of the block. It must be called before any of the block's transactions are executed.
+	Speculate(txEnvs []*txs.Envelope) error
+}
+
+// A BatchCommitter that can speculate, only returned by NewBatchCommitter when parallel execution is enabled
+type parallelCommitter struct {
+	*executor
+}
+
+var _ Speculator = parallelCommitter{}
+
+type engines struct {
+	vm  *evm.EVM
+	wvm *wasm.WVM
+}
+
+// The outcome of executing a transaction against the state at the start of its block
+type speculation struct {
+	txe      *exec.TxExecution
+	err      error
+	cache    *acmstate.Cache
+	metadata *acmstate.MetadataCache
+	reads    *accessSet
+	changes  []*acmstate.AccountChange
+}
+
+// Accounts and storage keys touched by one or more transactions
+type accessSet struct {
+	accounts map[crypto.Address]struct{}
+	storage  map[crypto.Address]map[binary.Word256]struct{}
+}
+
+func newAccessSet() *accessSet {
+	return &accessSet{
+		accounts: make(map[crypto.Address]struct{}),
+		storage:  make(map[crypto.Address]map[binary.Word256]struct{}),
+	}
+}
+
+func (as *accessSet) addAccount(address crypto.Address) {
+	as.accounts[address] = struct{}{}
+}
+
+func (as *accessSet) addStorage(address crypto.Address, key binary.Word256) {
+	keys, ok := as.storage[address]
+	if !ok {
+		keys = make(map[binary.Word256]struct{})
+		as.storage[address] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+// Add what changes would write where it differs from what was there before
+func (as *accessSet) addChanges(changes []*acmstate.AccountChange) {
+	for _, change := range changes {
+		if change.Before == nil || change.After == nil || !change.Before.Equal(change.After) {
+			as.addAccount(change.Address)
+		}
+		for _, sc := range change.Storage {
+			as.addStorage(change.Address, sc.Key)
+		}
+	}
+}
+
+func (as *accessSet) intersects(other *accessSet) bool {
+	for address := range other.accounts {
+		if _, ok := as.accounts[address]; ok {
+			return true
+		}
+	}
+	for address, keys := range other.storage {
+		for key := range keys {
+			if _, ok := as.storage[address][key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Records everything a speculative transaction reads from the state at the start of its block. Each is used by a
+// single transaction so needs no locking.
+type readRecorder struct {
+	backend acmstate.Reader
+	reads   *accessSet
+}
+
+func (rr *readRecorder) GetAccount(address crypto.Address) (*acm.Account, error) {
+	rr.reads.addAccount(address)
+	return rr.backend.GetAccount(address)
+}
+
+func (rr *readRecorder) GetStorage(address crypto.Address, key binary.Word256) ([]byte, error) {
+	rr.reads.addStorage(address, key)
+	return rr.backend.GetStorage(address, key)
+}
+
+// Serialises the workers' reads of the committed state
+type lockedReader struct {
+	sync.Mutex
+	backend ExecutorState
+}
+
+func (lr *lockedReader) GetAccount(address crypto.Address) (*acm.Account, error) {
+	lr.Lock()
+	defer lr.Unlock()
+	return lr.backend.GetAccount(address)
+}
+
+func (lr *lockedReader) GetStorage(address crypto.Address, key binary.Word256) ([]byte, error) {
+	lr.Lock()
+	defer lr.Unlock()
+	return lr.backend.GetStorage(address, key)
+}
+
+func (lr *lockedReader) GetMetadata(metahash acmstate.MetadataHash) (string, error) {
+	lr.Lock()
+	defer lr.Unlock()
+	return lr.backend.GetMetadata(metahash)
+}
+
+// Only transactions that touch nothing but accounts and storage can be executed ahead of their predecessors
+func speculative(txEnv *txs.Envelope) bool {
+	switch txEnv.Tx.Type() {
+	case payload.TypeCall, payload.TypeSend:
+		return true
+	}
+	return false
+}
+
+// Transactions are matched to their speculations by their encoding since the signatures are not covered by their hash
+func speculationKey(txEnv *txs.Envelope) (string, error) {
+	bs, err := txEnv.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+func (pc parallelCommitter) Speculate(txEnvs []*txs.Envelope) error {
+	exe := pc.executor
+	if len(exe.block.TxExecutions) > 0 {
+		return fmt.Errorf("cannot speculate once transactions have been executed in block %d", exe.block.Height)
+	}
+	keys := make([]string, len(txEnvs))
+	for i, txEnv := range txEnvs {
+		key, err := speculationKey(txEnv)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+	// No transaction has been executed so the committed state is the state at the start of the block
+	committed := &lockedReader{backend: exe.state}
+	base := acmstate.NewCache(committed, acmstate.Named("SpeculationCache"), acmstate.ReadOnly)
+	speculations := make([]*speculation, len(txEnvs))
+	indices := make(chan int)
+	wg := new(sync.WaitGroup)
+	for _, eng := range exe.workerEngines {
+		wg.Add(1)
+		go func(eng *engines) {
+			defer wg.Done()
+			for i := range indices {
+				speculations[i] = exe.speculate(eng, base, committed, txEnvs[i])
+			}
+		}(eng)
+	}
+	for i, txEnv := range txEnvs {
+		if speculative(txEnv) {
+			indices <- i
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	exe.speculations = make(map[string]*speculation)
+	for i, spec := range speculations {
+		// Any repeat of a transaction is left to be executed in order
+		if _, ok := exe.speculations[keys[i]]; spec != nil && !ok {
+			exe.speculations[keys[i]] = spec
+		}
+	}
+	exe.logger.TraceMsg("Speculatively executed block",
+		"height", exe.block.Height,
+		"txs", len(txEnvs),
+		"speculations", len(exe.speculations))
+	return nil
+}
+
+// Execute txEnv against its own cache over base recording what it reads. Returns nil if the transaction could not be
+// executed in a way that can be reproduced in order.
+func (exe *executor) speculate(eng *engines, base acmstate.Reader, metadataBase acmstate.MetadataReader,
+	txEnv *txs.Envelope) (spec *speculation) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			exe.logger.InfoMsg("Recovered from panic in speculative execution",
+				structure.TxHashKey, txEnv.Tx.Hash(),
+				structure.ErrorKey, fmt.Errorf("%v\n%s", r, debug.Stack()))
+			spec = nil
+		}
+	}()
+	if txEnv.Verify(exe.params.ChainID) != nil {
+		return nil
+	}
+	reads := newAccessSet()
+	cache := acmstate.NewCache(&readRecorder{backend: base, reads: reads}, acmstate.Named("SpeculativeTxCache"))
+	metadata := acmstate.NewMetadataCache(metadataBase)
+	var txExecutor contexts.Context
+	switch txEnv.Tx.Type() {
+	case payload.TypeCall:
+		txExecutor = &contexts.CallContext{
+			EVM:           eng.vm,
+			WVM:           eng.wvm,
+			Blockchain:    exe.blockchain,
+			State:         cache,
+			MetadataState: metadata,
+			RunCall:       exe.runCall,
+			Fees:          exe.params.Fees,
+			Logger:        exe.logger,
+		}
+	case payload.TypeSend:
+		txExecutor = &contexts.SendContext{
+			State:  cache,
+			Logger: exe.logger,
+		}
+	default:
+		return nil
+	}
+	txe := exec.NewTxExecution(txEnv)
+	txe.Height = exe.block.Height
+	err := exe.execute(cache, txExecutor, txe, exe.logger)
+	changes, changesErr := cache.Changes()
+	if changesErr != nil {
+		return nil
+	}
+	if exe.recordStateDiffs {
+		txe.StateDiff = exec.NewStateDiff(changes)
+	}
+	return &speculation{
+		txe:      txe,
+		err:      err,
+		cache:    cache,
+		metadata: metadata,
+		reads:    reads,
+		changes:  changes,
+	}
+}
+
+// Take the speculation for txEnv if it read nothing the block has since changed
+func (exe *executor) takeSpeculation(txEnv *txs.Envelope) *speculation {
+	if len(exe.speculations) == 0 {
+		return nil
+	}
+	key, err := speculationKey(txEnv)
+	if err != nil {
+		return nil
+	}
+	spec, ok := exe.speculations[key]
+	if !ok {
+		return nil
+	}
+	delete(exe.speculations, key)
+	if exe.blockWrites.intersects(spec.reads) {
+		exe.logger.TraceMsg("Speculation conflicts with earlier transaction so executing in order",
+			"height", exe.block.Height,
+			structure.TxHashKey, txEnv.Tx.Hash())
+		return nil
+	}
+	return spec
+}
+
+// Merge a speculation into the block leaving the block's caches as they would be had it been executed in order
+func (exe *executor) applySpeculation(spec *speculation) (*exec.TxExecution, error) {
+	// Empty the transaction cache so that nothing read into it between transactions shadows the speculation's writes
+	blockCache, err := exe.blockStateCache()
+	if err != nil {
+		return nil, err
+	}
+	// In order execution would have read through the block cache, which determines the storage written on commit
+	for address := range spec.reads.accounts {
+		_, err = blockCache.GetAccount(address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for address, keys := range spec.reads.storage {
+		for key := range keys {
+			_, err = blockCache.GetStorage(address, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	err = spec.cache.Sync(blockCache)
+	if err != nil {
+		return nil, err
+	}
+	err = spec.metadata.Sync(exe.metadataCache)
+	if err != nil {
+		return nil, err
+	}
+	exe.blockWrites.addChanges(spec.changes)
+	exe.block.AppendTxs(spec.txe)
+	if spec.err != nil {
+		return nil, spec.err
+	}
+	return spec.txe, nil
+}
+
+// Discard any speculations left over from the block and start recording the writes of the next
+func (exe *executor) resetSpeculation() {
+	if exe.blockWrites != nil {
+		exe.speculations = nil
+		exe.blockWrites = newAccessSet()
+	}
+}
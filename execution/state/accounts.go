@@ -143,12 +143,26 @@ func (ws *writeState) SetStorage(address crypto.Address, key binary.Word256, val
 }
 
 func (s *ReadState) IterateStorage(address crypto.Address, consumer func(key binary.Word256, value []byte) error) error {
+	return s.iterateStorage(address, nil, nil, consumer)
+}
+
+func (s *ReadState) IterateStorageRange(address crypto.Address, start binary.Word256, end *binary.Word256,
+	consumer func(key binary.Word256, value []byte) error) error {
+	var high []byte
+	if end != nil {
+		high = end.Bytes()
+	}
+	return s.iterateStorage(address, start.Bytes(), high, consumer)
+}
+
+func (s *ReadState) iterateStorage(address crypto.Address, low, high []byte,
+	consumer func(key binary.Word256, value []byte) error) error {
 	keyFormat := keys.Storage.Fix(address)
 	tree, err := s.Forest.Reader(keyFormat.Prefix())
 	if err != nil {
 		return err
 	}
-	return tree.Iterate(nil, nil, true,
+	return tree.Iterate(low, high, true,
 		func(key []byte, value []byte) error {
 
 			if len(key) != binary.Word256Bytes {
@@ -0,0 +1,162 @@
+package state
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/burrow/storage"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// Name of the database holding the plain when it is kept apart from the forest
+const PlainDBName = "burrow_plain"
+
+// The version of the last commit is recorded in the forest's database as well as in the plain (see
+// KeyFormatStore.PlainVersion) so that Open can tell when a crash has left separate databases out of step
+var forestVersionKey = []byte("ForestVersion")
+
+// DatabaseConfig selects the tm-db backend for each of the stores Burrow keeps in its database directory
+type DatabaseConfig struct {
+	// Backend of the forest (the merkle state) along with the blockchain metadata stored beside it
+	Forest dbm.BackendType
+	// Backend of the plain (events, transaction hashes, and ABIs) - if it differs from Forest then the plain is
+	// kept in a database of its own
+	Plain dbm.BackendType
+	// Backend of Tendermint's block store and the rest of Tendermint's data
+	BlockStore dbm.BackendType
+}
+
+func DefaultDatabaseConfig() *DatabaseConfig {
+	return &DatabaseConfig{
+		Forest:     dbm.GoLevelDBBackend,
+		Plain:      dbm.GoLevelDBBackend,
+		BlockStore: dbm.GoLevelDBBackend,
+	}
+}
+
+// Open the state database in dir, spreading it across a database per backend
+func (conf *DatabaseConfig) Open(dir string) (dbm.DB, error) {
+	db, err := NewDB(BurrowDBName, conf.forest(), dir)
+	if err != nil {
+		return nil, err
+	}
+	if !conf.SeparatePlain() {
+		if Exists(PlainDBName, dir) {
+			db.Close()
+			return nil, fmt.Errorf("found a separate plain database %s in %s but the plain is configured to "+
+				"share the %s database, run 'burrow migrate-db' to move it", PlainDBName, dir, conf.forest())
+		}
+		return db, nil
+	}
+	// The plain would have been in the forest's database if it was written with an earlier config
+	it, err := db.Iterator(storage.Prefix(plainPrefix), storage.Prefix(plainPrefix).Above())
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	stale := it.Valid()
+	it.Close()
+	if stale {
+		db.Close()
+		return nil, fmt.Errorf("found plain data in the %s database %s but the plain is configured to use %s, "+
+			"run 'burrow migrate-db' to move it", conf.forest(), BurrowDBName, conf.Plain)
+	}
+	plainDB, err := NewDB(PlainDBName, conf.Plain, dir)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	err = checkVersions(db, plainDB)
+	if err != nil {
+		db.Close()
+		plainDB.Close()
+		return nil, err
+	}
+	return storage.NewRouteDB(db, map[string]dbm.DB{plainPrefix: plainDB})
+}
+
+// Writes to the plain and the forest cannot be made atomic across separate databases so each commit writes the plain
+// before the forest. A crash between the two leaves the plain ahead, which is made good when Tendermint replays the
+// blocks the forest is missing since they write the same plain data again. If the plain is behind the forest (say
+// because its database was restored from an older backup) the plain data of the blocks between has been lost.
+func checkVersions(db, plainDB dbm.DB) error {
+	forestVersion, err := getVersion(db, forestVersionKey)
+	if err != nil {
+		return err
+	}
+	plainVersion, err := getVersion(plainDB, storage.Prefix(plainPrefix).Key(keys.PlainVersion.Key()))
+	if err != nil {
+		return err
+	}
+	// Databases written before versions were recorded cannot be checked
+	if forestVersion == 0 || plainVersion == 0 {
+		return nil
+	}
+	if plainVersion < forestVersion {
+		return fmt.Errorf("the plain database %s is at version %d but the forest database %s is at version %d, "+
+			"so the plain is missing data from the versions between, restore both from the same backup",
+			PlainDBName, plainVersion, BurrowDBName, forestVersion)
+	}
+	return nil
+}
+
+func getVersion(db dbm.DB, key []byte) (int64, error) {
+	bs, err := db.Get(key)
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	return decodeVersion(bs), nil
+}
+
+func encodeVersion(version int64) []byte {
+	bs := make([]byte, uint64Length)
+	binary.BigEndian.PutUint64(bs, uint64(version))
+	return bs
+}
+
+func decodeVersion(bs []byte) int64 {
+	return int64(binary.BigEndian.Uint64(bs))
+}
+
+// SeparatePlain is true when the plain is kept in a database of its own
+func (conf *DatabaseConfig) SeparatePlain() bool {
+	return conf.Plain != "" && conf.Plain != conf.forest()
+}
+
+// Databases returns the names of the state databases opened by Open
+func (conf *DatabaseConfig) Databases() []string {
+	if conf.SeparatePlain() {
+		return []string{BurrowDBName, PlainDBName}
+	}
+	return []string{BurrowDBName}
+}
+
+func (conf *DatabaseConfig) forest() dbm.BackendType {
+	if conf.Forest == "" {
+		return dbm.GoLevelDBBackend
+	}
+	return conf.Forest
+}
+
+// NewDB opens a tm-db database returning an error where tm-db would panic, such as for a backend that has not been
+// compiled in
+func NewDB(name string, backend dbm.BackendType, dir string) (db dbm.DB, err error) {
+	if backend == dbm.FSDBBackend {
+		// Every commit is written as a batch
+		return nil, fmt.Errorf("cannot use %s for database %s since it does not support batched writes", backend, name)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not open %s database %s in %s: %v", backend, name, dir, r)
+		}
+	}()
+	return dbm.NewDB(name, backend, dir), nil
+}
+
+// Exists is true if a database called name has been created in dir by an on-disk backend
+func Exists(name, dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, name+".db"))
+	return err == nil
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestDatabaseConfig_Open(t *testing.T) {
+	dir, err := ioutil.TempDir("", "database")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(db dbm.DB) []byte {
+		st := NewState(db)
+		_, _, err := st.Update(func(ws Updatable) error {
+			err := ws.SetMetadata(acmstate.GetMetadataHash("abi"), "abi")
+			if err != nil {
+				return err
+			}
+			return ws.UpdateAccount(acm.NewAccountFromSecret("Foo"))
+		})
+		require.NoError(t, err)
+		return st.Hash()
+	}
+
+	shared := DefaultDatabaseConfig()
+	db, err := shared.Open(dir)
+	require.NoError(t, err)
+	hash := write(db)
+	require.NoError(t, db.Close())
+
+	// The plain was written to the forest's database so we should refuse to open it elsewhere
+	split := &DatabaseConfig{Forest: dbm.GoLevelDBBackend, Plain: dbm.MemDBBackend}
+	assert.True(t, split.SeparatePlain())
+	_, err = split.Open(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "migrate-db")
+
+	// Migrate to a separate plain database
+	migrated, err := ioutil.TempDir("", "database")
+	require.NoError(t, err)
+	defer os.RemoveAll(migrated)
+	split.Plain = dbm.MemDBBackend
+	db, err = shared.Open(dir)
+	require.NoError(t, err)
+	splitDB, err := split.Open(migrated)
+	require.NoError(t, err)
+	_, err = storage.CopyDB(splitDB, db)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	st, err := LoadState(splitDB, VersionAtHeight(0))
+	require.NoError(t, err)
+	assert.Equal(t, hash, st.Hash())
+	abi, err := st.GetMetadata(acmstate.GetMetadataHash("abi"))
+	require.NoError(t, err)
+	assert.Equal(t, "abi", abi)
+	// Nothing from the plain should have landed in the forest's database
+	require.NoError(t, splitDB.Close())
+	forestDB, err := NewDB(BurrowDBName, dbm.GoLevelDBBackend, migrated)
+	require.NoError(t, err)
+	it, err := forestDB.Iterator(storage.Prefix(plainPrefix), storage.Prefix(plainPrefix).Above())
+	require.NoError(t, err)
+	assert.False(t, it.Valid())
+	it.Close()
+	require.NoError(t, forestDB.Close())
+
+	// The shared config should refuse to open a directory with a separate plain on disk
+	plainDB, err := NewDB(PlainDBName, dbm.GoLevelDBBackend, migrated)
+	require.NoError(t, err)
+	require.NoError(t, plainDB.Close())
+	assert.True(t, Exists(PlainDBName, migrated))
+	_, err = shared.Open(migrated)
+	require.Error(t, err)
+
+	// FSDB cannot write batches
+	_, err = NewDB(PlainDBName, dbm.FSDBBackend, migrated)
+	require.Error(t, err)
+
+	_, err = NewDB(BurrowDBName, "nosuchdb", dir)
+	require.Error(t, err)
+}
+
+func TestCheckVersions(t *testing.T) {
+	forestDB, plainDB := dbm.NewMemDB(), dbm.NewMemDB()
+	db, err := storage.NewRouteDB(forestDB, map[string]dbm.DB{plainPrefix: plainDB})
+	require.NoError(t, err)
+	st := NewState(db)
+	commit := func() {
+		_, _, err := st.Update(func(ws Updatable) error {
+			return ws.SetMetadata(acmstate.GetMetadataHash("abi"), "abi")
+		})
+		require.NoError(t, err)
+	}
+	commit()
+	commit()
+	require.NoError(t, checkVersions(forestDB, plainDB))
+
+	// A crash after writing the plain but before saving the forest leaves the plain ahead, which replay makes good
+	plainVersion := storage.Prefix(plainPrefix).Key(keys.PlainVersion.Key())
+	require.NoError(t, plainDB.Set(plainVersion, encodeVersion(st.Version()+1)))
+	require.NoError(t, checkVersions(forestDB, plainDB))
+
+	// But a plain behind the forest has lost data
+	require.NoError(t, plainDB.Set(plainVersion, encodeVersion(st.Version()-1)))
+	err = checkVersions(forestDB, plainDB)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), PlainDBName)
+
+	// Databases from before versions were recorded are let through
+	require.NoError(t, plainDB.Delete(plainVersion))
+	require.NoError(t, checkVersions(forestDB, plainDB))
+}
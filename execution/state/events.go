@@ -4,11 +4,47 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/encoding"
 	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/storage"
 )
 
+// EventsConfig determines how long a node keeps the events it stores in the plain. Where events are stored is
+// determined by the chain's genesis since events in the forest contribute to the AppHash.
+type EventsConfig struct {
+	// When events are stored in the plain only keep those from this many of the most recent blocks (all if zero)
+	RetainBlocks uint64
+}
+
+func DefaultEventsConfig() *EventsConfig {
+	return &EventsConfig{}
+}
+
+// Sets the height from which events are stored in the plain rather than the forest (never if zero), which must be the
+// same for every validator, and how long events are kept there. Events from before the switch remain readable from the
+// forest.
+func (s *State) SetEvents(plainEventsHeight uint64, conf *EventsConfig) error {
+	s.Lock()
+	defer s.Unlock()
+	if conf == nil {
+		conf = DefaultEventsConfig()
+	}
+	// Events only reach the plain from the first block with transactions at or above the height
+	if start, ok, err := s.plainEventsStart(); err != nil {
+		return err
+	} else if ok && (plainEventsHeight == 0 || plainEventsHeight > start) {
+		return fmt.Errorf("events have been stored in the plain from height %d but the genesis stores them there "+
+			"from height %d (zero for never)", start, plainEventsHeight)
+	}
+	s.writeState.plainEventsHeight = plainEventsHeight
+	s.writeState.events = *conf
+	return nil
+}
+
 func (ws *writeState) AddBlock(be *exec.BlockExecution) error {
 	// If there are no transactions, do not store anything. This reduces the amount of data we store and
 	// prevents the iavl tree from changing, which means the AppHash does not change.
@@ -36,6 +72,10 @@ func (ws *writeState) AddBlock(be *exec.BlockExecution) error {
 		offset += n
 	}
 
+	if ws.plainEventsHeight != 0 && be.Height >= ws.plainEventsHeight {
+		return ws.addPlainBlock(be, buf.Bytes())
+	}
+
 	tree, err := ws.forest.Writer(keys.Event.Prefix())
 	if err != nil {
 		return err
@@ -46,41 +86,165 @@ func (ws *writeState) AddBlock(be *exec.BlockExecution) error {
 	return nil
 }
 
-// Iterate SteamEvents over the closed interval [startHeight, endHeight] - i.e. startHeight and endHeight inclusive
-func (s *ReadState) IterateStreamEvents(startHeight, endHeight *uint64, consumer func(*exec.StreamEvent) error) error {
-	tree, err := s.Forest.Reader(keys.Event.Prefix())
+func (ws *writeState) addPlainBlock(be *exec.BlockExecution, bs []byte) error {
+	if has, err := ws.plain.Has(keys.EventStart.Key()); err != nil {
+		return err
+	} else if !has {
+		// Mark the first height stored here so we know to look in the forest for earlier ones
+		err = ws.plain.Set(keys.EventStart.Key(), keys.EventPlain.KeyNoPrefix(be.Height))
+		if err != nil {
+			return err
+		}
+	}
+	err := ws.plain.Set(keys.EventPlain.Key(be.Height), bs)
 	if err != nil {
 		return err
 	}
-	var startKey, endKey []byte
-	if startHeight != nil {
-		startKey = keys.Event.KeyNoPrefix(*startHeight)
+	for _, ev := range be.StreamEvents() {
+		for _, key := range indexKeys(be.Height, ev) {
+			err = ws.plain.Set(key, []byte{})
+			if err != nil {
+				return err
+			}
+		}
 	}
-	if endHeight != nil {
-		// Convert to inclusive end bounds since this generally makes more sense for block height
-		endKey = keys.Event.KeyNoPrefix(*endHeight + 1)
+	if ws.events.RetainBlocks == 0 || be.Height < ws.events.RetainBlocks {
+		return nil
 	}
-	return tree.Iterate(startKey, endKey, true, func(_, value []byte) error {
-		buf := bytes.NewBuffer(value)
+	return ws.dropPlainBlocks(be.Height - ws.events.RetainBlocks + 1)
+}
 
-		for {
-			ev := new(exec.StreamEvent)
-			_, err := encoding.ReadMessage(buf, ev)
-			if err != nil {
-				if err == io.EOF {
-					return nil
+// Delete the events below height from the plain along with their indexes and transaction references
+func (ws *writeState) dropPlainBlocks(height uint64) error {
+	it, err := ws.plain.Iterator(keys.EventPlain.Key(uint64(0)), keys.EventPlain.Key(height))
+	if err != nil {
+		return err
+	}
+	// We cannot write while iterating
+	var drop [][]byte
+	for ; it.Valid(); it.Next() {
+		drop = append(drop, it.Key())
+	}
+	it.Close()
+	for _, blockKey := range drop {
+		var blockHeight uint64
+		err = keys.EventPlain.Scan(blockKey, &blockHeight)
+		if err != nil {
+			return err
+		}
+		bs, err := ws.plain.Get(blockKey)
+		if err != nil {
+			return err
+		}
+		err = readStreamEvents(bs, func(ev *exec.StreamEvent) error {
+			if ev.BeginTx != nil {
+				err := ws.plain.Delete(keys.TxHash.Key(ev.BeginTx.TxHeader.TxHash))
+				if err != nil {
+					return err
 				}
-				return err
 			}
-
-			err = consumer(ev)
-			if err != nil {
-				return err
+			for _, key := range indexKeys(blockHeight, ev) {
+				err := ws.plain.Delete(key)
+				if err != nil {
+					return err
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = ws.plain.Delete(blockKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Iterate SteamEvents over the closed interval [startHeight, endHeight] - i.e. startHeight and endHeight inclusive
+func (s *ReadState) IterateStreamEvents(startHeight, endHeight *uint64, consumer func(*exec.StreamEvent) error) error {
+	start, end := uint64(0), uint64(math.MaxUint64)
+	if startHeight != nil {
+		start = *startHeight
+	}
+	if endHeight != nil {
+		end = *endHeight
+	}
+	plainStart, ok, err := s.plainEventsStart()
+	if err != nil {
+		return err
+	}
+	if !ok || start < plainStart {
+		forestEnd := end
+		if ok && forestEnd >= plainStart {
+			forestEnd = plainStart - 1
+		}
+		err = s.iterateForestEvents(start, forestEnd, consumer)
+		if err != nil || !ok || end < plainStart {
+			return err
 		}
+		start = plainStart
+	}
+	return s.iteratePlain(keys.EventPlain, start, end, func(_, value []byte) error {
+		return readStreamEvents(value, consumer)
 	})
 }
 
+// IterateStreamEventsFor is like IterateStreamEvents but uses the indexes of events stored in the plain to only visit
+// blocks with a log from address having topic in any position (either of which may be nil to match any). Some blocks
+// without matching logs may still be visited (in particular those with events stored in the forest).
+func (s *ReadState) IterateStreamEventsFor(address *crypto.Address, topic *binary.Word256, startHeight, endHeight uint64,
+	consumer func(*exec.StreamEvent) error) error {
+	plainStart, ok, err := s.plainEventsStart()
+	if err != nil {
+		return err
+	}
+	if !ok || (address == nil && topic == nil) {
+		return s.IterateStreamEvents(&startHeight, &endHeight, consumer)
+	}
+	if startHeight < plainStart {
+		forestEnd := endHeight
+		if forestEnd >= plainStart {
+			forestEnd = plainStart - 1
+		}
+		err = s.iterateForestEvents(startHeight, forestEnd, consumer)
+		if err != nil || endHeight < plainStart {
+			return err
+		}
+		startHeight = plainStart
+	}
+	var heights []uint64
+	if address != nil {
+		heights, err = s.indexedHeights(keys.EventAddress.Fix(*address), startHeight, endHeight)
+		if err != nil {
+			return err
+		}
+	}
+	if topic != nil {
+		topicHeights, err := s.indexedHeights(keys.EventTopic.Fix(*topic), startHeight, endHeight)
+		if err != nil {
+			return err
+		}
+		if address != nil {
+			heights = intersectHeights(heights, topicHeights)
+		} else {
+			heights = topicHeights
+		}
+	}
+	for _, height := range heights {
+		bs, err := s.Plain.Get(keys.EventPlain.Key(height))
+		if err != nil {
+			return err
+		}
+		err = readStreamEvents(bs, consumer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *ReadState) TxsAtHeight(height uint64) ([]*exec.TxExecution, error) {
 	const errHeader = "TxAtHeight():"
 	var stack exec.TxStack
@@ -119,12 +283,7 @@ func (s *ReadState) TxByHash(txHash []byte) (*exec.TxExecution, error) {
 		return nil, err
 	}
 
-	blockTree, err := s.Forest.Reader(keys.Event.Prefix())
-	if err != nil {
-		return nil, err
-	}
-
-	bs, err = blockTree.Get(keys.Event.KeyNoPrefix(key.Height))
+	bs, err = s.blockEvents(key.Height)
 	if err != nil {
 		return nil, err
 	} else if len(bs) == 0 {
@@ -151,3 +310,138 @@ func (s *ReadState) TxByHash(txHash []byte) (*exec.TxExecution, error) {
 		}
 	}
 }
+
+// Get the encoded events of the block at height from wherever they are stored
+func (s *ReadState) blockEvents(height uint64) ([]byte, error) {
+	plainStart, ok, err := s.plainEventsStart()
+	if err != nil {
+		return nil, err
+	}
+	if ok && height >= plainStart {
+		return s.Plain.Get(keys.EventPlain.Key(height))
+	}
+	blockTree, err := s.Forest.Reader(keys.Event.Prefix())
+	if err != nil {
+		return nil, err
+	}
+	return blockTree.Get(keys.Event.KeyNoPrefix(height))
+}
+
+func (s *ReadState) iterateForestEvents(startHeight, endHeight uint64, consumer func(*exec.StreamEvent) error) error {
+	tree, err := s.Forest.Reader(keys.Event.Prefix())
+	if err != nil {
+		return err
+	}
+	startKey := keys.Event.KeyNoPrefix(startHeight)
+	var endKey []byte
+	if endHeight < math.MaxUint64 {
+		// Convert to inclusive end bounds since this generally makes more sense for block height
+		endKey = keys.Event.KeyNoPrefix(endHeight + 1)
+	}
+	return tree.Iterate(startKey, endKey, true, func(_, value []byte) error {
+		return readStreamEvents(value, consumer)
+	})
+}
+
+// Iterate over the plain keys with format kf whose final segment is a height within [startHeight, endHeight]
+func (s *ReadState) iteratePlain(kf *storage.MustKeyFormat, startHeight, endHeight uint64,
+	fn func(key, value []byte) error) error {
+	endKey := kf.Prefix().Above()
+	if endHeight < math.MaxUint64 {
+		endKey = kf.Key(endHeight + 1)
+	}
+	it, err := s.Plain.Iterator(kf.Key(startHeight), endKey)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		err = fn(it.Key(), it.Value())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ReadState) indexedHeights(kf *storage.MustKeyFormat, startHeight, endHeight uint64) ([]uint64, error) {
+	var heights []uint64
+	err := s.iteratePlain(kf, startHeight, endHeight, func(key, _ []byte) error {
+		var height uint64
+		err := kf.Scan(key, &height)
+		if err != nil {
+			return err
+		}
+		heights = append(heights, height)
+		return nil
+	})
+	return heights, err
+}
+
+// Returns the first height whose events are stored in the plain, if any are
+func (s *ReadState) plainEventsStart() (uint64, bool, error) {
+	if s.Plain == nil {
+		// As for a ReadState loaded at a height
+		return 0, false, nil
+	}
+	bs, err := s.Plain.Get(keys.EventStart.Key())
+	if err != nil || len(bs) == 0 {
+		return 0, false, err
+	}
+	var height uint64
+	err = keys.EventPlain.ScanNoPrefix(bs, &height)
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// The keys under which the logs in ev are indexed
+func indexKeys(height uint64, ev *exec.StreamEvent) [][]byte {
+	if ev.Event == nil || ev.Event.Log == nil {
+		return nil
+	}
+	log := ev.Event.Log
+	indexes := [][]byte{keys.EventAddress.Key(log.Address, height)}
+	for _, topic := range log.Topics {
+		indexes = append(indexes, keys.EventTopic.Key(topic, height))
+	}
+	return indexes
+}
+
+func readStreamEvents(bs []byte, consumer func(*exec.StreamEvent) error) error {
+	buf := bytes.NewBuffer(bs)
+	for {
+		ev := new(exec.StreamEvent)
+		_, err := encoding.ReadMessage(buf, ev)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		err = consumer(ev)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Both are ascending
+func intersectHeights(xs, ys []uint64) []uint64 {
+	var heights []uint64
+	for i, j := 0, 0; i < len(xs) && j < len(ys); {
+		switch {
+		case xs[i] < ys[j]:
+			i++
+		case xs[i] > ys[j]:
+			j++
+		default:
+			heights = append(heights, xs[i])
+			i++
+			j++
+		}
+	}
+	return heights
+}
@@ -117,6 +117,79 @@ func TestReadState_TxByHash(t *testing.T) {
 	}
 }
 
+func TestReadState_PlainEvents(t *testing.T) {
+	s := NewState(dbm.NewMemDB())
+	numTxs := uint64(2)
+	events := uint64(2)
+	addBlock := func(height uint64) {
+		_, _, err := s.Update(func(ws Updatable) error {
+			return ws.AddBlock(mkBlock(height, numTxs, events))
+		})
+		require.NoError(t, err)
+	}
+	for height := uint64(0); height < 3; height++ {
+		addBlock(height)
+	}
+	require.NoError(t, s.SetEvents(3, &EventsConfig{RetainBlocks: 3}))
+	hash := s.Hash()
+	for height := uint64(3); height < 8; height++ {
+		addBlock(height)
+	}
+	require.Equal(t, hash, s.Hash(), "events in the plain should not change the AppHash")
+
+	heightsOf := func(iterate func(consumer func(*exec.StreamEvent) error) error) []uint64 {
+		var heights []uint64
+		require.NoError(t, iterate(func(ev *exec.StreamEvent) error {
+			if ev.BeginBlock != nil {
+				heights = append(heights, ev.BeginBlock.Height)
+			}
+			return nil
+		}))
+		return heights
+	}
+
+	// Blocks 3 and 4 have dropped out of the retention window
+	require.Equal(t, []uint64{0, 1, 2, 5, 6, 7}, heightsOf(func(consumer func(*exec.StreamEvent) error) error {
+		return s.IterateStreamEvents(nil, nil, consumer)
+	}))
+	start, end := uint64(2), uint64(6)
+	require.Equal(t, []uint64{2, 5, 6}, heightsOf(func(consumer func(*exec.StreamEvent) error) error {
+		return s.IterateStreamEvents(&start, &end, consumer)
+	}))
+
+	for _, height := range []uint64{1, 6} {
+		txe, err := s.TxByHash(mkTx(height, 1, events).TxHash)
+		require.NoError(t, err)
+		require.NotNil(t, txe)
+		require.Equal(t, height, txe.Height)
+	}
+	txe, err := s.TxByHash(mkTx(3, 1, events).TxHash)
+	require.NoError(t, err)
+	require.Nil(t, txe)
+
+	// Blocks in the forest are not indexed so are always visited
+	address := crypto.Address{6, 1}
+	require.Equal(t, []uint64{0, 1, 2, 6}, heightsOf(func(consumer func(*exec.StreamEvent) error) error {
+		return s.IterateStreamEventsFor(&address, nil, 0, 100, consumer)
+	}))
+	topic := binary.Word256{1, 2, 3}
+	require.Equal(t, []uint64{5, 6, 7}, heightsOf(func(consumer func(*exec.StreamEvent) error) error {
+		return s.IterateStreamEventsFor(nil, &topic, 3, 100, consumer)
+	}))
+	require.Equal(t, []uint64{6}, heightsOf(func(consumer func(*exec.StreamEvent) error) error {
+		return s.IterateStreamEventsFor(&address, &topic, 3, 100, consumer)
+	}))
+	address = crypto.Address{4, 0}
+	require.Empty(t, heightsOf(func(consumer func(*exec.StreamEvent) error) error {
+		return s.IterateStreamEventsFor(&address, nil, 3, 100, consumer)
+	}))
+
+	// The genesis must agree with where events have been stored
+	require.Error(t, s.SetEvents(0, nil))
+	require.Error(t, s.SetEvents(4, nil))
+	require.NoError(t, s.SetEvents(3, nil))
+}
+
 func deepCountTxs(txes []*exec.TxExecution) int {
 	sum := len(txes)
 	for _, txe := range txes {
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/encoding"
+	"github.com/hyperledger/burrow/storage"
+)
+
+// GetAccountWithProof returns the account at address (or nil if there is none) with a proof against the state hash.
+// For the proof to be of use the ReadState should be loaded at a fixed height with LoadHeight.
+func (s *ReadState) GetAccountWithProof(address crypto.Address) (*acm.Account, *storage.ForestProof, error) {
+	accBytes, proof, err := s.Forest.GetWithProof(keys.Account.Prefix(), keys.Account.KeyNoPrefix(address))
+	if err != nil {
+		return nil, nil, err
+	} else if accBytes == nil {
+		return nil, proof, nil
+	}
+	account := new(acm.Account)
+	err = encoding.Decode(accBytes, account)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not decode Account: %v", err)
+	}
+	return account, proof, nil
+}
+
+// GetStorageWithProof returns the value stored at key (or nil if there is none) with a proof against the state hash
+func (s *ReadState) GetStorageWithProof(address crypto.Address, key binary.Word256) ([]byte, *storage.ForestProof, error) {
+	keyFormat := keys.Storage.Fix(address)
+	return s.Forest.GetWithProof(keyFormat.Prefix(), keyFormat.KeyNoPrefix(key))
+}
+
+// VerifyAccountProof checks that proof shows account (or its absence if nil) at address in the state with appHash
+func VerifyAccountProof(appHash []byte, address crypto.Address, account *acm.Account, proof *storage.ForestProof) error {
+	err := checkProofPrefix(proof, keys.Account.Prefix())
+	if err != nil {
+		return err
+	}
+	var accBytes []byte
+	if account != nil {
+		if account.Address != address {
+			return fmt.Errorf("account has address %v but proof requested for %v", account.Address, address)
+		}
+		accBytes, err = encoding.Encode(account)
+		if err != nil {
+			return fmt.Errorf("could not encode Account: %v", err)
+		}
+	}
+	return proof.Verify(appHash, keys.Account.KeyNoPrefix(address), accBytes)
+}
+
+// VerifyStorageProof checks that proof shows value (or its absence if empty) at key in the storage of address in the
+// state with appHash
+func VerifyStorageProof(appHash []byte, address crypto.Address, key binary.Word256, value []byte,
+	proof *storage.ForestProof) error {
+	keyFormat := keys.Storage.Fix(address)
+	err := checkProofPrefix(proof, keyFormat.Prefix())
+	if err != nil {
+		return err
+	}
+	if len(value) == 0 {
+		// Zero values are never stored
+		value = nil
+	}
+	return proof.Verify(appHash, keyFormat.KeyNoPrefix(key), value)
+}
+
+func checkProofPrefix(proof *storage.ForestProof, prefix []byte) error {
+	if proof == nil {
+		return fmt.Errorf("proof is nil")
+	}
+	if !bytes.Equal(proof.Prefix, prefix) {
+		return fmt.Errorf("proof is for tree %X but expected %X", proof.Prefix, prefix)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package state
+
+import (
+	"fmt"
+)
+
+const (
+	DefaultPruningKeepRecent = 1000
+	DefaultPruningKeepEvery  = 10000
+)
+
+// PruningConfig determines which historical versions of state are kept for LoadHeight. Versions that are not kept are
+// deleted from the database after each commit.
+type PruningConfig struct {
+	// Keep every version of state - when set KeepRecent and KeepEvery are ignored
+	Archive bool
+	// Keep this many of the most recent heights (at least one)
+	KeepRecent uint64
+	// Also keep every height that is a multiple of KeepEvery (none if zero)
+	KeepEvery uint64
+}
+
+func DefaultPruningConfig() *PruningConfig {
+	return &PruningConfig{
+		Archive:    true,
+		KeepRecent: DefaultPruningKeepRecent,
+		KeepEvery:  DefaultPruningKeepEvery,
+	}
+}
+
+// Sets the pruning policy to apply on each commit
+func (s *State) SetPruning(conf *PruningConfig) {
+	s.Lock()
+	defer s.Unlock()
+	s.pruning = conf
+}
+
+// Delete any versions that have dropped out of the policy now that we have committed version. Loading a height
+// rebuilds the validator ring from the preceding versions so we keep those too.
+func (s *State) prune(version int64) error {
+	if s.pruning == nil || s.pruning.Archive {
+		return nil
+	}
+	keepRecent := int64(s.pruning.KeepRecent)
+	if keepRecent < 1 {
+		keepRecent = 1
+	}
+	before := version - keepRecent + 1 - DefaultValidatorsWindowSize
+	s.pinMtx.Lock()
+	for from := range s.pinned {
+		if from < before {
+			before = from
+		}
+	}
+	s.pinMtx.Unlock()
+	err := s.writeState.forest.Prune(before, s.pruning.retains)
+	if err != nil {
+		return fmt.Errorf("could not prune state: %v", err)
+	}
+	return nil
+}
+
+// Whether version is needed to load a height that is a multiple of KeepEvery
+func (pc *PruningConfig) retains(version int64) bool {
+	if pc.KeepEvery == 0 {
+		return false
+	}
+	height := HeightAtVersion(version)
+	return (pc.KeepEvery-height%pc.KeepEvery)%pc.KeepEvery <= DefaultValidatorsWindowSize
+}
+
+// Stop the versions needed to load height being pruned until the returned function is called, so that they can be read
+// without holding the write lock
+func (s *State) pin(height uint64) (unpin func(), err error) {
+	// Commits prune while holding the write lock, so none can prune height between checking it and pinning it
+	s.Lock()
+	defer s.Unlock()
+	err = s.checkRetained(height)
+	if err != nil {
+		return nil, err
+	}
+	from := VersionAtHeight(height) - DefaultValidatorsWindowSize
+	s.pinMtx.Lock()
+	defer s.pinMtx.Unlock()
+	if s.pinned == nil {
+		s.pinned = make(map[int64]int)
+	}
+	s.pinned[from]++
+	return func() {
+		s.pinMtx.Lock()
+		defer s.pinMtx.Unlock()
+		s.pinned[from]--
+		if s.pinned[from] == 0 {
+			delete(s.pinned, from)
+		}
+	}, nil
+}
+
+// Check that the versions needed to load version are still held
+func (s *State) checkRetained(height uint64) error {
+	version := VersionAtHeight(height)
+	last := s.Version()
+	if version > last {
+		return fmt.Errorf("cannot load state at height %d since it is beyond the last height %d",
+			height, HeightAtVersion(last))
+	}
+	start := version - DefaultValidatorsWindowSize
+	if start < 1 {
+		start = 1
+	}
+	for v := start; v <= version; v++ {
+		if !s.writeState.forest.VersionExists(v) {
+			return fmt.Errorf("cannot load state at height %d since it has been pruned", height)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/burrow/storage"
+)
+
+// Snapshot calls fn with every entry of the state database needed to load the state at height: the versions of the
+// forest that LoadState reads (the height and the validator window before it) and the ABIs kept on the plain. Keys
+// are relative to the state database, so writing the entries to an empty database and calling LoadState at the
+// height's version reproduces the state with the same hash. Historical events and transaction hashes are not included.
+// The versions being exported are pinned against pruning rather than locked, so blocks can carry on being committed
+// while fn is called.
+func (s *State) Snapshot(height uint64, fn func(key, value []byte) error) error {
+	unpin, err := s.pin(height)
+	if err != nil {
+		return err
+	}
+	defer unpin()
+	version := VersionAtHeight(height)
+	forest := storage.Prefix(forestPrefix)
+	err = s.writeState.forest.Export(version-DefaultValidatorsWindowSize, version, func(key, value []byte) error {
+		return fn(forest.Key(key), value)
+	})
+	if err != nil {
+		return fmt.Errorf("could not snapshot state at height %d: %v", height, err)
+	}
+	plain := storage.Prefix(plainPrefix)
+	it, err := s.Plain.Iterator(keys.Abi.Prefix(), keys.Abi.Prefix().Above())
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		err = fn(plain.Key(it.Key()), it.Value())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -26,6 +26,8 @@ import (
 )
 
 const (
+	// Name of the database holding state and blockchain metadata within the node's database directory
+	BurrowDBName                = "burrow_state"
 	DefaultValidatorsWindowSize = 10
 	defaultCacheCapacity        = 1024
 	uint64Length                = 8
@@ -38,6 +40,7 @@ const (
 
 // Implements account and blockchain state
 var _ acmstate.IterableReader = &State{}
+var _ acmstate.StorageRangeIterable = &ReadState{}
 var _ names.IterableReader = &State{}
 var _ Updatable = &writeState{}
 
@@ -51,6 +54,12 @@ type KeyFormatStore struct {
 	Registry  *storage.MustKeyFormat
 	TxHash    *storage.MustKeyFormat
 	Abi       *storage.MustKeyFormat
+	// Events kept out of the forest
+	EventPlain   *storage.MustKeyFormat
+	EventStart   *storage.MustKeyFormat
+	EventAddress *storage.MustKeyFormat
+	EventTopic   *storage.MustKeyFormat
+	PlainVersion *storage.MustKeyFormat
 }
 
 var keys = KeyFormatStore{
@@ -75,6 +84,16 @@ var keys = KeyFormatStore{
 	TxHash: storage.NewMustKeyFormat("th", txs.HashLength),
 	// CodeHash -> Abi
 	Abi: storage.NewMustKeyFormat("abi", sha256.Size),
+	// Height -> StreamEvent
+	EventPlain: storage.NewMustKeyFormat("ev", uint64Length),
+	// -> Height of first block with events on the plain
+	EventStart: storage.NewMustKeyFormat("es"),
+	// LogAddress, Height -> nil
+	EventAddress: storage.NewMustKeyFormat("ea", crypto.AddressLength, uint64Length),
+	// LogTopic, Height -> nil
+	EventTopic: storage.NewMustKeyFormat("et", binary.Word256Bytes, uint64Length),
+	// -> Version of the last commit to write to the plain
+	PlainVersion: storage.NewMustKeyFormat("cv"),
 }
 
 var Prefixes [][]byte
@@ -104,6 +123,9 @@ type writeState struct {
 	ring         *validator.Ring
 	accountStats acmstate.AccountStats
 	nodeStats    registry.NodeStats
+	events       EventsConfig
+	// Events from blocks at or above this height are stored in the plain (never if zero)
+	plainEventsHeight uint64
 }
 
 type ReadState struct {
@@ -118,7 +140,11 @@ type State struct {
 	db dbm.DB
 	ReadState
 	writeState writeState
-	logger     *logging.Logger
+	pruning    *PruningConfig
+	// Guards pinned, the number of readers pinning each first version of a validator window against pruning
+	pinMtx sync.Mutex
+	pinned map[int64]int
+	logger *logging.Logger
 }
 
 // NewState creates a new State object
@@ -248,6 +274,10 @@ func (s *State) Hash() []byte {
 }
 
 func (s *State) LoadHeight(height uint64) (*ReadState, error) {
+	err := s.checkRetained(height)
+	if err != nil {
+		return nil, err
+	}
 	version := VersionAtHeight(height)
 	forest, err := s.writeState.forest.GetImmutable(version)
 	if err != nil {
@@ -276,11 +306,20 @@ func (s *State) Update(updater func(up Updatable) error) ([]byte, int64, error)
 }
 
 func (s *State) commit() ([]byte, int64, error) {
+	// The plain has already been written so record it as being at the version we are about to save before the forest
+	err := s.writeState.plain.SetSync(keys.PlainVersion.Key(), encodeVersion(s.writeState.forest.Version()+1))
+	if err != nil {
+		return nil, 0, err
+	}
 	// save state at a new version may still be orphaned before we save the version against the hash
 	hash, version, err := s.writeState.forest.Save()
 	if err != nil {
 		return nil, 0, err
 	}
+	err = s.db.SetSync(forestVersionKey, encodeVersion(version))
+	if err != nil {
+		return nil, 0, err
+	}
 	totalPowerChange, totalFlow, err := s.writeState.ring.Rotate()
 	if err != nil {
 		return nil, 0, err
@@ -289,7 +328,11 @@ func (s *State) commit() ([]byte, int64, error) {
 		//noinspection ALL
 		s.logger.InfoMsg("validator set changes", "total_power_change", totalPowerChange, "total_flow", totalFlow)
 	}
-	return hash, version, err
+	err = s.prune(version)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash, version, nil
 }
 
 // Creates a copy of the database to the supplied db
@@ -15,9 +15,12 @@
 package state
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/config/source"
 	"github.com/hyperledger/burrow/permission"
 	"github.com/stretchr/testify/assert"
@@ -40,3 +43,164 @@ func TestState_UpdateAccount(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, source.JSONString(account), source.JSONString(accountOut))
 }
+
+func TestState_IterateStorageRange(t *testing.T) {
+	s := NewState(dbm.NewMemDB())
+	account := acm.NewAccountFromSecret("Foo")
+	_, _, err := s.Update(func(ws Updatable) error {
+		err := ws.UpdateAccount(account)
+		if err != nil {
+			return err
+		}
+		for i := byte(1); i <= 10; i++ {
+			word := binary.LeftPadWord256([]byte{i})
+			err = ws.SetStorage(account.Address, word, word.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	collect := func(start, end byte) []byte {
+		var high *binary.Word256
+		if end > 0 {
+			word := binary.LeftPadWord256([]byte{end})
+			high = &word
+		}
+		var keys []byte
+		err := s.IterateStorageRange(account.Address, binary.LeftPadWord256([]byte{start}), high,
+			func(key binary.Word256, value []byte) error {
+				keys = append(keys, key[binary.Word256Bytes-1])
+				return nil
+			})
+		require.NoError(t, err)
+		return keys
+	}
+	assert.Equal(t, []byte{3, 4, 5, 6}, collect(3, 7))
+	assert.Equal(t, []byte{8, 9, 10}, collect(8, 0))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, collect(0, 0))
+	assert.Empty(t, collect(11, 0))
+}
+
+func TestState_GetWithProof(t *testing.T) {
+	s := NewState(dbm.NewMemDB())
+	account := acm.NewAccountFromSecret("Foo")
+	key := binary.LeftPadWord256([]byte{1})
+	value := binary.LeftPadBytes([]byte{42}, binary.Word256Bytes)
+	_, _, err := s.Update(func(ws Updatable) error {
+		err := ws.UpdateAccount(account)
+		if err != nil {
+			return err
+		}
+		return ws.SetStorage(account.Address, key, value)
+	})
+	require.NoError(t, err)
+
+	accountOut, proof, err := s.GetAccountWithProof(account.Address)
+	require.NoError(t, err)
+	require.NoError(t, VerifyAccountProof(s.Hash(), account.Address, accountOut, proof))
+	assert.Error(t, VerifyAccountProof(s.Hash(), account.Address, nil, proof))
+
+	missing := acm.NewAccountFromSecret("Bar").Address
+	accountOut, proof, err = s.GetAccountWithProof(missing)
+	require.NoError(t, err)
+	assert.Nil(t, accountOut)
+	require.NoError(t, VerifyAccountProof(s.Hash(), missing, nil, proof))
+
+	valueOut, proof, err := s.GetStorageWithProof(account.Address, key)
+	require.NoError(t, err)
+	assert.Equal(t, value, valueOut)
+	require.NoError(t, VerifyStorageProof(s.Hash(), account.Address, key, valueOut, proof))
+	assert.Error(t, VerifyStorageProof(s.Hash(), account.Address, binary.Zero256, valueOut, proof))
+	assert.Error(t, VerifyStorageProof(s.Hash(), missing, key, valueOut, proof))
+}
+
+func TestState_Pruning(t *testing.T) {
+	s := NewState(dbm.NewMemDB())
+	s.SetPruning(&PruningConfig{KeepRecent: 2, KeepEvery: 20})
+	account := acm.NewAccountFromSecret("Foo")
+	for height := uint64(0); height < 40; height++ {
+		account.Balance = height
+		_, _, err := s.Update(func(ws Updatable) error {
+			return ws.UpdateAccount(account)
+		})
+		require.NoError(t, err)
+	}
+
+	for _, height := range []uint64{0, 20, 38, 39} {
+		st, err := s.LoadHeight(height)
+		require.NoError(t, err)
+		accountOut, err := st.GetAccount(account.Address)
+		require.NoError(t, err)
+		assert.Equal(t, height, accountOut.Balance)
+	}
+
+	for _, height := range []uint64{5, 25, 30} {
+		_, err := s.LoadHeight(height)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pruned")
+	}
+
+	_, err := s.LoadHeight(40)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "beyond")
+}
+
+func TestState_SnapshotPinsHeight(t *testing.T) {
+	s := NewState(dbm.NewMemDB())
+	s.SetPruning(&PruningConfig{KeepRecent: 1})
+	account := acm.NewAccountFromSecret("Foo")
+	update := func() error {
+		account.Balance++
+		_, _, err := s.Update(func(ws Updatable) error {
+			return ws.UpdateAccount(account)
+		})
+		return err
+	}
+	for height := 0; height < 15; height++ {
+		require.NoError(t, update())
+	}
+	rs, err := s.LoadHeight(14)
+	require.NoError(t, err)
+	hash := rs.Forest.Hash()
+
+	// Commits carry on while the snapshot is taken but do not prune the height being exported
+	db := dbm.NewMemDB()
+	started := false
+	err = s.Snapshot(14, func(key, value []byte) error {
+		if !started {
+			started = true
+			committed := make(chan error)
+			go func() {
+				for i := 0; i < 3; i++ {
+					if err := update(); err != nil {
+						committed <- err
+						return
+					}
+				}
+				committed <- nil
+			}()
+			select {
+			case err := <-committed:
+				if err != nil {
+					return err
+				}
+			case <-time.After(10 * time.Second):
+				return fmt.Errorf("commits blocked by snapshot")
+			}
+		}
+		return db.Set(key, value)
+	})
+	require.NoError(t, err)
+	restored, err := LoadState(db, VersionAtHeight(14))
+	require.NoError(t, err)
+	assert.Equal(t, hash, restored.Hash())
+
+	// Once the snapshot is done the height can be pruned
+	require.NoError(t, update())
+	_, err = s.LoadHeight(14)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pruned")
+}
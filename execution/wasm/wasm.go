@@ -1,27 +1,170 @@
 package wasm
 
 import (
+	"encoding/binary"
 	"fmt"
 
+	"github.com/hyperledger/burrow/acm"
 	"github.com/hyperledger/burrow/acm/acmstate"
 	burrow_binary "github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/engine"
 	"github.com/hyperledger/burrow/execution/errors"
-	"github.com/perlin-network/life/exec"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/execution/native"
+	"github.com/hyperledger/burrow/permission"
+	"github.com/perlin-network/life/compiler"
+	lifeExec "github.com/perlin-network/life/exec"
+	"github.com/perlin-network/life/utils"
 )
 
+const (
+	// Memory is allocated in pages of 64KiB
+	DefaultMaxMemoryPages = 2
+	// Every WASM instruction is charged at this rate
+	GasPerInstruction uint64 = 1
+	// Data copied into memory by host functions is charged for each 32-byte word
+	GasPerCopyWord uint64 = 1
+	// Logs are charged for each topic and each 32-byte word of data
+	GasPerLogTopic uint64 = 1
+	GasPerLogWord  uint64 = 1
+	// Contracts (e.g. those compiled by solang) start with this much memory unless the limit is lower
+	initialMemoryPages = 2
+	// ewasm passes values as 128-bit little-endian integers
+	valueLength = 16
+	// Return codes for the call family as defined by ewasm
+	callSuccess = 0
+	callFailure = 1
+	callRevert  = 2
+	// Block hashes are only available for recent blocks (as for the EVM)
+	maximumAllowedBlockLookBack = 256
+)
+
+// Implements ewasm, see https://github.com/ewasm/design
+
+type WVM struct {
+	options Options
+	// Provide any foreign dispatchers to allow calls between VMs
+	externals engine.Dispatcher
+}
+
+// Options are parameters that are generally stable across a burrow configuration.
+// Defaults will be used for any zero values.
+type Options struct {
+	// The most pages of linear memory a contract may use
+	MaxMemoryPages    uint64
+	CallStackMaxDepth uint64
+	Natives           *native.Natives
+}
+
+func New(options Options) *WVM {
+	// Set defaults
+	if options.MaxMemoryPages == 0 {
+		options.MaxMemoryPages = DefaultMaxMemoryPages
+	}
+	if options.Natives == nil {
+		options.Natives = native.MustDefaultNatives()
+	}
+	vm := &WVM{
+		options: options,
+	}
+	// As for the EVM we connect natives here but a caller may also Connect us to other engines
+	engine.Connect(vm, options.Natives)
+	return vm
+}
+
+func Default() *WVM {
+	return New(Options{})
+}
+
+// Initiate a WASM call against the provided state pushing events to eventSink. code should contain the WASM module,
+// which is run from its exported main function.
+func (vm *WVM) Execute(st acmstate.ReaderWriter, blockchain engine.Blockchain, eventSink exec.EventSink,
+	params engine.CallParams, code []byte) ([]byte, error) {
+
+	// Make it appear as if natives are stored in state
+	st = native.NewState(vm.options.Natives, st)
+
+	state := engine.State{
+		CallFrame:  engine.NewCallFrame(st).WithMaxCallStackDepth(vm.options.CallStackMaxDepth),
+		Blockchain: blockchain,
+		EventSink:  eventSink,
+	}
+	output, err := vm.Contract(code).Call(state, params)
+	if err == nil {
+		// Only sync back when there was no exception
+		err = state.CallFrame.Sync()
+	}
+	// Always return output - we may have a reverted exception for which the return is meaningful
+	return output, err
+}
+
+// Dispatch returns a Callable for accounts with WASM code only so that other engines can fall back to their own
+func (vm *WVM) Dispatch(acc *acm.Account) engine.Callable {
+	if len(acc.WASMCode) == 0 {
+		return nil
+	}
+	return vm.Contract(acc.WASMCode)
+}
+
+func (vm *WVM) SetExternals(externals engine.Dispatcher) {
+	vm.externals = externals
+}
+
+func (vm *WVM) Contract(code []byte) *Contract {
+	return &Contract{
+		vm:   vm,
+		code: code,
+	}
+}
+
+// Find a Callable for an account called from WASM, which may be hosted by another engine
+func (vm *WVM) dispatch(acc *acm.Account) engine.Callable {
+	callable := vm.Dispatch(acc)
+	if callable != nil {
+		return callable
+	}
+	if vm.externals != nil {
+		callable = vm.externals.Dispatch(acc)
+		if callable != nil {
+			return callable
+		}
+	}
+	if len(acc.EVMCode) != 0 {
+		// There is no EVM to run this
+		return nil
+	}
+	// Calls to accounts without code just transfer value
+	return engine.CallableFunc(func(st engine.State, params engine.CallParams) ([]byte, error) {
+		return native.Call(st, params, func(engine.State, engine.CallParams) ([]byte, error) {
+			return nil, nil
+		})
+	})
+}
+
+// A Contract is some WASM code that can be called from within an engine.State
+type Contract struct {
+	vm   *WVM
+	code []byte
+}
+
+func (c *Contract) Call(state engine.State, params engine.CallParams) ([]byte, error) {
+	return native.Call(state, params, c.execute)
+}
+
 type execContext struct {
 	errors.Maybe
-	address crypto.Address
-	input   []byte
-	output  []byte
-	state   acmstate.ReaderWriter
+	vm         *WVM
+	code       []byte
+	output     []byte
+	returnData []byte
+	params     engine.CallParams
+	state      engine.State
+	// The VM's count of gas used by instructions as of the last time we charged for them
+	instructionGas uint64
 }
 
-// Implements ewasm, see https://github.com/ewasm/design
-
-// RunWASM creates a WASM VM, and executes the given WASM contract code
-func RunWASM(state acmstate.ReaderWriter, address crypto.Address, createContract bool, wasm, input []byte) (output []byte, cerr error) {
+func (c *Contract) execute(state engine.State, params engine.CallParams) (output []byte, cerr error) {
 	const errHeader = "ewasm"
 	defer func() {
 		if r := recover(); r != nil {
@@ -29,21 +172,36 @@ func RunWASM(state acmstate.ReaderWriter, address crypto.Address, createContract
 		}
 	}()
 
+	// The VM treats a zero gas limit as no limit at all
+	if *params.Gas == 0 {
+		return nil, errors.Codes.InsufficientGas
+	}
+
 	// WASM
-	config := exec.VMConfig{
+	config := lifeExec.VMConfig{
 		DisableFloatingPoint: true,
-		MaxMemoryPages:       2,
-		DefaultMemoryPages:   2,
+		MaxMemoryPages:       int(c.vm.options.MaxMemoryPages),
+		DefaultMemoryPages:   initialMemoryPages,
+		GasLimit:             *params.Gas,
+		// Otherwise the VM panics without recording the gas it used
+		ReturnOnGasLimitExceeded: true,
+	}
+	if config.DefaultMemoryPages > config.MaxMemoryPages {
+		config.DefaultMemoryPages = config.MaxMemoryPages
 	}
 
 	execContext := execContext{
-		address: address,
-		state:   state,
-		input:   input,
+		vm:     c.vm,
+		code:   c.code,
+		params: params,
+		state:  state,
 	}
 
+	// The compiled code is instrumented to count gas as it runs and will abort once it exceeds the GasLimit
+	gasPolicy := &compiler.SimpleGasPolicy{GasPerInstruction: int64(GasPerInstruction)}
+
 	// panics in ResolveFunc() will be recovered for us, no need for our own
-	vm, err := exec.NewVirtualMachine(wasm, config, &execContext, nil)
+	vm, err := lifeExec.NewVirtualMachine(c.code, config, &execContext, gasPolicy)
 	if err != nil {
 		return nil, errors.Errorf(errors.Codes.InvalidContract, "%s: %v", errHeader, err)
 	}
@@ -56,88 +214,393 @@ func RunWASM(state acmstate.ReaderWriter, address crypto.Address, createContract
 		return nil, errors.Codes.UnresolvedSymbols
 	}
 
-	_, err = vm.Run(entryID)
-	if err != nil && errors.GetCode(err) != errors.Codes.None {
-		return nil, errors.Errorf(errors.Codes.ExecutionAborted, "%s: %v", errHeader, err)
+	err = execContext.run(vm, entryID)
+	if err == errors.Codes.InsufficientGas {
+		return nil, err
+	}
+	// Charge for any instructions run since the last host function call
+	gasErr := execContext.chargeInstructions(vm)
+	if gasErr != nil {
+		return nil, gasErr
+	}
+	if execContext.Error() != nil {
+		return nil, execContext.Error()
+	}
+	if err != nil {
+		switch errors.GetCode(err) {
+		case errors.Codes.None:
+		case errors.Codes.ExecutionReverted:
+			// Revert output is meaningful to the caller
+			return execContext.output, errors.Codes.ExecutionReverted
+		case errors.Codes.Generic:
+			return nil, errors.Errorf(errors.Codes.ExecutionAborted, "%s: %v", errHeader, err)
+		default:
+			return nil, err
+		}
 	}
 
 	return execContext.output, nil
 }
 
-func (e *execContext) ResolveFunc(module, field string) exec.FunctionImport {
+func (e *execContext) ResolveFunc(module, field string) lifeExec.FunctionImport {
 	if module != "ethereum" {
 		panic(fmt.Sprintf("unknown module %s", module))
 	}
 
+	hostFunc := e.resolveHostFunc(field)
+	return func(vm *lifeExec.VirtualMachine) int64 {
+		// Settle up before and after so that host functions see (and may spend) our true remaining gas and the VM
+		// cannot run past it
+		e.must(e.chargeInstructions(vm))
+		ret := hostFunc(vm)
+		e.must(e.chargeInstructions(vm))
+		return ret
+	}
+}
+
+func (e *execContext) resolveHostFunc(field string) lifeExec.FunctionImport {
 	switch field {
+	case "useGas":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			amount := uint64(vm.GetCurrentFrame().Locals[0])
+
+			e.useGas(amount)
+			return 0
+		}
+
+	case "getGasLeft":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return int64(*e.params.Gas)
+		}
+
+	case "getAddress":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			memoryWrite(vm, resultPtr, e.params.Callee.Bytes())
+			return 0
+		}
+
+	case "getExternalBalance":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+
+			e.useGas(native.GasGetAccount)
+			var balance uint64
+			acc := e.getAccount(readAddress(vm, addressPtr))
+			if acc != nil {
+				balance = acc.Balance
+			}
+			writeValue(vm, resultPtr, balance)
+			return 0
+		}
+
+	case "getBlockHash":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			number := uint64(vm.GetCurrentFrame().Locals[0])
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+
+			lastBlockHeight := e.state.Blockchain.LastBlockHeight()
+			if number >= lastBlockHeight || lastBlockHeight-number > maximumAllowedBlockLookBack {
+				return callFailure
+			}
+			hash, err := e.state.Blockchain.BlockHash(number)
+			if err != nil {
+				return callFailure
+			}
+			memoryWrite(vm, resultPtr, burrow_binary.LeftPadWord256(hash).Bytes())
+			return callSuccess
+		}
+
+	case "call":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			gasLimit := uint64(vm.GetCurrentFrame().Locals[0])
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			valuePtr := int(uint32(vm.GetCurrentFrame().Locals[2]))
+			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[3]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[4]))
+
+			return e.call(exec.CallTypeCall, gasLimit, readAddress(vm, addressPtr), readValue(vm, valuePtr),
+				memoryRead(vm, dataPtr, dataLen))
+		}
+
+	case "callCode":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			gasLimit := uint64(vm.GetCurrentFrame().Locals[0])
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			valuePtr := int(uint32(vm.GetCurrentFrame().Locals[2]))
+			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[3]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[4]))
+
+			return e.call(exec.CallTypeCode, gasLimit, readAddress(vm, addressPtr), readValue(vm, valuePtr),
+				memoryRead(vm, dataPtr, dataLen))
+		}
+
+	case "callDelegate":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			gasLimit := uint64(vm.GetCurrentFrame().Locals[0])
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[2]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[3]))
+
+			return e.call(exec.CallTypeDelegate, gasLimit, readAddress(vm, addressPtr), e.params.Value,
+				memoryRead(vm, dataPtr, dataLen))
+		}
+
+	case "callStatic":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			gasLimit := uint64(vm.GetCurrentFrame().Locals[0])
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[2]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[3]))
+
+			return e.call(exec.CallTypeStatic, gasLimit, readAddress(vm, addressPtr), 0,
+				memoryRead(vm, dataPtr, dataLen))
+		}
+
+	case "create":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			valuePtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[2]))
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[3]))
+
+			address, result := e.create(readValue(vm, valuePtr), memoryRead(vm, dataPtr, dataLen))
+			if result == callSuccess {
+				memoryWrite(vm, resultPtr, address.Bytes())
+			}
+			return result
+		}
+
 	case "getCallDataSize":
-		return func(vm *exec.VirtualMachine) int64 {
-			return int64(len(e.input))
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return int64(len(e.params.Input))
 		}
 
 	case "callDataCopy":
-		return func(vm *exec.VirtualMachine) int64 {
+		return func(vm *lifeExec.VirtualMachine) int64 {
 			destPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
 			dataOffset := int(uint32(vm.GetCurrentFrame().Locals[1]))
 			dataLen := int(uint32(vm.GetCurrentFrame().Locals[2]))
 
 			if dataLen > 0 {
-				copy(vm.Memory[destPtr:], e.input[dataOffset:dataOffset+dataLen])
+				e.copyToMemory(vm, destPtr, e.params.Input, dataOffset, dataLen)
 			}
 
 			return 0
 		}
 
+	case "getCodeSize":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return int64(len(e.code))
+		}
+
+	case "codeCopy":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			destPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+			codeOffset := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			codeLen := int(uint32(vm.GetCurrentFrame().Locals[2]))
+
+			e.copyToMemory(vm, destPtr, e.code, codeOffset, codeLen)
+			return 0
+		}
+
+	case "getExternalCodeSize":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			e.useGas(native.GasGetAccount)
+			return int64(len(accountCode(e.getAccount(readAddress(vm, addressPtr)))))
+		}
+
+	case "externalCodeCopy":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+			destPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			codeOffset := int(uint32(vm.GetCurrentFrame().Locals[2]))
+			codeLen := int(uint32(vm.GetCurrentFrame().Locals[3]))
+
+			e.useGas(native.GasGetAccount)
+			code := accountCode(e.getAccount(readAddress(vm, addressPtr)))
+			e.copyToMemory(vm, destPtr, code, codeOffset, codeLen)
+			return 0
+		}
+
+	case "getCaller":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			memoryWrite(vm, resultPtr, e.params.Caller.Bytes())
+			return 0
+		}
+
+	case "getCallValue":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			writeValue(vm, resultPtr, e.params.Value)
+			return 0
+		}
+
+	case "getTxOrigin":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			memoryWrite(vm, resultPtr, e.params.Origin.Bytes())
+			return 0
+		}
+
+	case "getTxGasPrice":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			writeValue(vm, resultPtr, e.params.GasPrice)
+			return 0
+		}
+
+	case "getBlockCoinbase":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			// Not supported
+			memoryWrite(vm, resultPtr, crypto.ZeroAddress.Bytes())
+			return 0
+		}
+
+	case "getBlockDifficulty":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			resultPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			// Not supported
+			memoryWrite(vm, resultPtr, burrow_binary.Zero256.Bytes())
+			return 0
+		}
+
+	case "getBlockGasLimit":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return int64(*e.params.Gas)
+		}
+
+	case "getBlockNumber":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return int64(e.state.Blockchain.LastBlockHeight())
+		}
+
+	case "getBlockTimestamp":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return e.state.Blockchain.LastBlockTime().Unix()
+		}
+
+	case "log":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			numberOfTopics := int(uint32(vm.GetCurrentFrame().Locals[2]))
+
+			if numberOfTopics > 4 {
+				panic(errors.Errorf(errors.Codes.InputOutOfBounds, "log can have at most 4 topics but got %d",
+					numberOfTopics))
+			}
+			topics := make([]burrow_binary.Word256, numberOfTopics)
+			for i := range topics {
+				topicPtr := int(uint32(vm.GetCurrentFrame().Locals[3+i]))
+				copy(topics[i][:], memoryRead(vm, topicPtr, burrow_binary.Word256Bytes))
+			}
+			logged := memoryRead(vm, dataPtr, dataLen)
+			e.useGas(GasPerLogTopic*uint64(numberOfTopics) + GasPerLogWord*uint64((dataLen+31)/32))
+			data := make([]byte, dataLen)
+			copy(data, logged)
+
+			e.Void(e.state.EventSink.Log(&exec.LogEvent{
+				Address: e.params.Callee,
+				Topics:  topics,
+				Data:    data,
+			}))
+			return 0
+		}
+
 	case "storageStore":
-		return func(vm *exec.VirtualMachine) int64 {
+		return func(vm *lifeExec.VirtualMachine) int64 {
 			keyPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
 			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
 
 			key := burrow_binary.Word256{}
 
-			copy(key[:], vm.Memory[keyPtr:keyPtr+32])
+			copy(key[:], memoryRead(vm, keyPtr, 32))
+			value := make([]byte, 32)
+			copy(value, memoryRead(vm, dataPtr, 32))
 
-			e.Void(e.state.SetStorage(e.address, key, vm.Memory[dataPtr:dataPtr+32]))
+			e.useGas(native.GasStorageUpdate)
+			e.Void(e.state.CallFrame.SetStorage(e.params.Callee, key, value))
 			return 0
 		}
 
 	case "storageLoad":
-		return func(vm *exec.VirtualMachine) int64 {
+		return func(vm *lifeExec.VirtualMachine) int64 {
 
 			keyPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
 			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[1]))
 
 			key := burrow_binary.Word256{}
 
-			copy(key[:], vm.Memory[keyPtr:keyPtr+32])
+			copy(key[:], memoryRead(vm, keyPtr, 32))
+
+			e.useGas(native.GasStorageRead)
+			val := e.Bytes(e.state.CallFrame.GetStorage(e.params.Callee, key))
+			memoryWrite(vm, dataPtr, burrow_binary.LeftPadWord256(val).Bytes())
 
-			val := e.Bytes(e.state.GetStorage(e.address, key))
-			copy(vm.Memory[dataPtr:], val)
+			return 0
+		}
+
+	case "getReturnDataSize":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			return int64(len(e.returnData))
+		}
+
+	case "returnDataCopy":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			destPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+			dataOffset := int(uint32(vm.GetCurrentFrame().Locals[1]))
+			dataLen := int(uint32(vm.GetCurrentFrame().Locals[2]))
 
+			if dataOffset+dataLen > len(e.returnData) {
+				panic(errors.Codes.ReturnDataOutOfBounds)
+			}
+			e.copyToMemory(vm, destPtr, e.returnData, dataOffset, dataLen)
 			return 0
 		}
 
 	case "finish":
-		return func(vm *exec.VirtualMachine) int64 {
+		return func(vm *lifeExec.VirtualMachine) int64 {
 			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
 			dataLen := int(uint32(vm.GetCurrentFrame().Locals[1]))
 
-			e.output = vm.Memory[dataPtr : dataPtr+dataLen]
+			e.output = memoryRead(vm, dataPtr, dataLen)
 
 			panic(errors.Codes.None)
 		}
 
 	case "revert":
-		return func(vm *exec.VirtualMachine) int64 {
+		return func(vm *lifeExec.VirtualMachine) int64 {
 
 			dataPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
 			dataLen := int(uint32(vm.GetCurrentFrame().Locals[1]))
 
-			e.output = vm.Memory[dataPtr : dataPtr+dataLen]
+			e.output = memoryRead(vm, dataPtr, dataLen)
 
 			panic(errors.Codes.ExecutionReverted)
 		}
 
+	case "selfDestruct":
+		return func(vm *lifeExec.VirtualMachine) int64 {
+			addressPtr := int(uint32(vm.GetCurrentFrame().Locals[0]))
+
+			e.selfDestruct(readAddress(vm, addressPtr))
+
+			panic(errors.Codes.None)
+		}
+
 	default:
 		panic(fmt.Sprintf("unknown function %s", field))
 	}
@@ -146,3 +609,274 @@ func (e *execContext) ResolveFunc(module, field string) exec.FunctionImport {
 func (e *execContext) ResolveGlobal(module, field string) int64 {
 	panic(fmt.Sprintf("global %s module %s not found", field, module))
 }
+
+func (e *execContext) call(callType exec.CallType, gasLimit uint64, target crypto.Address, value uint64,
+	input []byte) int64 {
+	e.returnData = nil
+	e.must(ensurePermission(e.state.CallFrame, e.params.Callee, permission.Call))
+
+	e.useGas(native.GasGetAccount)
+	acc := e.getAccount(target)
+	if acc == nil {
+		if callType != exec.CallTypeCall {
+			return callFailure
+		}
+		// We're sending funds to a new account so we must create it first
+		e.must(ensurePermission(e.state.CallFrame, e.params.Callee, permission.CreateAccount))
+		e.must(native.CreateAccount(e.state.CallFrame, target))
+		acc = e.getAccount(target)
+	}
+	callable := e.vm.dispatch(acc)
+	if callable == nil {
+		return callFailure
+	}
+
+	childCallFrame, err := e.state.CallFrame.NewFrame()
+	e.must(err)
+	childState := engine.State{
+		CallFrame:  childCallFrame,
+		Blockchain: e.state.Blockchain,
+		EventSink:  e.state.EventSink,
+	}
+
+	// As for the EVM we pass on at most all but one 64th of the remaining gas
+	if *e.params.Gas < gasLimit {
+		gasLimit = *e.params.Gas - *e.params.Gas/64
+	}
+	*e.params.Gas -= gasLimit
+
+	calleeParams := engine.CallParams{
+		CallType: callType,
+		Origin:   e.params.Origin,
+		Caller:   e.params.Callee,
+		Callee:   target,
+		Input:    input,
+		Value:    value,
+		Gas:      &gasLimit,
+		GasPrice: e.params.GasPrice,
+	}
+	switch callType {
+	case exec.CallTypeStatic:
+		childState.CallFrame.ReadOnly()
+		childState.EventSink = exec.NewLogFreeEventSink(childState.EventSink)
+	case exec.CallTypeCode:
+		// Run the code at target against our own storage
+		calleeParams.Callee = e.params.Callee
+	case exec.CallTypeDelegate:
+		// As above but also preserving our own caller
+		calleeParams.Caller = e.params.Caller
+		calleeParams.Callee = e.params.Callee
+	}
+
+	var callErr error
+	e.returnData, callErr = callable.Call(childState, calleeParams)
+	*e.params.Gas += *calleeParams.Gas
+
+	if callErr != nil {
+		if errors.GetCode(callErr) == errors.Codes.ExecutionReverted {
+			return callRevert
+		}
+		return callFailure
+	}
+	// Sync error is a hard stop
+	e.must(childState.CallFrame.Sync())
+	return callSuccess
+}
+
+func (e *execContext) create(value uint64, code []byte) (crypto.Address, int64) {
+	e.returnData = nil
+	e.useGas(native.GasCreateAccount)
+	e.must(ensurePermission(e.state.CallFrame, e.params.Callee, permission.CreateContract))
+
+	// Like Ethereum the new address is derived from the creator and its sequence number which we bump
+	nonce := make([]byte, 8)
+	e.must(native.UpdateAccount(e.state.CallFrame, e.params.Callee, func(acc *acm.Account) error {
+		binary.BigEndian.PutUint64(nonce, acc.Sequence)
+		acc.Sequence++
+		return nil
+	}))
+	address := crypto.NewContractAddress(e.params.Callee, nonce)
+
+	// Establish a frame in which the putative account exists
+	childCallFrame, err := e.state.CallFrame.NewFrame()
+	e.must(err)
+	e.must(native.CreateAccount(childCallFrame, address))
+
+	createParams := engine.CallParams{
+		Origin:   e.params.Origin,
+		Caller:   e.params.Callee,
+		Callee:   address,
+		Input:    nil,
+		Value:    value,
+		Gas:      e.params.Gas,
+		GasPrice: e.params.GasPrice,
+	}
+	ret, callErr := e.vm.Contract(code).Call(engine.State{
+		CallFrame:  childCallFrame,
+		Blockchain: e.state.Blockchain,
+		EventSink:  e.state.EventSink,
+	}, createParams)
+	if callErr != nil {
+		e.returnData = ret
+		if errors.GetCode(callErr) == errors.Codes.ExecutionReverted {
+			return address, callRevert
+		}
+		return address, callFailure
+	}
+	// The constructor returns the runtime code
+	e.must(native.InitWASMCode(childCallFrame, address, ret))
+	e.must(childCallFrame.Sync())
+	return address, callSuccess
+}
+
+func (e *execContext) selfDestruct(receiver crypto.Address) {
+	e.useGas(native.GasGetAccount)
+	if e.getAccount(receiver) == nil {
+		e.useGas(native.GasCreateAccount)
+		e.must(ensurePermission(e.state.CallFrame, e.params.Callee, permission.CreateAccount))
+		e.must(native.CreateAccount(e.state.CallFrame, receiver))
+	}
+	acc := e.getAccount(e.params.Callee)
+	if acc == nil {
+		panic(errors.Errorf(errors.Codes.NonExistentAccount, "account %v does not exist", e.params.Callee))
+	}
+	e.must(native.UpdateAccount(e.state.CallFrame, receiver, func(account *acm.Account) error {
+		return account.AddToBalance(acc.Balance)
+	}))
+	e.must(native.RemoveAccount(e.state.CallFrame, e.params.Callee))
+}
+
+func (e *execContext) useGas(amount uint64) {
+	if *e.params.Gas < amount {
+		panic(errors.Codes.InsufficientGas)
+	}
+	*e.params.Gas -= amount
+}
+
+// Runs the function entryID as VirtualMachine.Run does except that it stops once the VM exceeds its gas limit, at
+// which point all of our remaining gas has been used
+func (e *execContext) run(vm *lifeExec.VirtualMachine, entryID int) error {
+	vm.Ignite(entryID)
+	for !vm.Exited {
+		vm.Execute()
+		if vm.GasLimitExceeded {
+			*e.params.Gas = 0
+			return errors.Codes.InsufficientGas
+		}
+		if vm.Delegate != nil {
+			vm.Delegate()
+			vm.Delegate = nil
+		}
+	}
+	if vm.ExitError != nil {
+		return utils.UnifyError(vm.ExitError)
+	}
+	return nil
+}
+
+// Deducts gas for the instructions the VM has run since we last checked and limits the VM to our remaining gas
+func (e *execContext) chargeInstructions(vm *lifeExec.VirtualMachine) error {
+	used := vm.Gas - e.instructionGas
+	if *e.params.Gas < used {
+		return errors.Codes.InsufficientGas
+	}
+	*e.params.Gas -= used
+	e.instructionGas = vm.Gas
+	vm.Config.GasLimit = vm.Gas + *e.params.Gas
+	return nil
+}
+
+func (e *execContext) getAccount(address crypto.Address) *acm.Account {
+	acc, err := e.state.CallFrame.GetAccount(address)
+	e.must(err)
+	return acc
+}
+
+// Errors from host functions abort execution. The VM recovers the panic and returns the error from Run
+func (e *execContext) must(err error) {
+	if err != nil {
+		panic(errors.AsException(err))
+	}
+}
+
+func accountCode(acc *acm.Account) []byte {
+	if acc == nil {
+		return nil
+	}
+	if len(acc.WASMCode) != 0 {
+		return acc.WASMCode
+	}
+	return acc.EVMCode
+}
+
+func ensurePermission(callFrame *engine.CallFrame, address crypto.Address, perm permission.PermFlag) error {
+	hasPermission, err := native.HasPermission(callFrame, address, perm)
+	if err != nil {
+		return err
+	} else if !hasPermission {
+		return errors.PermissionDenied{
+			Address: address,
+			Perm:    perm,
+		}
+	}
+	return nil
+}
+
+func memoryRead(vm *lifeExec.VirtualMachine, offset, length int) []byte {
+	if offset+length > len(vm.Memory) {
+		panic(errors.Errorf(errors.Codes.MemoryOutOfBounds, "cannot read %d bytes at offset %d from memory of size %d",
+			length, offset, len(vm.Memory)))
+	}
+	return vm.Memory[offset : offset+length]
+}
+
+func memoryWrite(vm *lifeExec.VirtualMachine, offset int, data []byte) {
+	if offset+len(data) > len(vm.Memory) {
+		panic(errors.Errorf(errors.Codes.MemoryOutOfBounds, "cannot write %d bytes at offset %d to memory of size %d",
+			len(data), offset, len(vm.Memory)))
+	}
+	copy(vm.Memory[offset:], data)
+}
+
+func readAddress(vm *lifeExec.VirtualMachine, offset int) crypto.Address {
+	address, err := crypto.AddressFromBytes(memoryRead(vm, offset, crypto.AddressLength))
+	if err != nil {
+		panic(errors.AsException(err))
+	}
+	return address
+}
+
+// Values are 128-bit but our balances are 64-bit
+func readValue(vm *lifeExec.VirtualMachine, offset int) uint64 {
+	bs := memoryRead(vm, offset, valueLength)
+	for _, b := range bs[8:] {
+		if b != 0 {
+			panic(errors.Errorf(errors.Codes.IntegerOverflow, "value %X exceeds 64 bits", bs))
+		}
+	}
+	return binary.LittleEndian.Uint64(bs)
+}
+
+func writeValue(vm *lifeExec.VirtualMachine, offset int, value uint64) {
+	bs := make([]byte, valueLength)
+	binary.LittleEndian.PutUint64(bs, value)
+	memoryWrite(vm, offset, bs)
+}
+
+// Copies the length bytes of data from offset into memory at destPtr padded with zeroes where that extends beyond
+// data. The length is checked against memory and paid for before anything is written since the contract controls it.
+func (e *execContext) copyToMemory(vm *lifeExec.VirtualMachine, destPtr int, data []byte, offset, length int) {
+	if destPtr+length > len(vm.Memory) {
+		panic(errors.Errorf(errors.Codes.MemoryOutOfBounds, "cannot write %d bytes at offset %d to memory of size %d",
+			length, destPtr, len(vm.Memory)))
+	}
+	e.useGas(GasPerCopyWord * uint64((length+31)/32))
+	dest := vm.Memory[destPtr : destPtr+length]
+	copied := 0
+	if offset < len(data) {
+		copied = copy(dest, data[offset:])
+	}
+	for i := copied; i < length; i++ {
+		dest[i] = 0
+	}
+}
@@ -1,12 +1,25 @@
 package wasm
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/hyperledger/burrow/acm"
 	"github.com/hyperledger/burrow/acm/acmstate"
-	"github.com/hyperledger/burrow/binary"
+	burrow_binary "github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/execution/engine"
+	"github.com/hyperledger/burrow/execution/errors"
+	"github.com/hyperledger/burrow/execution/evm"
 	"github.com/hyperledger/burrow/execution/evm/abi"
+	"github.com/hyperledger/burrow/execution/evm/asm"
+	"github.com/hyperledger/burrow/execution/evm/asm/bc"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/execution/native"
+	lifeExec "github.com/perlin-network/life/exec"
+	hex "github.com/tmthrgd/go-hex"
 
 	"github.com/hyperledger/burrow/crypto"
 	"github.com/stretchr/testify/require"
@@ -14,9 +27,11 @@ import (
 
 func TestStaticCallWithValue(t *testing.T) {
 	cache := acmstate.NewMemoryState()
+	blockchain := &blockchain{chainID: "burrow-test-chain"}
+	eventSink := exec.NewNoopEventSink()
 
 	// run constructor
-	runtime, cerr := RunWASM(cache, crypto.ZeroAddress, true, Bytecode_storage_test, []byte{})
+	runtime, cerr := Default().Execute(cache, blockchain, eventSink, callParams(nil), Bytecode_storage_test)
 	require.NoError(t, cerr)
 
 	// run getFooPlus2
@@ -24,7 +39,7 @@ func TestStaticCallWithValue(t *testing.T) {
 	require.NoError(t, err)
 	calldata, _, err := spec.Pack("getFooPlus2")
 
-	returndata, cerr := RunWASM(cache, crypto.ZeroAddress, false, runtime, calldata)
+	returndata, cerr := Default().Execute(cache, blockchain, eventSink, callParams(calldata), runtime)
 	require.NoError(t, cerr)
 
 	data := abi.GetPackingTypes(spec.Functions["getFooPlus2"].Outputs)
@@ -39,7 +54,7 @@ func TestStaticCallWithValue(t *testing.T) {
 	// call incFoo
 	calldata, _, err = spec.Pack("incFoo")
 
-	returndata, cerr = RunWASM(cache, crypto.ZeroAddress, false, runtime, calldata)
+	returndata, cerr = Default().Execute(cache, blockchain, eventSink, callParams(calldata), runtime)
 	require.NoError(t, cerr)
 
 	require.Equal(t, returndata, []byte{})
@@ -48,7 +63,7 @@ func TestStaticCallWithValue(t *testing.T) {
 	calldata, _, err = spec.Pack("getFooPlus2")
 	require.NoError(t, err)
 
-	returndata, cerr = RunWASM(cache, crypto.ZeroAddress, false, runtime, calldata)
+	returndata, cerr = Default().Execute(cache, blockchain, eventSink, callParams(calldata), runtime)
 	require.NoError(t, cerr)
 
 	spec.Unpack(returndata, "getFooPlus2", data...)
@@ -58,6 +73,286 @@ func TestStaticCallWithValue(t *testing.T) {
 	require.Equal(t, expected, returnValue)
 }
 
-func blockHashGetter(height uint64) []byte {
-	return binary.LeftPadWord256([]byte(fmt.Sprintf("block_hash_%d", height))).Bytes()
+// Hand-assembled from:
+//
+//	(module
+//	  (import "ethereum" "getCaller" (func $getCaller (param i32)))
+//	  (import "ethereum" "getCallValue" (func $getCallValue (param i32)))
+//	  (import "ethereum" "getBlockNumber" (func $getBlockNumber (result i64)))
+//	  (import "ethereum" "log" (func $log (param i32 i32 i32 i32 i32 i32 i32)))
+//	  (import "ethereum" "finish" (func $finish (param i32 i32)))
+//	  (import "ethereum" "memory" (memory 2 2))
+//	  (func $main
+//	    (call $getCaller (i32.const 0))
+//	    (call $getCallValue (i32.const 20))
+//	    (i64.store (i32.const 36) (call $getBlockNumber))
+//	    (call $log (i32.const 0) (i32.const 44) (i32.const 1) (i32.const 0) (i32.const 0) (i32.const 0) (i32.const 0))
+//	    (call $finish (i32.const 0) (i32.const 44)))
+//	  (export "main" (func $main)))
+var bytecodeHostTest = hex.MustDecodeString("0061736D01000000011B0560017F006000017E60077F7F7F7F7F7F7F0060027F7F00600000027D" +
+	"0608657468657265756D0967657443616C6C6572000008657468657265756D0C67657443616C6C56616C7565000008657468657265756D" +
+	"0E676574426C6F636B4E756D626572000108657468657265756D036C6F67000208657468657265756D0666696E69736800030865746865" +
+	"7265756D066D656D6F72790201020203020104070801046D61696E00050A290127004100100041141001412410023703004100412C4101" +
+	"410041004100410010034100412C10040B")
+
+func TestHostFunctions(t *testing.T) {
+	st := acmstate.NewMemoryState()
+	blockchain := &blockchain{chainID: "burrow-test-chain", blockHeight: 42}
+	txe := new(exec.TxExecution)
+
+	caller := crypto.Address{1, 2, 3}
+	callee := crypto.Address{4, 5, 6}
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: caller, Balance: 1000}))
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: callee, WASMCode: bytecodeHostTest}))
+
+	params := callParams(nil)
+	params.Origin = caller
+	params.Caller = caller
+	params.Callee = callee
+	params.Value = 7
+	output, err := Default().Execute(st, blockchain, txe, params, bytecodeHostTest)
+	require.NoError(t, err)
+	require.True(t, *params.Gas < 1000000, "should have used some gas")
+
+	expected := make([]byte, 44)
+	copy(expected, caller.Bytes())
+	binary.LittleEndian.PutUint64(expected[20:], 7)
+	binary.LittleEndian.PutUint64(expected[36:], 42)
+	require.Equal(t, expected, output)
+
+	// The value is transferred with the call
+	acc, err := st.GetAccount(callee)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), acc.Balance)
+
+	var logs []*exec.LogEvent
+	for _, ev := range txe.Events {
+		if ev.Log != nil {
+			logs = append(logs, ev.Log)
+		}
+	}
+	require.Len(t, logs, 1)
+	log := logs[0]
+	require.Equal(t, callee, log.Address)
+	require.Equal(t, burrow_binary.HexBytes(expected), log.Data)
+	var topic burrow_binary.Word256
+	copy(topic[:], expected)
+	require.Equal(t, []burrow_binary.Word256{topic}, log.Topics)
+}
+
+func TestCallEVMToWASM(t *testing.T) {
+	st := acmstate.NewMemoryState()
+	blockchain := &blockchain{chainID: "burrow-test-chain", blockHeight: 42}
+	txe := new(exec.TxExecution)
+
+	natives := native.MustDefaultNatives()
+	evmVM := evm.New(evm.Options{Natives: natives})
+	wvm := New(Options{Natives: natives})
+	engine.Connect(evmVM, wvm, natives)
+
+	caller := crypto.Address{1, 2, 3}
+	wasmContract := crypto.Address{4, 5, 6}
+	evmContract := crypto.Address{7, 8, 9}
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: caller}))
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: wasmContract, WASMCode: bytecodeHostTest}))
+
+	// Call the WASM contract and return what it returns
+	bytecode := bc.MustSplice(
+		asm.PUSH1, 44, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH20, wasmContract,
+		asm.PUSH3, 0x0F, 0x42, 0x40, asm.CALL, asm.POP,
+		asm.PUSH1, 44, asm.PUSH1, 0, asm.RETURN)
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: evmContract, EVMCode: bytecode}))
+
+	params := callParams(nil)
+	params.Origin = caller
+	params.Caller = caller
+	params.Callee = evmContract
+	output, err := evmVM.Execute(st, blockchain, txe, params, bytecode)
+	require.NoError(t, err)
+
+	// The WASM contract sees the EVM contract as its caller
+	expected := make([]byte, 44)
+	copy(expected, evmContract.Bytes())
+	binary.LittleEndian.PutUint64(expected[36:], 42)
+	require.Equal(t, expected, output)
+
+	// Events from both engines end up in the same execution
+	var calls []*exec.CallEvent
+	var logs []*exec.LogEvent
+	for _, ev := range txe.Events {
+		if ev.Call != nil {
+			calls = append(calls, ev.Call)
+		}
+		if ev.Log != nil {
+			logs = append(logs, ev.Log)
+		}
+	}
+	require.Len(t, logs, 1)
+	require.Equal(t, wasmContract, logs[0].Address)
+	// The inner call finishes first
+	require.Len(t, calls, 2)
+	require.Equal(t, wasmContract, calls[0].CallData.Callee)
+	require.Equal(t, evmContract, calls[0].CallData.Caller)
+}
+
+func TestExtCodeSize(t *testing.T) {
+	st := acmstate.NewMemoryState()
+	blockchain := &blockchain{chainID: "burrow-test-chain"}
+	wasmContract := crypto.Address{4, 5, 6}
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: wasmContract, WASMCode: bytecodeHostTest}))
+	bytecode := bc.MustSplice(asm.PUSH20, wasmContract, asm.EXTCODESIZE, asm.PUSH1, 0, asm.MSTORE,
+		asm.PUSH1, 32, asm.PUSH1, 0, asm.RETURN)
+
+	// WASM code is only seen by EXTCODESIZE when enabled by the chain
+	output, err := evm.Default().Execute(st, blockchain, exec.NewNoopEventSink(), callParams(nil), bytecode)
+	require.NoError(t, err)
+	require.Equal(t, burrow_binary.Zero256.Bytes(), output)
+
+	output, err = evm.New(evm.Options{WASMCodeSize: true}).Execute(st, blockchain, exec.NewNoopEventSink(),
+		callParams(nil), bytecode)
+	require.NoError(t, err)
+	require.Equal(t, burrow_binary.Int64ToWord256(int64(len(bytecodeHostTest))).Bytes(), output)
+}
+
+// Hand-assembled from:
+//
+//	(module
+//	  (func $main
+//	    (loop (br 0)))
+//	  (export "main" (func $main)))
+var bytecodeLoopTest = hex.MustDecodeString("0061736D0100000001040160000003020100070801046D61696E00000A0901070003400C000B0B")
+
+func TestGasMetering(t *testing.T) {
+	st := acmstate.NewMemoryState()
+	blockchain := &blockchain{chainID: "burrow-test-chain"}
+
+	params := callParams(nil)
+	_, err := Default().Execute(st, blockchain, exec.NewNoopEventSink(), params, bytecodeLoopTest)
+	require.Error(t, err)
+	require.Equal(t, errors.Codes.InsufficientGas, errors.GetCode(err))
+	// Running out consumes all of the gas
+	require.Equal(t, uint64(0), *params.Gas)
+
+	gas := uint64(0)
+	params.Gas = &gas
+	_, err = Default().Execute(st, blockchain, exec.NewNoopEventSink(), params, bytecodeHostTest)
+	require.Equal(t, errors.Codes.InsufficientGas, errors.GetCode(err))
+}
+
+func TestCopyToMemory(t *testing.T) {
+	gas := uint64(10)
+	e := &execContext{params: engine.CallParams{Gas: &gas}}
+	vm := &lifeExec.VirtualMachine{Memory: make([]byte, 64)}
+	for i := range vm.Memory {
+		vm.Memory[i] = 0xFF
+	}
+
+	e.copyToMemory(vm, 8, []byte{1, 2, 3}, 1, 4)
+	require.Equal(t, []byte{2, 3, 0, 0, 0xFF}, vm.Memory[8:13])
+	require.Equal(t, uint64(9), gas)
+
+	// The length is checked against memory before anything is allocated or charged
+	require.Panics(t, func() {
+		e.copyToMemory(vm, 0, nil, 0, math.MaxUint32)
+	})
+	require.Equal(t, uint64(9), gas)
+
+	// Each word is charged for
+	require.Panics(t, func() {
+		e.copyToMemory(vm, 0, nil, 0, 10*32+1)
+	})
+}
+
+func TestHostFunctionGas(t *testing.T) {
+	st := acmstate.NewMemoryState()
+	callee := crypto.Address{1, 2, 3}
+	require.NoError(t, st.UpdateAccount(&acm.Account{Address: callee}))
+	e := &execContext{
+		params: engine.CallParams{Callee: callee},
+		state: engine.State{
+			CallFrame: engine.NewCallFrame(st),
+			EventSink: exec.NewNoopEventSink(),
+		},
+		returnData: make([]byte, 128),
+	}
+	// The gas used by a call to the host function with locals
+	gasUsed := func(field string, locals ...int64) uint64 {
+		gas := uint64(1000)
+		e.params.Gas = &gas
+		vm := &lifeExec.VirtualMachine{
+			Memory:    make([]byte, 256),
+			CallStack: []lifeExec.Frame{{Locals: locals}},
+		}
+		e.resolveHostFunc(field)(vm)
+		require.NoError(t, e.Error())
+		return 1000 - gas
+	}
+
+	t.Run("log", func(t *testing.T) {
+		require.Equal(t, GasPerLogWord, gasUsed("log", 0, 32, 0))
+		require.Equal(t, 4*GasPerLogWord, gasUsed("log", 0, 128, 0))
+		require.Equal(t, GasPerLogWord+2*GasPerLogTopic, gasUsed("log", 0, 32, 2, 0, 32))
+	})
+
+	t.Run("storageLoad", func(t *testing.T) {
+		require.Equal(t, native.GasStorageRead, gasUsed("storageLoad", 0, 32))
+	})
+
+	t.Run("returnDataCopy", func(t *testing.T) {
+		require.Equal(t, GasPerCopyWord, gasUsed("returnDataCopy", 0, 0, 32))
+		require.Equal(t, 4*GasPerCopyWord, gasUsed("returnDataCopy", 0, 0, 128))
+		require.Panics(t, func() {
+			gasUsed("returnDataCopy", 0, 64, 128)
+		})
+	})
+}
+
+func TestTxGasPrice(t *testing.T) {
+	st := acmstate.NewMemoryState()
+	blockchain := &blockchain{chainID: "burrow-test-chain"}
+
+	params := callParams(nil)
+	params.GasPrice = 3
+	output, err := evm.Default().Execute(st, blockchain, exec.NewNoopEventSink(), params,
+		bc.MustSplice(asm.GASPRICE_DEPRECATED, asm.PUSH1, 0, asm.MSTORE, asm.PUSH1, 32, asm.PUSH1, 0, asm.RETURN))
+	require.NoError(t, err)
+	require.Equal(t, burrow_binary.Int64ToWord256(3).Bytes(), output)
+
+	e := &execContext{params: params}
+	vm := &lifeExec.VirtualMachine{
+		Memory:    make([]byte, valueLength),
+		CallStack: []lifeExec.Frame{{Locals: []int64{0}}},
+	}
+	e.resolveHostFunc("getTxGasPrice")(vm)
+	require.Equal(t, uint64(3), binary.LittleEndian.Uint64(vm.Memory))
+}
+
+func callParams(input []byte) engine.CallParams {
+	gas := uint64(1000000)
+	return engine.CallParams{
+		Input: input,
+		Gas:   &gas,
+	}
+}
+
+type blockchain struct {
+	chainID     string
+	blockHeight uint64
+	blockTime   time.Time
+}
+
+func (b *blockchain) ChainID() string {
+	return b.chainID
+}
+
+func (b *blockchain) LastBlockHeight() uint64 {
+	return b.blockHeight
+}
+
+func (b *blockchain) LastBlockTime() time.Time {
+	return b.blockTime
+}
+
+func (b *blockchain) BlockHash(height uint64) ([]byte, error) {
+	return burrow_binary.LeftPadWord256([]byte(fmt.Sprintf("block_hash_%d", height))).Bytes(), nil
 }
@@ -15,7 +15,6 @@ import (
 	"github.com/hyperledger/burrow/bcm"
 	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/consensus/tendermint"
-	"github.com/hyperledger/burrow/core"
 	"github.com/hyperledger/burrow/event"
 	"github.com/hyperledger/burrow/execution"
 	"github.com/hyperledger/burrow/execution/state"
@@ -44,10 +43,15 @@ type Source struct {
 }
 
 func NewSource(burrowDB, tmDB dbm.DB, genesisDoc *genesis.GenesisDoc) *Source {
+	return NewSourceFromBlockStore(burrowDB, store.NewBlockStore(tmDB), genesisDoc)
+}
+
+// NewSourceFromBlockStore reads blocks from an existing Tendermint block store, such as that of a running node
+func NewSourceFromBlockStore(burrowDB dbm.DB, blockStore sm.BlockStoreRPC, genesisDoc *genesis.GenesisDoc) *Source {
 	// Avoid writing through to underlying DB
 	cacheDB := storage.NewCacheDB(burrowDB)
 	return &Source{
-		Explorer:   bcm.NewBlockStore(store.NewBlockStore(tmDB)),
+		Explorer:   bcm.NewBlockStore(blockStore),
 		db:         burrowDB,
 		cacheDB:    cacheDB,
 		blockchain: bcm.NewBlockchain(cacheDB, genesisDoc),
@@ -56,10 +60,24 @@ func NewSource(burrowDB, tmDB dbm.DB, genesisDoc *genesis.GenesisDoc) *Source {
 	}
 }
 
-func NewSourceFromDir(genesisDoc *genesis.GenesisDoc, dbDir string) *Source {
-	burrowDB := dbm.NewDB(core.BurrowDBName, dbm.GoLevelDBBackend, dbDir)
-	tmDB := dbm.NewDB("blockstore", dbm.GoLevelDBBackend, path.Join(dbDir, "data"))
-	return NewSource(burrowDB, tmDB, genesisDoc)
+// NewSourceFromDir reads a Burrow directory whose databases were written with the backends given by conf
+func NewSourceFromDir(genesisDoc *genesis.GenesisDoc, dbDir string, conf *state.DatabaseConfig) (*Source, error) {
+	if conf == nil {
+		conf = state.DefaultDatabaseConfig()
+	}
+	burrowDB, err := conf.Open(dbDir)
+	if err != nil {
+		return nil, err
+	}
+	blockStoreBackend := conf.BlockStore
+	if blockStoreBackend == "" {
+		blockStoreBackend = dbm.GoLevelDBBackend
+	}
+	tmDB, err := state.NewDB("blockstore", blockStoreBackend, path.Join(dbDir, "data"))
+	if err != nil {
+		return nil, err
+	}
+	return NewSource(burrowDB, tmDB, genesisDoc), nil
 }
 
 func NewSourceFromGenesis(genesisDoc *genesis.GenesisDoc) *Source {
@@ -99,8 +117,8 @@ func initBurrow(gd *genesis.GenesisDoc) (dbm.DB, *state.State, *bcm.Blockchain,
 	return db, st, chain, nil
 }
 
-// LoadAt height
-func (src *Source) LoadAt(height uint64) (err error) {
+// LoadAt height, any options are passed to the committer
+func (src *Source) LoadAt(height uint64, options ...execution.Option) (err error) {
 	if height >= 1 {
 		// Load and commit previous block
 		block, err := src.Explorer.Block(int64(height))
@@ -119,7 +137,7 @@ func (src *Source) LoadAt(height uint64) (err error) {
 
 	// Get our commit machinery
 	src.committer = execution.NewBatchCommitter(src.State, execution.ParamsFromGenesis(src.genesisDoc), src.blockchain,
-		event.NewEmitter(), src.logger)
+		event.NewEmitter(), src.logger, options...)
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/hyperledger/burrow/bcm"
 	"github.com/hyperledger/burrow/event"
 	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/evm"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/txs"
 
@@ -67,6 +68,31 @@ func TestReplay(t *testing.T) {
 	require.Len(t, rc, int(height-1))
 }
 
+func TestTraceTx(t *testing.T) {
+	var height uint64 = 6
+	genesisDoc, tmDB, burrowDB := makeChain(t, height)
+	src := NewSource(burrowDB, tmDB, genesisDoc)
+
+	block, err := src.Explorer.Block(4)
+	require.NoError(t, err)
+	var txHash []byte
+	err = block.Transactions(func(txEnv *txs.Envelope) error {
+		txHash = txEnv.Tx.Hash()
+		return nil
+	})
+	require.NoError(t, err)
+
+	// SendTxs do not touch the EVM so there is nothing to trace but the execution itself is reproduced
+	tracer := evm.NewCallTracer()
+	txe, err := src.TraceTx(4, txHash, tracer)
+	require.NoError(t, err)
+	require.Equal(t, txHash, txe.TxHash.Bytes())
+	require.Nil(t, tracer.Result())
+
+	_, err = src.TraceTx(3, txHash, tracer)
+	require.Error(t, err)
+}
+
 func makeChain(t *testing.T, max uint64) (*genesis.GenesisDoc, dbm.DB, dbm.DB) {
 	genesisDoc, _, validators := genesis.NewDeterministicGenesis(0).GenesisDoc(0, 1)
 
@@ -0,0 +1,81 @@
+package forensics
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/evm"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/txs"
+	"github.com/pkg/errors"
+)
+
+// Used to stop iterating over a block's transactions once the target has been executed
+var errTraced = errors.New("transaction traced")
+
+// TraceTx re-executes the transaction with txHash from the block at height on top of the state left by the previous
+// block. Any transactions preceding it in the block are executed first (untraced) so that it sees the same state it
+// originally ran against. Options are passed to the committer and should match those of the node that produced the
+// block.
+func (src *Source) TraceTx(height uint64, txHash []byte, tracer evm.Tracer,
+	options ...execution.Option) (*exec.TxExecution, error) {
+
+	if height < 1 {
+		return nil, errors.Errorf("cannot trace transaction at height %d", height)
+	}
+	gate := &gatedTracer{tracer: tracer}
+	options = append(options[:len(options):len(options)], execution.VMTracer(gate))
+	if err := src.LoadAt(height-1, options...); err != nil {
+		return nil, errors.Wrapf(err, "could not load state prior to height %d", height)
+	}
+
+	block, err := src.Explorer.Block(int64(height))
+	if err != nil {
+		return nil, errors.Wrap(err, "explorer.Block()")
+	}
+
+	var txe *exec.TxExecution
+	err = block.Transactions(func(txEnv *txs.Envelope) error {
+		gate.enabled = bytes.Equal(txEnv.Tx.Hash(), txHash)
+		exe, err := src.committer.Execute(txEnv)
+		if err != nil {
+			return errors.Wrap(err, "committer.Execute()")
+		}
+		if gate.enabled {
+			txe = exe
+			return errTraced
+		}
+		return nil
+	})
+	if err != errTraced {
+		if err != nil {
+			return nil, errors.Wrap(err, "block.Transactions()")
+		}
+		return nil, errors.Errorf("transaction %X not found in block at height %d", txHash, height)
+	}
+	return txe, nil
+}
+
+// Forwards events to the wrapped tracer only while enabled
+type gatedTracer struct {
+	tracer  evm.Tracer
+	enabled bool
+}
+
+func (gt *gatedTracer) Enter(call *evm.TraceCall) {
+	if gt.enabled {
+		gt.tracer.Enter(call)
+	}
+}
+
+func (gt *gatedTracer) Step(step *evm.TraceStep) {
+	if gt.enabled {
+		gt.tracer.Step(step)
+	}
+}
+
+func (gt *gatedTracer) Exit(ret *evm.TraceReturn) {
+	if gt.enabled {
+		gt.tracer.Exit(ret)
+	}
+}
@@ -51,6 +51,25 @@ var DefaultPermissionsAccount = PermissionsAccount(permission.DefaultAccountPerm
 
 type params struct {
 	ProposalThreshold uint64
+	// Charge CallTxs for the gas they use when set
+	Fees *FeeSchedule `json:",omitempty" toml:",omitempty"`
+	// Record on each TxExecution the changes its transaction made to account balances, code, permissions, and storage.
+	// Since the diffs are stored with each block's events this is part of consensus.
+	StateDiffs bool `json:",omitempty" toml:",omitempty"`
+	// Store the events of blocks from this height on in the plain, where they do not contribute to the AppHash and can
+	// be dropped, rather than in the forest (never if zero)
+	PlainEventsHeight uint64 `json:",omitempty" toml:",omitempty"`
+	// Have EXTCODESIZE give the size of WASM code so that Solidity will call contracts hosted by the WVM. Since this
+	// changes the result of EVM execution it is part of consensus.
+	WASMCodeSize bool `json:",omitempty" toml:",omitempty"`
+}
+
+// FeeSchedule determines what a CallTx pays for the gas it uses and who receives it
+type FeeSchedule struct {
+	// CallTxs offering a lower GasPrice are rejected
+	MinimumGasPrice uint64
+	// The account credited with the fees paid in each block, which otherwise go to the block's proposer
+	Treasury *crypto.Address `json:",omitempty" toml:",omitempty"`
 }
 
 type GenesisDoc struct {
@@ -33,7 +33,11 @@ type GenesisSpec struct {
 }
 
 type params struct {
-	ProposalThreshold uint64 `json:",omitempty" toml:",omitempty"`
+	ProposalThreshold uint64               `json:",omitempty" toml:",omitempty"`
+	Fees              *genesis.FeeSchedule `json:",omitempty" toml:",omitempty"`
+	StateDiffs        bool                 `json:",omitempty" toml:",omitempty"`
+	PlainEventsHeight uint64               `json:",omitempty" toml:",omitempty"`
+	WASMCodeSize      bool                 `json:",omitempty" toml:",omitempty"`
 }
 
 // Produce a fully realised GenesisDoc from a template GenesisDoc that may omit values
@@ -54,6 +58,10 @@ func (gs *GenesisSpec) GenesisDoc(keyClient keys.KeyClient, curve crypto.CurveTy
 	if gs.Params.ProposalThreshold != 0 {
 		genesisDoc.Params.ProposalThreshold = genesis.DefaultProposalThreshold
 	}
+	genesisDoc.Params.Fees = gs.Params.Fees
+	genesisDoc.Params.StateDiffs = gs.Params.StateDiffs
+	genesisDoc.Params.PlainEventsHeight = gs.Params.PlainEventsHeight
+	genesisDoc.Params.WASMCodeSize = gs.Params.WASMCodeSize
 
 	if len(gs.GlobalPermissions) == 0 {
 		genesisDoc.GlobalPermissions = permission.DefaultAccountPermissions.Clone()
@@ -21,6 +21,7 @@ require (
 	github.com/fatih/color v1.7.0
 	github.com/go-kit/kit v0.9.0
 	github.com/go-ozzo/ozzo-validation v3.5.0+incompatible
+	github.com/go-sql-driver/mysql v1.4.0
 	github.com/gogo/protobuf v1.3.1
 	github.com/golang/protobuf v1.3.3
 	github.com/gorilla/websocket v1.4.1
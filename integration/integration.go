@@ -111,7 +111,7 @@ func TestKernel(validatorAccount *acm.PrivateAccount, keysAccounts []*acm.Privat
 
 	fmt.Println("Creating integration test Kernel...")
 
-	kern, err := core.NewKernel(testConfig.BurrowDir)
+	kern, err := core.NewKernelWithDatabase(testConfig.BurrowDir, testConfig.Database)
 	if err != nil {
 		return nil, err
 	}
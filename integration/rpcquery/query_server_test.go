@@ -13,8 +13,10 @@ import (
 	"github.com/tendermint/tendermint/crypto/tmhash"
 
 	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/event/query"
 	"github.com/hyperledger/burrow/execution/names"
+	"github.com/hyperledger/burrow/execution/solidity"
 	"github.com/hyperledger/burrow/genesis"
 	"github.com/hyperledger/burrow/integration/rpctest"
 	"github.com/hyperledger/burrow/rpc/rpcquery"
@@ -89,6 +91,9 @@ func TestQueryServer(t *testing.T) {
 
 	t.Run("ListNames", func(t *testing.T) {
 		tcli := rpctest.NewTransactClient(t, kern.GRPCListenAddress().String())
+		ecli := rpctest.NewExecutionEventsClient(t, kern.GRPCListenAddress().String())
+		require.NoError(t, rpctest.WaitNBlocks(ecli, 1))
+		heightBefore := kern.Blockchain.LastBlockHeight()
 		dataA, dataB := "NO TAMBOURINES", "ELEPHANTS WELCOME"
 		n := 8
 		for i := 0; i < n; i++ {
@@ -104,12 +109,51 @@ func TestQueryServer(t *testing.T) {
 		qcli := rpctest.NewQueryClient(t, kern.GRPCListenAddress().String())
 		entries := receiveNames(t, qcli, "")
 		assert.Len(t, entries, n)
+		// None of the names existed before
+		stream, err := qcli.ListNames(context.Background(), &rpcquery.ListNamesParam{Height: heightBefore})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, io.EOF, err)
+		_, err = qcli.GetName(context.Background(), &rpcquery.GetNameParam{Name: "Flub/0", Height: heightBefore})
+		assert.Error(t, err)
 		entries = receiveNames(t, qcli, query.NewBuilder().AndEquals("Data", dataA).String())
 		if assert.Len(t, entries, n/2) {
 			assert.Equal(t, dataA, entries[0].Data)
 		}
 	})
 
+	t.Run("ListStorage", func(t *testing.T) {
+		tcli := rpctest.NewTransactClient(t, kern.GRPCListenAddress().String())
+		qcli := rpctest.NewQueryClient(t, kern.GRPCListenAddress().String())
+		createTxe, err := rpctest.CreateContract(tcli, rpctest.PrivateAccounts[0].GetAddress(),
+			solidity.Bytecode_StrangeLoop, nil)
+		require.NoError(t, err)
+		address := createTxe.Receipt.ContractAddress
+
+		// StrangeLoop initialises its first four slots
+		entries := receiveStorage(t, qcli, &rpcquery.ListStorageParam{Address: address})
+		require.Len(t, entries, 4)
+		for i, entry := range entries {
+			assert.Equal(t, binary.Int64ToWord256(int64(i)), entry.Key)
+		}
+		assert.Equal(t, binary.Int64ToWord256(23).Bytes(), binary.LeftPadBytes(entries[0].Value, binary.Word256Bytes))
+
+		entries = receiveStorage(t, qcli, &rpcquery.ListStorageParam{
+			Address: address,
+			Start:   []byte{1},
+			End:     []byte{3},
+		})
+		require.Len(t, entries, 2)
+		assert.Equal(t, binary.Int64ToWord256(1), entries[0].Key)
+
+		entries = receiveStorage(t, qcli, &rpcquery.ListStorageParam{Address: address, Start: []byte{2}, Limit: 1})
+		require.Len(t, entries, 1)
+		assert.Equal(t, binary.Int64ToWord256(2), entries[0].Key)
+
+		entries = receiveStorage(t, qcli, &rpcquery.ListStorageParam{Address: address, Height: createTxe.Height - 1})
+		assert.Len(t, entries, 0)
+	})
+
 	t.Run("GetBlockHeader", func(t *testing.T) {
 		qcli := rpctest.NewQueryClient(t, kern.GRPCListenAddress().String())
 		ecli := rpctest.NewExecutionEventsClient(t, kern.GRPCListenAddress().String())
@@ -140,3 +184,18 @@ func receiveNames(t testing.TB, qcli rpcquery.QueryClient, query string) []*name
 	}
 	return entries
 }
+
+func receiveStorage(t testing.TB, qcli rpcquery.QueryClient, param *rpcquery.ListStorageParam) []*rpcquery.StorageEntry {
+	stream, err := qcli.ListStorage(context.Background(), param)
+	require.NoError(t, err)
+	var entries []*rpcquery.StorageEntry
+	entry, err := stream.Recv()
+	for err == nil {
+		entries = append(entries, entry)
+		entry, err = stream.Recv()
+	}
+	if err != io.EOF {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return entries
+}
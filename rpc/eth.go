@@ -14,15 +14,21 @@ import (
 	bin "github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/consensus/tendermint"
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/encoding"
 	x "github.com/hyperledger/burrow/encoding/hex"
 	"github.com/hyperledger/burrow/encoding/rlp"
+	"github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/event/query"
 	"github.com/hyperledger/burrow/execution"
+	"github.com/hyperledger/burrow/execution/contexts"
+	"github.com/hyperledger/burrow/execution/evm"
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/execution/state"
 	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/project"
 	"github.com/hyperledger/burrow/rpc/web3"
+	"github.com/hyperledger/burrow/storage"
 	"github.com/hyperledger/burrow/txs"
 	"github.com/hyperledger/burrow/txs/payload"
 	tmConfig "github.com/tendermint/tendermint/config"
@@ -30,7 +36,6 @@ import (
 )
 
 const (
-	chainID      = 1
 	maxGasLimit  = 2<<52 - 1
 	hexZero      = "0x0"
 	hexZeroNonce = "0x0000000000000000"
@@ -40,49 +45,73 @@ const (
 // EthService is a web3 provider
 type EthService struct {
 	accounts   acmstate.IterableStatsReader
+	history    StateHistory
 	events     EventsReader
+	emitter    *event.Emitter
 	blockchain bcm.BlockchainInfo
 	validators validator.History
 	nodeView   *tendermint.NodeView
 	trans      *execution.Transactor
+	txTracer   TxTracer
+	filters    *filterRegistry
 	keyClient  keys.KeyClient
 	keyStore   *keys.KeyStore
 	config     *tmConfig.Config
 	logger     *logging.Logger
+	// Configure the engines of simulated calls as the node's executor's are
+	exeOptions []execution.Option
 }
 
 // NewEthService returns our web3 provider
-func NewEthService(accounts acmstate.IterableStatsReader,
-	events EventsReader, blockchain bcm.BlockchainInfo,
+func NewEthService(accounts acmstate.IterableStatsReader, history StateHistory,
+	events EventsReader, emitter *event.Emitter, blockchain bcm.BlockchainInfo,
 	validators validator.History, nodeView *tendermint.NodeView,
-	trans *execution.Transactor, keyStore *keys.KeyStore,
-	logger *logging.Logger) *EthService {
+	trans *execution.Transactor, txTracer TxTracer, keyStore *keys.KeyStore,
+	logger *logging.Logger, exeOptions ...execution.Option) *EthService {
 
 	keyClient := keys.NewLocalKeyClient(keyStore, logger)
 
 	return &EthService{
 		accounts,
+		history,
 		events,
+		emitter,
 		blockchain,
 		validators,
 		nodeView,
 		trans,
+		txTracer,
+		newFilterRegistry(filterTimeout),
 		keyClient,
 		keyStore,
 		tmConfig.DefaultConfig(),
 		logger,
+		exeOptions,
 	}
 }
 
 var _ web3.Service = &EthService{}
 
 type EventsReader interface {
+	IterateStreamEvents(startHeight, endHeight *uint64, consumer func(*exec.StreamEvent) error) error
 	TxsAtHeight(height uint64) ([]*exec.TxExecution, error)
 	TxByHash(txHash []byte) (*exec.TxExecution, error)
 }
 
 var _ EventsReader = &state.State{}
 
+// StateHistory provides access to the state as it was at previous heights
+type StateHistory interface {
+	LoadHeight(height uint64) (*state.ReadState, error)
+}
+
+var _ StateHistory = &state.State{}
+
+// TxTracer re-executes a committed transaction passing its execution to tracer
+type TxTracer interface {
+	TraceTx(height uint64, txHash []byte, tracer evm.Tracer) (*exec.TxExecution, error)
+}
+
 // Web3ClientVersion returns the version of burrow
 func (srv *EthService) Web3ClientVersion() (*web3.Web3ClientVersionResult, error) {
 	return &web3.Web3ClientVersionResult{
@@ -116,11 +145,10 @@ func (srv *EthService) NetPeerCount() (*web3.NetPeerCountResult, error) {
 	}, nil
 }
 
-// NetVersion returns the hex encoding of the network id,
-// this is typically a small int (where 1 == Ethereum mainnet)
+// NetVersion returns the hex encoding of the network id, which is the same as the chain ID
 func (srv *EthService) NetVersion() (*web3.NetVersionResult, error) {
 	return &web3.NetVersionResult{
-		ChainID: x.EncodeNumber(uint64(chainID)),
+		ChainID: x.EncodeBigInt(srv.chainID()),
 	}, nil
 }
 
@@ -131,13 +159,18 @@ func (srv *EthService) EthProtocolVersion() (*web3.EthProtocolVersionResult, err
 	}, nil
 }
 
-// EthChainId returns the chainID
+// EthChainId returns the chain ID as seen by the CHAINID opcode, which is what transactions are signed with
 func (srv *EthService) EthChainId() (*web3.EthChainIdResult, error) {
 	return &web3.EthChainIdResult{
-		ChainId: srv.blockchain.ChainID(),
+		ChainId: x.EncodeBigInt(srv.chainID()),
 	}, nil
 }
 
+// The numeric chain ID derived from Burrow's ChainID
+func (srv *EthService) chainID() *big.Int {
+	return encoding.GetEthChainID(srv.blockchain.ChainID())
+}
+
 // EthBlockNumber returns the latest height
 func (srv *EthService) EthBlockNumber() (*web3.EthBlockNumberResult, error) {
 	return &web3.EthBlockNumberResult{
@@ -147,45 +180,190 @@ func (srv *EthService) EthBlockNumber() (*web3.EthBlockNumberResult, error) {
 
 // EthCall executes a new message call immediately without creating a transaction
 func (srv *EthService) EthCall(req *web3.EthCallParams) (*web3.EthCallResult, error) {
-	var to, from crypto.Address
-	var err error
+	from, to, data, _, err := decodeCall(req.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := srv.stateAt(req.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	txe, err := execution.CallSim(st, srv.blockchain, from, to, data, srv.logger, srv.exeOptions...)
+	if err != nil {
+		return nil, err
+	} else if txe.Exception != nil {
+		return nil, txe.Exception.AsError()
+	}
 
-	if addr := req.Transaction.To; addr != "" {
+	var result string
+	if r := txe.GetResult(); r != nil {
+		result = x.EncodeBytes(r.GetReturn())
+	}
+
+	return &web3.EthCallResult{
+		ReturnValue: result,
+	}, nil
+}
+
+func decodeCall(tx web3.Transaction) (from, to crypto.Address, data []byte, value uint64, err error) {
+	if addr := tx.To; addr != "" {
 		to, err = x.DecodeToAddress(addr)
 		if err != nil {
-			return nil, err
+			return
 		}
 	}
 
-	if addr := req.Transaction.From; addr != "" {
+	if addr := tx.From; addr != "" {
 		from, err = x.DecodeToAddress(addr)
 		if err != nil {
-			return nil, err
+			return
+		}
+	}
+
+	if tx.Value != "" {
+		value, err = strconv.ParseUint(tx.Value, 0, 64)
+		if err != nil {
+			err = fmt.Errorf("failed to parse value: %v", err)
+			return
 		}
 	}
 
-	data, err := x.DecodeToBytes(req.Transaction.Data)
+	data, err = x.DecodeToBytes(tx.Data)
+	return
+}
+
+// DebugTraceTransaction replays a committed tx on top of the state left by the preceding txs in its block
+func (srv *EthService) DebugTraceTransaction(req *web3.DebugTraceTransactionParams) (*web3.DebugTraceTransactionResult, error) {
+	hash, err := x.DecodeToBytes(req.TransactionHash)
+	if err != nil {
+		return nil, err
+	}
+
+	txe, err := srv.events.TxByHash(hash)
 	if err != nil {
 		return nil, err
+	} else if txe == nil {
+		return nil, fmt.Errorf("tx with hash %s does not exist", req.TransactionHash)
 	}
 
-	txe, err := execution.CallSim(srv.accounts, srv.blockchain, from, to, data, srv.logger)
+	tracer, result, err := newTracer(req.TraceConfig)
+	if err != nil {
+		return nil, err
+	}
+	_, err = srv.txTracer.TraceTx(txe.Height, hash, tracer)
 	if err != nil {
 		return nil, err
-	} else if txe.Exception != nil {
-		return nil, txe.Exception.AsError()
 	}
 
-	var result string
-	if r := txe.GetResult(); r != nil {
-		result = x.EncodeBytes(r.GetReturn())
+	return &web3.DebugTraceTransactionResult{
+		Trace: result(),
+	}, nil
+}
+
+// DebugTraceCall traces a message call executed as per EthCall
+func (srv *EthService) DebugTraceCall(req *web3.DebugTraceCallParams) (*web3.DebugTraceCallResult, error) {
+	from, to, data, _, err := decodeCall(req.Transaction)
+	if err != nil {
+		return nil, err
 	}
 
-	return &web3.EthCallResult{
-		ReturnValue: result,
+	tracer, result, err := newTracer(req.TraceConfig)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.stateAt(req.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	// Exceptions are reported within the trace
+	_, err = execution.TraceCallSim(st, srv.blockchain, from, to, data, tracer, srv.logger, srv.exeOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &web3.DebugTraceCallResult{
+		Trace: result(),
 	}, nil
 }
 
+// DebugStorageRangeAt returns up to MaxResult storage entries of a contract from KeyStart. Since we only keep state
+// as it was at the end of each block the transaction index must be zero, for the state before the block, or the number
+// of transactions in the block, for the state after it.
+func (srv *EthService) DebugStorageRangeAt(req *web3.DebugStorageRangeAtParams) (*web3.DebugStorageRangeAtResult, error) {
+	addr, err := x.DecodeToAddress(req.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	start, err := decodeStorageKey(req.KeyStart)
+	if err != nil {
+		return nil, err
+	}
+	if req.MaxResult < 1 {
+		return nil, fmt.Errorf("maxResult must be positive")
+	}
+
+	height, err := srv.getBlockHeightByHash(req.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	numTxs, err := srv.blockchain.GetNumTxs(height)
+	if err != nil {
+		return nil, err
+	}
+	switch req.TxIndex {
+	case 0:
+		height--
+	case numTxs:
+	default:
+		return nil, fmt.Errorf("state is only available before (txIndex 0) or after (txIndex %d) the transactions "+
+			"of block %s", numTxs, req.BlockHash)
+	}
+	st, err := srv.history.LoadHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := make(map[string]web3.StorageEntry)
+	next, err := acmstate.IterateStorageRange(st, addr, start, nil, uint64(req.MaxResult),
+		func(key bin.Word256, value []byte) error {
+			storage[hexKeccak(key.Bytes())] = web3.StorageEntry{
+				Key:   x.EncodeBytes(key.Bytes()),
+				Value: x.EncodeBytes(bin.LeftPadWord256(value).Bytes()),
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	result := &web3.DebugStorageRangeAtResult{Storage: storage}
+	if next != nil {
+		nextKey := x.EncodeBytes(next.Bytes())
+		result.NextKey = &nextKey
+	}
+	return result, nil
+}
+
+// newTracer returns the tracer named by the config along with a function to collect its output
+func newTracer(config web3.TraceConfig) (evm.Tracer, func() interface{}, error) {
+	switch config.Tracer {
+	case "":
+		logger := evm.NewStructLogger(evm.StructLoggerConfig{
+			DisableStack:   config.DisableStack,
+			DisableMemory:  config.DisableMemory,
+			DisableStorage: config.DisableStorage,
+			Limit:          config.Limit,
+		})
+		return logger, func() interface{} { return logger.Result() }, nil
+	case "callTracer":
+		tracer := evm.NewCallTracer()
+		return tracer, func() interface{} { return tracer.Result() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported tracer '%s', expected callTracer or none for struct logs",
+			config.Tracer)
+	}
+}
+
 // EthGetBalance returns an accounts balance, or an error if it does not exist
 func (srv *EthService) EthGetBalance(req *web3.EthGetBalanceParams) (*web3.EthGetBalanceResult, error) {
 	addr, err := x.DecodeToAddress(req.Address)
@@ -193,8 +371,11 @@ func (srv *EthService) EthGetBalance(req *web3.EthGetBalanceParams) (*web3.EthGe
 		return nil, err
 	}
 
-	// TODO: read account state at height
-	acc, err := srv.accounts.GetAccount(addr)
+	st, err := srv.stateAt(req.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := st.GetAccount(addr)
 	if err != nil {
 		return nil, err
 	} else if acc == nil {
@@ -281,7 +462,11 @@ func (srv *EthService) EthGetCode(req *web3.EthGetCodeParams) (*web3.EthGetCodeR
 		return nil, err
 	}
 
-	acc, err := srv.accounts.GetAccount(addr)
+	st, err := srv.stateAt(req.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := st.GetAccount(addr)
 	if err != nil {
 		return nil, err
 	} else if acc == nil {
@@ -293,9 +478,29 @@ func (srv *EthService) EthGetCode(req *web3.EthGetCodeParams) (*web3.EthGetCodeR
 	}, nil
 }
 
+// EthGetStorageAt returns the word stored at a position in the storage of an address
 func (srv *EthService) EthGetStorageAt(req *web3.EthGetStorageAtParams) (*web3.EthGetStorageAtResult, error) {
-	// TODO
-	return nil, web3.ErrNotFound
+	addr, err := x.DecodeToAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	position, err := decodeStorageKey(req.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := srv.stateAt(req.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	value, err := st.GetStorage(addr, position)
+	if err != nil {
+		return nil, err
+	}
+
+	return &web3.EthGetStorageAtResult{
+		DataWord: x.EncodeBytes(bin.LeftPadWord256(value).Bytes()),
+	}, nil
 }
 
 func (srv *EthService) EthGetTransactionByBlockHashAndIndex(req *web3.EthGetTransactionByBlockHashAndIndexParams) (*web3.EthGetTransactionByBlockHashAndIndexResult, error) {
@@ -461,6 +666,24 @@ func (srv *EthService) EthGetTransactionReceipt(req *web3.EthGetTransactionRecei
 		status = x.EncodeNumber(0)
 	}
 
+	// The gas used by this and the transactions before it in the block
+	txes, err := srv.events.TxsAtHeight(txe.Height)
+	if err != nil {
+		return nil, err
+	}
+	var cumulativeGasUsed uint64
+	for _, blockTxe := range txes {
+		if blockTxe.Index <= txe.Index {
+			cumulativeGasUsed += blockTxe.Result.GetGasUsed()
+		}
+	}
+
+	// Without a fee schedule gas is free whatever price the transaction offers
+	var gasPrice uint64
+	if srv.blockchain.GenesisDoc().Params.Fees != nil {
+		gasPrice = tx.GasPrice
+	}
+
 	result := &web3.EthGetTransactionReceiptResult{
 		Receipt: web3.Receipt{
 			Status:            status,
@@ -469,8 +692,9 @@ func (srv *EthService) EthGetTransactionReceipt(req *web3.EthGetTransactionRecei
 			BlockHash:         x.EncodeBytes(block.Hash()),
 			From:              x.EncodeBytes(tx.GetInput().Address.Bytes()),
 			GasUsed:           x.EncodeNumber(txe.Result.GetGasUsed()),
+			EffectiveGasPrice: x.EncodeNumber(gasPrice),
 			TransactionHash:   x.EncodeBytes(hash),
-			CumulativeGasUsed: hexZero,
+			CumulativeGasUsed: x.EncodeNumber(cumulativeGasUsed),
 			LogsBloom:         hexZero,
 			Logs:              []web3.Logs{},
 		},
@@ -535,20 +759,52 @@ func (srv *EthService) EthPendingTransactions() (*web3.EthPendingTransactionsRes
 	}, nil
 }
 
+// EthEstimateGas simulates the call (or contract creation if there is no recipient) against current state to find the
+// least gas limit with which it succeeds, bounded by the gas given in the request or the default for simulated calls
 func (srv *EthService) EthEstimateGas(req *web3.EthEstimateGasParams) (*web3.EthEstimateGasResult, error) {
-	// TODO
+	from, to, data, value, err := decodeCall(req.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	var address *crypto.Address
+	if req.To != "" {
+		address = &to
+	}
+
+	gasCap := contexts.GasLimit
+	if gas := req.Gas; gas != "" {
+		gasCap, err = x.DecodeToNumber(gas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse gas: %v", err)
+		}
+	}
+
+	gasUsed, err := execution.EstimateGas(srv.accounts, srv.blockchain, from, address, data, value, gasCap, srv.logger,
+		srv.exeOptions...)
+	if err != nil {
+		return nil, err
+	}
+
 	return &web3.EthEstimateGasResult{
-		GasUsed: hexZero,
+		GasUsed: x.EncodeNumber(gasUsed),
 	}, nil
 }
 
+// EthGasPrice returns the minimum gas price of the chain's fee schedule, zero if it has none
 func (srv *EthService) EthGasPrice() (*web3.EthGasPriceResult, error) {
-	// TODO
 	return &web3.EthGasPriceResult{
-		GasPrice: hexZero,
+		GasPrice: x.EncodeNumber(srv.minimumGasPrice()),
 	}, nil
 }
 
+func (srv *EthService) minimumGasPrice() uint64 {
+	doc := srv.blockchain.GenesisDoc()
+	if doc.Params.Fees == nil {
+		return 0
+	}
+	return doc.Params.Fees.MinimumGasPrice
+}
+
 type RawTx struct {
 	Nonce    uint64 `json:"nonce"`
 	GasPrice uint64 `json:"gasPrice"`
@@ -557,7 +813,7 @@ type RawTx struct {
 	Value    []byte `json:"value"`
 	Data     []byte `json:"data"`
 
-	V uint64 `json:"v"`
+	V []byte `json:"v"`
 	R []byte `json:"r"`
 	S []byte `json:"s"`
 }
@@ -589,13 +845,20 @@ func (srv *EthService) EthSendRawTransaction(req *web3.EthSendRawTransactionPara
 		return nil, err
 	}
 
-	net := uint64(chainID)
-	enc, err := txs.RLPEncode(rawTx.Nonce, rawTx.GasPrice, rawTx.GasLimit, rawTx.To, rawTx.Value, rawTx.Data)
+	net := srv.chainID()
+	enc, err := txs.RLPEncode(net, rawTx.Nonce, rawTx.GasPrice, rawTx.GasLimit, rawTx.To, rawTx.Value, rawTx.Data)
 	if err != nil {
 		return nil, err
 	}
 
-	sig := crypto.CompressedSignatureFromParams(rawTx.V-net-8-1, rawTx.R, rawTx.S)
+	// Under EIP-155 v is the recovery ID plus 35 plus twice the chain ID
+	recoveryID := new(big.Int).SetBytes(rawTx.V)
+	recoveryID.Sub(recoveryID, new(big.Int).Lsh(net, 1))
+	recoveryID.Sub(recoveryID, big.NewInt(35))
+	if recoveryID.Sign() < 0 || recoveryID.Cmp(big.NewInt(1)) > 0 {
+		return nil, fmt.Errorf("transaction is not signed for chain ID %v", net)
+	}
+	sig := crypto.CompressedSignatureFromParams(recoveryID.Uint64()+27, rawTx.R, rawTx.S)
 	pub, err := crypto.PublicKeyFromSignature(sig, crypto.Keccak256(enc))
 	if err != nil {
 		return nil, err
@@ -665,7 +928,7 @@ func (srv *EthService) EthSyncing() (*web3.EthSyncingResult, error) {
 }
 
 func (srv *EthService) getBlockHeightByHash(hash string) (uint64, error) {
-	for i := uint64(1); i < srv.blockchain.LastBlockHeight(); i++ {
+	for i := uint64(1); i <= srv.blockchain.LastBlockHeight(); i++ {
 		head, err := srv.blockchain.GetBlockHeader(i)
 		if err != nil {
 			return 0, err
@@ -680,6 +943,18 @@ func (srv *EthService) getBlockHeaderAtHeight(height uint64) (*types.Header, err
 	return srv.blockchain.GetBlockHeader(height)
 }
 
+// decodeStorageKey accepts a storage key as a hex quantity or as up to 32 bytes of hex data
+func decodeStorageKey(key string) (bin.Word256, error) {
+	k, ok := new(big.Int).SetString(x.RemovePrefix(key), 16)
+	if !ok {
+		return bin.Zero256, fmt.Errorf("could not decode storage key %s", key)
+	}
+	if k.Sign() < 0 || k.BitLen() > bin.Word256Bits {
+		return bin.Zero256, fmt.Errorf("storage key %s is not a 256-bit word", key)
+	}
+	return bin.LeftPadWord256(k.Bytes()), nil
+}
+
 func hexKeccak(data []byte) string {
 	return x.EncodeBytes(crypto.Keccak256(data))
 }
@@ -823,6 +1098,225 @@ func (srv *EthService) getHeightByWordOrNumber(i string) (uint64, error) {
 	return height, nil
 }
 
+// EthGetLogs returns the logs matching the filter
+func (srv *EthService) EthGetLogs(req *web3.EthGetLogsParams) (*web3.EthGetLogsResult, error) {
+	filter, err := newLogFilter(&req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := srv.getLogRange(filter)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := srv.getLogs(start, end, filter.query)
+	if err != nil {
+		return nil, err
+	}
+	return &web3.EthGetLogsResult{
+		Logs: logs,
+	}, nil
+}
+
+// EthNewFilter installs a filter whose changes are the logs it matches in blocks committed since it was last polled
+func (srv *EthService) EthNewFilter(req *web3.EthNewFilterParams) (*web3.EthNewFilterResult, error) {
+	filter, err := newLogFilter(&req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &web3.EthNewFilterResult{
+		FilterId: srv.filters.install(&ethFilter{
+			logs:       filter,
+			lastHeight: srv.blockchain.LastBlockHeight(),
+		}),
+	}, nil
+}
+
+// EthNewBlockFilter installs a filter whose changes are the hashes of blocks committed since it was last polled
+func (srv *EthService) EthNewBlockFilter() (*web3.EthNewBlockFilterResult, error) {
+	return &web3.EthNewBlockFilterResult{
+		FilterId: srv.filters.install(&ethFilter{
+			lastHeight: srv.blockchain.LastBlockHeight(),
+		}),
+	}, nil
+}
+
+// EthGetFilterChanges returns the logs or block hashes for a filter since it was last polled
+func (srv *EthService) EthGetFilterChanges(req *web3.EthGetFilterChangesParams) (*web3.EthGetFilterChangesResult, error) {
+	result := &web3.EthGetFilterChangesResult{
+		Logs:        []web3.Logs{},
+		BlockHashes: []string{},
+	}
+	err := srv.filters.poll(req.FilterId, func(filter *ethFilter) error {
+		tip := srv.blockchain.LastBlockHeight()
+		if tip <= filter.lastHeight {
+			return nil
+		}
+		start, end := filter.lastHeight+1, tip
+		if filter.logs == nil {
+			for height := start; height <= end; height++ {
+				header, err := srv.getBlockHeaderAtHeight(height)
+				if err != nil {
+					return err
+				}
+				result.BlockHashes = append(result.BlockHashes, hexKeccak(header.Hash().Bytes()))
+			}
+		} else {
+			from, to, err := srv.getLogRange(filter.logs)
+			if err != nil {
+				return err
+			}
+			if from > start {
+				start = from
+			}
+			if to < end {
+				end = to
+			}
+			if start <= end {
+				result.Logs, err = srv.getLogs(start, end, filter.logs.query)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		filter.lastHeight = tip
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EthGetFilterLogs returns all logs matching a filter created by eth_newFilter
+func (srv *EthService) EthGetFilterLogs(req *web3.EthGetFilterLogsParams) (*web3.EthGetFilterLogsResult, error) {
+	var logs []web3.Logs
+	err := srv.filters.poll(req.FilterId, func(filter *ethFilter) error {
+		if filter.logs == nil {
+			return fmt.Errorf("filter %s is not a log filter", req.FilterId)
+		}
+		start, end, err := srv.getLogRange(filter.logs)
+		if err != nil {
+			return err
+		}
+		logs, err = srv.getLogs(start, end, filter.logs.query)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &web3.EthGetFilterLogsResult{
+		Logs: logs,
+	}, nil
+}
+
+// EthUninstallFilter removes a filter, filters are also removed when they have not been polled for some time
+func (srv *EthService) EthUninstallFilter(req *web3.EthUninstallFilterParams) (*web3.EthUninstallFilterResult, error) {
+	return &web3.EthUninstallFilterResult{
+		FilterUninstalledSuccess: srv.filters.uninstall(req.FilterId),
+	}, nil
+}
+
+// getLogRange returns the inclusive range of heights covered by the filter
+func (srv *EthService) getLogRange(filter *logFilter) (uint64, uint64, error) {
+	if filter.blockHash != "" {
+		height, err := srv.getBlockHeightByHash(filter.blockHash)
+		return height, height, err
+	}
+	start, err := srv.getHeightByWordOrNumber(orLatest(filter.fromBlock))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := srv.getHeightByWordOrNumber(orLatest(filter.toBlock))
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// stateAt returns the state as of blockNumber, reading the current state directly for the latest block
+func (srv *EthService) stateAt(blockNumber string) (acmstate.Reader, error) {
+	switch blockNumber {
+	case "", "latest", "pending":
+		return srv.accounts, nil
+	}
+	height, err := srv.getHeightByWordOrNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return srv.history.LoadHeight(height)
+}
+
+func orLatest(height string) string {
+	if height == "" {
+		return "latest"
+	}
+	return height
+}
+
+// getLogs returns the logs matching qry from successful transactions in the inclusive range of heights
+func (srv *EthService) getLogs(start, end uint64, qry query.Query) ([]web3.Logs, error) {
+	logs := []web3.Logs{}
+	var stack exec.TxStack
+	var blockHash string
+	// Position of the log within its block
+	var logIndex uint64
+	err := srv.events.IterateStreamEvents(&start, &end, func(sev *exec.StreamEvent) error {
+		if sev.BeginBlock != nil {
+			header, err := srv.getBlockHeaderAtHeight(sev.BeginBlock.Height)
+			if err != nil {
+				return err
+			}
+			blockHash = hexKeccak(header.Hash().Bytes())
+			logIndex = 0
+			return nil
+		}
+		txe, err := stack.Consume(sev)
+		if err != nil {
+			return err
+		}
+		if txe != nil {
+			logs = appendLogs(logs, txe, blockHash, &logIndex, qry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// appendLogs appends the logs from a successful txe that match qry, logIndex counts the logs in the block so far
+func appendLogs(logs []web3.Logs, txe *exec.TxExecution, blockHash string, logIndex *uint64,
+	qry query.Query) []web3.Logs {
+
+	if txe.Exception != nil {
+		return logs
+	}
+	for _, ev := range txe.Events {
+		if ev.Log == nil {
+			continue
+		}
+		if qry.Matches(ev) {
+			topics := make([]string, len(ev.Log.Topics))
+			for i, topic := range ev.Log.Topics {
+				topics[i] = x.EncodeBytes(topic.Bytes())
+			}
+			logs = append(logs, web3.Logs{
+				LogIndex:         x.EncodeNumber(*logIndex),
+				TransactionIndex: x.EncodeNumber(txe.Index),
+				TransactionHash:  x.EncodeBytes(txe.TxHash),
+				Address:          x.EncodeBytes(ev.Log.Address.Bytes()),
+				BlockHash:        blockHash,
+				BlockNumber:      x.EncodeNumber(txe.Height),
+				Data:             x.EncodeBytes(ev.Log.Data),
+				Topics:           topics,
+			})
+		}
+		*logIndex++
+	}
+	return logs
+}
+
 // EthSendTransaction constructs, signs and broadcasts a tx from the local node
 // Note: https://github.com/ethereum/EIPs/blob/master/EIPS/eip-1767.md#rationale
 func (srv *EthService) EthSendTransaction(req *web3.EthSendTransactionParams) (*web3.EthSendTransactionResult, error) {
@@ -976,10 +1470,6 @@ func (srv *EthService) EthSign(req *web3.EthSignParams) (*web3.EthSignResult, er
 
 // N / A
 
-func (srv *EthService) EthUninstallFilter(*web3.EthUninstallFilterParams) (*web3.EthUninstallFilterResult, error) {
-	return nil, web3.ErrNotFound
-}
-
 func (srv *EthService) EthSubmitHashrate(req *web3.EthSubmitHashrateParams) (*web3.EthSubmitHashrateResult, error) {
 	return nil, web3.ErrNotFound
 }
@@ -988,14 +1478,6 @@ func (srv *EthService) EthSubmitWork(*web3.EthSubmitWorkParams) (*web3.EthSubmit
 	return nil, web3.ErrNotFound
 }
 
-func (srv *EthService) EthNewBlockFilter() (*web3.EthNewBlockFilterResult, error) {
-	return nil, web3.ErrNotFound
-}
-
-func (srv *EthService) EthNewFilter(req *web3.EthNewFilterParams) (*web3.EthNewFilterResult, error) {
-	return nil, web3.ErrNotFound
-}
-
 func (srv *EthService) EthNewPendingTransactionFilter() (*web3.EthNewPendingTransactionFilterResult, error) {
 	return nil, web3.ErrNotFound
 }
@@ -1016,26 +1498,87 @@ func (srv *EthService) EthGetUncleCountByBlockNumber(req *web3.EthGetUncleCountB
 	return nil, web3.ErrNotFound
 }
 
+// EthGetProof returns the account and storage values with IAVL proofs against the AppHash of the state at the block.
+// Since our state is not a patricia trie each proof has a single element: the protobuf-encoded storage.ForestProof
+// (see state.VerifyAccountProof and state.VerifyStorageProof).
 func (srv *EthService) EthGetProof(req *web3.EthGetProofParams) (*web3.EthGetProofResult, error) {
-	return nil, web3.ErrNotFound
-}
+	address, err := x.DecodeToAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	height, err := srv.getHeightByWordOrNumber(orLatest(req.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.history.LoadHeight(height)
+	if err != nil {
+		return nil, err
+	}
 
-func (srv *EthService) EthGetWork() (*web3.EthGetWorkResult, error) {
-	return nil, web3.ErrNotFound
-}
+	acc, proof, err := st.GetAccountWithProof(address)
+	if err != nil {
+		return nil, err
+	}
+	accountProof, err := encodeProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	result := web3.ProofAccount{
+		Address:      req.Address,
+		AccountProof: accountProof,
+		Balance:      hexZero,
+		Nonce:        hexZero,
+		CodeHash:     x.EncodeBytes(crypto.Keccak256(nil)),
+		StorageProof: make([]web3.StorageProof, len(req.StorageKeys)),
+	}
+	if acc != nil {
+		result.Balance = x.EncodeBytes(balance.NativeToWei(acc.Balance).Bytes())
+		result.Nonce = x.EncodeNumber(acc.Sequence)
+		result.CodeHash = x.EncodeBytes(crypto.Keccak256(acc.EVMCode))
+	}
 
-func (srv *EthService) EthGetFilterChanges(req *web3.EthGetFilterChangesParams) (*web3.EthGetFilterChangesResult, error) {
-	return nil, web3.ErrNotFound
+	var storageRoot []byte
+	for i, k := range req.StorageKeys {
+		key, err := decodeStorageKey(k)
+		if err != nil {
+			return nil, err
+		}
+		value, proof, err := st.GetStorageWithProof(address, key)
+		if err != nil {
+			return nil, err
+		}
+		storageProof, err := encodeProof(proof)
+		if err != nil {
+			return nil, err
+		}
+		if proof.Commit != nil {
+			storageRoot = proof.Commit.Hash
+		}
+		result.StorageProof[i] = web3.StorageProof{
+			Key:   k,
+			Value: x.EncodeBytes(bin.LeftPadWord256(value).Bytes()),
+			Proof: storageProof,
+		}
+	}
+	result.StorageHash = x.EncodeBytes(storageRoot)
+
+	return &web3.EthGetProofResult{
+		ProofAccountOrNull: result,
+	}, nil
 }
 
-func (srv *EthService) EthGetFilterLogs(req *web3.EthGetFilterLogsParams) (*web3.EthGetFilterLogsResult, error) {
-	return nil, web3.ErrNotFound
+func encodeProof(proof *storage.ForestProof) ([]string, error) {
+	bs, err := encoding.Encode(proof)
+	if err != nil {
+		return nil, err
+	}
+	return []string{x.EncodeBytes(bs)}, nil
 }
 
-func (srv *EthService) EthCoinbase() (*web3.EthCoinbaseResult, error) {
+func (srv *EthService) EthGetWork() (*web3.EthGetWorkResult, error) {
 	return nil, web3.ErrNotFound
 }
 
-func (srv *EthService) EthGetLogs(req *web3.EthGetLogsParams) (*web3.EthGetLogsResult, error) {
+func (srv *EthService) EthCoinbase() (*web3.EthCoinbaseResult, error) {
 	return nil, web3.ErrNotFound
 }
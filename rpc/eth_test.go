@@ -3,20 +3,34 @@ package rpc_test
 import (
 	"context"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/hyperledger/burrow/acm/acmstate"
 	"github.com/hyperledger/burrow/acm/balance"
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/encoding"
 	x "github.com/hyperledger/burrow/encoding/hex"
+	"github.com/hyperledger/burrow/encoding/rlp"
+	"github.com/hyperledger/burrow/execution/engine"
+	"github.com/hyperledger/burrow/execution/evm"
 	"github.com/hyperledger/burrow/execution/evm/abi"
+	"github.com/hyperledger/burrow/execution/evm/asm"
+	"github.com/hyperledger/burrow/execution/evm/asm/bc"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/execution/state"
 	"github.com/hyperledger/burrow/integration"
 	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/project"
 	"github.com/hyperledger/burrow/rpc"
 	"github.com/hyperledger/burrow/rpc/web3"
+	"github.com/hyperledger/burrow/storage"
+	"github.com/hyperledger/burrow/txs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,8 +68,8 @@ func TestWeb3Service(t *testing.T) {
 	accountState := kern.State
 	eventsState := kern.State
 	validatorState := kern.State
-	eth := rpc.NewEthService(accountState, eventsState, kern.Blockchain, validatorState,
-		nodeView, kern.Transactor, store, kern.Logger)
+	eth := rpc.NewEthService(accountState, kern.State, eventsState, kern.Emitter, kern.Blockchain, validatorState,
+		nodeView, kern.Transactor, kern, store, kern.Logger)
 
 	t.Run("Web3Sha3", func(t *testing.T) {
 		result, err := eth.Web3Sha3(&web3.Web3Sha3Params{"0x68656c6c6f20776f726c64"}) // hello world
@@ -86,7 +100,9 @@ func TestWeb3Service(t *testing.T) {
 		t.Run("NetVersion", func(t *testing.T) {
 			result, err := eth.NetVersion()
 			require.NoError(t, err)
-			require.Equal(t, x.EncodeNumber(1), result.ChainID)
+			chainID, err := eth.EthChainId()
+			require.NoError(t, err)
+			require.Equal(t, chainID.ChainId, result.ChainID)
 		})
 
 		t.Run("EthProtocolVersion", func(t *testing.T) {
@@ -98,13 +114,28 @@ func TestWeb3Service(t *testing.T) {
 		t.Run("EthChainId", func(t *testing.T) {
 			result, err := eth.EthChainId()
 			require.NoError(t, err)
-			doc := config.GenesisDoc
-			require.Equal(t, doc.ChainID(), result.ChainId)
+			// The same as contracts see
+			gas := uint64(100)
+			out, err := evm.Default().Execute(acmstate.NewMemoryState(), kern.Blockchain, exec.NewNoopEventSink(),
+				engine.CallParams{Gas: &gas}, bc.MustSplice(asm.CHAINID, asm.PUSH1, 0, asm.MSTORE, asm.PUSH1, 32,
+					asm.PUSH1, 0, asm.RETURN))
+			require.NoError(t, err)
+			require.Equal(t, x.EncodeBigInt(new(big.Int).SetBytes(out)), result.ChainId)
 		})
 	})
 
+	blockFilter, err := eth.EthNewBlockFilter()
+	require.NoError(t, err)
+
+	subCtx, cancelSub := context.WithCancel(ctx)
+	defer cancelSub()
+	heads, err := eth.EthSubscribe(subCtx, &web3.EthSubscribeParams{SubscriptionType: "newHeads"})
+	require.NoError(t, err)
+	_, err = eth.EthSubscribe(subCtx, &web3.EthSubscribeParams{SubscriptionType: "syncing"})
+	require.Error(t, err)
+
 	t.Run("EthTransactions", func(t *testing.T) {
-		var txHash, contractAddress string
+		var txHash, contractAddress, blockHash string
 
 		receivee := genesisAccounts[2].GetPublicKey().GetAddress()
 		acc, err := kern.State.GetAccount(receivee)
@@ -112,12 +143,33 @@ func TestWeb3Service(t *testing.T) {
 		before := acc.GetBalance()
 
 		t.Run("EthSendRawTransaction", func(t *testing.T) {
-			// see: https://github.com/ethereumjs/ethereumjs-tx/blob/master/examples/transactions.ts#L9
-			raw := `0xf867808082520894f97798df751deb4b6e39d4cf998ee7cd4dcb9acc880de0b6b3a76400008025a0f0d2396973296cd6a71141c974d4a851f5eae8f08a8fba2dc36a0fef9bd6440ca0171995aa750d3f9f8e4d0eac93ff67634274f3c5acf422723f49ff09a6885422`
+			result, err := eth.EthChainId()
+			require.NoError(t, err)
+			chainID, err := x.DecodeToBigInt(result.ChainId)
+			require.NoError(t, err)
+			// Sign as a wallet would with the chain ID we advertise (EIP-155)
+			value := balance.NativeToWei(1).Bytes()
+			signBytes, err := txs.RLPEncode(chainID, 0, 0, 21000, receivee.Bytes(), value, nil)
+			require.NoError(t, err)
+			key, _ := btcec.PrivKeyFromBytes(btcec.S256(), genesisAccounts[1].PrivateKey().RawBytes())
+			sig, err := btcec.SignCompact(btcec.S256(), key, crypto.Keccak256(signBytes), false)
+			require.NoError(t, err)
+			v := new(big.Int).Lsh(chainID, 1)
+			v.Add(v, big.NewInt(int64(sig[0]-27+35)))
+			raw, err := rlp.Encode([]interface{}{uint64(0), uint64(0), uint64(21000), receivee.Bytes(), value,
+				[]byte{}, v.Bytes(), sig[1:33], sig[33:]})
+			require.NoError(t, err)
 			_, err = eth.EthSendRawTransaction(&web3.EthSendRawTransactionParams{
-				SignedTransactionData: raw,
+				SignedTransactionData: x.EncodeBytes(raw),
 			})
 			require.NoError(t, err)
+
+			// Signatures for another chain are rejected
+			_, err = eth.EthSendRawTransaction(&web3.EthSendRawTransactionParams{
+				// see: https://github.com/ethereumjs/ethereumjs-tx/blob/master/examples/transactions.ts#L9
+				SignedTransactionData: `0xf867808082520894f97798df751deb4b6e39d4cf998ee7cd4dcb9acc880de0b6b3a76400008025a0f0d2396973296cd6a71141c974d4a851f5eae8f08a8fba2dc36a0fef9bd6440ca0171995aa750d3f9f8e4d0eac93ff67634274f3c5acf422723f49ff09a6885422`,
+			})
+			require.Error(t, err)
 		})
 
 		t.Run("EthGetBalance", func(t *testing.T) {
@@ -132,6 +184,17 @@ func TestWeb3Service(t *testing.T) {
 			require.Equal(t, after.Uint64(), before+1)
 		})
 
+		t.Run("EthGetBalanceAtHeight", func(t *testing.T) {
+			result, err := eth.EthGetBalance(&web3.EthGetBalanceParams{
+				Address:     x.EncodeBytes(receivee.Bytes()),
+				BlockNumber: "earliest",
+			})
+			require.NoError(t, err)
+			genesisBalance, err := x.DecodeToBigInt(result.GetBalanceResult)
+			require.NoError(t, err)
+			require.Equal(t, before, balance.WeiToNative(genesisBalance.Bytes()).Uint64())
+		})
+
 		t.Run("EthGetTransactionCount", func(t *testing.T) {
 			result, err := eth.EthGetTransactionCount(&web3.EthGetTransactionCountParams{
 				Address: genesisAccounts[1].GetAddress().String(),
@@ -162,6 +225,12 @@ func TestWeb3Service(t *testing.T) {
 			require.NoError(t, err)
 			contractAddress = result.Receipt.ContractAddress
 			require.NotEmpty(t, contractAddress)
+			// Receipts do not give the block hash by which blocks are found
+			block, err := eth.EthGetBlockByNumber(&web3.EthGetBlockByNumberParams{
+				BlockNumber: result.Receipt.BlockNumber,
+			})
+			require.NoError(t, err)
+			blockHash = block.GetBlockByNumberResult.Hash
 		})
 
 		t.Run("EthCall", func(t *testing.T) {
@@ -187,6 +256,95 @@ func TestWeb3Service(t *testing.T) {
 			require.Equal(t, "Hello, World", vars[0].Value)
 		})
 
+		t.Run("EthEstimateGas", func(t *testing.T) {
+			require.NotEmpty(t, contractAddress, "need contract address to call")
+
+			packed, _, err := abi.EncodeFunctionCall(string(rpc.Abi_HelloWorld), "Hello", logger)
+			require.NoError(t, err)
+
+			result, err := eth.EthEstimateGas(&web3.EthEstimateGasParams{
+				Transaction: web3.Transaction{
+					From: x.EncodeBytes(genesisAccounts[1].GetAddress().Bytes()),
+					To:   contractAddress,
+					Data: x.EncodeBytes(packed),
+				},
+			})
+			require.NoError(t, err)
+			gas, err := x.DecodeToNumber(result.GasUsed)
+			require.NoError(t, err)
+			require.True(t, gas > 0)
+
+			_, err = eth.EthEstimateGas(&web3.EthEstimateGasParams{
+				Transaction: web3.Transaction{
+					From: x.EncodeBytes(genesisAccounts[1].GetAddress().Bytes()),
+					To:   contractAddress,
+					Gas:  x.EncodeNumber(gas - 1),
+					Data: x.EncodeBytes(packed),
+				},
+			})
+			require.Error(t, err)
+
+			// Hello is not payable so sending it value reverts
+			_, err = eth.EthEstimateGas(&web3.EthEstimateGasParams{
+				Transaction: web3.Transaction{
+					From:  x.EncodeBytes(genesisAccounts[1].GetAddress().Bytes()),
+					To:    contractAddress,
+					Value: x.EncodeNumber(1),
+					Data:  x.EncodeBytes(packed),
+				},
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "reverted")
+		})
+
+		t.Run("DebugTraceCall", func(t *testing.T) {
+			require.NotEmpty(t, contractAddress, "need contract address to call")
+
+			packed, _, err := abi.EncodeFunctionCall(string(rpc.Abi_HelloWorld), "Hello", logger)
+			require.NoError(t, err)
+			tx := web3.Transaction{
+				From: x.EncodeBytes(genesisAccounts[1].GetAddress().Bytes()),
+				To:   contractAddress,
+				Data: x.EncodeBytes(packed),
+			}
+
+			result, err := eth.DebugTraceCall(&web3.DebugTraceCallParams{Transaction: tx})
+			require.NoError(t, err)
+			logs := result.Trace.(*evm.StructLogResult)
+			require.False(t, logs.Failed)
+			require.NotEmpty(t, logs.StructLogs)
+			require.Equal(t, "RETURN", logs.StructLogs[len(logs.StructLogs)-1].Op)
+
+			result, err = eth.DebugTraceCall(&web3.DebugTraceCallParams{
+				Transaction: tx,
+				TraceConfig: web3.TraceConfig{Tracer: "callTracer"},
+			})
+			require.NoError(t, err)
+			frame := result.Trace.(*evm.CallFrame)
+			require.Equal(t, "CALL", frame.Type)
+			require.Equal(t, contractAddress, frame.To)
+			require.Equal(t, tx.Data, frame.Input)
+		})
+
+		t.Run("DebugTraceTransaction", func(t *testing.T) {
+			require.NotEmpty(t, txHash, "need tx hash to trace tx")
+			result, err := eth.DebugTraceTransaction(&web3.DebugTraceTransactionParams{
+				TransactionHash: txHash,
+				TraceConfig:     web3.TraceConfig{Tracer: "callTracer"},
+			})
+			require.NoError(t, err)
+			frame := result.Trace.(*evm.CallFrame)
+			require.Equal(t, "CREATE", frame.Type)
+			require.Equal(t, contractAddress, frame.To)
+			require.Empty(t, frame.Error)
+
+			_, err = eth.DebugTraceTransaction(&web3.DebugTraceTransactionParams{
+				TransactionHash: txHash,
+				TraceConfig:     web3.TraceConfig{Tracer: "prestateTracer"},
+			})
+			require.Error(t, err)
+		})
+
 		t.Run("EthGetCode", func(t *testing.T) {
 			require.NotEmpty(t, contractAddress, "need contract address get code")
 			result, err := eth.EthGetCode(&web3.EthGetCodeParams{
@@ -195,6 +353,108 @@ func TestWeb3Service(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, x.EncodeBytes(rpc.DeployedBytecode_HelloWorld), strings.ToLower(result.Bytes))
 		})
+
+		t.Run("EthGetStorageAt", func(t *testing.T) {
+			result, err := eth.EthGetStorageAt(&web3.EthGetStorageAtParams{
+				Address:     contractAddress,
+				Position:    x.EncodeNumber(0),
+				BlockNumber: "latest",
+			})
+			require.NoError(t, err)
+			word, err := x.DecodeToBytes(result.DataWord)
+			require.NoError(t, err)
+			require.Len(t, word, 32)
+
+			// The contract did not exist at genesis
+			_, err = eth.EthGetCode(&web3.EthGetCodeParams{
+				Address:     contractAddress,
+				BlockNumber: "earliest",
+			})
+			require.Error(t, err)
+		})
+
+		t.Run("DebugStorageRangeAt", func(t *testing.T) {
+			count, err := eth.EthGetBlockTransactionCountByHash(&web3.EthGetBlockTransactionCountByHashParams{
+				BlockHash: blockHash,
+			})
+			require.NoError(t, err)
+			numTxs, err := x.DecodeToNumber(count.BlockTransactionCountByHash)
+			require.NoError(t, err)
+			params := &web3.DebugStorageRangeAtParams{
+				BlockHash:       blockHash,
+				TxIndex:         int(numTxs),
+				ContractAddress: contractAddress,
+				KeyStart:        x.EncodeNumber(0),
+				MaxResult:       10,
+			}
+			// HelloWorld keeps nothing in storage
+			result, err := eth.DebugStorageRangeAt(params)
+			require.NoError(t, err)
+			require.Empty(t, result.Storage)
+			require.Nil(t, result.NextKey)
+
+			// We do not have the state between transactions
+			params.TxIndex = int(numTxs) + 1
+			_, err = eth.DebugStorageRangeAt(params)
+			require.Error(t, err)
+		})
+
+		t.Run("EthGetProof", func(t *testing.T) {
+			address := genesisAccounts[1].GetAddress()
+			height := kern.Blockchain.LastBlockHeight()
+			result, err := eth.EthGetProof(&web3.EthGetProofParams{
+				Address:     x.EncodeBytes(address.Bytes()),
+				StorageKeys: []string{x.EncodeNumber(0)},
+				BlockNumber: x.EncodeNumber(height),
+			})
+			require.NoError(t, err)
+			require.Len(t, result.ProofAccountOrNull.AccountProof, 1)
+			require.Len(t, result.ProofAccountOrNull.StorageProof, 1)
+
+			st, err := kern.State.LoadHeight(height)
+			require.NoError(t, err)
+			acc, err := st.GetAccount(address)
+			require.NoError(t, err)
+			bs, err := x.DecodeToBytes(result.ProofAccountOrNull.AccountProof[0])
+			require.NoError(t, err)
+			proof := new(storage.ForestProof)
+			require.NoError(t, encoding.Decode(bs, proof))
+			require.NoError(t, state.VerifyAccountProof(st.Forest.Hash(), address, acc, proof))
+		})
+	})
+
+	t.Run("EthFilters", func(t *testing.T) {
+		changes, err := eth.EthGetFilterChanges(&web3.EthGetFilterChangesParams{FilterId: blockFilter.FilterId})
+		require.NoError(t, err)
+		require.NotEmpty(t, changes.BlockHashes)
+		block, err := eth.EthGetBlockByHash(&web3.EthGetBlockByHashParams{BlockHash: changes.BlockHashes[0]})
+		require.NoError(t, err)
+		require.Equal(t, changes.BlockHashes[0], block.GetBlockByHashResult.Hash)
+
+		// HelloWorld does not emit events
+		logs, err := eth.EthGetLogs(&web3.EthGetLogsParams{Filter: web3.Filter{FromBlock: "earliest"}})
+		require.NoError(t, err)
+		require.Empty(t, logs.Logs)
+
+		result, err := eth.EthUninstallFilter(&web3.EthUninstallFilterParams{FilterId: blockFilter.FilterId})
+		require.NoError(t, err)
+		require.True(t, result.FilterUninstalledSuccess)
+		_, err = eth.EthGetFilterChanges(&web3.EthGetFilterChangesParams{FilterId: blockFilter.FilterId})
+		require.Error(t, err)
+	})
+
+	t.Run("EthSubscribe", func(t *testing.T) {
+		select {
+		case head := <-heads:
+			require.IsType(t, web3.Block{}, head)
+			require.NotEmpty(t, head.(web3.Block).Hash)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for newHeads notification")
+		}
+		cancelSub()
+		for range heads {
+			// drained until closed
+		}
 	})
 
 	t.Run("EthMining", func(t *testing.T) {
@@ -0,0 +1,248 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	x "github.com/hyperledger/burrow/encoding/hex"
+	"github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/event/query"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/rpc/web3"
+)
+
+// Filters that are not polled within this period are uninstalled (matches geth)
+const filterTimeout = 5 * time.Minute
+
+// logFilter is a web3 filter object translated into a query over LogEvents
+type logFilter struct {
+	fromBlock string
+	toBlock   string
+	blockHash string
+	query     query.Query
+}
+
+func newLogFilter(filter *web3.Filter) (*logFilter, error) {
+	lf := &logFilter{
+		fromBlock: filter.FromBlock,
+		toBlock:   filter.ToBlock,
+	}
+	if filter.BlockHash != "" {
+		if filter.FromBlock != "" || filter.ToBlock != "" {
+			return nil, fmt.Errorf("cannot specify blockHash with fromBlock or toBlock")
+		}
+		if _, err := x.DecodeToBytes(filter.BlockHash); err != nil {
+			return nil, err
+		}
+		lf.blockHash = strings.ToLower(filter.BlockHash)
+	}
+	addresses, err := filterAddresses(filter.Address)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := filterTopics(filter.Topics)
+	if err != nil {
+		return nil, err
+	}
+	lf.query, err = logQuery(addresses, topics).Query()
+	if err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+// logQuery matches LogEvents emitted by any of addresses with, for each position, any of the topics at that position.
+// An empty set of addresses or topics at a position matches anything.
+func logQuery(addresses []crypto.Address, topics [][]binary.Word256) *query.Builder {
+	clauses := []string{query.NewBuilder().AndEquals(event.EventTypeKey, exec.TypeLog.String()).String()}
+	values := make([]string, len(addresses))
+	for i, address := range addresses {
+		values[i] = address.String()
+	}
+	clauses = append(clauses, anyOf(event.AddressKey, values)...)
+	for i, alternatives := range topics {
+		values = make([]string, len(alternatives))
+		for j, topic := range alternatives {
+			values[j] = topic.String()
+		}
+		clauses = append(clauses, anyOf(exec.LogNKey(i), values)...)
+	}
+	return query.NewBuilder(clauses...)
+}
+
+// Returns a condition matching tag against any of values, or no conditions if there are no values
+func anyOf(tag string, values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	conditions := make([]string, len(values))
+	for i, value := range values {
+		conditions[i] = fmt.Sprintf("%s = '%s'", tag, value)
+	}
+	return []string{"(" + strings.Join(conditions, " OR ") + ")"}
+}
+
+// Address may be null, a single address, or a list of addresses
+func filterAddresses(address interface{}) ([]crypto.Address, error) {
+	var addresses []crypto.Address
+	switch addr := address.(type) {
+	case nil:
+	case string:
+		a, err := x.DecodeToAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	case []interface{}:
+		for _, v := range addr {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected address in filter but got %v", v)
+			}
+			a, err := x.DecodeToAddress(s)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, a)
+		}
+	default:
+		return nil, fmt.Errorf("expected address or list of addresses in filter but got %v", address)
+	}
+	return addresses, nil
+}
+
+// Each topic may be null (matching anything), a single topic, or a list of alternative topics
+func filterTopics(topics []interface{}) ([][]binary.Word256, error) {
+	words := make([][]binary.Word256, len(topics))
+	for i, topic := range topics {
+		switch t := topic.(type) {
+		case nil:
+		case string:
+			word, err := decodeTopic(t)
+			if err != nil {
+				return nil, err
+			}
+			words[i] = []binary.Word256{word}
+		case []interface{}:
+			for _, v := range t {
+				s, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected topic in filter but got %v", v)
+				}
+				word, err := decodeTopic(s)
+				if err != nil {
+					return nil, err
+				}
+				words[i] = append(words[i], word)
+			}
+		default:
+			return nil, fmt.Errorf("expected topic or list of topics in filter but got %v", topic)
+		}
+	}
+	return words, nil
+}
+
+func decodeTopic(topic string) (binary.Word256, error) {
+	bs, err := x.DecodeToBytes(topic)
+	if err != nil {
+		return binary.Zero256, err
+	}
+	if len(bs) > binary.Word256Bytes {
+		return binary.Zero256, fmt.Errorf("topic %s is longer than 32 bytes", topic)
+	}
+	return binary.LeftPadWord256(bs), nil
+}
+
+// ethFilter is a filter installed by eth_newFilter or eth_newBlockFilter
+type ethFilter struct {
+	// Serialises polls of this filter
+	sync.Mutex
+	// nil for block filters
+	logs *logFilter
+	// The last height returned by eth_getFilterChanges
+	lastHeight uint64
+	lastPolled time.Time
+}
+
+// filterRegistry holds installed filters, removing any that have not been polled within the timeout
+type filterRegistry struct {
+	sync.Mutex
+	filters map[uint64]*ethFilter
+	nextID  uint64
+	timeout time.Duration
+}
+
+func newFilterRegistry(timeout time.Duration) *filterRegistry {
+	return &filterRegistry{
+		filters: make(map[uint64]*ethFilter),
+		nextID:  1,
+		timeout: timeout,
+	}
+}
+
+func (fr *filterRegistry) install(filter *ethFilter) string {
+	fr.Lock()
+	defer fr.Unlock()
+	fr.expire()
+	id := fr.nextID
+	fr.nextID++
+	filter.lastPolled = time.Now()
+	fr.filters[id] = filter
+	return x.EncodeNumber(id)
+}
+
+// poll passes the filter with id to update, holding only that filter's lock while doing so, and marks it as polled
+func (fr *filterRegistry) poll(id string, update func(*ethFilter) error) error {
+	fr.Lock()
+	fr.expire()
+	filter, err := fr.get(id)
+	if err == nil {
+		filter.lastPolled = time.Now()
+	}
+	fr.Unlock()
+	if err != nil {
+		return err
+	}
+	filter.Lock()
+	defer filter.Unlock()
+	return update(filter)
+}
+
+func (fr *filterRegistry) uninstall(id string) bool {
+	fr.Lock()
+	defer fr.Unlock()
+	fr.expire()
+	n, err := x.DecodeToNumber(id)
+	if err != nil {
+		return false
+	}
+	_, ok := fr.filters[n]
+	delete(fr.filters, n)
+	return ok
+}
+
+func (fr *filterRegistry) get(id string) (*ethFilter, error) {
+	n, err := x.DecodeToNumber(id)
+	if err != nil {
+		return nil, err
+	}
+	filter, ok := fr.filters[n]
+	if !ok {
+		return nil, fmt.Errorf("filter %s not found", id)
+	}
+	return filter, nil
+}
+
+// Must be called with the lock held
+func (fr *filterRegistry) expire() {
+	cutoff := time.Now().Add(-fr.timeout)
+	for id, filter := range fr.filters {
+		if filter.lastPolled.Before(cutoff) {
+			delete(fr.filters, id)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	x "github.com/hyperledger/burrow/encoding/hex"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/rpc/web3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilter(t *testing.T) {
+	address := crypto.Address{1, 2, 3}
+	other := crypto.Address{4, 5, 6}
+	topic0 := binary.LeftPadWord256([]byte{0xAA})
+	topic1 := binary.LeftPadWord256([]byte{0xBB})
+
+	ev := &exec.Event{
+		Header: &exec.Header{
+			EventType: exec.TypeLog,
+		},
+		Log: &exec.LogEvent{
+			Address: address,
+			Topics:  []binary.Word256{topic0, topic1},
+		},
+	}
+
+	matches := func(filter web3.Filter) bool {
+		lf, err := newLogFilter(&filter)
+		require.NoError(t, err)
+		return lf.query.Matches(ev)
+	}
+
+	assert.True(t, matches(web3.Filter{}))
+	assert.True(t, matches(web3.Filter{Address: x.EncodeBytes(address.Bytes())}))
+	assert.False(t, matches(web3.Filter{Address: x.EncodeBytes(other.Bytes())}))
+	assert.True(t, matches(web3.Filter{
+		Address: []interface{}{x.EncodeBytes(other.Bytes()), x.EncodeBytes(address.Bytes())},
+	}))
+	// Topics are positional with null as a wildcard
+	assert.True(t, matches(web3.Filter{Topics: []interface{}{nil, "0xbb"}}))
+	assert.False(t, matches(web3.Filter{Topics: []interface{}{"0xbb"}}))
+	assert.True(t, matches(web3.Filter{Topics: []interface{}{[]interface{}{"0xbb", "0xaa"}}}))
+	assert.False(t, matches(web3.Filter{
+		Address: x.EncodeBytes(address.Bytes()),
+		Topics:  []interface{}{x.EncodeBytes(topic0.Bytes()), "0xcc"},
+	}))
+
+	_, err := newLogFilter(&web3.Filter{BlockHash: "0x01", FromBlock: "latest"})
+	require.Error(t, err)
+	_, err = newLogFilter(&web3.Filter{Topics: []interface{}{3}})
+	require.Error(t, err)
+}
+
+func TestFilterRegistry(t *testing.T) {
+	fr := newFilterRegistry(time.Minute)
+	id := fr.install(&ethFilter{lastHeight: 3})
+
+	err := fr.poll(id, func(filter *ethFilter) error {
+		assert.Equal(t, uint64(3), filter.lastHeight)
+		filter.lastHeight = 4
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Expired filters are removed on next access
+	fr.filters[1].lastPolled = time.Now().Add(-2 * time.Minute)
+	other := fr.install(&ethFilter{})
+	assert.NotEqual(t, id, other)
+	require.Error(t, fr.poll(id, func(*ethFilter) error { return nil }))
+
+	assert.True(t, fr.uninstall(other))
+	assert.False(t, fr.uninstall(other))
+}
@@ -6,10 +6,13 @@ import (
 	"io"
 
 	"github.com/hyperledger/burrow/bcm"
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/event"
 	"github.com/hyperledger/burrow/event/query"
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/logging"
+	hex "github.com/tmthrgd/go-hex"
 )
 
 const SubscribeBufferSize = 100
@@ -21,6 +24,19 @@ type Provider interface {
 	TxByHash(txHash []byte) (*exec.TxExecution, error)
 }
 
+// An IndexedProvider can skip blocks that have no logs from an address or with a topic
+type IndexedProvider interface {
+	Provider
+	IterateStreamEventsFor(address *crypto.Address, topic *binary.Word256, start, end uint64,
+		consumer func(*exec.StreamEvent) error) error
+}
+
+// The log address and topic (if any) that an events query requires
+type logFilter struct {
+	address *crypto.Address
+	topic   *binary.Word256
+}
+
 type executionEventsServer struct {
 	eventsProvider Provider
 	emitter        *event.Emitter
@@ -72,7 +88,7 @@ func (ees *executionEventsServer) Stream(request *BlocksRequest, stream Executio
 	if err != nil {
 		return fmt.Errorf("could not parse TxExecution query: %v", err)
 	}
-	return ees.streamEvents(stream.Context(), request.BlockRange, func(ev *exec.StreamEvent) error {
+	return ees.streamEvents(stream.Context(), request.BlockRange, nil, func(ev *exec.StreamEvent) error {
 		if qry.Matches(ev) {
 			return stream.Send(ev)
 		}
@@ -88,7 +104,7 @@ func (ees *executionEventsServer) Events(request *BlocksRequest, stream Executio
 	}
 	var response *EventsResponse
 	var stack exec.TxStack
-	return ees.streamEvents(stream.Context(), request.BlockRange, func(sev *exec.StreamEvent) error {
+	return ees.streamEvents(stream.Context(), request.BlockRange, requiredLogs(qry), func(sev *exec.StreamEvent) error {
 		switch {
 		case sev.BeginBlock != nil:
 			response = &EventsResponse{
@@ -117,7 +133,7 @@ func (ees *executionEventsServer) Events(request *BlocksRequest, stream Executio
 	})
 }
 
-func (ees *executionEventsServer) streamEvents(ctx context.Context, blockRange *BlockRange,
+func (ees *executionEventsServer) streamEvents(ctx context.Context, blockRange *BlockRange, filter *logFilter,
 	consumer func(execution *exec.StreamEvent) error) error {
 
 	start, end, streaming := blockRange.Bounds(ees.tip.LastBlockHeight())
@@ -125,7 +141,7 @@ func (ees *executionEventsServer) streamEvents(ctx context.Context, blockRange *
 
 	// Pull blocks from state and receive the upper bound (exclusive) on the what we were able to send
 	// Set this to start since it will be the start of next streaming batch (if needed)
-	start, err := ees.iterateStreamEvents(start, end, consumer)
+	start, err := ees.iterateStreamEvents(start, end, filter, consumer)
 
 	// If we are not streaming and all blocks requested were retrieved from state then we are done
 	if !streaming && start > end {
@@ -145,7 +161,7 @@ func (ees *executionEventsServer) streamEvents(ctx context.Context, blockRange *
 			if catchupEnd > end {
 				catchupEnd = end
 			}
-			start, err = ees.iterateStreamEvents(start, catchupEnd, consumer)
+			start, err = ees.iterateStreamEvents(start, catchupEnd, filter, consumer)
 			if err != nil {
 				return err
 			}
@@ -196,8 +212,21 @@ func (ees *executionEventsServer) subscribeBlockExecution(ctx context.Context,
 	return nil
 }
 
-func (ees *executionEventsServer) iterateStreamEvents(startHeight, endHeight uint64,
+func (ees *executionEventsServer) iterateStreamEvents(startHeight, endHeight uint64, filter *logFilter,
 	consumer func(*exec.StreamEvent) error) (uint64, error) {
+	if indexed, ok := ees.eventsProvider.(IndexedProvider); ok && filter != nil {
+		// We cannot rely on seeing the EndBlock of the last block when the index lets us skip it, but we will have
+		// seen all blocks up to the tip as of now
+		tip := ees.tip.LastBlockHeight()
+		if endHeight > tip {
+			endHeight = tip
+		}
+		if endHeight < startHeight {
+			return startHeight, nil
+		}
+		err := indexed.IterateStreamEventsFor(filter.address, filter.topic, startHeight, endHeight, consumer)
+		return endHeight + 1, err
+	}
 	// Assume that we have seen the previous block before start to have ended up here
 	// NOTE: this will underflow when start is 0 (as it often will be - and needs to be for restored chains)
 	// however we at most underflow by 1 and we always add 1 back on when returning so we get away with this.
@@ -212,3 +241,37 @@ func (ees *executionEventsServer) iterateStreamEvents(startHeight, endHeight uin
 	// Returns the appropriate _next_ starting block - the one after the one we have seen - from which to stream next
 	return lastHeightSeen + 1, err
 }
+
+// Find any log address or topic that every event matching qry must have
+func requiredLogs(qry query.Query) *logFilter {
+	pq, ok := qry.(*query.PegQuery)
+	if !ok {
+		return nil
+	}
+	filter := new(logFilter)
+	for tag, value := range pq.Equalities() {
+		if tag == event.AddressKey {
+			address, err := crypto.AddressFromHexString(value)
+			if err == nil {
+				filter.address = &address
+			}
+			continue
+		}
+		if filter.topic != nil {
+			continue
+		}
+		for i := 0; i <= 4; i++ {
+			if tag == exec.LogNKey(i) {
+				bs, err := hex.DecodeString(value)
+				if err == nil && len(bs) == binary.Word256Bytes {
+					topic := binary.LeftPadWord256(bs)
+					filter.topic = &topic
+				}
+			}
+		}
+	}
+	if filter.address == nil && filter.topic == nil {
+		return nil
+	}
+	return filter
+}
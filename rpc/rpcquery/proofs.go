@@ -0,0 +1,19 @@
+package rpcquery
+
+import (
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/state"
+)
+
+// Verify checks the proof of the account (or its absence) at address against AppHash, which the caller should check
+// against a trusted block header
+func (ap *AccountProof) Verify(address crypto.Address) error {
+	return state.VerifyAccountProof(ap.AppHash, address, ap.Account, ap.Proof)
+}
+
+// Verify checks the proof of the value (or its absence) at key in the storage of address against AppHash, which the
+// caller should check against a trusted block header
+func (sp *StorageProof) Verify(address crypto.Address, key binary.Word256) error {
+	return state.VerifyStorageProof(sp.AppHash, address, key, sp.Value, sp.Proof)
+}
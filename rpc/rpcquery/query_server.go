@@ -9,6 +9,7 @@ import (
 	"github.com/hyperledger/burrow/acm/acmstate"
 	"github.com/hyperledger/burrow/acm/validator"
 	"github.com/hyperledger/burrow/bcm"
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/consensus/tendermint"
 	"github.com/hyperledger/burrow/crypto"
 	"github.com/hyperledger/burrow/deploy/compile"
@@ -42,6 +43,13 @@ type QueryState interface {
 	registry.IterableReader
 	proposal.IterableReader
 	validator.History
+	LoadHeight(height uint64) (*state.ReadState, error)
+}
+
+// The parts of state that can be read at a past height
+type historicalState interface {
+	acmstate.IterableReader
+	names.IterableReader
 }
 
 func NewQueryServer(state QueryState, blockchain bcm.BlockchainInfo, nodeView *tendermint.NodeView, logger *logging.Logger) *queryServer {
@@ -60,7 +68,11 @@ func (qs *queryServer) Status(ctx context.Context, param *StatusParam) (*rpc.Res
 // Account state
 
 func (qs *queryServer) GetAccount(ctx context.Context, param *GetAccountParam) (*acm.Account, error) {
-	acc, err := qs.state.GetAccount(param.Address)
+	st, err := qs.stateAt(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := st.GetAccount(param.Address)
 	if acc == nil {
 		acc = &acm.Account{}
 	}
@@ -124,17 +136,63 @@ func (qs *queryServer) GetMetadata(ctx context.Context, param *GetMetadataParam)
 }
 
 func (qs *queryServer) GetStorage(ctx context.Context, param *GetStorageParam) (*StorageValue, error) {
-	val, err := qs.state.GetStorage(param.Address, param.Key)
+	st, err := qs.stateAt(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	val, err := st.GetStorage(param.Address, param.Key)
 	return &StorageValue{Value: val}, err
 }
 
+// GetAccountProof proves the account against the state at the requested (or latest) height
+func (qs *queryServer) GetAccountProof(ctx context.Context, param *GetAccountParam) (*AccountProof, error) {
+	height := qs.heightOrLatest(param.Height)
+	st, err := qs.state.LoadHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	acc, proof, err := st.GetAccountWithProof(param.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountProof{
+		Height:  height,
+		AppHash: st.Forest.Hash(),
+		Account: acc,
+		Proof:   proof,
+	}, nil
+}
+
+// GetStorageProof proves the storage value against the state at the requested (or latest) height
+func (qs *queryServer) GetStorageProof(ctx context.Context, param *GetStorageParam) (*StorageProof, error) {
+	height := qs.heightOrLatest(param.Height)
+	st, err := qs.state.LoadHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	val, proof, err := st.GetStorageWithProof(param.Address, param.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageProof{
+		Height:  height,
+		AppHash: st.Forest.Hash(),
+		Value:   val,
+		Proof:   proof,
+	}, nil
+}
+
 func (qs *queryServer) ListAccounts(param *ListAccountsParam, stream Query_ListAccountsServer) error {
 	qry, err := query.NewOrEmpty(param.Query)
 	if err != nil {
 		return err
 	}
+	st, err := qs.stateAt(param.Height)
+	if err != nil {
+		return err
+	}
 	var streamErr error
-	err = qs.state.IterateAccounts(func(acc *acm.Account) error {
+	err = st.IterateAccounts(func(acc *acm.Account) error {
 		if qry.Matches(acc) {
 			return stream.Send(acc)
 		} else {
@@ -147,10 +205,50 @@ func (qs *queryServer) ListAccounts(param *ListAccountsParam, stream Query_ListA
 	return streamErr
 }
 
+// ListStorage streams the storage entries of a contract with keys in [Start, End)
+func (qs *queryServer) ListStorage(param *ListStorageParam, stream Query_ListStorageServer) error {
+	if len(param.Start) > binary.Word256Bytes || len(param.End) > binary.Word256Bytes {
+		return fmt.Errorf("storage keys must be at most %d bytes", binary.Word256Bytes)
+	}
+	var end *binary.Word256
+	if len(param.End) > 0 {
+		word := binary.LeftPadWord256(param.End)
+		end = &word
+	}
+	st, err := qs.stateAt(param.Height)
+	if err != nil {
+		return err
+	}
+	_, err = acmstate.IterateStorageRange(st, param.Address, binary.LeftPadWord256(param.Start), end, param.Limit,
+		func(key binary.Word256, value []byte) error {
+			return stream.Send(&StorageEntry{Key: key, Value: value})
+		})
+	return err
+}
+
+// Read from the state at height, or the latest state if height is zero
+func (qs *queryServer) stateAt(height uint64) (historicalState, error) {
+	if height == 0 {
+		return qs.state, nil
+	}
+	return qs.state.LoadHeight(height)
+}
+
+func (qs *queryServer) heightOrLatest(height uint64) uint64 {
+	if height == 0 {
+		return qs.blockchain.LastBlockHeight()
+	}
+	return height
+}
+
 // Names
 
 func (qs *queryServer) GetName(ctx context.Context, param *GetNameParam) (entry *names.Entry, err error) {
-	entry, err = qs.state.GetName(param.Name)
+	st, err := qs.stateAt(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	entry, err = st.GetName(param.Name)
 	if entry == nil && err == nil {
 		err = status.Error(codes.NotFound, fmt.Sprintf("name %s not found", param.Name))
 	}
@@ -162,8 +260,12 @@ func (qs *queryServer) ListNames(param *ListNamesParam, stream Query_ListNamesSe
 	if err != nil {
 		return err
 	}
+	st, err := qs.stateAt(param.Height)
+	if err != nil {
+		return err
+	}
 	var streamErr error
-	err = qs.state.IterateNames(func(entry *names.Entry) error {
+	err = st.IterateNames(func(entry *names.Entry) error {
 		if qry.Matches(entry) {
 			return stream.Send(entry)
 		} else {
@@ -19,6 +19,7 @@ import (
 	names "github.com/hyperledger/burrow/execution/names"
 	registry "github.com/hyperledger/burrow/execution/registry"
 	rpc "github.com/hyperledger/burrow/rpc"
+	storage "github.com/hyperledger/burrow/storage"
 	payload "github.com/hyperledger/burrow/txs/payload"
 	types "github.com/tendermint/tendermint/abci/types"
 	grpc "google.golang.org/grpc"
@@ -89,10 +90,12 @@ func (*StatusParam) XXX_MessageName() string {
 }
 
 type GetAccountParam struct {
-	Address              github_com_hyperledger_burrow_crypto.Address `protobuf:"bytes,1,opt,name=Address,proto3,customtype=github.com/hyperledger/burrow/crypto.Address" json:"Address"`
-	XXX_NoUnkeyedLiteral struct{}                                     `json:"-"`
-	XXX_unrecognized     []byte                                       `json:"-"`
-	XXX_sizecache        int32                                        `json:"-"`
+	Address github_com_hyperledger_burrow_crypto.Address `protobuf:"bytes,1,opt,name=Address,proto3,customtype=github.com/hyperledger/burrow/crypto.Address" json:"Address"`
+	// Height at which to read state, the latest height if zero
+	Height               uint64   `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetAccountParam) Reset()         { *m = GetAccountParam{} }
@@ -119,6 +122,13 @@ func (m *GetAccountParam) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GetAccountParam proto.InternalMessageInfo
 
+func (m *GetAccountParam) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
 func (*GetAccountParam) XXX_MessageName() string {
 	return "rpcquery.GetAccountParam"
 }
@@ -202,11 +212,13 @@ func (*MetadataResult) XXX_MessageName() string {
 }
 
 type GetStorageParam struct {
-	Address              github_com_hyperledger_burrow_crypto.Address `protobuf:"bytes,1,opt,name=Address,proto3,customtype=github.com/hyperledger/burrow/crypto.Address" json:"Address"`
-	Key                  github_com_hyperledger_burrow_binary.Word256 `protobuf:"bytes,2,opt,name=Key,proto3,customtype=github.com/hyperledger/burrow/binary.Word256" json:"Key"`
-	XXX_NoUnkeyedLiteral struct{}                                     `json:"-"`
-	XXX_unrecognized     []byte                                       `json:"-"`
-	XXX_sizecache        int32                                        `json:"-"`
+	Address github_com_hyperledger_burrow_crypto.Address `protobuf:"bytes,1,opt,name=Address,proto3,customtype=github.com/hyperledger/burrow/crypto.Address" json:"Address"`
+	Key     github_com_hyperledger_burrow_binary.Word256 `protobuf:"bytes,2,opt,name=Key,proto3,customtype=github.com/hyperledger/burrow/binary.Word256" json:"Key"`
+	// Height at which to read state, the latest height if zero
+	Height               uint64   `protobuf:"varint,3,opt,name=Height,proto3" json:"Height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetStorageParam) Reset()         { *m = GetStorageParam{} }
@@ -233,6 +245,13 @@ func (m *GetStorageParam) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GetStorageParam proto.InternalMessageInfo
 
+func (m *GetStorageParam) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
 func (*GetStorageParam) XXX_MessageName() string {
 	return "rpcquery.GetStorageParam"
 }
@@ -273,7 +292,9 @@ func (*StorageValue) XXX_MessageName() string {
 }
 
 type ListAccountsParam struct {
-	Query                string   `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	Query string `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	// Height at which to read state, the latest height if zero
+	Height               uint64   `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -310,12 +331,21 @@ func (m *ListAccountsParam) GetQuery() string {
 	return ""
 }
 
+func (m *ListAccountsParam) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
 func (*ListAccountsParam) XXX_MessageName() string {
 	return "rpcquery.ListAccountsParam"
 }
 
 type GetNameParam struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Height at which to read state, the latest height if zero
+	Height               uint64   `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -352,12 +382,21 @@ func (m *GetNameParam) GetName() string {
 	return ""
 }
 
+func (m *GetNameParam) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
 func (*GetNameParam) XXX_MessageName() string {
 	return "rpcquery.GetNameParam"
 }
 
 type ListNamesParam struct {
-	Query                string   `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	Query string `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	// Height at which to read state, the latest height if zero
+	Height               uint64   `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -394,6 +433,13 @@ func (m *ListNamesParam) GetQuery() string {
 	return ""
 }
 
+func (m *ListNamesParam) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
 func (*ListNamesParam) XXX_MessageName() string {
 	return "rpcquery.ListNamesParam"
 }
@@ -945,6 +991,216 @@ func (m *GetBlockParam) GetHeight() uint64 {
 func (*GetBlockParam) XXX_MessageName() string {
 	return "rpcquery.GetBlockParam"
 }
+
+type AccountProof struct {
+	// The height of the state against which the proof was made
+	Height uint64 `protobuf:"varint,1,opt,name=Height,proto3" json:"Height,omitempty"`
+	// The AppHash of the state at Height (found in the header of the following block)
+	AppHash github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,2,opt,name=AppHash,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"AppHash"`
+	// The account or nil if none exists at the address
+	Account              *acm.Account         `protobuf:"bytes,3,opt,name=Account,proto3" json:"Account,omitempty"`
+	Proof                *storage.ForestProof `protobuf:"bytes,4,opt,name=Proof,proto3" json:"Proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AccountProof) Reset()         { *m = AccountProof{} }
+func (m *AccountProof) String() string { return proto.CompactTextString(m) }
+func (*AccountProof) ProtoMessage()    {}
+func (*AccountProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_88e25d9b99e39f02, []int{22}
+}
+func (m *AccountProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountProof.Unmarshal(m, b)
+}
+func (m *AccountProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountProof.Marshal(b, m, deterministic)
+}
+func (m *AccountProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountProof.Merge(m, src)
+}
+func (m *AccountProof) XXX_Size() int {
+	return xxx_messageInfo_AccountProof.Size(m)
+}
+func (m *AccountProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountProof proto.InternalMessageInfo
+
+func (m *AccountProof) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *AccountProof) GetAccount() *acm.Account {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func (m *AccountProof) GetProof() *storage.ForestProof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (*AccountProof) XXX_MessageName() string {
+	return "rpcquery.AccountProof"
+}
+
+type StorageProof struct {
+	// The height of the state against which the proof was made
+	Height uint64 `protobuf:"varint,1,opt,name=Height,proto3" json:"Height,omitempty"`
+	// The AppHash of the state at Height (found in the header of the following block)
+	AppHash github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,2,opt,name=AppHash,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"AppHash"`
+	// The value or empty if none is stored at the key
+	Value                github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,3,opt,name=Value,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"Value"`
+	Proof                *storage.ForestProof                          `protobuf:"bytes,4,opt,name=Proof,proto3" json:"Proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
+	XXX_unrecognized     []byte                                        `json:"-"`
+	XXX_sizecache        int32                                         `json:"-"`
+}
+
+func (m *StorageProof) Reset()         { *m = StorageProof{} }
+func (m *StorageProof) String() string { return proto.CompactTextString(m) }
+func (*StorageProof) ProtoMessage()    {}
+func (*StorageProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_88e25d9b99e39f02, []int{23}
+}
+func (m *StorageProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageProof.Unmarshal(m, b)
+}
+func (m *StorageProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageProof.Marshal(b, m, deterministic)
+}
+func (m *StorageProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageProof.Merge(m, src)
+}
+func (m *StorageProof) XXX_Size() int {
+	return xxx_messageInfo_StorageProof.Size(m)
+}
+func (m *StorageProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageProof proto.InternalMessageInfo
+
+func (m *StorageProof) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *StorageProof) GetProof() *storage.ForestProof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (*StorageProof) XXX_MessageName() string {
+	return "rpcquery.StorageProof"
+}
+
+type ListStorageParam struct {
+	Address github_com_hyperledger_burrow_crypto.Address `protobuf:"bytes,1,opt,name=Address,proto3,customtype=github.com/hyperledger/burrow/crypto.Address" json:"Address"`
+	// The first key to return (left-padded to 32 bytes)
+	Start github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,2,opt,name=Start,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"Start"`
+	// The key before which to stop (left-padded to 32 bytes), unbounded if empty
+	End github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,3,opt,name=End,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"End"`
+	// The maximum number of entries to return, unlimited if zero
+	Limit uint64 `protobuf:"varint,4,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	// Height at which to read state, the latest height if zero
+	Height               uint64   `protobuf:"varint,5,opt,name=Height,proto3" json:"Height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListStorageParam) Reset()         { *m = ListStorageParam{} }
+func (m *ListStorageParam) String() string { return proto.CompactTextString(m) }
+func (*ListStorageParam) ProtoMessage()    {}
+func (*ListStorageParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_88e25d9b99e39f02, []int{24}
+}
+func (m *ListStorageParam) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListStorageParam.Unmarshal(m, b)
+}
+func (m *ListStorageParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListStorageParam.Marshal(b, m, deterministic)
+}
+func (m *ListStorageParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListStorageParam.Merge(m, src)
+}
+func (m *ListStorageParam) XXX_Size() int {
+	return xxx_messageInfo_ListStorageParam.Size(m)
+}
+func (m *ListStorageParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListStorageParam.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListStorageParam proto.InternalMessageInfo
+
+func (m *ListStorageParam) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListStorageParam) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (*ListStorageParam) XXX_MessageName() string {
+	return "rpcquery.ListStorageParam"
+}
+
+type StorageEntry struct {
+	Key                  github_com_hyperledger_burrow_binary.Word256  `protobuf:"bytes,1,opt,name=Key,proto3,customtype=github.com/hyperledger/burrow/binary.Word256" json:"Key"`
+	Value                github_com_hyperledger_burrow_binary.HexBytes `protobuf:"bytes,2,opt,name=Value,proto3,customtype=github.com/hyperledger/burrow/binary.HexBytes" json:"Value"`
+	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
+	XXX_unrecognized     []byte                                        `json:"-"`
+	XXX_sizecache        int32                                         `json:"-"`
+}
+
+func (m *StorageEntry) Reset()         { *m = StorageEntry{} }
+func (m *StorageEntry) String() string { return proto.CompactTextString(m) }
+func (*StorageEntry) ProtoMessage()    {}
+func (*StorageEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_88e25d9b99e39f02, []int{25}
+}
+func (m *StorageEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageEntry.Unmarshal(m, b)
+}
+func (m *StorageEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageEntry.Marshal(b, m, deterministic)
+}
+func (m *StorageEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageEntry.Merge(m, src)
+}
+func (m *StorageEntry) XXX_Size() int {
+	return xxx_messageInfo_StorageEntry.Size(m)
+}
+func (m *StorageEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageEntry proto.InternalMessageInfo
+
+func (*StorageEntry) XXX_MessageName() string {
+	return "rpcquery.StorageEntry"
+}
 func init() {
 	proto.RegisterType((*StatusParam)(nil), "rpcquery.StatusParam")
 	golang_proto.RegisterType((*StatusParam)(nil), "rpcquery.StatusParam")
@@ -990,79 +1246,100 @@ func init() {
 	golang_proto.RegisterType((*Stats)(nil), "rpcquery.Stats")
 	proto.RegisterType((*GetBlockParam)(nil), "rpcquery.GetBlockParam")
 	golang_proto.RegisterType((*GetBlockParam)(nil), "rpcquery.GetBlockParam")
+	proto.RegisterType((*AccountProof)(nil), "rpcquery.AccountProof")
+	golang_proto.RegisterType((*AccountProof)(nil), "rpcquery.AccountProof")
+	proto.RegisterType((*StorageProof)(nil), "rpcquery.StorageProof")
+	golang_proto.RegisterType((*StorageProof)(nil), "rpcquery.StorageProof")
+	proto.RegisterType((*ListStorageParam)(nil), "rpcquery.ListStorageParam")
+	golang_proto.RegisterType((*ListStorageParam)(nil), "rpcquery.ListStorageParam")
+	proto.RegisterType((*StorageEntry)(nil), "rpcquery.StorageEntry")
+	golang_proto.RegisterType((*StorageEntry)(nil), "rpcquery.StorageEntry")
 }
 
 func init() { proto.RegisterFile("rpcquery.proto", fileDescriptor_88e25d9b99e39f02) }
 func init() { golang_proto.RegisterFile("rpcquery.proto", fileDescriptor_88e25d9b99e39f02) }
 
 var fileDescriptor_88e25d9b99e39f02 = []byte{
-	// 1051 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xef, 0x6e, 0x1b, 0x45,
-	0x10, 0xe7, 0x9a, 0xc4, 0x71, 0xc6, 0x8e, 0xdd, 0x6e, 0x82, 0x9b, 0x5e, 0xa9, 0x53, 0x56, 0x22,
-	0x0d, 0x51, 0x7b, 0x36, 0xa6, 0x01, 0x04, 0x48, 0xa8, 0x8e, 0xc0, 0x0e, 0xa5, 0x51, 0x38, 0x43,
-	0x2b, 0x81, 0x84, 0xb4, 0xbe, 0x5b, 0xec, 0x53, 0xcf, 0xb7, 0x66, 0x6f, 0xaf, 0xc5, 0x8f, 0x81,
-	0x78, 0x0a, 0x1e, 0x80, 0xef, 0x7c, 0xec, 0x23, 0xa0, 0x7e, 0x88, 0x50, 0xfb, 0x22, 0xe8, 0xf6,
-	0x8f, 0x7d, 0x77, 0x71, 0x23, 0x40, 0xf4, 0x8b, 0xb5, 0x33, 0x3b, 0xf3, 0x1b, 0xef, 0xec, 0xfc,
-	0x7e, 0x7b, 0x50, 0xe3, 0x53, 0xef, 0xa7, 0x84, 0xf2, 0x99, 0x33, 0xe5, 0x4c, 0x30, 0x54, 0x36,
-	0xb6, 0x7d, 0x67, 0x14, 0x88, 0x71, 0x32, 0x74, 0x3c, 0x36, 0x69, 0x8d, 0xd8, 0x88, 0xb5, 0x64,
-	0xc0, 0x30, 0xf9, 0x51, 0x5a, 0xd2, 0x90, 0x2b, 0x95, 0x68, 0x7f, 0x98, 0x09, 0x17, 0x34, 0xf2,
-	0x29, 0x9f, 0x04, 0x91, 0xc8, 0x2e, 0xc9, 0xd0, 0x0b, 0x5a, 0x62, 0x36, 0xa5, 0xb1, 0xfa, 0xd5,
-	0x89, 0x95, 0x88, 0x4c, 0xe6, 0xc6, 0x06, 0xf1, 0x26, 0x7a, 0x59, 0x7f, 0x42, 0xc2, 0xc0, 0x27,
-	0x82, 0x71, 0xed, 0xa8, 0x71, 0x3a, 0x0a, 0x62, 0x61, 0xfe, 0xaa, 0xbd, 0xc1, 0xa7, 0x9e, 0x5e,
-	0x6e, 0x4e, 0xc9, 0x2c, 0x64, 0xc4, 0x57, 0x26, 0x0e, 0xa0, 0x32, 0x10, 0x44, 0x24, 0xf1, 0x29,
-	0xe1, 0x64, 0x82, 0xf6, 0xa1, 0xde, 0x0d, 0x99, 0xf7, 0xf8, 0x9b, 0x60, 0x42, 0x1f, 0x05, 0x62,
-	0x1c, 0x44, 0x3b, 0xd6, 0x4d, 0x6b, 0x7f, 0xc3, 0x2d, 0xba, 0x51, 0x1b, 0xb6, 0xa4, 0x6b, 0x40,
-	0x69, 0x94, 0x89, 0xbe, 0x24, 0xa3, 0x97, 0x6d, 0x61, 0x02, 0xf5, 0x1e, 0x15, 0xf7, 0x3c, 0x8f,
-	0x25, 0x91, 0x50, 0xe5, 0x4e, 0x60, 0xfd, 0x9e, 0xef, 0x73, 0x1a, 0xc7, 0xb2, 0x4c, 0xb5, 0x7b,
-	0xf7, 0xd9, 0xd9, 0xee, 0x1b, 0xcf, 0xcf, 0x76, 0x6f, 0x67, 0x5a, 0x34, 0x9e, 0x4d, 0x29, 0x0f,
-	0xa9, 0x3f, 0xa2, 0xbc, 0x35, 0x4c, 0x38, 0x67, 0x4f, 0x5b, 0x1e, 0x9f, 0x4d, 0x05, 0x73, 0x74,
-	0xae, 0x6b, 0x40, 0xf0, 0xef, 0x16, 0x5c, 0xee, 0x51, 0xf1, 0x80, 0x0a, 0xe2, 0x13, 0x41, 0x54,
-	0x91, 0x2f, 0x8b, 0x45, 0xda, 0xff, 0xb9, 0x00, 0xfa, 0x16, 0xaa, 0x06, 0xbc, 0x4f, 0xe2, 0xb1,
-	0x3c, 0x6e, 0xb5, 0xfb, 0xde, 0xf3, 0xb3, 0xdd, 0x3b, 0x17, 0x03, 0x0e, 0x83, 0x88, 0xf0, 0x99,
-	0xd3, 0xa7, 0x3f, 0x77, 0x67, 0x82, 0xc6, 0x6e, 0x0e, 0x06, 0xdf, 0x86, 0x9a, 0xb1, 0x5d, 0x1a,
-	0x27, 0xa1, 0x40, 0x36, 0x94, 0x8d, 0x47, 0xdf, 0xc0, 0xdc, 0xc6, 0xbf, 0x59, 0xb2, 0x93, 0x03,
-	0xc1, 0x38, 0x19, 0xd1, 0xd7, 0xd2, 0x49, 0xf4, 0x05, 0xac, 0xdc, 0xa7, 0x33, 0x7d, 0xbe, 0x7f,
-	0x88, 0xa5, 0xcf, 0xf8, 0x88, 0x71, 0xbf, 0x73, 0xf8, 0x81, 0x9b, 0x02, 0xe0, 0xef, 0xa1, 0xaa,
-	0xff, 0xe7, 0x43, 0x12, 0x26, 0x14, 0xdd, 0x87, 0x35, 0xb9, 0xd0, 0xff, 0xf2, 0x50, 0x23, 0xff,
-	0xcb, 0xee, 0x29, 0x0c, 0xfc, 0x2e, 0x5c, 0xf9, 0x2a, 0x88, 0xcd, 0x48, 0xe9, 0x11, 0xde, 0x86,
-	0xb5, 0xaf, 0x53, 0x56, 0xea, 0xb6, 0x29, 0x03, 0x63, 0xa8, 0xf6, 0xa8, 0x38, 0x21, 0x13, 0xdd,
-	0x2f, 0x04, 0xab, 0xa9, 0xa1, 0x83, 0xe4, 0x1a, 0xef, 0x41, 0x2d, 0x85, 0x4b, 0xd7, 0x17, 0x62,
-	0x5d, 0x83, 0xab, 0x29, 0x16, 0x15, 0x4f, 0x19, 0x7f, 0xec, 0x6a, 0xa6, 0xc9, 0x04, 0xdc, 0x80,
-	0xed, 0x1e, 0x15, 0x0f, 0x0d, 0x1d, 0x07, 0x54, 0x0d, 0x3a, 0xee, 0xc1, 0xf5, 0x82, 0xbf, 0x1f,
-	0xc4, 0x82, 0xe9, 0xb4, 0x94, 0x76, 0xc7, 0x91, 0x17, 0x26, 0x3e, 0x3d, 0xe5, 0xf4, 0x49, 0xc0,
-	0x12, 0x75, 0x8b, 0x2b, 0x6e, 0xd1, 0x8d, 0xbb, 0x50, 0x2f, 0x14, 0x46, 0x2d, 0x58, 0x19, 0x50,
-	0xb1, 0x63, 0xdd, 0x5c, 0xd9, 0xaf, 0x74, 0x6e, 0x38, 0x73, 0x95, 0x52, 0x01, 0x94, 0x53, 0x7f,
-	0x5e, 0xd7, 0x4d, 0x23, 0xf1, 0x2f, 0x16, 0x6c, 0x2d, 0xd9, 0xfc, 0xdf, 0x67, 0xe8, 0x00, 0x56,
-	0x4f, 0x98, 0x4f, 0xe5, 0x10, 0x55, 0x3a, 0x0d, 0x67, 0x2e, 0x4a, 0xa9, 0xf7, 0xd8, 0xa7, 0x91,
-	0x08, 0xc4, 0xcc, 0x95, 0x31, 0xb8, 0x07, 0x5b, 0x4b, 0xba, 0x83, 0xda, 0xb0, 0xae, 0x97, 0xfa,
-	0x7c, 0x8d, 0xc5, 0xf9, 0xb2, 0xf1, 0xae, 0x09, 0xc3, 0x27, 0x50, 0xcd, 0x6e, 0xa0, 0x06, 0x94,
-	0xc6, 0x34, 0x18, 0x8d, 0x85, 0x3c, 0xd3, 0xaa, 0xab, 0x2d, 0xb4, 0xa7, 0xba, 0x76, 0x49, 0xa2,
-	0x6e, 0x3b, 0x0b, 0x05, 0x2d, 0x34, 0x6b, 0x4f, 0x2a, 0xca, 0x29, 0x67, 0x53, 0x16, 0x93, 0x70,
-	0x3e, 0x3c, 0x92, 0xfd, 0xb2, 0x4b, 0xae, 0x5c, 0xe3, 0x36, 0xa0, 0x74, 0x78, 0x4c, 0xa0, 0x1e,
-	0x20, 0x1b, 0xca, 0xca, 0x43, 0x7d, 0x19, 0x5d, 0x76, 0xe7, 0x36, 0x7e, 0x00, 0x35, 0x13, 0xad,
-	0x49, 0xbf, 0x04, 0x17, 0xdd, 0x82, 0x52, 0x97, 0x84, 0x21, 0x13, 0xba, 0x8d, 0x75, 0xc7, 0x08,
-	0xb8, 0x72, 0xbb, 0x7a, 0x1b, 0xd7, 0x61, 0x53, 0x8a, 0x02, 0xd1, 0x44, 0xc0, 0x14, 0xd6, 0xa4,
-	0x85, 0x0e, 0xe0, 0xb2, 0xa1, 0x48, 0x2a, 0xc5, 0x47, 0xe9, 0x9d, 0xa8, 0x66, 0x9c, 0xf3, 0xa7,
-	0xb2, 0x9e, 0xf5, 0xb1, 0x44, 0x1c, 0x99, 0x2b, 0x5c, 0x75, 0x97, 0x6d, 0xe1, 0x5b, 0xb2, 0xae,
-	0x14, 0x7c, 0x75, 0xe6, 0x06, 0x94, 0xfa, 0xb9, 0x8e, 0x2b, 0xab, 0xf3, 0xeb, 0xba, 0x66, 0x13,
-	0xea, 0x40, 0x49, 0x3d, 0x3a, 0xe8, 0xcd, 0xc5, 0x75, 0x66, 0x9e, 0x21, 0xfb, 0x4a, 0xea, 0x76,
-	0x54, 0x57, 0x74, 0xe4, 0x21, 0xc0, 0xe2, 0xf5, 0x40, 0xd7, 0x16, 0x79, 0x85, 0x37, 0xc5, 0xae,
-	0x3a, 0xe9, 0xc3, 0x68, 0x02, 0x8f, 0xa0, 0x92, 0x79, 0x10, 0x90, 0x9d, 0xcb, 0xcb, 0xbd, 0x13,
-	0xf6, 0xce, 0x62, 0xaf, 0x20, 0xc6, 0x9f, 0xc9, 0xda, 0x5a, 0xc7, 0x0a, 0xb5, 0xb3, 0x2a, 0x6c,
-	0x37, 0xb2, 0xc7, 0xc9, 0xa8, 0xde, 0x27, 0x50, 0xcd, 0x0a, 0x15, 0xba, 0xbe, 0x88, 0x3b, 0x27,
-	0x60, 0xf9, 0x03, 0xb4, 0x2d, 0xd4, 0x82, 0x75, 0x2d, 0x5d, 0xa8, 0x91, 0x2b, 0x3d, 0x57, 0x33,
-	0xbb, 0xea, 0xa8, 0x2f, 0x83, 0xcf, 0xa3, 0x54, 0x10, 0x0e, 0x61, 0x63, 0xae, 0x63, 0x68, 0x27,
-	0x5f, 0x6a, 0x21, 0x6e, 0xf9, 0xa4, 0xb6, 0x85, 0x5c, 0x40, 0xe7, 0x65, 0x0d, 0xbd, 0x9d, 0x2f,
-	0xb9, 0x44, 0xf4, 0xec, 0x4c, 0x43, 0x8a, 0xd9, 0xc7, 0xf2, 0xa5, 0xca, 0x11, 0xb2, 0x99, 0x03,
-	0x3c, 0x27, 0x95, 0xf6, 0x2b, 0x18, 0x8e, 0x7e, 0x80, 0xc6, 0x72, 0x09, 0x45, 0xef, 0xbc, 0x12,
-	0x31, 0x2b, 0xb2, 0xf6, 0x8d, 0xe5, 0xc0, 0x06, 0xe5, 0x63, 0x39, 0x29, 0x86, 0x91, 0x85, 0x49,
-	0xc9, 0xf1, 0xdf, 0x2e, 0x72, 0x10, 0x1d, 0xc3, 0x66, 0x8e, 0xfc, 0xe8, 0xad, 0x7c, 0xd7, 0xf3,
-	0xaa, 0x90, 0x9d, 0xb4, 0xbc, 0x02, 0xb4, 0x2d, 0x74, 0x17, 0xca, 0x86, 0xc6, 0xe8, 0x6a, 0x61,
-	0xd2, 0x0c, 0xb5, 0xed, 0x7a, 0x9e, 0x36, 0x31, 0xfa, 0x08, 0x6a, 0x86, 0x84, 0x7d, 0x4a, 0x7c,
-	0xca, 0x0b, 0xb9, 0x0b, 0x7a, 0xda, 0x9b, 0x8e, 0xfa, 0xa4, 0x54, 0x71, 0xdd, 0x4f, 0xff, 0x7c,
-	0xd1, 0xb4, 0xfe, 0x7a, 0xd1, 0xb4, 0xfe, 0x78, 0xd9, 0xb4, 0x9e, 0xbd, 0x6c, 0x5a, 0xdf, 0x1d,
-	0x5c, 0xac, 0xf6, 0x7c, 0xea, 0xb5, 0x0c, 0xf4, 0xb0, 0x24, 0xbf, 0x22, 0xdf, 0xff, 0x3b, 0x00,
-	0x00, 0xff, 0xff, 0xa9, 0x73, 0xa1, 0xad, 0x1c, 0x0b, 0x00, 0x00,
+	// 1260 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xdd, 0x6e, 0x1b, 0xc5,
+	0x17, 0xff, 0x6f, 0xec, 0x24, 0xce, 0xb1, 0x63, 0xb7, 0xd3, 0xfc, 0x5d, 0x77, 0x4b, 0x93, 0xb2,
+	0x12, 0x69, 0x14, 0xb5, 0x6b, 0x63, 0x1a, 0x40, 0x05, 0x81, 0xe2, 0xb4, 0x75, 0x42, 0xda, 0x10,
+	0x36, 0xd0, 0x4a, 0x20, 0x21, 0xad, 0xbd, 0x53, 0x7b, 0x55, 0xdb, 0x63, 0x66, 0xc7, 0x2d, 0x7e,
+	0x0c, 0x9e, 0x01, 0x89, 0x37, 0xe0, 0x1e, 0xee, 0x2a, 0xf1, 0x02, 0xa8, 0x17, 0x11, 0x6a, 0x79,
+	0x00, 0x1e, 0x01, 0xed, 0x7c, 0xac, 0x67, 0x36, 0x4e, 0x50, 0x12, 0xca, 0x8d, 0x35, 0xe7, 0xec,
+	0xf9, 0x98, 0x39, 0x9f, 0x3f, 0x43, 0x91, 0x0e, 0xdb, 0xdf, 0x8d, 0x30, 0x1d, 0xbb, 0x43, 0x4a,
+	0x18, 0x41, 0x39, 0x45, 0xdb, 0xb7, 0x3a, 0x21, 0xeb, 0x8e, 0x5a, 0x6e, 0x9b, 0xf4, 0xab, 0x1d,
+	0xd2, 0x21, 0x55, 0x2e, 0xd0, 0x1a, 0x3d, 0xe1, 0x14, 0x27, 0xf8, 0x49, 0x28, 0xda, 0x1f, 0x68,
+	0xe2, 0x0c, 0x0f, 0x02, 0x4c, 0xfb, 0xe1, 0x80, 0xe9, 0x47, 0xbf, 0xd5, 0x0e, 0xab, 0x6c, 0x3c,
+	0xc4, 0x91, 0xf8, 0x95, 0x8a, 0xf9, 0x81, 0xdf, 0x4f, 0x88, 0x05, 0xbf, 0xdd, 0x97, 0xc7, 0xd2,
+	0x33, 0xbf, 0x17, 0x06, 0x3e, 0x23, 0x54, 0x32, 0x8a, 0x14, 0x77, 0xc2, 0x88, 0xa9, 0xab, 0xda,
+	0x0b, 0x74, 0xd8, 0x96, 0xc7, 0xc5, 0xa1, 0x3f, 0xee, 0x11, 0x3f, 0x50, 0x64, 0xc4, 0x08, 0xf5,
+	0x3b, 0x58, 0x90, 0x4e, 0x08, 0xf9, 0x03, 0xe6, 0xb3, 0x51, 0xb4, 0xef, 0x53, 0xbf, 0x8f, 0xd6,
+	0xa0, 0xd4, 0xe8, 0x91, 0xf6, 0xd3, 0x2f, 0xc3, 0x3e, 0x7e, 0x1c, 0xb2, 0x6e, 0x38, 0xa8, 0x58,
+	0xd7, 0xad, 0xb5, 0x05, 0x2f, 0xcd, 0x46, 0x35, 0xb8, 0xc4, 0x59, 0x07, 0x18, 0x0f, 0x34, 0xe9,
+	0x19, 0x2e, 0x3d, 0xed, 0x93, 0x33, 0x86, 0x52, 0x13, 0xb3, 0xcd, 0x76, 0x9b, 0x8c, 0x06, 0x4c,
+	0xb8, 0xdb, 0x83, 0xf9, 0xcd, 0x20, 0xa0, 0x38, 0x8a, 0xb8, 0x9b, 0x42, 0xe3, 0xf6, 0x8b, 0xc3,
+	0x95, 0xff, 0xbd, 0x3c, 0x5c, 0xb9, 0xa9, 0x45, 0xac, 0x3b, 0x1e, 0x62, 0xda, 0xc3, 0x41, 0x07,
+	0xd3, 0x6a, 0x6b, 0x44, 0x29, 0x79, 0x5e, 0x6d, 0xd3, 0xf1, 0x90, 0x11, 0x57, 0xea, 0x7a, 0xca,
+	0x08, 0x2a, 0xc3, 0xdc, 0x36, 0x0e, 0x3b, 0x5d, 0xc6, 0xef, 0x91, 0xf5, 0x24, 0xe5, 0xfc, 0x6c,
+	0xc1, 0x85, 0x26, 0x66, 0x0f, 0x31, 0xf3, 0x03, 0x9f, 0xf9, 0xc2, 0xf9, 0x67, 0x69, 0xe7, 0xb5,
+	0xb3, 0x3b, 0xfe, 0x0a, 0x0a, 0xca, 0xf8, 0xb6, 0x1f, 0x75, 0xb9, 0xfb, 0x42, 0xe3, 0xdd, 0x97,
+	0x87, 0x2b, 0xb7, 0x4e, 0x36, 0xd8, 0x0a, 0x07, 0x3e, 0x1d, 0xbb, 0xdb, 0xf8, 0xfb, 0xc6, 0x98,
+	0xe1, 0xc8, 0x33, 0xcc, 0x38, 0x37, 0xa1, 0xa8, 0x68, 0x0f, 0x47, 0xa3, 0x1e, 0x43, 0x36, 0xe4,
+	0x14, 0x47, 0x66, 0x26, 0xa1, 0x9d, 0x5f, 0x2d, 0x1e, 0xe1, 0x03, 0x91, 0xe0, 0x37, 0x13, 0xe1,
+	0xfb, 0x90, 0xd9, 0xc5, 0xe3, 0xca, 0xcc, 0x69, 0x6c, 0xc9, 0x37, 0x3e, 0x26, 0x34, 0xa8, 0x6f,
+	0xbc, 0xef, 0xc5, 0x06, 0xb4, 0x4c, 0x65, 0x8c, 0x4c, 0x7d, 0x03, 0x05, 0x79, 0xff, 0x47, 0x7e,
+	0x6f, 0x84, 0xd1, 0x2e, 0xcc, 0xf2, 0x83, 0xbc, 0xfd, 0x86, 0xf4, 0x78, 0xca, 0xa8, 0x0a, 0x1b,
+	0xce, 0x26, 0x5c, 0x7c, 0x10, 0x46, 0xaa, 0x04, 0x65, 0xc9, 0x2f, 0xc1, 0xec, 0x17, 0x71, 0x53,
+	0xcb, 0x70, 0x0a, 0xe2, 0xd8, 0x4a, 0xba, 0x03, 0x85, 0x26, 0x66, 0x7b, 0x7e, 0x5f, 0xc6, 0x17,
+	0x41, 0x36, 0x26, 0xa4, 0x32, 0x3f, 0x1f, 0xab, 0xfb, 0x09, 0x14, 0x63, 0xf7, 0xb1, 0xcc, 0x99,
+	0x7c, 0x5f, 0x81, 0xcb, 0xb1, 0x6f, 0xcc, 0x9e, 0x13, 0xfa, 0xd4, 0x93, 0x0d, 0xcf, 0x0d, 0x39,
+	0x65, 0x58, 0x6a, 0x62, 0xf6, 0x48, 0x4d, 0x85, 0x03, 0x2c, 0x1a, 0xcc, 0x69, 0xc2, 0xd5, 0x14,
+	0x7f, 0x3b, 0x8c, 0x07, 0xc0, 0x38, 0x69, 0xf7, 0x9d, 0x41, 0xbb, 0x37, 0x0a, 0xf0, 0x3e, 0xc5,
+	0xcf, 0x42, 0x32, 0x12, 0x55, 0x92, 0xf1, 0xd2, 0x6c, 0xa7, 0x01, 0xa5, 0x94, 0x63, 0x54, 0x85,
+	0xcc, 0x01, 0x66, 0x15, 0xeb, 0x7a, 0x66, 0x2d, 0x5f, 0xbf, 0xe6, 0x26, 0xc3, 0x52, 0x08, 0x60,
+	0x8a, 0x83, 0xc4, 0xaf, 0x17, 0x4b, 0x3a, 0x3f, 0x58, 0x70, 0x69, 0xca, 0xc7, 0x7f, 0xbd, 0x46,
+	0xd7, 0x21, 0xbb, 0x47, 0x02, 0xcc, 0xa3, 0x97, 0xaf, 0x97, 0xdd, 0x64, 0x36, 0xc6, 0xdc, 0x9d,
+	0x00, 0x0f, 0x58, 0xc8, 0xc6, 0x1e, 0x97, 0x71, 0x9a, 0x70, 0x69, 0x4a, 0x74, 0x50, 0x0d, 0xe6,
+	0xe5, 0x51, 0xbe, 0xaf, 0x3c, 0x79, 0x9f, 0x2e, 0xef, 0x29, 0x31, 0x67, 0x0f, 0x0a, 0xfa, 0x87,
+	0x38, 0x89, 0x5d, 0x91, 0x44, 0x4b, 0x24, 0x51, 0x50, 0x68, 0x55, 0x44, 0x6d, 0x86, 0x5b, 0x5d,
+	0x72, 0x27, 0x83, 0x3c, 0x15, 0xac, 0x55, 0x3e, 0xb1, 0xf6, 0x29, 0x19, 0x92, 0xc8, 0xef, 0x25,
+	0xc5, 0xc6, 0xa7, 0x0b, 0x8f, 0x92, 0xc7, 0xcf, 0x4e, 0x0d, 0x50, 0x5c, 0x54, 0x4a, 0x50, 0x16,
+	0x96, 0x0d, 0x39, 0xc1, 0xc1, 0x01, 0x97, 0xce, 0x79, 0x09, 0xed, 0x3c, 0x84, 0xa2, 0x92, 0x96,
+	0x43, 0x65, 0x8a, 0x5d, 0x74, 0x03, 0xe6, 0x1a, 0x7e, 0xaf, 0x47, 0x98, 0x0c, 0x63, 0xc9, 0x55,
+	0x7b, 0x44, 0xb0, 0x3d, 0xf9, 0xd9, 0x29, 0xc1, 0x22, 0x1f, 0x3a, 0xbe, 0x6c, 0x28, 0x07, 0xc3,
+	0x2c, 0xa7, 0xd0, 0x3a, 0x5c, 0x50, 0xad, 0x16, 0xaf, 0x80, 0xad, 0x38, 0x27, 0x22, 0x18, 0x47,
+	0xf8, 0xf1, 0x3a, 0xd1, 0x79, 0x64, 0xc4, 0xb6, 0x54, 0x0a, 0xb3, 0xde, 0xb4, 0x4f, 0xce, 0x0d,
+	0xee, 0x97, 0x2f, 0x1a, 0xf1, 0xe6, 0x49, 0xdb, 0x58, 0x46, 0xdb, 0xfc, 0x66, 0x41, 0x41, 0x6d,
+	0x1d, 0x4a, 0xc8, 0x93, 0xe3, 0x04, 0xd1, 0xe7, 0x30, 0xbf, 0x39, 0x1c, 0x6a, 0xf3, 0xfb, 0x8c,
+	0xd3, 0x46, 0x59, 0x41, 0xab, 0x30, 0x2f, 0x1d, 0xf3, 0x29, 0x97, 0xaf, 0x17, 0xdc, 0x78, 0x87,
+	0x4b, 0x9e, 0xa7, 0x3e, 0xa2, 0x75, 0x98, 0xe5, 0x37, 0xab, 0x64, 0xb9, 0xd4, 0x92, 0xab, 0x76,
+	0xf4, 0x7d, 0x42, 0x71, 0x24, 0x6e, 0xed, 0x09, 0x11, 0xe7, 0x2f, 0x2b, 0x99, 0x90, 0xff, 0xf1,
+	0x6b, 0x92, 0x51, 0x9c, 0x39, 0xff, 0x28, 0x3e, 0xd5, 0x93, 0x7f, 0x9a, 0x81, 0x0b, 0x71, 0x8d,
+	0xbf, 0xd1, 0xc5, 0xb6, 0xcb, 0xab, 0x96, 0xb2, 0xf3, 0x05, 0x4b, 0xd8, 0x40, 0x4d, 0xc8, 0xdc,
+	0x1b, 0x04, 0xe7, 0x0b, 0x54, 0x6c, 0x21, 0x5e, 0x10, 0x0f, 0xc2, 0x7e, 0xc8, 0x78, 0x98, 0xb2,
+	0x9e, 0x20, 0xb4, 0x94, 0xcf, 0x1a, 0x95, 0xfe, 0xe3, 0xa4, 0x36, 0xee, 0x0d, 0xe2, 0x11, 0x2d,
+	0xb7, 0xb5, 0x75, 0xde, 0x6d, 0x9d, 0xa4, 0x7e, 0xe6, 0xfc, 0xa9, 0xaf, 0xff, 0x99, 0x93, 0x5b,
+	0x0f, 0xd5, 0x61, 0x4e, 0x80, 0x4f, 0xf4, 0xff, 0xc9, 0x78, 0xd5, 0xe0, 0xa8, 0x7d, 0x31, 0x66,
+	0xbb, 0x62, 0x4a, 0x49, 0xc9, 0x0d, 0x80, 0x09, 0x8a, 0x44, 0x57, 0x26, 0x7a, 0x29, 0x6c, 0x69,
+	0x1b, 0xbd, 0x86, 0xb6, 0x20, 0xaf, 0x01, 0x40, 0x64, 0x1b, 0x7a, 0x06, 0x2e, 0xb4, 0x2b, 0x93,
+	0x6f, 0x29, 0xf0, 0xf5, 0x29, 0xf7, 0x2d, 0x23, 0x9c, 0xf2, 0xad, 0x17, 0xa7, 0x5d, 0xd6, 0x9f,
+	0xa3, 0xa1, 0x99, 0xbb, 0x06, 0x04, 0xe6, 0xed, 0x7b, 0xc2, 0x0b, 0x34, 0x2b, 0x86, 0xca, 0x5d,
+	0x03, 0xe6, 0x4d, 0xb1, 0xf2, 0x0f, 0x77, 0x11, 0x2a, 0x5b, 0x90, 0xd7, 0x9a, 0x4a, 0x8f, 0x48,
+	0xba, 0xd7, 0xa6, 0x98, 0xe0, 0xe5, 0x55, 0xb3, 0xd0, 0x47, 0x50, 0xd0, 0x11, 0x15, 0xba, 0x6a,
+	0x5a, 0x31, 0x90, 0x96, 0x99, 0x91, 0x9a, 0x85, 0xaa, 0x30, 0x2f, 0xb1, 0x14, 0x2a, 0x1b, 0xf7,
+	0x4f, 0xe0, 0x95, 0x5d, 0x70, 0xc5, 0x3f, 0x20, 0x51, 0xce, 0x1b, 0xb0, 0x90, 0x00, 0x28, 0x54,
+	0x31, 0x5d, 0x4d, 0x50, 0x95, 0xa9, 0x54, 0xb3, 0x90, 0x07, 0xe8, 0x28, 0x6e, 0x42, 0x6f, 0x9b,
+	0x2e, 0xa7, 0xa0, 0x2a, 0x5b, 0x8b, 0x6a, 0x5a, 0x7b, 0x87, 0xe7, 0xc0, 0xd8, 0xf8, 0xcb, 0x86,
+	0xc1, 0x23, 0x58, 0xcc, 0x3e, 0x06, 0x42, 0xa0, 0x6f, 0xa1, 0x3c, 0x1d, 0xa3, 0xa1, 0x77, 0x8e,
+	0xb5, 0xa8, 0xa3, 0x38, 0xfb, 0xda, 0x74, 0xc3, 0xca, 0xca, 0x1d, 0x5e, 0xfa, 0x6a, 0xe5, 0xa7,
+	0x4a, 0xdf, 0x00, 0x18, 0x76, 0x7a, 0xc9, 0xa3, 0x1d, 0x58, 0x34, 0xd0, 0x05, 0x7a, 0xcb, 0x8c,
+	0xba, 0x09, 0x3b, 0xf4, 0xd6, 0x31, 0x21, 0x46, 0xcd, 0x42, 0xb7, 0x21, 0xa7, 0x70, 0x02, 0xba,
+	0x9c, 0x2a, 0x57, 0x85, 0x1d, 0xec, 0x92, 0x39, 0x07, 0x22, 0xf4, 0x21, 0x14, 0xd5, 0x96, 0xdf,
+	0xc6, 0x7e, 0x80, 0x69, 0x4a, 0x77, 0xb2, 0xff, 0xed, 0x45, 0x57, 0xfc, 0x75, 0x16, 0x72, 0x8d,
+	0x8f, 0x7f, 0x7f, 0xb5, 0x6c, 0xfd, 0xf1, 0x6a, 0xd9, 0xfa, 0xe5, 0xf5, 0xb2, 0xf5, 0xe2, 0xf5,
+	0xb2, 0xf5, 0xf5, 0xfa, 0xc9, 0xe3, 0x8a, 0x0e, 0xdb, 0x55, 0x65, 0xba, 0x35, 0xc7, 0xff, 0x1e,
+	0xbf, 0xf7, 0xf7, 0x00, 0x64, 0xfc, 0x2d, 0x28, 0x04, 0x10, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1081,6 +1358,12 @@ type QueryClient interface {
 	GetAccount(ctx context.Context, in *GetAccountParam, opts ...grpc.CallOption) (*acm.Account, error)
 	GetMetadata(ctx context.Context, in *GetMetadataParam, opts ...grpc.CallOption) (*MetadataResult, error)
 	GetStorage(ctx context.Context, in *GetStorageParam, opts ...grpc.CallOption) (*StorageValue, error)
+	// GetAccountProof returns an account along with a proof of its value (or absence) against the AppHash
+	GetAccountProof(ctx context.Context, in *GetAccountParam, opts ...grpc.CallOption) (*AccountProof, error)
+	// GetStorageProof returns a storage value along with a proof of its value (or absence) against the AppHash
+	GetStorageProof(ctx context.Context, in *GetStorageParam, opts ...grpc.CallOption) (*StorageProof, error)
+	// ListStorage streams the storage of a contract in ascending order of key
+	ListStorage(ctx context.Context, in *ListStorageParam, opts ...grpc.CallOption) (Query_ListStorageClient, error)
 	ListAccounts(ctx context.Context, in *ListAccountsParam, opts ...grpc.CallOption) (Query_ListAccountsClient, error)
 	GetName(ctx context.Context, in *GetNameParam, opts ...grpc.CallOption) (*names.Entry, error)
 	ListNames(ctx context.Context, in *ListNamesParam, opts ...grpc.CallOption) (Query_ListNamesClient, error)
@@ -1138,8 +1421,58 @@ func (c *queryClient) GetStorage(ctx context.Context, in *GetStorageParam, opts
 	return out, nil
 }
 
+func (c *queryClient) GetAccountProof(ctx context.Context, in *GetAccountParam, opts ...grpc.CallOption) (*AccountProof, error) {
+	out := new(AccountProof)
+	err := c.cc.Invoke(ctx, "/rpcquery.Query/GetAccountProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) GetStorageProof(ctx context.Context, in *GetStorageParam, opts ...grpc.CallOption) (*StorageProof, error) {
+	out := new(StorageProof)
+	err := c.cc.Invoke(ctx, "/rpcquery.Query/GetStorageProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ListStorage(ctx context.Context, in *ListStorageParam, opts ...grpc.CallOption) (Query_ListStorageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[0], "/rpcquery.Query/ListStorage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryListStorageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Query_ListStorageClient interface {
+	Recv() (*StorageEntry, error)
+	grpc.ClientStream
+}
+
+type queryListStorageClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryListStorageClient) Recv() (*StorageEntry, error) {
+	m := new(StorageEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *queryClient) ListAccounts(ctx context.Context, in *ListAccountsParam, opts ...grpc.CallOption) (Query_ListAccountsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[0], "/rpcquery.Query/ListAccounts", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[1], "/rpcquery.Query/ListAccounts", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1180,7 +1513,7 @@ func (c *queryClient) GetName(ctx context.Context, in *GetNameParam, opts ...grp
 }
 
 func (c *queryClient) ListNames(ctx context.Context, in *ListNamesParam, opts ...grpc.CallOption) (Query_ListNamesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[1], "/rpcquery.Query/ListNames", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[2], "/rpcquery.Query/ListNames", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1248,7 +1581,7 @@ func (c *queryClient) GetProposal(ctx context.Context, in *GetProposalParam, opt
 }
 
 func (c *queryClient) ListProposals(ctx context.Context, in *ListProposalsParam, opts ...grpc.CallOption) (Query_ListProposalsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[2], "/rpcquery.Query/ListProposals", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[3], "/rpcquery.Query/ListProposals", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1303,6 +1636,12 @@ type QueryServer interface {
 	GetAccount(context.Context, *GetAccountParam) (*acm.Account, error)
 	GetMetadata(context.Context, *GetMetadataParam) (*MetadataResult, error)
 	GetStorage(context.Context, *GetStorageParam) (*StorageValue, error)
+	// GetAccountProof returns an account along with a proof of its value (or absence) against the AppHash
+	GetAccountProof(context.Context, *GetAccountParam) (*AccountProof, error)
+	// GetStorageProof returns a storage value along with a proof of its value (or absence) against the AppHash
+	GetStorageProof(context.Context, *GetStorageParam) (*StorageProof, error)
+	// ListStorage streams the storage of a contract in ascending order of key
+	ListStorage(*ListStorageParam, Query_ListStorageServer) error
 	ListAccounts(*ListAccountsParam, Query_ListAccountsServer) error
 	GetName(context.Context, *GetNameParam) (*names.Entry, error)
 	ListNames(*ListNamesParam, Query_ListNamesServer) error
@@ -1332,6 +1671,15 @@ func (*UnimplementedQueryServer) GetMetadata(ctx context.Context, req *GetMetada
 func (*UnimplementedQueryServer) GetStorage(ctx context.Context, req *GetStorageParam) (*StorageValue, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStorage not implemented")
 }
+func (*UnimplementedQueryServer) GetAccountProof(ctx context.Context, req *GetAccountParam) (*AccountProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountProof not implemented")
+}
+func (*UnimplementedQueryServer) GetStorageProof(ctx context.Context, req *GetStorageParam) (*StorageProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageProof not implemented")
+}
+func (*UnimplementedQueryServer) ListStorage(req *ListStorageParam, srv Query_ListStorageServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListStorage not implemented")
+}
 func (*UnimplementedQueryServer) ListAccounts(req *ListAccountsParam, srv Query_ListAccountsServer) error {
 	return status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
 }
@@ -1439,6 +1787,63 @@ func _Query_GetStorage_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Query_GetAccountProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).GetAccountProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcquery.Query/GetAccountProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).GetAccountProof(ctx, req.(*GetAccountParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_GetStorageProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).GetStorageProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcquery.Query/GetStorageProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).GetStorageProof(ctx, req.(*GetStorageParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ListStorage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListStorageParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServer).ListStorage(m, &queryListStorageServer{stream})
+}
+
+type Query_ListStorageServer interface {
+	Send(*StorageEntry) error
+	grpc.ServerStream
+}
+
+type queryListStorageServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryListStorageServer) Send(m *StorageEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Query_ListAccounts_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ListAccountsParam)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1648,6 +2053,14 @@ var _Query_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStorage",
 			Handler:    _Query_GetStorage_Handler,
 		},
+		{
+			MethodName: "GetAccountProof",
+			Handler:    _Query_GetAccountProof_Handler,
+		},
+		{
+			MethodName: "GetStorageProof",
+			Handler:    _Query_GetStorageProof_Handler,
+		},
 		{
 			MethodName: "GetName",
 			Handler:    _Query_GetName_Handler,
@@ -1678,6 +2091,11 @@ var _Query_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListStorage",
+			Handler:       _Query_ListStorage_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ListAccounts",
 			Handler:       _Query_ListAccounts_Handler,
@@ -1725,6 +2143,9 @@ func (m *GetAccountParam) Size() (n int) {
 	_ = l
 	l = m.Address.Size()
 	n += 1 + l + sovRpcquery(uint64(l))
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1777,6 +2198,9 @@ func (m *GetStorageParam) Size() (n int) {
 	n += 1 + l + sovRpcquery(uint64(l))
 	l = m.Key.Size()
 	n += 1 + l + sovRpcquery(uint64(l))
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1807,6 +2231,9 @@ func (m *ListAccountsParam) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRpcquery(uint64(l))
 	}
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1823,6 +2250,9 @@ func (m *GetNameParam) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRpcquery(uint64(l))
 	}
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1839,6 +2269,9 @@ func (m *ListNamesParam) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRpcquery(uint64(l))
 	}
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2055,6 +2488,94 @@ func (m *GetBlockParam) Size() (n int) {
 	return n
 }
 
+func (m *AccountProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
+	l = m.AppHash.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	if m.Account != nil {
+		l = m.Account.Size()
+		n += 1 + l + sovRpcquery(uint64(l))
+	}
+	if m.Proof != nil {
+		l = m.Proof.Size()
+		n += 1 + l + sovRpcquery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StorageProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
+	l = m.AppHash.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	l = m.Value.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	if m.Proof != nil {
+		l = m.Proof.Size()
+		n += 1 + l + sovRpcquery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ListStorageParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Address.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	l = m.Start.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	l = m.End.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	if m.Limit != 0 {
+		n += 1 + sovRpcquery(uint64(m.Limit))
+	}
+	if m.Height != 0 {
+		n += 1 + sovRpcquery(uint64(m.Height))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StorageEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Key.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	l = m.Value.Size()
+	n += 1 + l + sovRpcquery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovRpcquery(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -27,10 +27,11 @@ type transactServer struct {
 	txCodec    txs.Codec
 	logger     *logging.Logger
 	lock       *sync.Mutex
+	exeOptions []execution.Option
 }
 
 func NewTransactServer(state acmstate.Reader, blockchain bcm.BlockchainInfo, transactor *execution.Transactor,
-	txCodec txs.Codec, logger *logging.Logger) TransactServer {
+	txCodec txs.Codec, logger *logging.Logger, exeOptions ...execution.Option) TransactServer {
 	return &transactServer{
 		state:      state,
 		blockchain: blockchain,
@@ -38,6 +39,7 @@ func NewTransactServer(state acmstate.Reader, blockchain bcm.BlockchainInfo, tra
 		txCodec:    txCodec,
 		logger:     logger.WithScope("NewTransactServer()"),
 		lock:       &sync.Mutex{},
+		exeOptions: exeOptions,
 	}
 }
 
@@ -105,14 +107,15 @@ func (ts *transactServer) CallTxSim(ctx context.Context, param *payload.CallTx)
 	}
 	ts.lock.Lock()
 	defer ts.lock.Unlock()
-	return execution.CallSim(ts.state, ts.blockchain, param.Input.Address, *param.Address, param.Data, ts.logger)
+	return execution.CallSim(ts.state, ts.blockchain, param.Input.Address, *param.Address, param.Data, ts.logger,
+		ts.exeOptions...)
 }
 
 func (ts *transactServer) CallCodeSim(ctx context.Context, param *CallCodeParam) (*exec.TxExecution, error) {
 	ts.lock.Lock()
 	defer ts.lock.Unlock()
 	return execution.CallCodeSim(ts.state, ts.blockchain, param.FromAddress, param.FromAddress, param.Code, param.Data,
-		ts.logger)
+		ts.logger, ts.exeOptions...)
 }
 
 func (ts *transactServer) SendTxSync(ctx context.Context, param *payload.SendTx) (*exec.TxExecution, error) {
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	x "github.com/hyperledger/burrow/encoding/hex"
+	"github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/logging/structure"
+	"github.com/hyperledger/burrow/rpc/web3"
+)
+
+const (
+	subscriptionBufferSize = 100
+
+	newHeadsSubscription               = "newHeads"
+	logsSubscription                   = "logs"
+	newPendingTransactionsSubscription = "newPendingTransactions"
+)
+
+var _ web3.Subscriber = &EthService{}
+
+// EthSubscribe streams new block headers, matching logs, or pending transaction hashes as each block is committed
+func (srv *EthService) EthSubscribe(ctx context.Context, req *web3.EthSubscribeParams) (<-chan interface{}, error) {
+	var notify func(be *exec.BlockExecution) ([]interface{}, error)
+	switch req.SubscriptionType {
+	case newHeadsSubscription:
+		notify = srv.notifyNewHead
+	case logsSubscription:
+		filter, err := newLogFilter(&req.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if filter.blockHash != "" || filter.fromBlock != "" || filter.toBlock != "" {
+			return nil, fmt.Errorf("logs subscription only accepts address and topics")
+		}
+		notify = func(be *exec.BlockExecution) ([]interface{}, error) {
+			return srv.notifyLogs(be, filter)
+		}
+	case newPendingTransactionsSubscription:
+		notify = srv.pendingTransactionNotifier()
+	default:
+		return nil, fmt.Errorf("unsupported subscription type '%s'", req.SubscriptionType)
+	}
+
+	subID := event.GenSubID()
+	blocks, err := srv.emitter.Subscribe(ctx, subID, exec.QueryForBlockExecution(), subscriptionBufferSize)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan interface{})
+	go func() {
+		defer func() {
+			close(out)
+			srv.emitter.UnsubscribeAll(context.Background(), subID)
+			for range blocks {
+				// flush
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-blocks:
+				if !ok {
+					return
+				}
+				results, err := notify(msg.(*exec.BlockExecution))
+				if err != nil {
+					srv.logger.InfoMsg("could not build subscription notification",
+						"subscription_type", req.SubscriptionType,
+						structure.ErrorKey, err)
+					continue
+				}
+				for _, result := range results {
+					select {
+					case <-ctx.Done():
+						return
+					case out <- result:
+					}
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (srv *EthService) notifyNewHead(be *exec.BlockExecution) ([]interface{}, error) {
+	block, err := srv.getBlockInfoAtHeight(be.Height, false)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{block}, nil
+}
+
+func (srv *EthService) notifyLogs(be *exec.BlockExecution, filter *logFilter) ([]interface{}, error) {
+	header, err := srv.getBlockHeaderAtHeight(be.Height)
+	if err != nil {
+		return nil, err
+	}
+	blockHash := hexKeccak(header.Hash().Bytes())
+	var logs []web3.Logs
+	var logIndex uint64
+	for _, txe := range be.TxExecutions {
+		logs = appendLogs(logs, txe, blockHash, &logIndex, filter.query)
+	}
+	results := make([]interface{}, len(logs))
+	for i, log := range logs {
+		results[i] = log
+	}
+	return results, nil
+}
+
+// Since the mempool is only sampled as each block is committed we also report any transactions that went into that
+// block without being seen there, so every transaction is reported exactly once
+func (srv *EthService) pendingTransactionNotifier() func(be *exec.BlockExecution) ([]interface{}, error) {
+	// Only the hashes from the previous sample need to be remembered since a transaction leaves the mempool at most once
+	seen := make(map[string]struct{})
+	return func(be *exec.BlockExecution) ([]interface{}, error) {
+		var results []interface{}
+		for _, txe := range be.TxExecutions {
+			hash := x.EncodeBytes(txe.TxHash)
+			if _, ok := seen[hash]; !ok {
+				results = append(results, hash)
+			}
+		}
+		if srv.nodeView == nil {
+			return results, nil
+		}
+		envelopes, err := srv.nodeView.MempoolTransactions(-1)
+		if err != nil {
+			return results, err
+		}
+		sample := make(map[string]struct{}, len(envelopes))
+		for _, env := range envelopes {
+			hash := x.EncodeBytes(env.Tx.Hash())
+			sample[hash] = struct{}{}
+			if _, ok := seen[hash]; !ok {
+				results = append(results, hash)
+			}
+		}
+		seen = sample
+		return results, nil
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+
+	"github.com/gorilla/websocket"
 )
 
 const JSONRPC = "2.0"
@@ -200,6 +202,10 @@ func StructToResult(in interface{}) interface{} {
 }
 
 func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		srv.ServeWebSocket(w, r)
+		return
+	}
 	if r.Method == http.MethodOptions {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
@@ -220,16 +226,10 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	r.Body.Close()
 
-	requests := make([]RPCRequest, 0)
-	err = json.Unmarshal(data, &requests)
+	requests, err := ParseRequests(data)
 	if err != nil {
-		request := new(RPCRequest)
-		err = json.Unmarshal(data, request)
-		if err != nil {
-			WriteData(w, ErrCouldNotParse.RPCError().AsRPCErrorResponse(nil))
-			return
-		}
-		requests = []RPCRequest{*request}
+		WriteData(w, ErrCouldNotParse.RPCError().AsRPCErrorResponse(nil))
+		return
 	}
 
 	responses := make([]interface{}, 0)
@@ -244,6 +244,21 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ParseRequests accepts either a single request or a batch
+func ParseRequests(data []byte) ([]RPCRequest, error) {
+	requests := make([]RPCRequest, 0)
+	err := json.Unmarshal(data, &requests)
+	if err != nil {
+		request := new(RPCRequest)
+		err = json.Unmarshal(data, request)
+		if err != nil {
+			return nil, err
+		}
+		requests = []RPCRequest{*request}
+	}
+	return requests, nil
+}
+
 func (srv *Server) Do(in RPCRequest) interface{} {
 	if in.JSONRPC != JSONRPC || in.Method == "" || in.ID == nil {
 		return ErrInvalidParams.RPCError().AsRPCErrorResponse(nil)
@@ -485,6 +500,26 @@ func (srv *Server) Do(in RPCRequest) interface{} {
 		if err == nil {
 			out, err = srv.service.EthUninstallFilter(req)
 		}
+	case "eth_subscribe", "eth_unsubscribe":
+		err = fmt.Errorf("%s requires a WebSocket connection", in.Method)
+	case "debug_traceTransaction":
+		req := new(DebugTraceTransactionParams)
+		err = ParamsToStruct(in.Params, req)
+		if err == nil {
+			out, err = srv.service.DebugTraceTransaction(req)
+		}
+	case "debug_traceCall":
+		req := new(DebugTraceCallParams)
+		err = ParamsToStruct(in.Params, req)
+		if err == nil {
+			out, err = srv.service.DebugTraceCall(req)
+		}
+	case "debug_storageRangeAt":
+		req := new(DebugStorageRangeAtParams)
+		err = ParamsToStruct(in.Params, req)
+		if err == nil {
+			out, err = srv.service.DebugStorageRangeAt(req)
+		}
 	}
 
 	if err != nil {
@@ -102,6 +102,12 @@ type Service interface {
 	EthSyncing() (*EthSyncingResult, error)
 	// Uninstalls a filter with given id. Should always be called when watch is no longer needed. Additionally Filters timeout when they aren't requested with eth_getFilterChanges for a period of time.
 	EthUninstallFilter(*EthUninstallFilterParams) (*EthUninstallFilterResult, error)
+	// Re-executes a committed transaction against the state it originally ran on and returns a trace of its execution.
+	DebugTraceTransaction(*DebugTraceTransactionParams) (*DebugTraceTransactionResult, error)
+	// Executes a new message call (locally) immediately without creating a transaction on the block chain and returns a trace of its execution.
+	DebugTraceCall(*DebugTraceCallParams) (*DebugTraceCallResult, error)
+	// Returns the storage of a contract before or after the transactions of a block in ascending order of key.
+	DebugStorageRangeAt(*DebugStorageRangeAtParams) (*DebugStorageRangeAtResult, error)
 }
 type Web3ClientVersionResult struct {
 	// client version
@@ -336,7 +342,8 @@ type EthGetFilterChangesParams struct {
 	FilterId string `json:"filterId"`
 }
 type Log struct {
-	Topics []Topics `json:"topics"`
+	// Array of 32 Bytes DATA topics
+	Topics []string `json:"topics"`
 	// Hex representation of a Keccak 256 hash
 	TransactionHash string `json:"transactionHash"`
 	// Sender of the transaction
@@ -356,7 +363,8 @@ type LogResult struct {
 	// An indexed event generated during a transaction
 	Log
 
-	Topics []Topics `json:"topics"`
+	// Array of 32 Bytes DATA topics
+	Topics []string `json:"topics"`
 	// Hex representation of a Keccak 256 hash
 	TransactionHash string `json:"transactionHash"`
 	// Sender of the transaction
@@ -373,7 +381,10 @@ type LogResult struct {
 	TransactionIndex string `json:"transactionIndex"`
 }
 type EthGetFilterChangesResult struct {
-	LogResult []LogResult `json:"logResult"`
+	// Logs since the last poll for filters created by eth_newFilter
+	Logs []Logs `json:"logs"`
+	// Hashes of blocks since the last poll for filters created by eth_newBlockFilter
+	BlockHashes []string `json:"blockHashes"`
 }
 type EthGetFilterLogsParams struct {
 	// An identifier used to reference the filter.
@@ -397,7 +408,8 @@ type Logs struct {
 	// Hex representation of a variable length byte array
 	Data string `json:"data"`
 
-	Topics []Topics `json:"topics"`
+	// Array of 32 Bytes DATA topics
+	Topics []string `json:"topics"`
 }
 type EthGetFilterLogsResult struct {
 	Logs []Logs `json:"logs"`
@@ -434,10 +446,12 @@ type Filter struct {
 	FromBlock string `json:"fromBlock"`
 	// The hex representation of the block's height
 	ToBlock string `json:"toBlock"`
-
-	Address string `json:"address"`
+	// Contract address or a list of addresses from which logs should originate
+	Address interface{} `json:"address"`
 	// Array of 32 Bytes DATA topics. Topics are order-dependent. Each topic can also be an array of DATA with 'or' options
-	Topics []string `json:"topics"`
+	Topics []interface{} `json:"topics"`
+	// Restricts the logs returned to the single block with this hash, cannot be used with fromBlock or toBlock
+	BlockHash string `json:"blockHash"`
 }
 type Address struct {
 	// Address of the contract from which to monitor events
@@ -515,6 +529,8 @@ type Receipt struct {
 	CumulativeGasUsed string `json:"cumulativeGasUsed"`
 	// Hex representation of the integer
 	GasUsed string `json:"gasUsed"`
+	// Hex representation of the price paid for each unit of gas used
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
 	// An array of all the logs triggered during the transaction
 	Logs []Logs `json:"logs"`
 	// A 2048 bit bloom filter from the logs of the transaction. Each log sets 3 bits though taking the low-order 11 bits of each of the first three pairs of bytes in a Keccak 256 hash of the log's byte series
@@ -816,3 +832,78 @@ type EthUninstallFilterResult struct {
 	// Whether of not the filter was successfully uninstalled
 	FilterUninstalledSuccess bool `json:"filterUninstalledSuccess"`
 }
+type TraceConfig struct {
+	// Name of the tracer to use, either callTracer or empty for struct logs
+	Tracer string `json:"tracer"`
+	// Omit the stack from struct logs
+	DisableStack bool `json:"disableStack"`
+	// Omit memory from struct logs
+	DisableMemory bool `json:"disableMemory"`
+	// Omit storage from struct logs
+	DisableStorage bool `json:"disableStorage"`
+	// Maximum number of struct logs to return, zero for no limit
+	Limit int `json:"limit"`
+}
+type DebugTraceTransactionParams struct {
+	// Hex representation of a Keccak 256 hash
+	TransactionHash string `json:"transactionHash"`
+
+	TraceConfig TraceConfig `json:"traceConfig"`
+}
+type DebugTraceTransactionResult struct {
+	// Struct logs or call frame depending on the tracer
+	Trace interface{} `json:"trace"`
+}
+type DebugTraceCallParams struct {
+	Transaction
+
+	BlockNumber string `json:"blockNumber"`
+
+	TraceConfig TraceConfig `json:"traceConfig"`
+}
+type DebugTraceCallResult struct {
+	// Struct logs or call frame depending on the tracer
+	Trace interface{} `json:"trace"`
+}
+type DebugStorageRangeAtParams struct {
+	// The hex representation of the Keccak 256 of the RLP encoded block
+	BlockHash string `json:"blockHash"`
+	// Zero for the state before the block or the number of transactions in the block for the state after it
+	TxIndex int `json:"txIndex"`
+
+	ContractAddress string `json:"contractAddress"`
+	// Hex representation of the first storage key to return
+	KeyStart string `json:"keyStart"`
+	// Maximum number of entries to return
+	MaxResult int `json:"maxResult"`
+}
+type StorageEntry struct {
+	// Hex representation of the storage key
+	Key string `json:"key"`
+	// Hex representation of a 256 bit unit of data
+	Value string `json:"value"`
+}
+type DebugStorageRangeAtResult struct {
+	// Storage entries by the Keccak 256 of their key
+	Storage map[string]StorageEntry `json:"storage"`
+	// The key at which to continue or null if there are no more entries
+	NextKey *string `json:"nextKey"`
+}
+type EthSubscribeParams struct {
+	// One of newHeads, logs, or newPendingTransactions
+	SubscriptionType string `json:"subscriptionType"`
+	// A filter used to monitor the blockchain for log/events (logs subscriptions only)
+	Filter Filter `json:"filter"`
+}
+type EthSubscribeResult struct {
+	// An identifier used to reference the subscription
+	SubscriptionId string `json:"subscriptionId"`
+}
+type EthUnsubscribeParams struct {
+	// An identifier used to reference the subscription
+	SubscriptionId string `json:"subscriptionId"`
+}
+type EthUnsubscribeResult struct {
+	// Whether of not the subscription was successfully cancelled
+	UnsubscribeSuccess bool `json:"unsubscribeSuccess"`
+}
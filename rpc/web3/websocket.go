@@ -0,0 +1,181 @@
+package web3
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+const subscriptionMethod = "eth_subscription"
+
+// Subscriber is implemented by services that support eth_subscribe, which is only available over WebSocket
+type Subscriber interface {
+	// EthSubscribe returns a channel of results for the subscription that is closed once ctx is done
+	EthSubscribe(ctx context.Context, req *EthSubscribeParams) (<-chan interface{}, error)
+}
+
+// https://geth.ethereum.org/docs/rpc/pubsub
+type RPCNotification struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  SubscriptionResult `json:"params"`
+}
+
+type SubscriptionResult struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+var upgrader = websocket.Upgrader{
+	// We allow any origin over HTTP so do the same here
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ServeWebSocket serves JSON-RPC requests over a WebSocket connection, on which subscriptions are also available
+func (srv *Server) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an error
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	wc := &wsConn{
+		Server:        srv,
+		conn:          conn,
+		ctx:           ctx,
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+	defer func() {
+		// Cancelling the parent context ends all subscriptions
+		cancel()
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var response interface{}
+		var starts []func()
+		requests, err := ParseRequests(data)
+		if err != nil {
+			response = ErrCouldNotParse.RPCError().AsRPCErrorResponse(nil)
+		} else {
+			responses := make([]interface{}, len(requests))
+			for i, req := range requests {
+				var start func()
+				responses[i], start = wc.do(req)
+				if start != nil {
+					starts = append(starts, start)
+				}
+			}
+			response = responses
+			if len(responses) == 1 {
+				response = responses[0]
+			}
+		}
+		if wc.write(response) != nil {
+			return
+		}
+		// Only begin notifying once the client has the subscription ID
+		for _, start := range starts {
+			start()
+		}
+	}
+}
+
+type wsConn struct {
+	*Server
+	conn *websocket.Conn
+	ctx  context.Context
+	// Serialises writes to conn
+	writeMtx sync.Mutex
+	// Subscriptions are only accessed from the read loop
+	subscriptions map[string]context.CancelFunc
+}
+
+// do serves a request returning the response and, for a new subscription, a function to start its notifications
+func (wc *wsConn) do(in RPCRequest) (interface{}, func()) {
+	switch in.Method {
+	case "eth_subscribe":
+		return wc.subscribe(in)
+	case "eth_unsubscribe":
+		req := new(EthUnsubscribeParams)
+		err := ParamsToStruct(in.Params, req)
+		if err != nil {
+			return ErrInvalidParams.RPCErrorWithMessage(err.Error()).AsRPCErrorResponse(in.ID), nil
+		}
+		cancel, ok := wc.subscriptions[req.SubscriptionId]
+		if ok {
+			cancel()
+			delete(wc.subscriptions, req.SubscriptionId)
+		}
+		return wc.result(in, &EthUnsubscribeResult{UnsubscribeSuccess: ok}), nil
+	default:
+		return wc.Do(in), nil
+	}
+}
+
+func (wc *wsConn) subscribe(in RPCRequest) (interface{}, func()) {
+	subscriber, ok := wc.service.(Subscriber)
+	if !ok {
+		return ErrNotFound.RPCError().AsRPCErrorResponse(in.ID), nil
+	}
+	req := new(EthSubscribeParams)
+	err := ParamsToStruct(in.Params, req)
+	if err != nil {
+		return ErrInvalidParams.RPCErrorWithMessage(err.Error()).AsRPCErrorResponse(in.ID), nil
+	}
+	ctx, cancel := context.WithCancel(wc.ctx)
+	results, err := subscriber.EthSubscribe(ctx, req)
+	if err != nil {
+		cancel()
+		return ErrInternal.RPCErrorWithMessage(err.Error()).AsRPCErrorResponse(in.ID), nil
+	}
+	id := newSubscriptionID()
+	wc.subscriptions[id] = cancel
+
+	return wc.result(in, &EthSubscribeResult{SubscriptionId: id}), func() {
+		go func() {
+			for result := range results {
+				err := wc.write(RPCNotification{
+					JSONRPC: JSONRPC,
+					Method:  subscriptionMethod,
+					Params: SubscriptionResult{
+						Subscription: id,
+						Result:       result,
+					},
+				})
+				if err != nil {
+					// The read loop will also fail and clean up
+					cancel()
+				}
+			}
+		}()
+	}
+}
+
+func (wc *wsConn) result(in RPCRequest, out interface{}) RPCResultResponse {
+	return RPCResultResponse{
+		JSONRPC: JSONRPC,
+		ID:      in.ID,
+		Result:  StructToResult(out),
+	}
+}
+
+func (wc *wsConn) write(msg interface{}) error {
+	wc.writeMtx.Lock()
+	defer wc.writeMtx.Unlock()
+	return wc.conn.WriteJSON(msg)
+}
+
+func newSubscriptionID() string {
+	bs := make([]byte, 16)
+	rand.Read(bs)
+	return "0x" + hex.EncodeToString(bs)
+}
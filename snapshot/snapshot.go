@@ -0,0 +1,337 @@
+// Package snapshot writes and reads chunked snapshots of the state at a height, from which a fresh node can load its
+// state without replaying every block.
+//
+// A snapshot is a Manifest and a sequence of Chunks. Each chunk holds a run of raw state database entries (see
+// State.Snapshot) and the Manifest commits to each chunk by hash along with the AppHash the state must have once every
+// chunk is applied. This lines up with ABCI state sync: a Manifest can be offered as the snapshot's metadata (with
+// Manifest.Hash as its hash) and each chunk verified by VerifyChunk as it arrives.
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/encoding"
+	"github.com/hyperledger/burrow/execution/state"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	// Format of the snapshots written by this package, chunks of format 1 hold raw entries of the state database
+	Format = 1
+	// Target size of each chunk in bytes
+	DefaultChunkSize = 4 << 20
+	manifestFileName = "manifest"
+	chunkFileFormat  = "chunk-%05d"
+	tempSuffix       = ".tmp"
+)
+
+type SnapshotConfig struct {
+	// Take a snapshot at every height that is a multiple of Interval (never if zero)
+	Interval uint64
+	// Directory in which to store snapshots (relative to BurrowDir unless absolute)
+	Directory string
+	// Target size of each chunk in bytes
+	ChunkSize int
+	// Number of the most recent snapshots to keep (all if zero)
+	Keep int
+}
+
+func DefaultSnapshotConfig() *SnapshotConfig {
+	return &SnapshotConfig{
+		Directory: "snapshots",
+		ChunkSize: DefaultChunkSize,
+		Keep:      2,
+	}
+}
+
+// Path returns the directory in which to store snapshots for a node whose BurrowDir is burrowDir
+func (conf *SnapshotConfig) Path(burrowDir string) string {
+	if filepath.IsAbs(conf.Directory) {
+		return conf.Directory
+	}
+	return filepath.Join(burrowDir, conf.Directory)
+}
+
+// Store reads and writes snapshots in a directory with one sub-directory per height
+type Store struct {
+	directory string
+}
+
+func NewStore(directory string) *Store {
+	return &Store{
+		directory: directory,
+	}
+}
+
+// Create writes a snapshot of st at height, which must be a height whose state has not been pruned
+func (ss *Store) Create(st *state.State, chainID string, height uint64, blockTime time.Time,
+	chunkSize int) (*Manifest, error) {
+	const errHeader = "Store.Create():"
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	rs, err := st.LoadHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	manifest := &Manifest{
+		Format:    Format,
+		ChainID:   chainID,
+		Height:    height,
+		AppHash:   rs.Forest.Hash(),
+		BlockTime: blockTime,
+	}
+	// Write to a temporary directory so that only complete snapshots are ever visible
+	dir := ss.dir(height)
+	tmp := dir + tempSuffix
+	err = os.RemoveAll(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	err = os.MkdirAll(tmp, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	chunk := new(Chunk)
+	size := 0
+	flush := func() error {
+		bs, err := encoding.Encode(chunk)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(tmp, fmt.Sprintf(chunkFileFormat, len(manifest.ChunkHashes)))
+		err = ioutil.WriteFile(path, bs, 0600)
+		if err != nil {
+			return err
+		}
+		manifest.ChunkHashes = append(manifest.ChunkHashes, hash(bs))
+		chunk = new(Chunk)
+		size = 0
+		return nil
+	}
+	err = st.Snapshot(height, func(key, value []byte) error {
+		chunk.Entries = append(chunk.Entries, &Entry{
+			Key:   copyBytes(key),
+			Value: copyBytes(value),
+		})
+		size += len(key) + len(value)
+		if size >= chunkSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && len(chunk.Entries) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		os.RemoveAll(tmp)
+		return nil, fmt.Errorf("%s could not write snapshot at height %d: %v", errHeader, height, err)
+	}
+	bs, err := encoding.Encode(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	err = ioutil.WriteFile(filepath.Join(tmp, manifestFileName), bs, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	err = os.Rename(tmp, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	return manifest, nil
+}
+
+// Manifest returns the manifest of the snapshot at height
+func (ss *Store) Manifest(height uint64) (*Manifest, error) {
+	bs, err := ioutil.ReadFile(filepath.Join(ss.dir(height), manifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest of snapshot at height %d: %v", height, err)
+	}
+	manifest := new(Manifest)
+	err = encoding.Decode(bs, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode manifest of snapshot at height %d: %v", height, err)
+	}
+	if manifest.Height != height {
+		return nil, fmt.Errorf("snapshot in directory for height %d has manifest for height %d", height,
+			manifest.Height)
+	}
+	return manifest, nil
+}
+
+// List returns the manifest of every snapshot in the store in ascending order of height
+func (ss *Store) List() ([]*Manifest, error) {
+	infos, err := ioutil.ReadDir(ss.directory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var heights []uint64
+	for _, info := range infos {
+		height, err := strconv.ParseUint(info.Name(), 10, 64)
+		if err == nil && info.IsDir() {
+			heights = append(heights, height)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	manifests := make([]*Manifest, len(heights))
+	for i, height := range heights {
+		manifests[i], err = ss.Manifest(height)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return manifests, nil
+}
+
+// Chunk returns the encoded chunk at index of the snapshot described by manifest after checking it against its hash
+func (ss *Store) Chunk(manifest *Manifest, index int) ([]byte, error) {
+	bs, err := ioutil.ReadFile(filepath.Join(ss.dir(manifest.Height), fmt.Sprintf(chunkFileFormat, index)))
+	if err != nil {
+		return nil, fmt.Errorf("could not read chunk %d of snapshot at height %d: %v", index, manifest.Height, err)
+	}
+	err = manifest.VerifyChunk(index, bs)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// Delete the snapshot at height
+func (ss *Store) Delete(height uint64) error {
+	return os.RemoveAll(ss.dir(height))
+}
+
+// Prune deletes all but the keep most recent snapshots (or none if keep is not positive)
+func (ss *Store) Prune(keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	manifests, err := ss.List()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(manifests)-keep; i++ {
+		err = ss.Delete(manifests[i].Height)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore writes the snapshot at height to db, which should hold no state, and loads the state from it. The state is
+// checked against the manifest's AppHash, but it is up to the caller to check that AppHash against a trusted header.
+func (ss *Store) Restore(height uint64, db dbm.DB) (*state.State, *Manifest, error) {
+	const errHeader = "Store.Restore():"
+	manifest, err := ss.Manifest(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	if manifest.Format != Format {
+		return nil, nil, fmt.Errorf("%s snapshot at height %d has format %d but only format %d is supported",
+			errHeader, height, manifest.Format, Format)
+	}
+	for i := range manifest.ChunkHashes {
+		bs, err := ss.Chunk(manifest, i)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %v", errHeader, err)
+		}
+		err = ApplyChunk(db, bs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s could not apply chunk %d: %v", errHeader, i, err)
+		}
+	}
+	st, err := state.LoadState(db, state.VersionAtHeight(height))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s could not load restored state: %v", errHeader, err)
+	}
+	err = manifest.VerifyAppHash(st.Hash())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s restored state does not match: %v", errHeader, err)
+	}
+	return st, manifest, nil
+}
+
+// Verify checks the snapshot at height by restoring it into memory
+func (ss *Store) Verify(height uint64) (*Manifest, error) {
+	_, manifest, err := ss.Restore(height, dbm.NewMemDB())
+	return manifest, err
+}
+
+func (ss *Store) dir(height uint64) string {
+	return filepath.Join(ss.directory, strconv.FormatUint(height, 10))
+}
+
+// ApplyChunk writes the entries of an encoded chunk to db
+func ApplyChunk(db dbm.DB, bs []byte) error {
+	chunk := new(Chunk)
+	err := encoding.Decode(bs, chunk)
+	if err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	defer batch.Close()
+	for _, entry := range chunk.Entries {
+		batch.Set(entry.Key, entry.Value)
+	}
+	return batch.WriteSync()
+}
+
+// Hash commits to the whole snapshot via the hashes of its chunks
+func (m *Manifest) Hash() []byte {
+	hasher := sha256.New()
+	for _, chunkHash := range m.ChunkHashes {
+		hasher.Write(chunkHash)
+	}
+	return hasher.Sum(nil)
+}
+
+// VerifyChunk checks an encoded chunk against its hash in the manifest
+func (m *Manifest) VerifyChunk(index int, bs []byte) error {
+	if index < 0 || index >= len(m.ChunkHashes) {
+		return fmt.Errorf("snapshot at height %d has %d chunks so has no chunk %d", m.Height, len(m.ChunkHashes),
+			index)
+	}
+	if actual := hash(bs); !bytes.Equal(actual, m.ChunkHashes[index]) {
+		return fmt.Errorf("chunk %d of snapshot at height %d has hash %v but manifest gives %v", index, m.Height,
+			actual, m.ChunkHashes[index])
+	}
+	return nil
+}
+
+// VerifyAppHash checks the manifest's AppHash against one obtained elsewhere (for instance from the header of the block
+// after the snapshot's height)
+func (m *Manifest) VerifyAppHash(appHash []byte) error {
+	if !bytes.Equal(m.AppHash, appHash) {
+		return fmt.Errorf("snapshot at height %d has AppHash %v but expected %v", m.Height, m.AppHash,
+			binary.HexBytes(appHash))
+	}
+	return nil
+}
+
+func hash(bs []byte) binary.HexBytes {
+	sum := sha256.Sum256(bs)
+	return sum[:]
+}
+
+func copyBytes(bs []byte) []byte {
+	cp := make([]byte, len(bs))
+	copy(cp, bs)
+	return cp
+}
@@ -0,0 +1,100 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/execution/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := state.NewState(dbm.NewMemDB())
+	account := acm.NewAccountFromSecret("Foo")
+	metahash := acmstate.GetMetadataHash("abi")
+	for height := uint64(0); height < 20; height++ {
+		account.Balance = height
+		_, _, err := st.Update(func(ws state.Updatable) error {
+			err := ws.SetStorage(account.Address, binary.LeftPadWord256([]byte{byte(height)}), []byte{1})
+			if err != nil {
+				return err
+			}
+			if height == 3 {
+				err = ws.SetMetadata(metahash, "abi")
+				if err != nil {
+					return err
+				}
+			}
+			return ws.UpdateAccount(account)
+		})
+		require.NoError(t, err)
+	}
+
+	store := NewStore(dir)
+	blockTime := time.Unix(1000, 0).UTC()
+	// Use tiny chunks so that we get many of them
+	manifest, err := store.Create(st, "chain", 15, blockTime, 256)
+	require.NoError(t, err)
+	assert.Greater(t, len(manifest.ChunkHashes), 1)
+	rs, err := st.LoadHeight(15)
+	require.NoError(t, err)
+	assert.Equal(t, rs.Forest.Hash(), []byte(manifest.AppHash))
+
+	_, err = store.Create(st, "chain", 19, blockTime, 0)
+	require.NoError(t, err)
+	manifests, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	assert.Equal(t, manifest.ChunkHashes, manifests[0].ChunkHashes)
+	assert.Equal(t, blockTime, manifests[0].BlockTime)
+
+	t.Run("Restore", func(t *testing.T) {
+		restored, manifest, err := store.Restore(15, dbm.NewMemDB())
+		require.NoError(t, err)
+		assert.Equal(t, []byte(manifest.AppHash), restored.Hash())
+		accountOut, err := restored.GetAccount(account.Address)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(15), accountOut.Balance)
+		abi, err := restored.GetMetadata(metahash)
+		require.NoError(t, err)
+		assert.Equal(t, "abi", abi)
+		// We can carry on from the restored state
+		_, _, err = restored.Update(func(ws state.Updatable) error {
+			return ws.UpdateAccount(account)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("CorruptChunk", func(t *testing.T) {
+		path := filepath.Join(dir, "15", "chunk-00001")
+		bs, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		bs[len(bs)-1]++
+		require.NoError(t, ioutil.WriteFile(path, bs, 0600))
+		_, err = store.Verify(15)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "chunk 1")
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		require.NoError(t, store.Prune(1))
+		manifests, err := store.List()
+		require.NoError(t, err)
+		require.Len(t, manifests, 1)
+		assert.Equal(t, uint64(19), manifests[0].Height)
+		_, err = store.Verify(19)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	dbm "github.com/tendermint/tm-db"
+)
+
+// Number of entries written per batch by CopyDB
+const copyBatchSize = 10000
+
+// CopyDB writes every entry of src into dst in batches, returning the number of entries copied
+func CopyDB(dst, src dbm.DB) (int, error) {
+	it, err := src.Iterator(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	count := 0
+	batch := dst.NewBatch()
+	for ; it.Valid(); it.Next() {
+		batch.Set(it.Key(), it.Value())
+		count++
+		if count%copyBatchSize == 0 {
+			err = batch.Write()
+			batch.Close()
+			if err != nil {
+				return count, err
+			}
+			batch = dst.NewBatch()
+		}
+	}
+	defer batch.Close()
+	if err = it.Error(); err != nil {
+		return count, err
+	}
+	return count, batch.WriteSync()
+}
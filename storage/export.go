@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	amino "github.com/tendermint/go-amino"
+)
+
+// Prefixes of the entries IAVL keeps in its database (see nodedb.go in IAVL)
+const (
+	iavlNodePrefix   = 'n'
+	iavlOrphanPrefix = 'o'
+	iavlRootPrefix   = 'r'
+	// Orphan keys are the prefix followed by the last version to hold the node, the first, then the node's hash
+	iavlOrphanHashOffset = 1 + 2*8
+)
+
+// Export calls fn with each raw database entry (keyed relative to the forest's database) that is needed to load any
+// version of the forest from from to to inclusive, and only those. Since the hash of each IAVL node commits to the
+// version at which it was written, we export the nodes themselves rather than the keys and values they hold so that a
+// database built from the entries has exactly the same hashes as this one. Entries are visited in a deterministic
+// order so that any two nodes exporting the same versions produce the same sequence.
+func (muf *MutableForest) Export(from, to int64, fn func(key, value []byte) error) error {
+	const errHeader = "MutableForest.Export():"
+	if from < 1 {
+		from = 1
+	}
+	commitsDB := NewPrefixDB(muf.db, commitsPrefix)
+	var versions []int64
+	treeVersions := make(map[string][]int64)
+	for version := from; version <= to; version++ {
+		has, err := commitsDB.Has(iavlRootKey(version))
+		if err != nil {
+			return fmt.Errorf("%s %v", errHeader, err)
+		}
+		if !has {
+			continue
+		}
+		versions = append(versions, version)
+		commits, err := muf.commitsAt(version)
+		if err != nil {
+			return fmt.Errorf("%s %v", errHeader, err)
+		}
+		for prefix, treeVersion := range commits {
+			vs := treeVersions[prefix]
+			if len(vs) == 0 || vs[len(vs)-1] != treeVersion {
+				treeVersions[prefix] = append(vs, treeVersion)
+			}
+		}
+	}
+	if len(versions) == 0 || versions[len(versions)-1] != to {
+		return fmt.Errorf("%s version %d of forest does not exist", errHeader, to)
+	}
+	err := exportTree(commitsDB, versions, prefixed(commitsPrefix, fn))
+	if err != nil {
+		return fmt.Errorf("%s could not export commits tree: %v", errHeader, err)
+	}
+	prefixes := make([]string, 0, len(treeVersions))
+	for prefix := range treeVersions {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		// Tree versions only ever increase with forest version so these are in ascending order
+		err = exportTree(NewPrefixDB(muf.db, treePrefix+prefix), treeVersions[prefix], prefixed(treePrefix+prefix, fn))
+		if err != nil {
+			return fmt.Errorf("%s could not export tree %X: %v", errHeader, prefix, err)
+		}
+	}
+	return nil
+}
+
+// Export the roots of versions of an IAVL tree and every node reachable from them. Orphan records for those nodes are
+// also exported so that the versions can later be pruned from the copy.
+func exportTree(db *PrefixDB, versions []int64, fn func(key, value []byte) error) error {
+	seen := make(map[string]struct{})
+	for _, version := range versions {
+		rootKey := iavlRootKey(version)
+		root, err := db.Get(rootKey)
+		if err != nil {
+			return err
+		}
+		if root == nil {
+			return fmt.Errorf("no root for version %d", version)
+		}
+		err = fn(rootKey, root)
+		if err != nil {
+			return err
+		}
+		// An empty tree has an empty root hash
+		if len(root) > 0 {
+			err = exportNodes(db, root, seen, fn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	last := versions[len(versions)-1]
+	it, err := db.Iterator([]byte{iavlOrphanPrefix}, []byte{iavlOrphanPrefix + 1})
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if len(key) <= iavlOrphanHashOffset {
+			return fmt.Errorf("malformed orphan key %X", key)
+		}
+		// Nodes orphaned after the last version are still live there and will be orphaned again by the copy
+		if int64(binary.BigEndian.Uint64(key[1:])) >= last {
+			continue
+		}
+		if _, ok := seen[string(key[iavlOrphanHashOffset:])]; ok {
+			err = fn(key, it.Value())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Visit the subtree below hash depth-first, left to right, skipping nodes already seen
+func exportNodes(db *PrefixDB, hash []byte, seen map[string]struct{}, fn func(key, value []byte) error) error {
+	stack := [][]byte{hash}
+	for len(stack) > 0 {
+		hash = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := seen[string(hash)]; ok {
+			continue
+		}
+		seen[string(hash)] = struct{}{}
+		key := append([]byte{iavlNodePrefix}, hash...)
+		value, err := db.Get(key)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return fmt.Errorf("missing node %X", hash)
+		}
+		err = fn(key, value)
+		if err != nil {
+			return err
+		}
+		left, right, err := iavlNodeChildren(value)
+		if err != nil {
+			return fmt.Errorf("could not decode node %X: %v", hash, err)
+		}
+		if left != nil {
+			stack = append(stack, right, left)
+		}
+	}
+	return nil
+}
+
+// Decode the hashes of the children of an encoded IAVL node (both nil for a leaf). See MakeNode in IAVL for the
+// encoding.
+func iavlNodeChildren(bs []byte) (left, right []byte, err error) {
+	height, n, err := amino.DecodeInt8(bs)
+	if err != nil {
+		return nil, nil, err
+	}
+	bs = bs[n:]
+	if height == 0 {
+		return nil, nil, nil
+	}
+	// Skip size and version
+	for i := 0; i < 2; i++ {
+		_, n, err = amino.DecodeVarint(bs)
+		if err != nil {
+			return nil, nil, err
+		}
+		bs = bs[n:]
+	}
+	// Skip key
+	_, n, err = amino.DecodeByteSlice(bs)
+	if err != nil {
+		return nil, nil, err
+	}
+	bs = bs[n:]
+	left, n, err = amino.DecodeByteSlice(bs)
+	if err != nil {
+		return nil, nil, err
+	}
+	bs = bs[n:]
+	right, _, err = amino.DecodeByteSlice(bs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
+func iavlRootKey(version int64) []byte {
+	key := make([]byte, 9)
+	key[0] = iavlRootPrefix
+	binary.BigEndian.PutUint64(key[1:], uint64(version))
+	return key
+}
+
+func prefixed(prefix string, fn func(key, value []byte) error) func(key, value []byte) error {
+	p := Prefix(prefix)
+	return func(key, value []byte) error {
+		return fn(p.Key(key), value)
+	}
+}
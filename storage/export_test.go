@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestMutableForest_Export(t *testing.T) {
+	forest, err := NewMutableForest(dbm.NewMemDB(), 100)
+	require.NoError(t, err)
+	for version := 1; version <= 6; version++ {
+		setForest(t, forest, "often", "key", fmt.Sprint(version))
+		// Overwrite some keys so that there are orphans
+		setForest(t, forest, "often", fmt.Sprint(version%3), fmt.Sprint(version))
+		if version == 1 {
+			setForest(t, forest, "seldom", "key", fmt.Sprint(version))
+		}
+		_, _, err = forest.Save()
+		require.NoError(t, err)
+	}
+
+	export := func() map[string]string {
+		entries := make(map[string]string)
+		var order []string
+		require.NoError(t, forest.Export(4, 6, func(key, value []byte) error {
+			entries[string(key)] = string(value)
+			order = append(order, string(key))
+			return nil
+		}))
+		assert.Len(t, order, len(entries), "each entry is exported once")
+		return entries
+	}
+	entries := export()
+	assert.Equal(t, entries, export(), "export is deterministic")
+
+	db := dbm.NewMemDB()
+	for key, value := range entries {
+		require.NoError(t, db.Set([]byte(key), []byte(value)))
+	}
+	copied, err := NewMutableForest(db, 100)
+	require.NoError(t, err)
+	require.NoError(t, copied.Load(6))
+	assert.Equal(t, forest.Hash(), copied.Hash())
+	assert.True(t, copied.VersionExists(4))
+	assert.False(t, copied.VersionExists(3))
+	assertForestValue(t, copied, 4, "often", "4")
+	assertForestValue(t, copied, 5, "seldom", "1")
+
+	// The copy carries on exactly as the original would
+	for _, f := range []*MutableForest{forest, copied} {
+		setForest(t, f, "often", "key", "7")
+		setForest(t, f, "seldom", "key", "7")
+		_, _, err = f.Save()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, forest.Hash(), copied.Hash())
+
+	// And versions can be pruned from it
+	require.NoError(t, copied.Prune(6, func(version int64) bool { return false }))
+	assert.False(t, copied.VersionExists(5))
+	assertForestValue(t, copied, 7, "often", "7")
+
+	require.Error(t, forest.Export(1, 8, func(key, value []byte) error { return nil }))
+}
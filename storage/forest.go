@@ -18,6 +18,8 @@ const (
 // Access the read path of a forest
 type ForestReader interface {
 	Reader(prefix []byte) (KVCallbackIterableReader, error)
+	GetWithProof(prefix, key []byte) ([]byte, *ForestProof, error)
+	Hash() []byte
 }
 
 // MutableForest is a collection of versioned lazily-loaded RWTrees organised by prefix. It maintains a global state hash
@@ -62,6 +64,8 @@ type ForestReader interface {
 // where the global version for the forest is returned.
 
 type MutableForest struct {
+	// The database holding the commitsTree and every tree in the forest
+	db dbm.DB
 	// A tree containing a reference for all contained trees in the form of prefix -> CommitID
 	commitsTree *RWTree
 	// Much of the implementation of MutableForest is contained in ImmutableForest which is embedded here and used
@@ -71,12 +75,15 @@ type MutableForest struct {
 	dirty map[string]*RWTree
 	// List of dirty prefixes in deterministic order so we may loop over them on Save() and obtain a consistent commitTree hash
 	dirtyPrefixes []string
+	// The next version to consider for pruning and the last version below it that was retained
+	pruneFrom    int64
+	lastRetained int64
 }
 
 // ImmutableForest contains much of the implementation for MutableForest yet it's external API is immutable
 type ImmutableForest struct {
 	// Store of tree prefix -> last commitID (version + hash) - serves as a set of all known trees and provides a global hash
-	commitsTree KVCallbackIterableReader
+	commitsTree ProvableReader
 	treeDB      dbm.DB
 	// Cache for frequently used trees
 	treeCache *lru.Cache
@@ -103,13 +110,14 @@ func NewMutableForest(db dbm.DB, cacheSize int) (*MutableForest, error) {
 		return nil, err
 	}
 	return &MutableForest{
+		db:              db,
 		ImmutableForest: forest,
 		commitsTree:     commitsTree,
 		dirty:           make(map[string]*RWTree),
 	}, nil
 }
 
-func NewImmutableForest(commitsTree KVCallbackIterableReader, treeDB dbm.DB, cacheSize int,
+func NewImmutableForest(commitsTree ProvableReader, treeDB dbm.DB, cacheSize int,
 	options ...ForestOption) (*ImmutableForest, error) {
 	cache, err := lru.New(cacheSize)
 	if err != nil {
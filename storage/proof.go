@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+// ProvableReader is a tree that can prove the values it holds against its hash
+type ProvableReader interface {
+	KVCallbackIterableReader
+	Hash() []byte
+	GetWithProof(key []byte) ([]byte, *iavl.RangeProof, error)
+}
+
+// GetWithProof returns the value at key in the tree at prefix (or nil if there is none) along with a proof of that
+// against the forest's hash. The tree is read at the version recorded in the commitsTree so that both parts of the
+// proof are consistent.
+func (imf *ImmutableForest) GetWithProof(prefix, key []byte) ([]byte, *ForestProof, error) {
+	const errHeader = "ImmutableForest.GetWithProof():"
+	commitBytes, commitProof, err := imf.commitsTree.GetWithProof(prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s could not get proof from commitsTree: %v", errHeader, err)
+	}
+	proof := &ForestProof{
+		Prefix:      prefix,
+		CommitProof: proofOp(prefix, commitBytes, commitProof),
+	}
+	if commitBytes == nil {
+		// No such tree so the key is absent
+		return nil, proof, nil
+	}
+	proof.Commit, err = unmarshalCommitID(commitBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %v", errHeader, err)
+	}
+	rwt, err := imf.tree(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := rwt.GetImmutable(proof.Commit.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s could not load version %d of tree %X: %v", errHeader,
+			proof.Commit.Version, prefix, err)
+	}
+	value, treeProof, err := tree.GetWithProof(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s could not get proof from tree %X: %v", errHeader, prefix, err)
+	}
+	proof.TreeProof = proofOp(key, value, treeProof)
+	return value, proof, nil
+}
+
+// Hash returns the hash of the commitsTree, which commits to every tree in the forest
+func (imf *ImmutableForest) Hash() []byte {
+	return imf.commitsTree.Hash()
+}
+
+// Verify checks that the proof shows the key to have value (or to be absent if value is nil) within a forest whose
+// hash is forestHash
+func (fp *ForestProof) Verify(forestHash, key, value []byte) error {
+	if fp == nil {
+		return fmt.Errorf("ForestProof.Verify(): proof is nil")
+	}
+	var commitBytes []byte
+	if fp.Commit != nil {
+		var err error
+		commitBytes, err = marshalCommitID(fp.Commit.Hash, fp.Commit.Version)
+		if err != nil {
+			return err
+		}
+	}
+	err := verifyProofOp(fp.CommitProof, forestHash, fp.Prefix, commitBytes)
+	if err != nil {
+		return fmt.Errorf("ForestProof.Verify(): could not verify tree %X in commitsTree: %v", fp.Prefix, err)
+	}
+	if fp.Commit == nil {
+		if value != nil {
+			return fmt.Errorf("ForestProof.Verify(): tree %X does not exist so cannot contain key %X", fp.Prefix, key)
+		}
+		return nil
+	}
+	err = verifyProofOp(fp.TreeProof, fp.Commit.Hash, key, value)
+	if err != nil {
+		return fmt.Errorf("ForestProof.Verify(): could not verify key %X in tree %X: %v", key, fp.Prefix, err)
+	}
+	return nil
+}
+
+func proofOp(key, value []byte, proof *iavl.RangeProof) *merkle.ProofOp {
+	if proof == nil {
+		// IAVL returns no proof for an empty tree
+		return nil
+	}
+	var op merkle.ProofOp
+	if value == nil {
+		op = iavl.NewAbsenceOp(key, proof).ProofOp()
+	} else {
+		op = iavl.NewValueOp(key, proof).ProofOp()
+	}
+	return &op
+}
+
+func verifyProofOp(op *merkle.ProofOp, root, key, value []byte) error {
+	if op == nil {
+		// Only an empty tree (which has an empty hash) has nothing to prove
+		if len(root) != 0 {
+			return fmt.Errorf("missing proof for non-empty tree")
+		}
+		if value != nil {
+			return fmt.Errorf("empty tree cannot contain a value")
+		}
+		return nil
+	}
+	if !bytes.Equal(op.Key, key) {
+		return fmt.Errorf("proof is for key %X not %X", op.Key, key)
+	}
+	var operator merkle.ProofOperator
+	var args [][]byte
+	var err error
+	if value == nil {
+		operator, err = iavl.AbsenceOpDecoder(*op)
+	} else {
+		operator, err = iavl.ValueOpDecoder(*op)
+		args = [][]byte{value}
+	}
+	if err != nil {
+		return err
+	}
+	// Run checks the item against the proof and returns the root hash the proof implies
+	roots, err := operator.Run(args)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(roots[0], root) {
+		return fmt.Errorf("proof implies root hash %X but expected %X", roots[0], root)
+	}
+	return nil
+}
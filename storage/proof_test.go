@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestImmutableForest_GetWithProof(t *testing.T) {
+	forest, err := NewMutableForest(dbm.NewMemDB(), 100)
+	require.NoError(t, err)
+
+	// Nothing to prove against an empty forest
+	value, proof, err := forest.GetWithProof([]byte("fooos"), []byte("bar"))
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	require.NoError(t, proof.Verify(forest.Hash(), []byte("bar"), nil))
+
+	prefix := []byte("fooos")
+	tree, err := forest.Writer(prefix)
+	require.NoError(t, err)
+	tree.Set([]byte("bar"), []byte("nog"))
+	tree.Set([]byte("baz"), []byte("frog"))
+	_, version, err := forest.Save()
+	require.NoError(t, err)
+
+	// Prove against a fixed version while the forest moves on
+	imf, err := forest.GetImmutable(version)
+	require.NoError(t, err)
+	hash := imf.Hash()
+	tree, err = forest.Writer(prefix)
+	require.NoError(t, err)
+	tree.Set([]byte("bar"), []byte("changed"))
+	_, _, err = forest.Save()
+	require.NoError(t, err)
+
+	t.Run("Value", func(t *testing.T) {
+		value, proof, err := imf.GetWithProof(prefix, []byte("bar"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("nog"), value)
+		require.NoError(t, proof.Verify(hash, []byte("bar"), value))
+
+		assert.Error(t, proof.Verify(hash, []byte("bar"), []byte("changed")))
+		assert.Error(t, proof.Verify(hash, []byte("baz"), value))
+		assert.Error(t, proof.Verify(hash, []byte("bar"), nil))
+		assert.Error(t, proof.Verify(forest.Hash(), []byte("bar"), value))
+	})
+
+	t.Run("AbsentKey", func(t *testing.T) {
+		value, proof, err := imf.GetWithProof(prefix, []byte("qux"))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+		require.NoError(t, proof.Verify(hash, []byte("qux"), nil))
+		assert.Error(t, proof.Verify(hash, []byte("qux"), []byte("nog")))
+	})
+
+	t.Run("AbsentTree", func(t *testing.T) {
+		value, proof, err := imf.GetWithProof([]byte("nope"), []byte("bar"))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+		assert.Nil(t, proof.Commit)
+		require.NoError(t, proof.Verify(hash, []byte("bar"), nil))
+		assert.Error(t, proof.Verify(hash, []byte("bar"), []byte("nog")))
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		value, proof, err := forest.GetWithProof(prefix, []byte("bar"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("changed"), value)
+		require.NoError(t, proof.Verify(forest.Hash(), []byte("bar"), value))
+	})
+}
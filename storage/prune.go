@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+)
+
+// Prune deletes every version of the forest below before for which retain returns false along with any versions of
+// the trees in the forest that no remaining version refers to. Versions are visited in ascending order and the forest
+// remembers how far it has got, so after the first call (which will visit every earlier version) each call only
+// considers versions that have become eligible since the last. The latest version is never deleted.
+func (muf *MutableForest) Prune(before int64, retain func(version int64) bool) error {
+	const errHeader = "MutableForest.Prune():"
+	latest := muf.Version()
+	if before > latest {
+		before = latest
+	}
+	if muf.pruneFrom == 0 {
+		muf.pruneFrom = 1
+	}
+	for ; muf.pruneFrom < before; muf.pruneFrom++ {
+		version := muf.pruneFrom
+		if !muf.VersionExists(version) {
+			continue
+		}
+		if retain(version) {
+			muf.lastRetained = version
+			continue
+		}
+		// The next version must exist since the latest version does
+		next := version + 1
+		for !muf.VersionExists(next) {
+			next++
+		}
+		err := muf.deleteVersion(version, muf.lastRetained, next)
+		if err != nil {
+			return fmt.Errorf("%s could not delete version %d: %v", errHeader, version, err)
+		}
+	}
+	return nil
+}
+
+// VersionExists returns true if the forest holds version (i.e. it has been saved and not pruned)
+func (muf *MutableForest) VersionExists(version int64) bool {
+	return muf.commitsTree.VersionExists(version)
+}
+
+// Delete version from the commitsTree and any tree versions that it holds unless they are also held by the neighbouring
+// versions previous and next (versions of a tree are shared by a contiguous run of forest versions, so these are the
+// only other versions that could refer to them)
+func (muf *MutableForest) deleteVersion(version, previous, next int64) error {
+	commits, err := muf.commitsAt(version)
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]int64)
+	for _, neighbour := range []int64{previous, next} {
+		if neighbour == 0 {
+			continue
+		}
+		neighbourCommits, err := muf.commitsAt(neighbour)
+		if err != nil {
+			return err
+		}
+		for prefix, treeVersion := range neighbourCommits {
+			if treeVersion == commits[prefix] {
+				keep[prefix] = treeVersion
+			}
+		}
+	}
+	for prefix, treeVersion := range commits {
+		if _, ok := keep[prefix]; ok {
+			continue
+		}
+		tree, err := muf.tree([]byte(prefix))
+		if err != nil {
+			return err
+		}
+		// A tree that has since been deleted from the forest will not have its versions loaded, so we leave it be
+		if !tree.VersionExists(treeVersion) || treeVersion == tree.Version() {
+			continue
+		}
+		err = tree.DeleteVersion(treeVersion)
+		if err != nil {
+			return fmt.Errorf("could not delete version %d of tree %X: %v", treeVersion, prefix, err)
+		}
+	}
+	return muf.commitsTree.DeleteVersion(version)
+}
+
+// Get the version of each tree in the forest as of version of the forest
+func (muf *MutableForest) commitsAt(version int64) (map[string]int64, error) {
+	commitsTree, err := muf.commitsTree.GetImmutable(version)
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits tree for version %d: %v", version, err)
+	}
+	commits := make(map[string]int64)
+	err = commitsTree.Iterate(nil, nil, true, func(prefix []byte, value []byte) error {
+		commitID, err := unmarshalCommitID(value)
+		if err != nil {
+			return err
+		}
+		commits[string(prefix)] = commitID.Version
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestMutableForest_Prune(t *testing.T) {
+	forest, err := NewMutableForest(dbm.NewMemDB(), 100)
+	require.NoError(t, err)
+	// "often" gets a new tree version with every forest version but "seldom" only at versions 1 and 6
+	for version := 1; version <= 10; version++ {
+		setForest(t, forest, "often", "key", fmt.Sprint(version))
+		if version == 1 || version == 6 {
+			setForest(t, forest, "seldom", "key", fmt.Sprint(version))
+		}
+		_, _, err = forest.Save()
+		require.NoError(t, err)
+	}
+
+	retain := func(version int64) bool { return version == 3 }
+	require.NoError(t, forest.Prune(8, retain))
+	for version := int64(1); version <= 10; version++ {
+		assert.Equal(t, version == 3 || version >= 8, forest.VersionExists(version), "version %d", version)
+	}
+	assertForestValue(t, forest, 3, "often", "3")
+	assertForestValue(t, forest, 3, "seldom", "1")
+	assertForestValue(t, forest, 8, "often", "8")
+	assertForestValue(t, forest, 8, "seldom", "6")
+
+	// Tree versions that only pruned versions referred to are gone
+	often, err := forest.Writer([]byte("often"))
+	require.NoError(t, err)
+	assert.False(t, often.VersionExists(1))
+	assert.True(t, often.VersionExists(3))
+
+	// Pruning carries on from where it left off
+	setForest(t, forest, "often", "key", "11")
+	_, _, err = forest.Save()
+	require.NoError(t, err)
+	require.NoError(t, forest.Prune(11, retain))
+	assert.False(t, forest.VersionExists(10))
+	assert.True(t, forest.VersionExists(11))
+	assertForestValue(t, forest, 3, "seldom", "1")
+	assertForestValue(t, forest, 11, "seldom", "6")
+}
+
+func assertForestValue(t *testing.T, forest *MutableForest, version int64, prefix, value string) {
+	imf, err := forest.GetImmutable(version)
+	require.NoError(t, err)
+	reader, err := imf.Reader([]byte(prefix))
+	require.NoError(t, err)
+	actual, err := reader.Get([]byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, value, string(actual), "value of %s at version %d", prefix, version)
+}
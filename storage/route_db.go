@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	dbm "github.com/tendermint/tm-db"
+)
+
+// RouteDB presents several databases as one by sending each key to the database routed for its prefix, or to a
+// default database for keys that match no route. This lets a single keyspace such as that of State be split across
+// databases with different backends. Writes are not atomic across the underlying databases.
+type RouteDB struct {
+	// Sorted by prefix
+	routes []route
+	db     dbm.DB
+}
+
+type route struct {
+	prefix Prefix
+	db     dbm.DB
+}
+
+var _ dbm.DB = &RouteDB{}
+
+// NewRouteDB sends keys starting with each prefix in routes to the corresponding database and all other keys to db.
+// No route prefix may be a prefix of another.
+func NewRouteDB(db dbm.DB, routes map[string]dbm.DB) (*RouteDB, error) {
+	rdb := &RouteDB{
+		db: db,
+	}
+	for prefix, routeDB := range routes {
+		if prefix == "" {
+			return nil, fmt.Errorf("NewRouteDB(): cannot route the empty prefix")
+		}
+		rdb.routes = append(rdb.routes, route{prefix: Prefix(prefix), db: routeDB})
+	}
+	sort.Slice(rdb.routes, func(i, j int) bool {
+		return bytes.Compare(rdb.routes[i].prefix, rdb.routes[j].prefix) < 0
+	})
+	for i := 1; i < len(rdb.routes); i++ {
+		if bytes.HasPrefix(rdb.routes[i].prefix, rdb.routes[i-1].prefix) {
+			return nil, fmt.Errorf("NewRouteDB(): route prefix '%s' overlaps route prefix '%s'",
+				rdb.routes[i].prefix, rdb.routes[i-1].prefix)
+		}
+	}
+	return rdb, nil
+}
+
+// DB implementation
+func (rdb *RouteDB) Get(key []byte) ([]byte, error) {
+	return rdb.route(key).Get(key)
+}
+
+func (rdb *RouteDB) Has(key []byte) (bool, error) {
+	return rdb.route(key).Has(key)
+}
+
+func (rdb *RouteDB) Set(key, value []byte) error {
+	return rdb.route(key).Set(key, value)
+}
+
+func (rdb *RouteDB) SetSync(key, value []byte) error {
+	return rdb.route(key).SetSync(key, value)
+}
+
+func (rdb *RouteDB) Delete(key []byte) error {
+	return rdb.route(key).Delete(key)
+}
+
+func (rdb *RouteDB) DeleteSync(key []byte) error {
+	return rdb.route(key).DeleteSync(key)
+}
+
+func (rdb *RouteDB) Iterator(low, high []byte) (KVIterator, error) {
+	return newRouteIterator(rdb.segments(low, high), low, high, false)
+}
+
+func (rdb *RouteDB) ReverseIterator(low, high []byte) (KVIterator, error) {
+	return newRouteIterator(rdb.segments(low, high), low, high, true)
+}
+
+func (rdb *RouteDB) Close() error {
+	var errs []error
+	for _, db := range rdb.dbs() {
+		err := db.Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("RouteDB.Close(): errors closing databases: %v", errs)
+	}
+	return nil
+}
+
+func (rdb *RouteDB) Print() error {
+	for _, db := range rdb.dbs() {
+		err := db.Print()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rdb *RouteDB) Stats() map[string]string {
+	stats := make(map[string]string)
+	for key, value := range rdb.db.Stats() {
+		stats["RouteDB.db."+key] = value
+	}
+	for _, r := range rdb.routes {
+		for key, value := range r.db.Stats() {
+			stats[fmt.Sprintf("RouteDB.route.%X.%s", r.prefix, key)] = value
+		}
+	}
+	return stats
+}
+
+func (rdb *RouteDB) NewBatch() dbm.Batch {
+	return &routeBatch{
+		routeDB: rdb,
+		batches: make(map[dbm.DB]dbm.Batch),
+	}
+}
+
+func (rdb *RouteDB) route(key []byte) dbm.DB {
+	// Find the greatest prefix not above key, which is the only one that could prefix it
+	i := sort.Search(len(rdb.routes), func(i int) bool {
+		return bytes.Compare(rdb.routes[i].prefix, key) > 0
+	})
+	if i > 0 && bytes.HasPrefix(key, rdb.routes[i-1].prefix) {
+		return rdb.routes[i-1].db
+	}
+	return rdb.db
+}
+
+// Each underlying database once, the default first
+func (rdb *RouteDB) dbs() []dbm.DB {
+	dbs := []dbm.DB{rdb.db}
+	seen := map[dbm.DB]bool{rdb.db: true}
+	for _, r := range rdb.routes {
+		if !seen[r.db] {
+			seen[r.db] = true
+			dbs = append(dbs, r.db)
+		}
+	}
+	return dbs
+}
+
+// Since every route covers a contiguous range of keys we can split the domain [low, high) into ascending segments
+// each belonging to a single database
+func (rdb *RouteDB) segments(low, high []byte) []segment {
+	low, high = NormaliseDomain(low, high)
+	var segments []segment
+	add := func(db dbm.DB, start, end []byte) {
+		if CompareKeys(start, end) < 0 {
+			segments = append(segments, segment{db: db, start: start, end: end})
+		}
+	}
+	cur := low
+	for _, r := range rdb.routes {
+		start, end := []byte(r.prefix), r.prefix.Above()
+		if CompareKeys(start, high) >= 0 {
+			break
+		}
+		if CompareKeys(end, cur) <= 0 {
+			continue
+		}
+		add(rdb.db, cur, start)
+		if CompareKeys(start, cur) < 0 {
+			start = cur
+		}
+		if CompareKeys(high, end) < 0 {
+			end = high
+		}
+		add(r.db, start, end)
+		if end == nil {
+			return segments
+		}
+		cur = end
+	}
+	add(rdb.db, cur, high)
+	return segments
+}
+
+type segment struct {
+	db    dbm.DB
+	start []byte
+	end   []byte
+}
+
+// Iterates over each segment in turn opening the iterator for the next segment once the current one is exhausted
+type routeIterator struct {
+	segments []segment
+	reverse  bool
+	low      []byte
+	high     []byte
+	source   dbm.Iterator
+	err      error
+}
+
+func newRouteIterator(segments []segment, low, high []byte, reverse bool) (*routeIterator, error) {
+	if reverse {
+		for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+			segments[i], segments[j] = segments[j], segments[i]
+		}
+	}
+	it := &routeIterator{
+		segments: segments,
+		reverse:  reverse,
+		low:      low,
+		high:     high,
+	}
+	err := it.advance()
+	if err != nil {
+		it.Close()
+		return nil, err
+	}
+	return it, nil
+}
+
+func (it *routeIterator) Domain() ([]byte, []byte) {
+	return it.low, it.high
+}
+
+func (it *routeIterator) Valid() bool {
+	return it.source != nil && it.source.Valid()
+}
+
+func (it *routeIterator) Next() {
+	if !it.Valid() {
+		panic("routeIterator.Next() called on invalid iterator")
+	}
+	it.source.Next()
+	it.err = it.advance()
+}
+
+func (it *routeIterator) Key() []byte {
+	if !it.Valid() {
+		panic("routeIterator.Key() called on invalid iterator")
+	}
+	return it.source.Key()
+}
+
+func (it *routeIterator) Value() []byte {
+	if !it.Valid() {
+		panic("routeIterator.Value() called on invalid iterator")
+	}
+	return it.source.Value()
+}
+
+func (it *routeIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	if it.source != nil {
+		return it.source.Error()
+	}
+	return nil
+}
+
+func (it *routeIterator) Close() {
+	if it.source != nil {
+		it.source.Close()
+		it.source = nil
+	}
+	it.segments = nil
+}
+
+// Move on to the next segment with any keys if the current source is exhausted
+func (it *routeIterator) advance() error {
+	for it.source == nil || !it.source.Valid() {
+		if it.source != nil {
+			it.source.Close()
+			it.source = nil
+		}
+		if len(it.segments) == 0 {
+			return nil
+		}
+		seg := it.segments[0]
+		it.segments = it.segments[1:]
+		var err error
+		if it.reverse {
+			it.source, err = seg.db.ReverseIterator(seg.start, seg.end)
+		} else {
+			it.source, err = seg.db.Iterator(seg.start, seg.end)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type routeBatch struct {
+	routeDB *RouteDB
+	// Batches for each underlying database in the order they were first written to
+	order   []dbm.DB
+	batches map[dbm.DB]dbm.Batch
+}
+
+func (rb *routeBatch) Set(key, value []byte) {
+	rb.batch(key).Set(key, value)
+}
+
+func (rb *routeBatch) Delete(key []byte) {
+	rb.batch(key).Delete(key)
+}
+
+func (rb *routeBatch) Write() error {
+	for _, db := range rb.order {
+		err := rb.batches[db].Write()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rb *routeBatch) WriteSync() error {
+	for _, db := range rb.order {
+		err := rb.batches[db].WriteSync()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rb *routeBatch) Close() {
+	for _, db := range rb.order {
+		rb.batches[db].Close()
+	}
+}
+
+func (rb *routeBatch) batch(key []byte) dbm.Batch {
+	db := rb.routeDB.route(key)
+	batch, ok := rb.batches[db]
+	if !ok {
+		batch = db.NewBatch()
+		rb.batches[db] = batch
+		rb.order = append(rb.order, db)
+	}
+	return batch
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestRouteDB(t *testing.T) {
+	defaultDB, hDB, zDB := dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB()
+	rdb, err := NewRouteDB(defaultDB, map[string]dbm.DB{"h": hDB, "q": hDB, "zz": zDB})
+	require.NoError(t, err)
+	// All keys are written to reference as well so we can compare iteration
+	reference := dbm.NewMemDB()
+
+	keys := []string{"a", "b", "g", "gz", "h", "h1", "h2", "i", "p", "q", "qq", "r", "z", "zy", "zz", "zz1", "zzz"}
+	batch := rdb.NewBatch()
+	for i, key := range keys {
+		if i%2 == 0 {
+			require.NoError(t, rdb.Set([]byte(key), []byte(key)))
+		} else {
+			batch.Set([]byte(key), []byte(key))
+		}
+		require.NoError(t, reference.Set([]byte(key), []byte(key)))
+	}
+	require.NoError(t, batch.Write())
+	batch.Close()
+
+	t.Run("Routing", func(t *testing.T) {
+		assertKeys(t, defaultDB, nil, nil, false, "a", "b", "g", "gz", "i", "p", "r", "z", "zy")
+		assertKeys(t, hDB, nil, nil, false, "h", "h1", "h2", "q", "qq")
+		assertKeys(t, zDB, nil, nil, false, "zz", "zz1", "zzz")
+		value, err := rdb.Get([]byte("h1"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("h1"), value)
+		require.NoError(t, rdb.Delete([]byte("h1")))
+		require.NoError(t, reference.Delete([]byte("h1")))
+		has, err := hDB.Has([]byte("h1"))
+		require.NoError(t, err)
+		assert.False(t, has)
+	})
+
+	t.Run("Iteration", func(t *testing.T) {
+		domains := [][2][]byte{
+			{nil, nil},
+			{[]byte("b"), nil},
+			{nil, []byte("h2")},
+			{[]byte("h"), []byte("i")},
+			{[]byte("h1"), []byte("qz")},
+			{[]byte("gz"), []byte("zz1")},
+			{[]byte("zz"), nil},
+			{[]byte("zzz"), []byte("zzzz")},
+		}
+		for _, domain := range domains {
+			for _, reverse := range []bool{false, true} {
+				expected := collectKeys(t, reference, domain[0], domain[1], reverse)
+				assertKeys(t, rdb, domain[0], domain[1], reverse, expected...)
+			}
+		}
+	})
+
+	t.Run("Overlapping", func(t *testing.T) {
+		_, err := NewRouteDB(defaultDB, map[string]dbm.DB{"h": hDB, "ha": zDB})
+		require.Error(t, err)
+	})
+}
+
+func assertKeys(t *testing.T, db dbm.DB, low, high []byte, reverse bool, expected ...string) {
+	t.Helper()
+	assert.Equal(t, expected, collectKeys(t, db, low, high, reverse), "domain [%q, %q) reverse: %v",
+		low, high, reverse)
+}
+
+func collectKeys(t *testing.T, db dbm.DB, low, high []byte, reverse bool) []string {
+	var it dbm.Iterator
+	var err error
+	if reverse {
+		it, err = db.ReverseIterator(low, high)
+	} else {
+		it, err = db.Iterator(low, high)
+	}
+	require.NoError(t, err)
+	defer it.Close()
+	var keys []string
+	for ; it.Valid(); it.Next() {
+		assert.Equal(t, it.Key(), it.Value())
+		keys = append(keys, string(it.Key()))
+	}
+	require.NoError(t, it.Error())
+	return keys
+}
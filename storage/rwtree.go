@@ -84,6 +84,16 @@ func (rwt *RWTree) GetImmutable(version int64) (*ImmutableTree, error) {
 	return rwt.tree.GetImmutable(version)
 }
 
+// Returns true if version has been saved and not deleted
+func (rwt *RWTree) VersionExists(version int64) bool {
+	return rwt.tree.VersionExists(version)
+}
+
+// Delete a saved version of the tree, which may not be the latest
+func (rwt *RWTree) DeleteVersion(version int64) error {
+	return rwt.tree.DeleteVersion(version)
+}
+
 func (rwt *RWTree) IterateWriteTree(start, end []byte, ascending bool, fn func(key []byte, value []byte) error) error {
 	return rwt.tree.IterateWriteTree(start, end, ascending, fn)
 }
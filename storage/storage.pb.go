@@ -12,6 +12,7 @@ import (
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
 	golang_proto "github.com/golang/protobuf/proto"
+	merkle "github.com/tendermint/tendermint/crypto/merkle"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -81,28 +82,116 @@ func (m *CommitID) GetHash() []byte {
 func (*CommitID) XXX_MessageName() string {
 	return "storage.CommitID"
 }
+
+// ForestProof proves the value (or absence) of a key in one of the trees of a forest against the forest's hash.
+// The proof is in two parts: one for the key within the tree and one for the tree's CommitID within the
+// commitsTree.
+type ForestProof struct {
+	// The prefix of the tree within the forest
+	Prefix []byte `protobuf:"bytes,1,opt,name=Prefix,proto3" json:"Prefix,omitempty"`
+	// The CommitID stored for the tree in the commitsTree or nil if there is no such tree
+	Commit *CommitID `protobuf:"bytes,2,opt,name=Commit,proto3" json:"Commit,omitempty"`
+	// IAVL proof of the key within the tree against Commit.Hash, nil if the tree is empty
+	TreeProof *merkle.ProofOp `protobuf:"bytes,3,opt,name=TreeProof,proto3" json:"TreeProof,omitempty"`
+	// IAVL proof of Commit at Prefix within the commitsTree, nil if the forest is empty
+	CommitProof          *merkle.ProofOp `protobuf:"bytes,4,opt,name=CommitProof,proto3" json:"CommitProof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ForestProof) Reset()         { *m = ForestProof{} }
+func (m *ForestProof) String() string { return proto.CompactTextString(m) }
+func (*ForestProof) ProtoMessage()    {}
+func (*ForestProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{1}
+}
+func (m *ForestProof) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ForestProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ForestProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForestProof.Merge(m, src)
+}
+func (m *ForestProof) XXX_Size() int {
+	return m.Size()
+}
+func (m *ForestProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForestProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForestProof proto.InternalMessageInfo
+
+func (m *ForestProof) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+func (m *ForestProof) GetCommit() *CommitID {
+	if m != nil {
+		return m.Commit
+	}
+	return nil
+}
+
+func (m *ForestProof) GetTreeProof() *merkle.ProofOp {
+	if m != nil {
+		return m.TreeProof
+	}
+	return nil
+}
+
+func (m *ForestProof) GetCommitProof() *merkle.ProofOp {
+	if m != nil {
+		return m.CommitProof
+	}
+	return nil
+}
+
+func (*ForestProof) XXX_MessageName() string {
+	return "storage.ForestProof"
+}
 func init() {
 	proto.RegisterType((*CommitID)(nil), "storage.CommitID")
 	golang_proto.RegisterType((*CommitID)(nil), "storage.CommitID")
+	proto.RegisterType((*ForestProof)(nil), "storage.ForestProof")
+	golang_proto.RegisterType((*ForestProof)(nil), "storage.ForestProof")
 }
 
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 func init() { golang_proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 186 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x2e, 0xc9, 0x2f,
-	0x4a, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x72, 0xa5, 0x74, 0xd3,
-	0x33, 0x4b, 0x32, 0x4a, 0x93, 0xf4, 0x92, 0xf3, 0x73, 0xf5, 0xd3, 0xf3, 0xd3, 0xf3, 0xf5, 0xc1,
-	0xf2, 0x49, 0xa5, 0x69, 0x60, 0x1e, 0x98, 0x03, 0x66, 0x41, 0xf4, 0x29, 0xd9, 0x71, 0x71, 0x38,
-	0xe7, 0xe7, 0xe6, 0x66, 0x96, 0x78, 0xba, 0x08, 0x49, 0x70, 0xb1, 0x87, 0xa5, 0x16, 0x15, 0x67,
-	0xe6, 0xe7, 0x49, 0x30, 0x2a, 0x30, 0x6a, 0x30, 0x07, 0xc1, 0xb8, 0x42, 0x42, 0x5c, 0x2c, 0x1e,
-	0x89, 0xc5, 0x19, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c, 0x41, 0x60, 0xb6, 0x15, 0xcb, 0x8c, 0x05,
-	0xf2, 0x0c, 0x4e, 0xf6, 0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24, 0xc7, 0x78, 0xe3, 0x91, 0x1c,
-	0xe3, 0x83, 0x47, 0x72, 0x8c, 0x07, 0x1e, 0xcb, 0x31, 0x9e, 0x78, 0x2c, 0xc7, 0x18, 0xa5, 0x8a,
-	0xe4, 0x90, 0x8c, 0xca, 0x82, 0xd4, 0xa2, 0x9c, 0xd4, 0x94, 0xf4, 0xd4, 0x22, 0xfd, 0xa4, 0xd2,
-	0xa2, 0xa2, 0xfc, 0x72, 0x7d, 0xa8, 0x7b, 0x93, 0xd8, 0xc0, 0xee, 0x30, 0x06, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0xc6, 0xa6, 0xc1, 0x7f, 0xd0, 0x00, 0x00, 0x00,
+	// 309 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x90, 0x31, 0x4b, 0x03, 0x41,
+	0x10, 0x85, 0x5d, 0x13, 0x12, 0xdd, 0xc4, 0xc2, 0x2d, 0xe4, 0x48, 0xb1, 0xc6, 0x80, 0x10, 0x0b,
+	0xf7, 0x40, 0x3b, 0x05, 0x03, 0x46, 0x44, 0x2b, 0xc3, 0x21, 0x16, 0x76, 0xb9, 0x64, 0x72, 0x39,
+	0xcc, 0x65, 0x8e, 0xb9, 0x0d, 0x9a, 0x7f, 0x62, 0xe9, 0x4f, 0xb1, 0x4c, 0x69, 0x25, 0x96, 0x72,
+	0xf9, 0x23, 0xc2, 0xee, 0x1e, 0xb9, 0xd2, 0x6a, 0xe7, 0xb1, 0xef, 0x7d, 0xc3, 0x1b, 0xbe, 0x97,
+	0x69, 0xa4, 0x61, 0x04, 0x2a, 0x25, 0xd4, 0x28, 0xea, 0x4e, 0xb6, 0x4e, 0xa3, 0x58, 0x4f, 0x17,
+	0xa1, 0x1a, 0x61, 0xe2, 0x47, 0x18, 0xa1, 0x6f, 0xfe, 0xc3, 0xc5, 0xc4, 0x28, 0x23, 0xcc, 0x64,
+	0x73, 0xad, 0xcb, 0x92, 0x5d, 0xc3, 0x7c, 0x0c, 0x94, 0xc4, 0x73, 0x5d, 0x1e, 0x47, 0xb4, 0x4c,
+	0x35, 0xfa, 0x09, 0xd0, 0xcb, 0x0c, 0xdc, 0x63, 0xc3, 0x9d, 0x2b, 0xbe, 0xd3, 0xc7, 0x24, 0x89,
+	0xf5, 0xfd, 0x8d, 0xf0, 0x78, 0xfd, 0x09, 0x28, 0x8b, 0x71, 0xee, 0xb1, 0x36, 0xeb, 0x56, 0x82,
+	0x42, 0x0a, 0xc1, 0xab, 0x77, 0xc3, 0x6c, 0xea, 0x6d, 0xb7, 0x59, 0xb7, 0x19, 0x98, 0xf9, 0xa2,
+	0xfa, 0xfe, 0x71, 0xb8, 0xd5, 0xf9, 0x66, 0xbc, 0x71, 0x8b, 0x04, 0x99, 0x1e, 0x10, 0xe2, 0x44,
+	0x1c, 0xf0, 0xda, 0x80, 0x60, 0x12, 0xbf, 0x19, 0x44, 0x33, 0x70, 0x4a, 0x9c, 0xf0, 0x9a, 0xdd,
+	0x63, 0x18, 0x8d, 0xb3, 0x7d, 0x55, 0x94, 0x2f, 0xd6, 0x07, 0xce, 0x20, 0x7a, 0x7c, 0xf7, 0x91,
+	0x00, 0x0c, 0xcf, 0xab, 0x18, 0xf7, 0x91, 0xda, 0xb4, 0x51, 0xb6, 0x8d, 0x72, 0x35, 0x8c, 0xed,
+	0x21, 0x0d, 0x36, 0x19, 0xd1, 0xe7, 0x0d, 0x8b, 0xb2, 0x88, 0xea, 0x7f, 0x11, 0xe5, 0xd4, 0x75,
+	0x6f, 0x95, 0x4b, 0xf6, 0x95, 0x4b, 0xf6, 0x93, 0x4b, 0xf6, 0x9b, 0x4b, 0xf6, 0xb9, 0x96, 0x6c,
+	0xb5, 0x96, 0xec, 0xf9, 0xb8, 0x74, 0xef, 0xe9, 0x32, 0x05, 0x9a, 0xc1, 0x38, 0x02, 0xf2, 0xc3,
+	0x05, 0x11, 0xbe, 0xfa, 0xae, 0x57, 0x58, 0x33, 0x07, 0x3e, 0xff, 0x1b, 0x00, 0xb0, 0xda, 0x76,
+	0x6d, 0xe6, 0x01, 0x00, 0x00,
 }
 
 func (m *CommitID) Marshal() (dAtA []byte, err error) {
@@ -144,6 +233,76 @@ func (m *CommitID) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ForestProof) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ForestProof) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ForestProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.CommitProof != nil {
+		{
+			size, err := m.CommitProof.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintStorage(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.TreeProof != nil {
+		{
+			size, err := m.TreeProof.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintStorage(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Commit != nil {
+		{
+			size, err := m.Commit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintStorage(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Prefix) > 0 {
+		i -= len(m.Prefix)
+		copy(dAtA[i:], m.Prefix)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Prefix)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintStorage(dAtA []byte, offset int, v uint64) int {
 	offset -= sovStorage(v)
 	base := offset
@@ -174,6 +333,34 @@ func (m *CommitID) Size() (n int) {
 	return n
 }
 
+func (m *ForestProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.Commit != nil {
+		l = m.Commit.Size()
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.TreeProof != nil {
+		l = m.TreeProof.Size()
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.CommitProof != nil {
+		l = m.CommitProof.Size()
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovStorage(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -287,6 +474,202 @@ func (m *CommitID) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ForestProof) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ForestProof: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ForestProof: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Commit == nil {
+				m.Commit = &CommitID{}
+			}
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TreeProof", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TreeProof == nil {
+				m.TreeProof = &merkle.ProofOp{}
+			}
+			if err := m.TreeProof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitProof", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CommitProof == nil {
+				m.CommitProof = &merkle.ProofOp{}
+			}
+			if err := m.CommitProof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipStorage(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
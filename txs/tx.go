@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 
 	"github.com/hyperledger/burrow/acm"
@@ -79,6 +80,7 @@ func (tx *Tx) SignBytes(enc Envelope_Encoding) ([]byte, error) {
 		case *payload.CallTx:
 			input := pay.Input
 			return RLPEncode(
+				encoding.GetEthChainID(tx.ChainID),
 				input.Sequence-1,
 				pay.GasPrice,
 				pay.GasLimit,
@@ -94,15 +96,17 @@ func (tx *Tx) SignBytes(enc Envelope_Encoding) ([]byte, error) {
 	}
 }
 
-func RLPEncode(seq, gasPrice, gasLimit uint64, address, amount, data []byte) ([]byte, error) {
+// RLPEncode gives the bytes an Ethereum transaction signs under EIP-155, which commits to the chain ID as seen by the
+// CHAINID opcode (see encoding.GetEthChainID)
+func RLPEncode(chainID *big.Int, seq, gasPrice, gasLimit uint64, address, amount, data []byte) ([]byte, error) {
 	return rlp.Encode([]interface{}{
-		seq,       // nonce
-		gasPrice,  // gasPrice
-		gasLimit,  // gasLimit
-		address,   // to
-		amount,    // value
-		data,      // data
-		uint64(1), // chainID
+		seq,             // nonce
+		gasPrice,        // gasPrice
+		gasLimit,        // gasLimit
+		address,         // to
+		amount,          // value
+		data,            // data
+		chainID.Bytes(), // chainID
 		uint(0), uint(0),
 	})
 }
@@ -0,0 +1,288 @@
+// Package api serves a read-only HTTP API over the tables of a Vent projection: REST endpoints for each table, a
+// GraphQL endpoint with a schema generated from the projection, and server-sent events relaying notification channels.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/sqldb"
+	"github.com/hyperledger/burrow/vent/sqldb/adapters"
+	"github.com/hyperledger/burrow/vent/sqlsol"
+	"github.com/hyperledger/burrow/vent/types"
+)
+
+// Path the API is served under by vent start
+const Path = "/api"
+
+// Query parameters with special meaning, all others filter on columns
+const (
+	FieldsParam = "fields"
+	OrderParam  = "order"
+	LimitParam  = "limit"
+	OffsetParam = "offset"
+)
+
+// API handles requests under the path it is mounted at (with http.StripPrefix):
+//
+//	GET /tables                   - the tables and their columns
+//	GET /tables/<table>           - rows from a table, see parseQuery
+//	GET|POST /graphql             - GraphQL queries
+//	GET /graphql/schema           - the GraphQL schema
+//	GET /notify/<channel>         - notifications as server-sent events (Postgres only)
+type API struct {
+	db       *sqldb.SQLDB
+	notifier *notifier
+	tables   types.EventTables
+	channels map[string]struct{}
+	schema   *schema
+	mux      *http.ServeMux
+	logger   *logging.Logger
+}
+
+// New serves the tables of projection from db, listening for notifications with a separate connection to dbURL
+func New(projection *sqlsol.Projection, db *sqldb.SQLDB, dbURL string, logger *logging.Logger) (*API, error) {
+	api := &API{
+		db:       db,
+		notifier: newNotifier(dbURL, logger.WithScope("Notify")),
+		tables:   projection.Tables,
+		channels: map[string]struct{}{types.BlockHeightLabel: {}},
+		mux:      http.NewServeMux(),
+		logger:   logger.WithScope("API"),
+	}
+	for _, table := range projection.Tables {
+		// Build the column index now rather than racing to do so in concurrent requests
+		table.GetColumn("")
+		for channel := range table.NotifyChannels {
+			api.channels[channel] = struct{}{}
+		}
+	}
+	var err error
+	api.schema, err = newSchema(projection.Tables)
+	if err != nil {
+		return nil, err
+	}
+	api.mux.HandleFunc("/tables", api.listTables)
+	api.mux.HandleFunc("/tables/", api.getRows)
+	api.mux.HandleFunc("/graphql", api.graphQL)
+	api.mux.HandleFunc("/graphql/schema", api.graphQLSchema)
+	api.mux.HandleFunc("/notify/", api.notify)
+	return api, nil
+}
+
+func (api *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mux.ServeHTTP(w, r)
+}
+
+// Close the connection used for notifications, ending any streams
+func (api *API) Close() error {
+	return api.notifier.Close()
+}
+
+// Table describes a table for clients
+type Table struct {
+	Name           string
+	Columns        []Column
+	NotifyChannels []string `json:",omitempty"`
+}
+
+type Column struct {
+	Name    string
+	Type    string
+	Primary bool `json:",omitempty"`
+}
+
+// Rows is the response to a table query
+type Rows struct {
+	Table  string
+	Limit  int
+	Offset int
+	Rows   []map[string]interface{}
+}
+
+func (api *API) listTables(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	var tables []Table
+	for _, name := range api.tableNames() {
+		table := api.tables[name]
+		t := Table{Name: name}
+		for _, column := range table.Columns {
+			t.Columns = append(t.Columns, Column{Name: column.Name, Type: column.Type.String(), Primary: column.Primary})
+		}
+		for channel := range table.NotifyChannels {
+			t.NotifyChannels = append(t.NotifyChannels, channel)
+		}
+		sort.Strings(t.NotifyChannels)
+		tables = append(tables, t)
+	}
+	writeJSON(w, http.StatusOK, tables)
+}
+
+func (api *API) getRows(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/tables/")
+	table, ok := api.tables[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no table named '%s'", name))
+		return
+	}
+	q, err := parseQuery(table, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	rows, err := api.rows(q)
+	if err != nil {
+		api.logger.InfoMsg("Could not query table", "table", name, "err", err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("could not query table '%s'", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, Rows{Table: name, Limit: q.Limit, Offset: q.Offset, Rows: rows})
+}
+
+// parseQuery reads a query from URL parameters:
+//
+//	fields=a,b        - return only columns a and b
+//	order=a,-b        - order by a ascending then b descending
+//	limit=n&offset=m  - return at most n (up to MaxLimit) rows after skipping m
+//	a=x               - where a equals x
+//	a.<op>=x          - where a compares with x according to op, one of the Op constants. For in x is a
+//	                    comma-separated list and for null x is true or false
+func parseQuery(table *types.SQLTable, params url.Values) (*Query, error) {
+	q := NewQuery(table)
+	var err error
+	for param, values := range params {
+		value := values[len(values)-1]
+		switch param {
+		case FieldsParam:
+			for _, name := range strings.Split(value, ",") {
+				column := table.GetColumn(name)
+				if column == nil {
+					return nil, fmt.Errorf("no column named '%s' in table %s", name, table.Name)
+				}
+				q.Columns = append(q.Columns, column)
+			}
+		case OrderParam:
+			for _, name := range strings.Split(value, ",") {
+				order := Order{}
+				if strings.HasPrefix(name, "-") {
+					order.Descending = true
+					name = name[1:]
+				}
+				order.Column = table.GetColumn(name)
+				if order.Column == nil {
+					return nil, fmt.Errorf("no column named '%s' in table %s", name, table.Name)
+				}
+				q.Order = append(q.Order, order)
+			}
+		case LimitParam:
+			q.Limit, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse limit: %v", err)
+			}
+		case OffsetParam:
+			q.Offset, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse offset: %v", err)
+			}
+		default:
+			name, op := param, OpEqual
+			if i := strings.LastIndex(param, "."); i >= 0 {
+				name, op = param[:i], Op(param[i+1:])
+			}
+			column := table.GetColumn(name)
+			if column == nil {
+				return nil, fmt.Errorf("no column named '%s' in table %s", name, table.Name)
+			}
+			var filter Filter
+			if op == OpIn {
+				filter, err = NewFilter(column, op, strings.Split(value, ",")...)
+			} else {
+				filter, err = NewFilter(column, op, value)
+			}
+			if err != nil {
+				return nil, err
+			}
+			q.Filters = append(q.Filters, filter)
+		}
+	}
+	// Map iteration order would otherwise make the generated SQL vary
+	sort.Slice(q.Filters, func(i, j int) bool {
+		fi, fj := q.Filters[i], q.Filters[j]
+		return fi.Column.Name < fj.Column.Name || (fi.Column.Name == fj.Column.Name && fi.Op < fj.Op)
+	})
+	return q, q.CheckLimit()
+}
+
+func (api *API) rows(q *Query) ([]map[string]interface{}, error) {
+	query, args := q.SQL(api.db.DBAdapter)
+	rows, err := api.db.DB.Query(api.db.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := q.SelectedColumns()
+	result := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		err = rows.Scan(pointers...)
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column.Name] = normalise(column, values[i])
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+func (api *API) tableNames() []string {
+	names := make([]string, 0, len(api.tables))
+	for name := range api.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (api *API) notifySupported() bool {
+	_, ok := api.db.DBAdapter.(adapters.DBNotifyTriggerAdapter)
+	return ok
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct{ Error string }{err.Error()})
+}
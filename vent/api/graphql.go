@@ -0,0 +1,558 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	ascending  = "ASC"
+	descending = "DESC"
+)
+
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// Scalars for column types beyond those built in to GraphQL
+var graphQLScalars = []string{"BigInt", "Bytes", "JSON", "Numeric", "Timestamp"}
+
+// Operators available in where arguments, the column name alone is equality
+var graphQLOps = []Op{OpNotEqual, OpGreater, OpGreaterEqual, OpLess, OpLessEqual, OpLike, OpIn, OpNull}
+
+// schema exposes each table as a field of Query taking where, orderBy, limit, and offset arguments
+type schema struct {
+	// By field name
+	tables map[string]*types.SQLTable
+	sdl    string
+}
+
+func graphQLType(columnType types.SQLColumnType) string {
+	switch columnType {
+	case types.SQLColumnTypeBool:
+		return "Boolean"
+	case types.SQLColumnTypeInt, types.SQLColumnTypeSerial:
+		return "Int"
+	case types.SQLColumnTypeBigInt:
+		return "BigInt"
+	case types.SQLColumnTypeNumeric:
+		return "Numeric"
+	case types.SQLColumnTypeByteA:
+		return "Bytes"
+	case types.SQLColumnTypeTimeStamp:
+		return "Timestamp"
+	case types.SQLColumnTypeJSON:
+		return "JSON"
+	default:
+		return "String"
+	}
+}
+
+func newSchema(tables types.EventTables) (*schema, error) {
+	s := &schema{tables: make(map[string]*types.SQLTable)}
+	typeNames := map[string]string{"Query": "", "Boolean": "", "Int": "", "Float": "", "String": "", "ID": ""}
+	for _, scalar := range graphQLScalars {
+		typeNames[scalar] = ""
+	}
+	tableNames := make([]string, 0, len(tables))
+	for name := range tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+
+	sdl := new(bytes.Buffer)
+	for _, scalar := range graphQLScalars {
+		fmt.Fprintf(sdl, "scalar %s\n", scalar)
+	}
+	query := new(bytes.Buffer)
+	objects := new(bytes.Buffer)
+	for _, name := range tableNames {
+		// Tables that GraphQL cannot name are left to the REST API
+		if !validGraphQLName(name) {
+			continue
+		}
+		table := tables[name]
+		typeName := strcase.ToCamel(name)
+		for _, generated := range []string{typeName, typeName + "Where", typeName + "OrderBy"} {
+			if other, ok := typeNames[generated]; ok {
+				return nil, fmt.Errorf("GraphQL type %s for table %s clashes with %s", generated, name,
+					describeClash(other))
+			}
+			typeNames[generated] = name
+		}
+		s.tables[name] = table
+
+		fmt.Fprintf(query, "  %s(where: %sWhere, orderBy: [%sOrderBy!], limit: Int = %d, offset: Int = 0): [%s!]!\n",
+			name, typeName, typeName, DefaultLimit, typeName)
+		object := new(bytes.Buffer)
+		where := new(bytes.Buffer)
+		orderBy := new(bytes.Buffer)
+		for _, column := range table.Columns {
+			if !validGraphQLName(column.Name) {
+				continue
+			}
+			scalar := graphQLType(column.Type)
+			nonNull := ""
+			if column.Primary {
+				nonNull = "!"
+			}
+			fmt.Fprintf(object, "  %s: %s%s\n", column.Name, scalar, nonNull)
+			fmt.Fprintf(where, "  %s: %s\n", column.Name, scalar)
+			for _, op := range graphQLOps {
+				switch op {
+				case OpIn:
+					fmt.Fprintf(where, "  %s_%s: [%s!]\n", column.Name, op, scalar)
+				case OpNull:
+					fmt.Fprintf(where, "  %s_%s: Boolean\n", column.Name, op)
+				case OpLike:
+					if scalar == "String" {
+						fmt.Fprintf(where, "  %s_%s: String\n", column.Name, op)
+					}
+				default:
+					fmt.Fprintf(where, "  %s_%s: %s\n", column.Name, op, scalar)
+				}
+			}
+			fmt.Fprintf(orderBy, "  %s_%s\n  %s_%s\n", column.Name, ascending, column.Name, descending)
+		}
+		fmt.Fprintf(objects, "\ntype %s {\n%s}\n", typeName, object)
+		fmt.Fprintf(objects, "\ninput %sWhere {\n%s}\n", typeName, where)
+		fmt.Fprintf(objects, "\nenum %sOrderBy {\n%s}\n", typeName, orderBy)
+	}
+	fmt.Fprintf(sdl, "\ntype Query {\n%s}\n", query)
+	sdl.Write(objects.Bytes())
+	s.sdl = sdl.String()
+	return s, nil
+}
+
+func validGraphQLName(name string) bool {
+	return graphQLName.MatchString(name) && !strings.HasPrefix(name, "__")
+}
+
+func describeClash(tableName string) string {
+	if tableName == "" {
+		return "a built-in type"
+	}
+	return "the type for table " + tableName
+}
+
+// graphQLRequest is the body of a POST to the GraphQL endpoint, or the parameters of a GET
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   interface{}    `json:"data,omitempty"`
+	Errors []graphQLError `json:"errors,omitempty"`
+}
+
+type graphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (api *API) graphQLSchema(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(api.schema.sdl))
+}
+
+func (api *API) graphQL(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	request, err := readGraphQLRequest(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, graphQLResponse{Errors: []graphQLError{{Message: err.Error()}}})
+		return
+	}
+	data, err := api.executeGraphQL(request)
+	if err != nil {
+		if qerr, ok := err.(queryError); ok {
+			api.logger.InfoMsg("Could not execute GraphQL query", "err", qerr.error)
+			writeJSON(w, http.StatusOK, graphQLResponse{Errors: []graphQLError{{
+				Message: fmt.Sprintf("could not query table '%s'", qerr.field.name),
+				Path:    []interface{}{qerr.field.key()},
+			}}})
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, graphQLResponse{Errors: []graphQLError{{Message: err.Error()}}})
+		return
+	}
+	writeJSON(w, http.StatusOK, graphQLResponse{Data: data})
+}
+
+func readGraphQLRequest(w http.ResponseWriter, r *http.Request) (*graphQLRequest, error) {
+	request := new(graphQLRequest)
+	if r.Method == http.MethodGet {
+		params := r.URL.Query()
+		request.Query = params.Get("query")
+		request.OperationName = params.Get("operationName")
+		if variables := params.Get("variables"); variables != "" {
+			err := decodeJSON([]byte(variables), &request.Variables)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode variables: %v", err)
+			}
+		}
+		return request, nil
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/graphql" {
+		request.Query = string(body)
+		return request, nil
+	}
+	err = decodeJSON(body, request)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode GraphQL request: %v", err)
+	}
+	return request, nil
+}
+
+// Numbers are kept as json.Number so that large integers survive
+func decodeJSON(bs []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// queryError is a failure of the database rather than of the request
+type queryError struct {
+	error
+	field *field
+}
+
+func (api *API) executeGraphQL(request *graphQLRequest) (*orderedMap, error) {
+	if request.Query == "" {
+		return nil, fmt.Errorf("no GraphQL query")
+	}
+	doc, err := parseDocument(request.Query)
+	if err != nil {
+		return nil, err
+	}
+	op, err := doc.operation(request.OperationName)
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != "query" {
+		return nil, fmt.Errorf("only queries are supported, notifications are available as server-sent events "+
+			"from notify/<channel> rather than by %s", op.kind)
+	}
+	variables := make(map[string]interface{})
+	for _, def := range op.variables {
+		if value, ok := request.Variables[def.name]; ok {
+			variables[def.name] = value
+		} else {
+			variables[def.name] = def.defaultValue
+		}
+	}
+
+	data := newOrderedMap()
+	for _, f := range op.selections {
+		if f.name == "__typename" {
+			data.set(f.key(), "Query")
+			continue
+		}
+		table, ok := api.schema.tables[f.name]
+		if !ok {
+			return nil, fmt.Errorf("no table named '%s'", f.name)
+		}
+		q, selected, err := graphQLQuery(table, f, variables)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := api.rows(q)
+		if err != nil {
+			return nil, queryError{error: err, field: f}
+		}
+		result := make([]*orderedMap, len(rows))
+		for i, row := range rows {
+			result[i] = newOrderedMap()
+			for _, s := range selected {
+				if s.name == "__typename" {
+					result[i].set(s.key(), strcase.ToCamel(table.Name))
+				} else {
+					result[i].set(s.key(), row[s.name])
+				}
+			}
+		}
+		data.set(f.key(), result)
+	}
+	return data, nil
+}
+
+func (doc *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has more than one operation")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("no operation named '%s'", name)
+}
+
+// graphQLQuery builds the query for a table field returning the fields selected from each row
+func graphQLQuery(table *types.SQLTable, f *field, variables map[string]interface{}) (*Query, []*field, error) {
+	if !f.hasSelections {
+		return nil, nil, fmt.Errorf("field %s must have a selection of columns", f.name)
+	}
+	q := NewQuery(table)
+	columns := make(map[string]bool)
+	for _, s := range f.selections {
+		if s.arguments != nil || s.hasSelections {
+			return nil, nil, fmt.Errorf("column %s of %s takes no arguments or selections", s.name, f.name)
+		}
+		if s.name == "__typename" {
+			continue
+		}
+		column := table.GetColumn(s.name)
+		if column == nil || !validGraphQLName(s.name) {
+			return nil, nil, fmt.Errorf("no column named '%s' in table %s", s.name, f.name)
+		}
+		if !columns[s.name] {
+			columns[s.name] = true
+			q.Columns = append(q.Columns, column)
+		}
+	}
+	if len(q.Columns) == 0 {
+		// Only __typename was selected but we still need a row count
+		q.Columns = table.Columns[:1]
+	}
+
+	// Arguments in name order for a stable query
+	names := make([]string, 0, len(f.arguments))
+	for name := range f.arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := resolveVariables(f.arguments[name], variables)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value == nil {
+			continue
+		}
+		switch name {
+		case "where":
+			q.Filters, err = graphQLFilters(table, value)
+		case "orderBy":
+			q.Order, err = graphQLOrder(table, value)
+		case "limit":
+			q.Limit, err = graphQLInt(name, value)
+		case "offset":
+			q.Offset, err = graphQLInt(name, value)
+		default:
+			err = fmt.Errorf("unknown argument '%s' for %s", name, f.name)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return q, f.selections, q.CheckLimit()
+}
+
+func graphQLFilters(table *types.SQLTable, value interface{}) ([]Filter, error) {
+	where, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("where must be an input object")
+	}
+	keys := make([]string, 0, len(where))
+	for key := range where {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var filters []Filter
+	for _, key := range keys {
+		value := where[key]
+		if value == nil {
+			continue
+		}
+		name, op := key, OpEqual
+		column := table.GetColumn(name)
+		if column == nil {
+			if i := strings.LastIndex(key, "_"); i > 0 {
+				name, op = key[:i], Op(key[i+1:])
+				column = table.GetColumn(name)
+			}
+		}
+		if column == nil || !validGraphQLName(name) {
+			return nil, fmt.Errorf("unknown where field '%s' for %s", key, table.Name)
+		}
+		var values []string
+		if list, ok := value.([]interface{}); ok && op == OpIn {
+			for _, v := range list {
+				s, err := graphQLString(key, v)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, s)
+			}
+		} else {
+			s, err := graphQLString(key, value)
+			if err != nil {
+				return nil, err
+			}
+			values = []string{s}
+		}
+		filter, err := NewFilter(column, op, values...)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func graphQLOrder(table *types.SQLTable, value interface{}) ([]Order, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		// Input coercion allows a single value in place of a list
+		list = []interface{}{value}
+	}
+	order := make([]Order, len(list))
+	for i, v := range list {
+		var s string
+		switch e := v.(type) {
+		case enumValue:
+			s = string(e)
+		case string:
+			// Enums are strings in variables
+			s = e
+		default:
+			return nil, fmt.Errorf("orderBy must be a list of <column>_%s or <column>_%s", ascending, descending)
+		}
+		j := strings.LastIndex(s, "_")
+		if j < 0 || (s[j+1:] != ascending && s[j+1:] != descending) {
+			return nil, fmt.Errorf("orderBy must be a list of <column>_%s or <column>_%s but got %s", ascending,
+				descending, s)
+		}
+		order[i].Column = table.GetColumn(s[:j])
+		order[i].Descending = s[j+1:] == descending
+		if order[i].Column == nil {
+			return nil, fmt.Errorf("no column named '%s' in table %s", s[:j], table.Name)
+		}
+	}
+	return order, nil
+}
+
+func graphQLInt(name string, value interface{}) (int, error) {
+	s, err := graphQLString(name, value)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %v", name, err)
+	}
+	return i, nil
+}
+
+// graphQLString formats scalar values so they can be parsed according to the column they are compared with
+func graphQLString(name string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("%s must be a scalar value", name)
+}
+
+func resolveVariables(value interface{}, variables map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case variable:
+		resolved, ok := variables[string(v)]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v)
+		}
+		return resolved, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := resolveVariables(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved, err := resolveVariables(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = resolved
+		}
+		return object, nil
+	}
+	return value, nil
+}
+
+// orderedMap keeps response fields in the order they were selected, as GraphQL requires
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (om *orderedMap) set(key string, value interface{}) {
+	if _, ok := om.values[key]; !ok {
+		om.keys = append(om.keys, key)
+	}
+	om.values[key] = value
+}
+
+func (om *orderedMap) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range om.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		bs, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(bs)
+		buf.WriteByte(':')
+		bs, err = json.Marshal(om.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(bs)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,435 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The subset of GraphQL (https://spec.graphql.org/June2018/) needed to query tables: operations containing fields,
+// with aliases, arguments, and variables. Fragments and directives are not supported.
+
+type document struct {
+	operations []*operation
+}
+
+type operation struct {
+	// query, mutation, or subscription
+	kind       string
+	name       string
+	variables  []*variableDefinition
+	selections []*field
+}
+
+type variableDefinition struct {
+	name         string
+	defaultValue interface{}
+}
+
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]interface{}
+	selections []*field
+	// Whether the field had a selection set, which may not be empty
+	hasSelections bool
+}
+
+// Key is the name under which a field appears in the response
+func (f *field) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// Values other than the JSON-like int64, float64, string, bool, nil, []interface{}, and map[string]interface{}
+type (
+	variable  string
+	enumValue string
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("'%s' at %d", t.value, t.pos)
+}
+
+type parser struct {
+	source string
+	pos    int
+	token  token
+}
+
+func parseDocument(source string) (doc *document, err error) {
+	p := &parser{source: source}
+	// The parser panics with parseError to unwind
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(parseError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, perr
+		}
+	}()
+	p.next()
+	doc = new(document)
+	for p.token.kind != tokenEOF {
+		doc.operations = append(doc.operations, p.parseOperation())
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("GraphQL document contains no operations")
+	}
+	return doc, nil
+}
+
+type parseError struct {
+	error
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	panic(parseError{fmt.Errorf("GraphQL syntax error: "+format, args...)})
+}
+
+func (p *parser) parseOperation() *operation {
+	op := &operation{kind: "query"}
+	if p.peek(tokenPunctuator, "{") {
+		op.selections = p.parseSelectionSet()
+		return op
+	}
+	if p.token.kind != tokenName {
+		p.fail("expected an operation but got %v", p.token)
+	}
+	switch p.token.value {
+	case "query", "mutation", "subscription":
+		op.kind = p.token.value
+	case "fragment":
+		p.fail("fragments are not supported")
+	default:
+		p.fail("unknown operation type %v", p.token)
+	}
+	p.next()
+	if p.token.kind == tokenName {
+		op.name = p.token.value
+		p.next()
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			p.expect("$")
+			def := &variableDefinition{name: p.parseName()}
+			p.expect(":")
+			p.parseType()
+			if p.skip("=") {
+				def.defaultValue = p.parseValue(true)
+			}
+			op.variables = append(op.variables, def)
+		}
+	}
+	p.checkNoDirectives()
+	op.selections = p.parseSelectionSet()
+	return op
+}
+
+func (p *parser) parseSelectionSet() []*field {
+	p.expect("{")
+	var fields []*field
+	for !p.skip("}") {
+		if p.peek(tokenPunctuator, "...") {
+			p.fail("fragments are not supported")
+		}
+		f := &field{name: p.parseName()}
+		if p.skip(":") {
+			f.alias = f.name
+			f.name = p.parseName()
+		}
+		if p.skip("(") {
+			f.arguments = make(map[string]interface{})
+			for !p.skip(")") {
+				name := p.parseName()
+				p.expect(":")
+				f.arguments[name] = p.parseValue(false)
+			}
+		}
+		p.checkNoDirectives()
+		if p.peek(tokenPunctuator, "{") {
+			f.hasSelections = true
+			f.selections = p.parseSelectionSet()
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		p.fail("empty selection set")
+	}
+	return fields
+}
+
+// Types of variables are not checked so are only parsed
+func (p *parser) parseType() {
+	if p.skip("[") {
+		p.parseType()
+		p.expect("]")
+	} else {
+		p.parseName()
+	}
+	p.skip("!")
+}
+
+func (p *parser) parseValue(constant bool) interface{} {
+	t := p.token
+	switch t.kind {
+	case tokenPunctuator:
+		switch t.value {
+		case "$":
+			if constant {
+				p.fail("variable not allowed in default value at %d", t.pos)
+			}
+			p.next()
+			return variable(p.parseName())
+		case "[":
+			p.next()
+			list := make([]interface{}, 0)
+			for !p.skip("]") {
+				list = append(list, p.parseValue(constant))
+			}
+			return list
+		case "{":
+			p.next()
+			object := make(map[string]interface{})
+			for !p.skip("}") {
+				name := p.parseName()
+				p.expect(":")
+				object[name] = p.parseValue(constant)
+			}
+			return object
+		}
+	case tokenInt:
+		p.next()
+		i, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			p.fail("could not parse integer %v: %v", t, err)
+		}
+		return i
+	case tokenFloat:
+		p.next()
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			p.fail("could not parse float %v: %v", t, err)
+		}
+		return f
+	case tokenString:
+		p.next()
+		return t.value
+	case tokenName:
+		p.next()
+		switch t.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return enumValue(t.value)
+	}
+	p.fail("expected a value but got %v", t)
+	return nil
+}
+
+func (p *parser) checkNoDirectives() {
+	if p.peek(tokenPunctuator, "@") {
+		p.fail("directives are not supported")
+	}
+}
+
+func (p *parser) parseName() string {
+	if p.token.kind != tokenName {
+		p.fail("expected a name but got %v", p.token)
+	}
+	name := p.token.value
+	p.next()
+	return name
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.token.kind == kind && p.token.value == value
+}
+
+// skip consumes the punctuator if it is next
+func (p *parser) skip(punctuator string) bool {
+	if p.peek(tokenPunctuator, punctuator) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punctuator string) {
+	if !p.skip(punctuator) {
+		p.fail("expected '%s' but got %v", punctuator, p.token)
+	}
+}
+
+// next lexes the next token
+func (p *parser) next() {
+	src := p.source
+	// Skip whitespace, commas, and comments
+	for p.pos < len(src) {
+		c := src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+		} else if c == '#' {
+			for p.pos < len(src) && src[p.pos] != '\n' && src[p.pos] != '\r' {
+				p.pos++
+			}
+		} else if strings.HasPrefix(src[p.pos:], "\ufeff") {
+			p.pos += len("\ufeff")
+		} else {
+			break
+		}
+	}
+	start := p.pos
+	if p.pos >= len(src) {
+		p.token = token{kind: tokenEOF, pos: start}
+		return
+	}
+	c := src[p.pos]
+	switch {
+	case strings.HasPrefix(src[p.pos:], "..."):
+		p.pos += 3
+		p.token = token{kind: tokenPunctuator, value: "...", pos: start}
+	case strings.IndexByte("!$():=@[]{}|&", c) >= 0:
+		p.pos++
+		p.token = token{kind: tokenPunctuator, value: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(src) && (src[p.pos] == '_' || isLetter(src[p.pos]) || isDigit(src[p.pos])) {
+			p.pos++
+		}
+		p.token = token{kind: tokenName, value: src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		p.lexNumber()
+	case c == '"':
+		p.lexString()
+	default:
+		p.fail("unexpected character '%c' at %d", c, start)
+	}
+}
+
+func (p *parser) lexNumber() {
+	src := p.source
+	start := p.pos
+	kind := tokenInt
+	if src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := func() {
+		begin := p.pos
+		for p.pos < len(src) && isDigit(src[p.pos]) {
+			p.pos++
+		}
+		if p.pos == begin {
+			p.fail("malformed number at %d", start)
+		}
+	}
+	digits()
+	if p.pos < len(src) && src[p.pos] == '.' {
+		kind = tokenFloat
+		p.pos++
+		digits()
+	}
+	if p.pos < len(src) && (src[p.pos] == 'e' || src[p.pos] == 'E') {
+		kind = tokenFloat
+		p.pos++
+		if p.pos < len(src) && (src[p.pos] == '+' || src[p.pos] == '-') {
+			p.pos++
+		}
+		digits()
+	}
+	p.token = token{kind: kind, value: src[start:p.pos], pos: start}
+}
+
+func (p *parser) lexString() {
+	src := p.source
+	start := p.pos
+	if strings.HasPrefix(src[p.pos:], `"""`) {
+		p.fail("block strings are not supported")
+	}
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.pos >= len(src) || src[p.pos] == '\n' || src[p.pos] == '\r' {
+			p.fail("unterminated string at %d", start)
+		}
+		c := src[p.pos]
+		if c == '"' {
+			p.pos++
+			break
+		}
+		if c != '\\' {
+			r, size := utf8.DecodeRuneInString(src[p.pos:])
+			sb.WriteRune(r)
+			p.pos += size
+			continue
+		}
+		p.pos++
+		if p.pos >= len(src) {
+			p.fail("unterminated string at %d", start)
+		}
+		escape := src[p.pos]
+		p.pos++
+		switch escape {
+		case '"', '\\', '/':
+			sb.WriteByte(escape)
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			if p.pos+4 > len(src) {
+				p.fail("malformed unicode escape at %d", p.pos)
+			}
+			code, err := strconv.ParseUint(src[p.pos:p.pos+4], 16, 32)
+			if err != nil {
+				p.fail("malformed unicode escape at %d", p.pos)
+			}
+			sb.WriteRune(rune(code))
+			p.pos += 4
+		default:
+			p.fail("unknown escape '\\%c' at %d", escape, p.pos-1)
+		}
+	}
+	p.token = token{kind: tokenString, value: sb.String(), pos: start}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
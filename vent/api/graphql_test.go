@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocument(t *testing.T) {
+	doc, err := parseDocument(`
+		# Frogs by height
+		query Frogs($owner: String = "toad", $limit: Int!) {
+			frogs: Names(where: {owner: $owner, _height_in: [1, 2.5e1]}, orderBy: [name_DESC], limit: $limit) {
+				name
+				__typename
+			}
+		}
+		{ Names { name } }`)
+	require.NoError(t, err)
+	require.Len(t, doc.operations, 2)
+
+	op := doc.operations[0]
+	assert.Equal(t, "query", op.kind)
+	assert.Equal(t, "Frogs", op.name)
+	require.Len(t, op.variables, 2)
+	assert.Equal(t, "toad", op.variables[0].defaultValue)
+	assert.Nil(t, op.variables[1].defaultValue)
+
+	f := op.selections[0]
+	assert.Equal(t, "frogs", f.key())
+	assert.Equal(t, "Names", f.name)
+	assert.Equal(t, map[string]interface{}{
+		"where": map[string]interface{}{
+			"owner":      variable("owner"),
+			"_height_in": []interface{}{int64(1), 25.0},
+		},
+		"orderBy": []interface{}{enumValue("name_DESC")},
+		"limit":   variable("limit"),
+	}, f.arguments)
+	require.Len(t, f.selections, 2)
+	assert.Equal(t, "__typename", f.selections[1].name)
+
+	_, err = doc.operation("")
+	assert.Error(t, err)
+	op, err = doc.operation("Frogs")
+	require.NoError(t, err)
+	assert.Equal(t, "Frogs", op.name)
+}
+
+func TestParseDocument_Errors(t *testing.T) {
+	for _, query := range []string{
+		``,
+		`{}`,
+		`{ Names { name }`,
+		`{ Names { ...Fields } }`,
+		`fragment Fields on Names { name }`,
+		`{ Names @include(if: true) { name } }`,
+		`{ Names(where: {name: "frog}) { name } }`,
+		`query ($a: Int = $b) { Names { name } }`,
+	} {
+		_, err := parseDocument(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	s, err := newSchema(types.EventTables{"Names": testTable(), "not-graphql": {Name: "not-graphql"}})
+	require.NoError(t, err)
+	assert.Contains(t, s.sdl, "  Names(where: NamesWhere, orderBy: [NamesOrderBy!], limit: Int = 100, offset: Int = 0): [Names!]!\n")
+	assert.Contains(t, s.sdl, "type Names {\n  name: String!\n  owner: String\n  _height: BigInt\n  data: JSON\n}\n")
+	assert.Contains(t, s.sdl, "  _height_gte: BigInt\n")
+	assert.Contains(t, s.sdl, "  name_in: [String!]\n")
+	assert.Contains(t, s.sdl, "  owner_like: String\n")
+	assert.NotContains(t, s.sdl, "_height_like")
+	assert.Contains(t, s.sdl, "  _height_DESC\n")
+	assert.NotContains(t, s.sdl, "not-graphql")
+
+	_, err = newSchema(types.EventTables{"query": {Name: "query"}})
+	assert.Error(t, err)
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	doc, err := parseDocument(`query ($owner: String) {
+		Names(where: {owner: $owner, _height_gt: "5", data_null: false}, orderBy: _height_DESC, limit: 2) {
+			first: name
+			name
+			__typename
+		}
+	}`)
+	require.NoError(t, err)
+	f := doc.operations[0].selections[0]
+	q, selected, err := graphQLQuery(testTable(), f, map[string]interface{}{"owner": "toad"})
+	require.NoError(t, err)
+	assert.Len(t, selected, 3)
+	query, args := q.SQL(testNamer{})
+	assert.Equal(t, `SELECT "name" FROM "vent"."Names" WHERE "_height" > ? AND "data" IS NOT NULL AND "owner" = ? `+
+		`ORDER BY "_height" DESC LIMIT 2 OFFSET 0`, query)
+	assert.Equal(t, []interface{}{int64(5), "toad"}, args)
+
+	for _, query := range []string{
+		`{ Names }`,
+		`{ Names { frog } }`,
+		`{ Names(where: {frog_gt: 1}) { name } }`,
+		`{ Names(orderBy: name_SIDEWAYS) { name } }`,
+		`{ Names(limit: 5000) { name } }`,
+		`{ Names(colour: "green") { name } }`,
+		`{ Names(where: {_height: $undefined}) { name } }`,
+	} {
+		doc, err := parseDocument(query)
+		require.NoError(t, err)
+		_, _, err = graphQLQuery(testTable(), doc.operations[0].selections[0], nil)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	om := newOrderedMap()
+	om.set("b", 1)
+	om.set("a", []int{2})
+	om.set("b", 3)
+	bs, err := json.Marshal(om)
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":3,"a":[2]}`, string(bs))
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/lib/pq"
+)
+
+const (
+	notifyMinReconnect = 10 * time.Second
+	notifyMaxReconnect = time.Minute
+	// Comments sent while idle stop proxies closing the stream
+	notifyKeepAlive = 30 * time.Second
+	// Notifications buffered for each stream, a stream that falls this far behind is closed
+	notifyBufferSize = 64
+)
+
+// notify streams the payloads sent on a notification channel as server-sent events
+// (https://html.spec.whatwg.org/multipage/server-sent-events.html) until the client disconnects
+func (api *API) notify(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	channel := strings.TrimPrefix(r.URL.Path, "/notify/")
+	if _, ok := api.channels[channel]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no notification channel named '%s'", channel))
+		return
+	}
+	if !api.notifySupported() {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("notifications are only available from Postgres"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	notifications, err := api.notifier.subscribe(channel)
+	if err != nil {
+		api.logger.InfoMsg("Could not listen for notifications", "channel", channel, "err", err)
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("could not listen on channel '%s'", channel))
+		return
+	}
+	defer api.notifier.unsubscribe(channel, notifications)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(notifyKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case n, ok := <-notifications:
+			// We were too slow to keep up or the API is closing, the client can reconnect
+			if !ok {
+				return
+			}
+			// A nil notification signals that the connection was re-established, during which we may have missed some
+			if n == nil {
+				fmt.Fprint(w, "event: reconnected\ndata:\n\n")
+			} else {
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", channel, strings.Replace(n.Extra, "\n", "\ndata: ", -1))
+			}
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// notifier shares a single LISTEN connection between every stream, relaying each notification to the streams
+// subscribed to its channel
+type notifier struct {
+	dbURL  string
+	logger *logging.Logger
+	// Serialises LISTEN and UNLISTEN with the subscriptions they are for. This is not held while dispatching because
+	// the listener cannot complete a LISTEN while its notifications are not being drained.
+	listenMtx sync.Mutex
+	listener  *pq.Listener
+	closed    bool
+	// Guards subscribers
+	mtx         sync.Mutex
+	subscribers map[string]map[chan *pq.Notification]struct{}
+}
+
+func newNotifier(dbURL string, logger *logging.Logger) *notifier {
+	return &notifier{
+		dbURL:       dbURL,
+		logger:      logger,
+		subscribers: make(map[string]map[chan *pq.Notification]struct{}),
+	}
+}
+
+// subscribe returns a channel receiving the notifications sent on channel, connecting on first use
+func (nf *notifier) subscribe(channel string) (chan *pq.Notification, error) {
+	nf.listenMtx.Lock()
+	defer nf.listenMtx.Unlock()
+	if nf.closed {
+		return nil, fmt.Errorf("notifier is closed")
+	}
+	if nf.listener == nil {
+		nf.listener = pq.NewListener(nf.dbURL, notifyMinReconnect, notifyMaxReconnect, nil)
+		go nf.run(nf.listener.Notify)
+	}
+	if nf.count(channel) == 0 {
+		err := nf.listener.Listen(channel)
+		// The last stream on the channel may have been dropped without unlistening
+		if err != nil && err != pq.ErrChannelAlreadyOpen {
+			return nil, err
+		}
+	}
+	ch := make(chan *pq.Notification, notifyBufferSize)
+	nf.mtx.Lock()
+	defer nf.mtx.Unlock()
+	if nf.subscribers[channel] == nil {
+		nf.subscribers[channel] = make(map[chan *pq.Notification]struct{})
+	}
+	nf.subscribers[channel][ch] = struct{}{}
+	return ch, nil
+}
+
+// unsubscribe stops relaying to ch, and stops listening on channel once it has no other subscribers
+func (nf *notifier) unsubscribe(channel string, ch chan *pq.Notification) {
+	nf.listenMtx.Lock()
+	defer nf.listenMtx.Unlock()
+	nf.mtx.Lock()
+	delete(nf.subscribers[channel], ch)
+	nf.mtx.Unlock()
+	if nf.closed || nf.count(channel) > 0 {
+		return
+	}
+	err := nf.listener.Unlisten(channel)
+	if err != nil && err != pq.ErrChannelNotOpen {
+		nf.logger.InfoMsg("Could not stop listening for notifications", "channel", channel, "err", err)
+	}
+}
+
+func (nf *notifier) count(channel string) int {
+	nf.mtx.Lock()
+	defer nf.mtx.Unlock()
+	return len(nf.subscribers[channel])
+}
+
+// Relay notifications until the listener is closed
+func (nf *notifier) run(notifications <-chan *pq.Notification) {
+	for n := range notifications {
+		nf.dispatch(n)
+	}
+	nf.mtx.Lock()
+	defer nf.mtx.Unlock()
+	for channel, chs := range nf.subscribers {
+		for ch := range chs {
+			close(ch)
+		}
+		delete(nf.subscribers, channel)
+	}
+}
+
+// Send n to the subscribers of its channel, or to every subscriber if it is nil. Rather than hold up every other
+// stream we drop any subscriber whose buffer is full.
+func (nf *notifier) dispatch(n *pq.Notification) {
+	nf.mtx.Lock()
+	defer nf.mtx.Unlock()
+	for channel, chs := range nf.subscribers {
+		if n != nil && n.Channel != channel {
+			continue
+		}
+		for ch := range chs {
+			select {
+			case ch <- n:
+			default:
+				delete(chs, ch)
+				close(ch)
+			}
+		}
+	}
+}
+
+func (nf *notifier) Close() error {
+	nf.listenMtx.Lock()
+	defer nf.listenMtx.Unlock()
+	nf.closed = true
+	if nf.listener == nil {
+		return nil
+	}
+	return nf.listener.Close()
+}
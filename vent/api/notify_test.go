@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierDispatch(t *testing.T) {
+	nf := newNotifier("", logging.NewNoopLogger())
+	subscribe := func(channel string) chan *pq.Notification {
+		ch := make(chan *pq.Notification, 2)
+		if nf.subscribers[channel] == nil {
+			nf.subscribers[channel] = make(map[chan *pq.Notification]struct{})
+		}
+		nf.subscribers[channel][ch] = struct{}{}
+		return ch
+	}
+	names1 := subscribe("names")
+	names2 := subscribe("names")
+	heights := subscribe("height")
+
+	name := &pq.Notification{Channel: "names", Extra: "frog"}
+	nf.dispatch(name)
+	assert.Equal(t, name, <-names1)
+	assert.Equal(t, name, <-names2)
+	assert.Len(t, heights, 0)
+
+	// Reconnections go to everyone
+	nf.dispatch(nil)
+	assert.Nil(t, <-names1)
+	assert.Nil(t, <-names2)
+	assert.Nil(t, <-heights)
+
+	// A subscriber that falls behind is dropped without holding up the others
+	for i := 0; i < 3; i++ {
+		nf.dispatch(name)
+		assert.Equal(t, name, <-names1)
+	}
+	assert.Equal(t, 1, nf.count("names"))
+	missed := 0
+	for range names2 {
+		missed++
+	}
+	assert.Equal(t, 2, missed)
+
+	// Closing the listener ends every stream
+	notifications := make(chan *pq.Notification)
+	close(notifications)
+	nf.run(notifications)
+	_, ok := <-names1
+	assert.False(t, ok)
+	_, ok = <-heights
+	assert.False(t, ok)
+	assert.Equal(t, 0, nf.count("names"))
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/vent/types"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Op compares a column with a value
+type Op string
+
+const (
+	OpEqual        Op = "eq"
+	OpNotEqual     Op = "ne"
+	OpGreater      Op = "gt"
+	OpGreaterEqual Op = "gte"
+	OpLess         Op = "lt"
+	OpLessEqual    Op = "lte"
+	OpLike         Op = "like"
+	// Value is a list
+	OpIn Op = "in"
+	// Value is a bool, true for IS NULL and false for IS NOT NULL
+	OpNull Op = "null"
+)
+
+var ops = map[Op]string{
+	OpEqual:        "=",
+	OpNotEqual:     "<>",
+	OpGreater:      ">",
+	OpGreaterEqual: ">=",
+	OpLess:         "<",
+	OpLessEqual:    "<=",
+	OpLike:         "LIKE",
+}
+
+// Filter restricts the rows returned by a Query
+type Filter struct {
+	Column *types.SQLTableColumn
+	Op     Op
+	Value  interface{}
+}
+
+type Order struct {
+	Column     *types.SQLTableColumn
+	Descending bool
+}
+
+// Query selects rows from one of the projection's tables
+type Query struct {
+	Table *types.SQLTable
+	// All columns if empty
+	Columns []*types.SQLTableColumn
+	Filters []Filter
+	// By primary key if empty so that pages are stable
+	Order  []Order
+	Limit  int
+	Offset int
+}
+
+// namer quotes names for the database, as adapters.DBAdapter does
+type namer interface {
+	SecureName(name string) string
+	SchemaName(tableName string) string
+}
+
+func NewQuery(table *types.SQLTable) *Query {
+	return &Query{
+		Table: table,
+		Limit: DefaultLimit,
+	}
+}
+
+// NewFilter parses the values for column, which are only allowed to be more than one for OpIn
+func NewFilter(column *types.SQLTableColumn, op Op, values ...string) (Filter, error) {
+	filter := Filter{Column: column, Op: op}
+	switch op {
+	case OpIn:
+		in := make([]interface{}, len(values))
+		for i, value := range values {
+			v, err := ParseValue(column, value)
+			if err != nil {
+				return Filter{}, err
+			}
+			in[i] = v
+		}
+		filter.Value = in
+		return filter, nil
+	case OpNull:
+		if len(values) != 1 {
+			return Filter{}, fmt.Errorf("expected a single value for %s.%s", column.Name, op)
+		}
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return Filter{}, fmt.Errorf("%s.%s should be true or false: %v", column.Name, op, err)
+		}
+		filter.Value = isNull
+		return filter, nil
+	}
+	if _, ok := ops[op]; !ok {
+		return Filter{}, fmt.Errorf("unknown operator '%s' for column %s", op, column.Name)
+	}
+	if len(values) != 1 {
+		return Filter{}, fmt.Errorf("expected a single value for %s.%s", column.Name, op)
+	}
+	if op == OpLike {
+		filter.Value = values[0]
+		return filter, nil
+	}
+	v, err := ParseValue(column, values[0])
+	if err != nil {
+		return Filter{}, err
+	}
+	filter.Value = v
+	return filter, nil
+}
+
+// ParseValue converts a value given as a string to the type stored in column
+func ParseValue(column *types.SQLTableColumn, value string) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch column.Type {
+	case types.SQLColumnTypeInt, types.SQLColumnTypeSerial, types.SQLColumnTypeBigInt:
+		v, err = strconv.ParseInt(value, 10, 64)
+	case types.SQLColumnTypeBool:
+		v, err = strconv.ParseBool(value)
+	case types.SQLColumnTypeByteA:
+		v, err = hex.DecodeString(value)
+	case types.SQLColumnTypeNumeric:
+		// Numeric columns may hold uint256 so leave the database to convert the string
+		for _, r := range value {
+			if (r < '0' || r > '9') && r != '-' && r != '.' {
+				err = fmt.Errorf("not a number")
+				break
+			}
+		}
+		v = value
+	default:
+		v = value
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse '%s' as %v for column %s: %v", value, column.Type, column.Name, err)
+	}
+	return v, nil
+}
+
+// SQL returns the SELECT statement with ? placeholders for its arguments
+func (q *Query) SQL(n namer) (string, []interface{}) {
+	columns := q.SelectedColumns()
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = n.SecureName(column.Name)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(names, ", "), n.SchemaName(q.Table.Name))
+
+	var args []interface{}
+	var where []string
+	for _, filter := range q.Filters {
+		column := n.SecureName(filter.Column.Name)
+		switch filter.Op {
+		case OpIn:
+			in := filter.Value.([]interface{})
+			if len(in) == 0 {
+				where = append(where, "1 = 0")
+				continue
+			}
+			where = append(where, fmt.Sprintf("%s IN (%s)", column, strings.TrimSuffix(strings.Repeat("?, ", len(in)), ", ")))
+			args = append(args, in...)
+		case OpNull:
+			if filter.Value.(bool) {
+				where = append(where, column+" IS NULL")
+			} else {
+				where = append(where, column+" IS NOT NULL")
+			}
+		default:
+			where = append(where, fmt.Sprintf("%s %s ?", column, ops[filter.Op]))
+			args = append(args, filter.Value)
+		}
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	order := q.Order
+	if len(order) == 0 {
+		for _, column := range q.Table.Columns {
+			if column.Primary {
+				order = append(order, Order{Column: column})
+			}
+		}
+	}
+	if len(order) > 0 {
+		orderBy := make([]string, len(order))
+		for i, o := range order {
+			orderBy[i] = n.SecureName(o.Column.Name)
+			if o.Descending {
+				orderBy[i] += " DESC"
+			}
+		}
+		query += " ORDER BY " + strings.Join(orderBy, ", ")
+	}
+	query += fmt.Sprintf(" LIMIT %d OFFSET %d", q.Limit, q.Offset)
+	return query, args
+}
+
+func (q *Query) SelectedColumns() []*types.SQLTableColumn {
+	if len(q.Columns) > 0 {
+		return q.Columns
+	}
+	return q.Table.Columns
+}
+
+func (q *Query) CheckLimit() error {
+	if q.Limit < 0 || q.Limit > MaxLimit {
+		return fmt.Errorf("limit must be between 0 and %d", MaxLimit)
+	}
+	if q.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	return nil
+}
+
+// normalise converts a value scanned from the database, which depends on the driver, to a consistent type for JSON
+func normalise(column *types.SQLTableColumn, value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		switch column.Type {
+		case types.SQLColumnTypeByteA:
+			return binary.HexBytes(v)
+		case types.SQLColumnTypeJSON:
+			if json.Valid(v) {
+				return json.RawMessage(v)
+			}
+		}
+		return normalise(column, string(v))
+	case string:
+		switch column.Type {
+		case types.SQLColumnTypeInt, types.SQLColumnTypeSerial, types.SQLColumnTypeBigInt:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i
+			}
+		case types.SQLColumnTypeBool:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		case types.SQLColumnTypeJSON:
+			if json.Valid([]byte(v)) {
+				return json.RawMessage(v)
+			}
+		}
+		return v
+	case int64:
+		if column.Type == types.SQLColumnTypeBool {
+			return v != 0
+		}
+		if column.Type == types.SQLColumnTypeNumeric {
+			return strconv.FormatInt(v, 10)
+		}
+		return v
+	case float64:
+		if column.Type == types.SQLColumnTypeNumeric {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return v
+	case time.Time:
+		return v.UTC()
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	params, err := url.ParseQuery("fields=name,owner&order=-_height,name&limit=10&offset=20" +
+		"&owner=toad&_height.gte=5&_height.lt=10&name.in=frog,newt&data.null=false")
+	require.NoError(t, err)
+	q, err := parseQuery(testTable(), params)
+	require.NoError(t, err)
+
+	query, args := q.SQL(testNamer{})
+	assert.Equal(t, `SELECT "name", "owner" FROM "vent"."Names" `+
+		`WHERE "_height" >= ? AND "_height" < ? AND "data" IS NOT NULL AND "name" IN (?, ?) AND "owner" = ? `+
+		`ORDER BY "_height" DESC, "name" LIMIT 10 OFFSET 20`, query)
+	assert.Equal(t, []interface{}{int64(5), int64(10), "frog", "newt", "toad"}, args)
+}
+
+func TestParseQuery_Defaults(t *testing.T) {
+	q, err := parseQuery(testTable(), url.Values{})
+	require.NoError(t, err)
+	query, args := q.SQL(testNamer{})
+	assert.Equal(t, `SELECT "name", "owner", "_height", "data" FROM "vent"."Names" ORDER BY "name" LIMIT 100 OFFSET 0`,
+		query)
+	assert.Empty(t, args)
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, query := range []string{
+		"frog=1",
+		"fields=frog",
+		"order=-frog",
+		"limit=1001",
+		"offset=-1",
+		"_height=frog",
+		"name.between=a",
+		"data.null=maybe",
+	} {
+		params, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		_, err = parseQuery(testTable(), params)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	table := testTable()
+	assert.Equal(t, "frog", normalise(table.GetColumn("name"), []byte("frog")))
+	assert.Equal(t, int64(12), normalise(table.GetColumn("_height"), []byte("12")))
+	assert.Equal(t, json.RawMessage(`{"a":1}`), normalise(table.GetColumn("data"), `{"a":1}`))
+	assert.Equal(t, "not json", normalise(table.GetColumn("data"), []byte("not json")))
+}
+
+type testNamer struct{}
+
+func (testNamer) SecureName(name string) string {
+	return `"` + name + `"`
+}
+
+func (testNamer) SchemaName(tableName string) string {
+	return `"vent"."` + tableName + `"`
+}
+
+func testTable() *types.SQLTable {
+	return &types.SQLTable{
+		Name: "Names",
+		Columns: []*types.SQLTableColumn{
+			{Name: "name", Type: types.SQLColumnTypeVarchar, Length: 100, Primary: true},
+			{Name: "owner", Type: types.SQLColumnTypeText},
+			{Name: "_height", Type: types.SQLColumnTypeBigInt},
+			{Name: "data", Type: types.SQLColumnTypeJSON},
+		},
+		NotifyChannels: map[string][]string{"names": {"name", "owner"}},
+	}
+}
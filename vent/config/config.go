@@ -8,6 +8,7 @@ import (
 )
 
 const DefaultPostgresDBURL = "postgres://postgres@localhost:5432/postgres?sslmode=disable"
+const DefaultMySQLDBURL = "root@tcp(localhost:3306)/"
 
 // VentConfig is a set of configuration parameters
 type VentConfig struct {
@@ -22,6 +23,27 @@ type VentConfig struct {
 	SpecOpt        sqlsol.SpecOpt
 	// Announce status every AnnouncePeriod
 	AnnounceEvery time.Duration
+	// Deliver projected rows somewhere other than the SQL database
+	Sink SinkConfig
+	// Serve a read-only REST and GraphQL API over the projection's tables from the HTTP server
+	API bool
+}
+
+// SinkConfig selects where projected rows are delivered if not to the SQL database
+type SinkConfig struct {
+	// One of 'jsonl', 'webhook', 'kafka', or 'nats', empty for the SQL database
+	Type string
+	// JSON Lines file path, webhook URL, Kafka REST proxy URL, or NATS server URL
+	URL string
+	// Kafka topic or NATS subject
+	Topic string
+	// File recording the last block delivered, by default the JSON Lines file path suffixed with '.checkpoint' or
+	// 'vent.checkpoint' in the working directory
+	Checkpoint string
+	// How many times a failed delivery is retried before vent stops, negative to retry indefinitely
+	Retries int
+	// Delay before retrying a failed delivery, doubled on each subsequent attempt
+	RetryBackoff time.Duration
 }
 
 // DefaultFlags returns a configuration with default values
@@ -35,5 +57,10 @@ func DefaultVentConfig() *VentConfig {
 		LogLevel:      "debug",
 		SpecOpt:       sqlsol.None,
 		AnnounceEvery: time.Second * 5,
+		Sink: SinkConfig{
+			Topic:        "vent",
+			Retries:      10,
+			RetryBackoff: time.Second,
+		},
 	}
 }
@@ -12,6 +12,8 @@ import (
 
 type EventSpecGetter func(abi.EventID, crypto.Address) (*abi.EventSpec, error)
 
+type FunctionSpecGetter func(abi.FunctionID, crypto.Address) (*abi.FunctionSpec, error)
+
 // AbiProvider provides a method for loading ABIs from disk, and retrieving them from burrow on-demand
 type AbiProvider struct {
 	abiSpec *abi.Spec
@@ -68,3 +70,34 @@ func (p *AbiProvider) GetEventAbi(eventID abi.EventID, address crypto.Address) (
 
 	return evAbi, nil
 }
+
+// GetFunctionAbi get the ABI for a function selector. If it is not known, it is retrieved from the burrow node via
+// the address for the contract
+func (p *AbiProvider) GetFunctionAbi(functionID abi.FunctionID, address crypto.Address) (*abi.FunctionSpec, error) {
+	funcSpec, err := p.abiSpec.GetFunctionAbi(functionID, address)
+	if err == nil {
+		return funcSpec, nil
+	}
+	resp, err := p.cli.GetMetadata(context.Background(), &rpcquery.GetMetadataParam{Address: &address})
+	if err != nil {
+		p.logger.InfoMsg("Error retrieving abi for function", "address", address.String(),
+			"function_id", fmt.Sprintf("%X", functionID[:]), "error", err)
+		return nil, err
+	}
+	if resp == nil || resp.Metadata == "" {
+		return nil, fmt.Errorf("No ABI present for contract at address %v", address)
+	}
+	a, err := abi.ReadSpec([]byte(resp.Metadata))
+	if err != nil {
+		p.logger.InfoMsg("Failed to parse abi", "address", address.String(),
+			"function_id", fmt.Sprintf("%X", functionID[:]), "abi", resp.Metadata)
+		return nil, err
+	}
+	funcSpec, err = a.GetFunctionAbi(functionID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	p.abiSpec = abi.MergeSpec([]*abi.Spec{p.abiSpec, a})
+	return funcSpec, nil
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/event/query"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/rpc/rpcquery"
+	"github.com/hyperledger/burrow/vent/sqlsol"
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/pkg/errors"
+)
+
+// The longest string or bytes storage variable we will read (from its length word) before giving up
+const maxStorageBytesLength = 1 << 20
+
+// StateReader reads account state as it was after the block at a height
+type StateReader interface {
+	// GetAccount returns nil if there was no account at address
+	GetAccount(address crypto.Address, height uint64) (*acm.Account, error)
+	GetStorage(address crypto.Address, key binary.Word256, height uint64) ([]byte, error)
+	// ListAccounts returns the accounts matching the query filter
+	ListAccounts(filter string, height uint64) ([]*acm.Account, error)
+}
+
+type queryStateReader struct {
+	cli rpcquery.QueryClient
+}
+
+// NewStateReader reads historical state from a Burrow node over its query service, which requires the node to have
+// retained state at the heights read
+func NewStateReader(cli rpcquery.QueryClient) StateReader {
+	return &queryStateReader{cli: cli}
+}
+
+func (sr *queryStateReader) GetAccount(address crypto.Address, height uint64) (*acm.Account, error) {
+	acc, err := sr.cli.GetAccount(context.Background(), &rpcquery.GetAccountParam{Address: address, Height: height})
+	if err != nil {
+		return nil, err
+	}
+	// The query service returns an empty account rather than nil
+	if acc == nil || acc.Address != address {
+		return nil, nil
+	}
+	return acc, nil
+}
+
+func (sr *queryStateReader) GetStorage(address crypto.Address, key binary.Word256, height uint64) ([]byte, error) {
+	val, err := sr.cli.GetStorage(context.Background(), &rpcquery.GetStorageParam{
+		Address: address,
+		Key:     key,
+		Height:  height,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.Value, nil
+}
+
+func (sr *queryStateReader) ListAccounts(filter string, height uint64) ([]*acm.Account, error) {
+	stream, err := sr.cli.ListAccounts(context.Background(), &rpcquery.ListAccountsParam{Query: filter, Height: height})
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*acm.Account
+	for {
+		acc, err := stream.Recv()
+		if err == io.EOF {
+			return accounts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acc)
+	}
+}
+
+// buildAccountStateData adds rows to blockData for the accounts matching each AccountState class that changed in the
+// block or, when snapshot is set, for every matching account
+func buildAccountStateData(blockData *sqlsol.BlockData, projection *sqlsol.Projection,
+	blockExecution *exec.BlockExecution, snapshot bool, state StateReader, logger *logging.Logger) error {
+
+	var classes []*types.EventClass
+	for _, eventClass := range projection.Spec {
+		if eventClass.AccountState {
+			classes = append(classes, eventClass)
+		}
+	}
+	// A height of zero would read the latest state, and there is no state before the first block anyway
+	if len(classes) == 0 || blockExecution.Height == 0 {
+		return nil
+	}
+	if state == nil {
+		return fmt.Errorf("cannot project account state without a StateReader")
+	}
+
+	height := blockExecution.Height
+	chainID := blockExecution.GetHeader().GetChainID()
+	addresses := changedAccounts(blockExecution)
+	accounts := make([]*acm.Account, len(addresses))
+	for i, address := range addresses {
+		acc, err := state.GetAccount(address, height)
+		if err != nil {
+			return errors.Wrapf(err, "could not get account %v at height %d", address, height)
+		}
+		accounts[i] = acc
+	}
+
+	for _, eventClass := range classes {
+		qry, err := eventClass.Query()
+		if err != nil {
+			return errors.Wrapf(err, "Error parsing query from filter string")
+		}
+
+		seen := make(map[crypto.Address]bool)
+		if snapshot {
+			snapshotAccounts, err := state.ListAccounts(eventClass.Filter, height)
+			if err != nil {
+				return errors.Wrapf(err, "could not list accounts matching \"%s\" at height %d", eventClass.Filter, height)
+			}
+			for _, acc := range snapshotAccounts {
+				seen[acc.Address] = true
+				row, err := buildAccountData(eventClass, acc, height, chainID, state, logger)
+				if err != nil {
+					return err
+				}
+				blockData.AddRow(eventClass.TableName, row)
+			}
+		}
+
+		for i, acc := range accounts {
+			if seen[addresses[i]] {
+				continue
+			}
+			if acc == nil {
+				// The account was removed in this block so we retire its row if it matched before
+				row, ok, err := buildRemovedAccountData(eventClass, qry, addresses[i], height, chainID, state,
+					logger)
+				if err != nil {
+					return err
+				}
+				if ok {
+					blockData.AddRow(eventClass.TableName, row)
+				}
+				continue
+			}
+			if !qry.Matches(acc) {
+				continue
+			}
+			row, err := buildAccountData(eventClass, acc, height, chainID, state, logger)
+			if err != nil {
+				return err
+			}
+			blockData.AddRow(eventClass.TableName, row)
+		}
+	}
+	return nil
+}
+
+// changedAccounts returns the addresses, in order, of the accounts that the block may have changed. When state
+// diffs are recorded they name every account with changed balance, code, permissions, or storage, but we also take
+// the accounts named by events since diffs omit sequence numbers and may not be enabled. Changes made outside of any
+// transaction, such as the payment of fees, are always recorded in the block's own diff.
+func changedAccounts(blockExecution *exec.BlockExecution) []crypto.Address {
+	set := make(map[crypto.Address]struct{})
+	for _, diff := range blockExecution.GetStateDiff().GetAccounts() {
+		set[diff.Address] = struct{}{}
+	}
+	var collect func(txes []*exec.TxExecution)
+	collect = func(txes []*exec.TxExecution) {
+		for _, txe := range txes {
+			for _, diff := range txe.GetStateDiff().GetAccounts() {
+				set[diff.Address] = struct{}{}
+			}
+			for _, ev := range txe.Events {
+				switch {
+				case ev.Input != nil:
+					set[ev.Input.Address] = struct{}{}
+				case ev.Output != nil:
+					set[ev.Output.Address] = struct{}{}
+				case ev.Call != nil && ev.Call.CallData != nil:
+					set[ev.Call.CallData.Caller] = struct{}{}
+					set[ev.Call.CallData.Callee] = struct{}{}
+				case ev.Log != nil:
+					set[ev.Log.Address] = struct{}{}
+				case ev.GovernAccount != nil && ev.GovernAccount.AccountUpdate != nil &&
+					ev.GovernAccount.AccountUpdate.Address != nil:
+					set[*ev.GovernAccount.AccountUpdate.Address] = struct{}{}
+				}
+			}
+			if receipt := txe.GetReceipt(); receipt != nil && receipt.CreatesContract {
+				set[receipt.ContractAddress] = struct{}{}
+			}
+			if permArgs := txe.GetResult().GetPermArgs(); permArgs != nil && permArgs.Target != nil {
+				set[*permArgs.Target] = struct{}{}
+			}
+			collect(txe.TxExecutions)
+		}
+	}
+	collect(blockExecution.TxExecutions)
+	addresses := make([]crypto.Address, 0, len(set))
+	for address := range set {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	return addresses
+}
+
+// buildAccountData builds a row from the fields and storage of an account at a height
+func buildAccountData(eventClass *types.EventClass, acc *acm.Account, height uint64, chainID string,
+	state StateReader, logger *logging.Logger) (types.EventDataRow, error) {
+
+	row := make(map[string]interface{})
+	for _, mapping := range eventClass.FieldMappings {
+		var value interface{}
+		switch mapping.Field {
+		case types.ChainIDLabel:
+			value = chainID
+		case types.BlockHeightLabel:
+			value = strconv.FormatUint(height, 10)
+		case types.AccountAddressLabel:
+			value = acc.Address.String()
+		case types.AccountBalanceLabel:
+			value = strconv.FormatUint(acc.Balance, 10)
+		case types.AccountSequenceLabel:
+			value = strconv.FormatUint(acc.Sequence, 10)
+		case types.AccountPermissionsLabel:
+			value = strconv.FormatUint(uint64(acc.Permissions.Base.ResultantPerms()), 10)
+		case types.AccountRolesLabel:
+			value = strings.Join(acc.Permissions.Roles, ",")
+		case types.AccountCodeHashLabel:
+			if len(acc.CodeHash) > 0 {
+				value = []byte(acc.CodeHash)
+			}
+		default:
+			if mapping.Slot == "" {
+				continue
+			}
+			var err error
+			value, err = decodeStorageVariable(mapping, acc.Address, height, state, logger)
+			if err != nil {
+				return types.EventDataRow{}, errors.Wrapf(err, "could not decode storage variable %s of %v at height %d",
+					mapping.Field, acc.Address, height)
+			}
+		}
+		row[mapping.ColumnName] = value
+	}
+	return types.EventDataRow{Action: types.ActionUpsert, RowData: row, EventClass: eventClass}, nil
+}
+
+// buildRemovedAccountData builds a row recording that an account was removed at height if it matched before, which
+// deletes the row of the account unless the table keeps a row for every height
+func buildRemovedAccountData(eventClass *types.EventClass, qry query.Query,
+	address crypto.Address, height uint64, chainID string, state StateReader,
+	logger *logging.Logger) (types.EventDataRow, bool, error) {
+
+	prev, err := state.GetAccount(address, height-1)
+	if err != nil {
+		return types.EventDataRow{}, false, errors.Wrapf(err, "could not get account %v at height %d", address,
+			height-1)
+	}
+	if prev == nil || !qry.Matches(prev) {
+		return types.EventDataRow{}, false, nil
+	}
+	if eventClass.GetFieldMapping(types.ChainIDLabel).Primary {
+		// In log mode the row for this height has only its keys set
+		return types.EventDataRow{
+			Action: types.ActionUpsert,
+			RowData: map[string]interface{}{
+				eventClass.GetFieldMapping(types.ChainIDLabel).ColumnName:        chainID,
+				eventClass.GetFieldMapping(types.BlockHeightLabel).ColumnName:    strconv.FormatUint(height, 10),
+				eventClass.GetFieldMapping(types.AccountAddressLabel).ColumnName: address.String(),
+			},
+			EventClass: eventClass,
+		}, true, nil
+	}
+	// Build the row as it was so that we have whatever primary key columns were chosen
+	row, err := buildAccountData(eventClass, prev, height-1, chainID, state, logger)
+	if err != nil {
+		return types.EventDataRow{}, false, err
+	}
+	row.Action = types.ActionDelete
+	return row, true, nil
+}
+
+// decodeStorageVariable reads a storage variable laid out as solc does: value types are packed right-aligned into
+// their slot at their offset, while strings and bytes shorter than 32 bytes are stored left-aligned in their slot with
+// twice their length in its lowest byte, or otherwise from the slot keccak256(slot) with twice their length plus one
+// in their own slot
+func decodeStorageVariable(mapping *types.EventFieldMapping, address crypto.Address, height uint64,
+	state StateReader, logger *logging.Logger) (interface{}, error) {
+
+	slot, err := mapping.StorageSlot()
+	if err != nil {
+		return nil, err
+	}
+	size, err := types.StorageTypeSize(mapping.Type)
+	if err != nil {
+		return nil, err
+	}
+	value, err := state.GetStorage(address, slot, height)
+	if err != nil {
+		return nil, err
+	}
+	word := binary.LeftPadWord256(value)
+
+	evmType := strings.ToLower(mapping.Type)
+	if size == 0 {
+		bs, err := readStorageBytes(address, slot, word, height, state)
+		if err != nil {
+			return nil, err
+		}
+		if evmType == types.EventFieldTypeString || mapping.BytesToString {
+			return sanitiseBytesForString(bs, logger), nil
+		}
+		return bs, nil
+	}
+
+	end := binary.Word256Bytes - mapping.Offset
+	bs := word[end-size : end]
+	switch {
+	case evmType == types.EventFieldTypeBool:
+		return bs[0] != 0, nil
+	case evmType == types.EventFieldTypeAddress:
+		return crypto.MustAddressFromBytes(bs).String(), nil
+	case strings.HasPrefix(evmType, types.EventFieldTypeBytes):
+		if mapping.BytesToString {
+			return sanitiseBytesForString(bs, logger), nil
+		}
+		return append([]byte(nil), bs...), nil
+	case strings.HasPrefix(evmType, types.EventFieldTypeUInt):
+		return new(big.Int).SetBytes(bs).String(), nil
+	default:
+		return binary.FromTwosComplement(new(big.Int).SetBytes(bs), uint(size*8)).String(), nil
+	}
+}
+
+func readStorageBytes(address crypto.Address, slot, word binary.Word256, height uint64,
+	state StateReader) ([]byte, error) {
+
+	if word[binary.Word256Bytes-1]&1 == 0 {
+		length := int(word[binary.Word256Bytes-1] / 2)
+		if length >= binary.Word256Bytes {
+			return nil, fmt.Errorf("malformed short string or bytes with length %d", length)
+		}
+		return append([]byte(nil), word[:length]...), nil
+	}
+	length := new(big.Int).Rsh(new(big.Int).SetBytes(word[:]), 1)
+	if !length.IsInt64() || length.Int64() > maxStorageBytesLength {
+		return nil, fmt.Errorf("string or bytes with length %v exceeds the maximum of %d bytes", length,
+			maxStorageBytesLength)
+	}
+	n := int(length.Int64())
+	start := new(big.Int).SetBytes(crypto.Keccak256(slot[:]))
+	bs := make([]byte, 0, n+binary.Word256Bytes)
+	for i := int64(0); len(bs) < n; i++ {
+		key := binary.LeftPadWord256(binary.U256(new(big.Int).Add(start, big.NewInt(i))).Bytes())
+		value, err := state.GetStorage(address, key, height)
+		if err != nil {
+			return nil, err
+		}
+		chunk := binary.LeftPadWord256(value)
+		bs = append(bs, chunk[:]...)
+	}
+	return bs[:n], nil
+}
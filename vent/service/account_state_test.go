@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/burrow/acm"
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/event/query"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/sqlsol"
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmTypes "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestDecodeStorageVariable(t *testing.T) {
+	address := crypto.Address{1, 2, 3}
+	owner := crypto.Address{0xAB, 0xCD}
+	state := newTestState()
+
+	// owner (address) at offset 0, paused (bool) at offset 20, and delta (int16) at offset 21 share slot 0
+	var slot0 binary.Word256
+	copy(slot0[12:], owner[:])
+	slot0[11] = 1
+	copy(slot0[9:11], []byte{0xFF, 0xFE})
+	state.setStorage(address, binary.Zero256, slot0[:])
+
+	// A short string in slot 1
+	short := binary.RightPadWord256([]byte("frog"))
+	short[31] = 4 * 2
+	state.setStorage(address, binary.Int64ToWord256(1), short[:])
+
+	// A long string in slot 2, stored from keccak256(2)
+	long := strings.Repeat("toad", 20)
+	state.setStorage(address, binary.Int64ToWord256(2), binary.Int64ToWord256(int64(len(long)*2+1)).Bytes())
+	start := new(big.Int).SetBytes(crypto.Keccak256(binary.Int64ToWord256(2).Bytes()))
+	for i := 0; i*binary.Word256Bytes < len(long); i++ {
+		key := binary.LeftPadWord256(new(big.Int).Add(start, big.NewInt(int64(i))).Bytes())
+		end := (i + 1) * binary.Word256Bytes
+		if end > len(long) {
+			end = len(long)
+		}
+		word := binary.RightPadWord256([]byte(long[i*binary.Word256Bytes : end]))
+		state.setStorage(address, key, word[:])
+	}
+
+	logger := logging.NewNoopLogger()
+	for _, tc := range []struct {
+		mapping *types.EventFieldMapping
+		value   interface{}
+	}{
+		{&types.EventFieldMapping{Type: "address", Slot: "0"}, owner.String()},
+		{&types.EventFieldMapping{Type: "bool", Slot: "0", Offset: 20}, true},
+		{&types.EventFieldMapping{Type: "int16", Slot: "0", Offset: 21}, "-2"},
+		{&types.EventFieldMapping{Type: "uint16", Slot: "0", Offset: 21}, "65534"},
+		{&types.EventFieldMapping{Type: "bytes2", Slot: "0", Offset: 21}, []byte{0xFF, 0xFE}},
+		{&types.EventFieldMapping{Type: "uint256", Slot: "3"}, "0"},
+		{&types.EventFieldMapping{Type: "string", Slot: "1"}, "frog"},
+		{&types.EventFieldMapping{Type: "bytes", Slot: "1"}, []byte("frog")},
+		{&types.EventFieldMapping{Type: "bytes4", Slot: "1", Offset: 28, BytesToString: true}, "frog"},
+		{&types.EventFieldMapping{Type: "string", Slot: "0x2"}, long},
+	} {
+		value, err := decodeStorageVariable(tc.mapping, address, 1, state, logger)
+		require.NoError(t, err, tc.mapping.Type)
+		assert.Equal(t, tc.value, value, tc.mapping.Type)
+	}
+}
+
+func TestBlockConsumer_AccountState(t *testing.T) {
+	token := crypto.Address{1}
+	other := crypto.Address{2}
+	caller := crypto.Address{3}
+
+	state := newTestState()
+	state.setAccount(4, &acm.Account{Address: token, Balance: 10})
+	state.setAccount(4, &acm.Account{Address: caller, Balance: 100})
+	state.setStorage(token, binary.Zero256, binary.Int64ToWord256(7).Bytes())
+
+	projection, err := sqlsol.NewProjection(types.ProjectionSpec{
+		{
+			TableName:    "Tokens",
+			Filter:       "Balance < 50",
+			AccountState: true,
+			FieldMappings: []*types.EventFieldMapping{
+				{Field: types.AccountAddressLabel, Type: types.EventFieldTypeAddress, ColumnName: "address",
+					Primary: true},
+				{Field: types.AccountBalanceLabel, Type: "uint64", ColumnName: "balance"},
+				{Field: "supply", Type: "uint256", ColumnName: "supply", Slot: "0"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	doneCh := make(chan struct{})
+	eventCh := make(chan types.EventData, 100)
+	blockConsumer := NewBlockConsumer(projection, sqlsol.None, nil, nil, state, eventCh, doneCh,
+		logging.NewNoopLogger())
+
+	block := func(height uint64, callees ...crypto.Address) *exec.BlockExecution {
+		txe := &exec.TxExecution{TxHeader: &exec.TxHeader{Height: height}}
+		for _, callee := range callees {
+			require.NoError(t, txe.Call(&exec.CallEvent{CallData: &exec.CallData{Caller: caller, Callee: callee}},
+				nil))
+		}
+		blk := &exec.BlockExecution{Height: height, Header: &tmTypes.Header{ChainID: "frogs"}}
+		blk.AppendTxs(txe)
+		return blk
+	}
+	consume := func(blk *exec.BlockExecution) types.EventDataTable {
+		require.NoError(t, blockConsumer(blk))
+		return (<-eventCh).Tables["Tokens"]
+	}
+
+	// The first block snapshots every matching account even if it did not change
+	rows := consume(block(4))
+	require.Len(t, rows, 1)
+	assert.Equal(t, types.ActionUpsert, rows[0].Action)
+	assert.Equal(t, map[string]interface{}{
+		columns.ChainID: "frogs",
+		columns.Height:  "4",
+		"address":       token.String(),
+		"balance":       "10",
+		"supply":        "7",
+	}, rows[0].RowData)
+
+	// Subsequently only changed accounts matching the filter
+	state.setAccount(5, &acm.Account{Address: token, Balance: 20})
+	state.setAccount(5, &acm.Account{Address: other, Balance: 30})
+	state.setAccount(5, &acm.Account{Address: caller, Balance: 90})
+	rows = consume(block(5, token))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "20", rows[0].RowData["balance"])
+
+	rows = consume(block(6))
+	assert.Len(t, rows, 0)
+
+	// Removed accounts that matched are deleted
+	state.removeAccount(7, token)
+	rows = consume(block(7, token))
+	require.Len(t, rows, 1)
+	assert.Equal(t, types.ActionDelete, rows[0].Action)
+	assert.Equal(t, token.String(), rows[0].RowData["address"])
+}
+
+func TestChangedAccounts(t *testing.T) {
+	a, b, c, d, e := crypto.Address{4}, crypto.Address{3}, crypto.Address{2}, crypto.Address{1}, crypto.Address{5}
+	txe := &exec.TxExecution{TxHeader: &exec.TxHeader{}}
+	txe.Input(a, nil)
+	require.NoError(t, txe.Log(&exec.LogEvent{Address: b}))
+	nested := &exec.TxExecution{TxHeader: &exec.TxHeader{}, StateDiff: &exec.StateDiff{
+		Accounts: []*exec.AccountDiff{{Address: c, Removed: true}},
+	}}
+	txe.TxExecutions = append(txe.TxExecutions, nested)
+	other := &exec.TxExecution{TxHeader: &exec.TxHeader{}}
+	other.Output(d, nil)
+	other.Input(a, nil)
+	be := &exec.BlockExecution{
+		TxExecutions: []*exec.TxExecution{txe, other},
+		// As when fees are paid
+		StateDiff: &exec.StateDiff{
+			Accounts: []*exec.AccountDiff{{Address: e, Balance: &exec.BalanceDiff{Before: 1, After: 2}}},
+		},
+	}
+	assert.Equal(t, []crypto.Address{d, c, b, a, e}, changedAccounts(be))
+}
+
+// testState holds the history of accounts by the height from which each version is current, and storage independent
+// of height
+type testState struct {
+	accounts map[crypto.Address]map[uint64]*acm.Account
+	storage  map[crypto.Address]map[binary.Word256][]byte
+}
+
+func newTestState() *testState {
+	return &testState{
+		accounts: make(map[crypto.Address]map[uint64]*acm.Account),
+		storage:  make(map[crypto.Address]map[binary.Word256][]byte),
+	}
+}
+
+func (ts *testState) setAccount(height uint64, acc *acm.Account) {
+	if ts.accounts[acc.Address] == nil {
+		ts.accounts[acc.Address] = make(map[uint64]*acm.Account)
+	}
+	ts.accounts[acc.Address][height] = acc
+}
+
+func (ts *testState) removeAccount(height uint64, address crypto.Address) {
+	ts.accounts[address][height] = nil
+}
+
+func (ts *testState) setStorage(address crypto.Address, key binary.Word256, value []byte) {
+	if ts.storage[address] == nil {
+		ts.storage[address] = make(map[binary.Word256][]byte)
+	}
+	ts.storage[address][key] = value
+}
+
+func (ts *testState) GetAccount(address crypto.Address, height uint64) (*acm.Account, error) {
+	var acc *acm.Account
+	var latest uint64
+	for h, version := range ts.accounts[address] {
+		if h <= height && h >= latest {
+			acc, latest = version, h
+		}
+	}
+	return acc, nil
+}
+
+func (ts *testState) GetStorage(address crypto.Address, key binary.Word256, height uint64) ([]byte, error) {
+	return ts.storage[address][key], nil
+}
+
+func (ts *testState) ListAccounts(filter string, height uint64) ([]*acm.Account, error) {
+	qry, err := query.NewOrEmpty(filter)
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*acm.Account
+	for address := range ts.accounts {
+		acc, err := ts.GetAccount(address, height)
+		if err != nil {
+			return nil, err
+		}
+		if acc != nil && qry.Matches(acc) {
+			accounts = append(accounts, acc)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address[:], accounts[j].Address[:]) < 0
+	})
+	return accounts, nil
+}
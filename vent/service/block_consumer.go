@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"io"
 	"reflect"
 
@@ -12,14 +13,26 @@ import (
 	"github.com/hyperledger/burrow/vent/sqlsol"
 	"github.com/hyperledger/burrow/vent/types"
 	"github.com/pkg/errors"
+	hex "github.com/tmthrgd/go-hex"
 )
 
+// NewBlockConsumer returns a function that builds the rows for each block from its events and sends them on eventCh.
+// Account state is read from state, which may be nil if the projection has no AccountState classes.
 func NewBlockConsumer(projection *sqlsol.Projection, opt sqlsol.SpecOpt, getEventSpec EventSpecGetter,
-	eventCh chan<- types.EventData, doneCh chan struct{},
+	getFunctionSpec FunctionSpecGetter, state StateReader, eventCh chan<- types.EventData, doneCh chan struct{},
 	logger *logging.Logger) func(blockExecution *exec.BlockExecution) error {
 
 	logger = logger.WithScope("makeBlockConsumer")
 
+	// Accounts may have changed before the first block we consume so we snapshot them all then
+	snapshotted := false
+
+	// Only look for the ABIs of calls if we might project them
+	hasCallClasses := false
+	for _, eventClass := range projection.Spec {
+		hasCallClasses = hasCallClasses || eventClass.Calls
+	}
+
 	return func(blockExecution *exec.BlockExecution) error {
 		if finished(doneCh) {
 			return io.EOF
@@ -73,6 +86,15 @@ func NewBlockConsumer(projection *sqlsol.Projection, opt sqlsol.SpecOpt, getEven
 
 				// get events for a given transaction
 				for _, event := range txe.Events {
+					if event.Call != nil {
+						if hasCallClasses {
+							err := buildCallRows(blockData, projection, event, txOrigin, getFunctionSpec, logger)
+							if err != nil {
+								return err
+							}
+						}
+						continue
+					}
 					if event.Log == nil {
 						// Only EVM events are of interest
 						continue
@@ -93,6 +115,9 @@ func NewBlockConsumer(projection *sqlsol.Projection, opt sqlsol.SpecOpt, getEven
 
 					// see which spec filter matches with the one in event data
 					for _, eventClass := range projection.Spec {
+						if eventClass.AccountState || eventClass.Calls {
+							continue
+						}
 						qry, err := eventClass.Query()
 
 						if err != nil {
@@ -124,6 +149,13 @@ func NewBlockConsumer(projection *sqlsol.Projection, opt sqlsol.SpecOpt, getEven
 			}
 		}
 
+		// project the state of the accounts that changed in the block, or of all of them on the first block
+		err := buildAccountStateData(blockData, projection, blockExecution, !snapshotted, state, logger)
+		if err != nil {
+			return errors.Wrapf(err, "Error building account state data")
+		}
+		snapshotted = snapshotted || blockExecution.Height > 0
+
 		// upsert rows in specific SQL event tables and update block number
 		// store block data in SQL tables (if any)
 		for name, rows := range blockData.Data.Tables {
@@ -135,6 +167,71 @@ func NewBlockConsumer(projection *sqlsol.Projection, opt sqlsol.SpecOpt, getEven
 	}
 }
 
+// buildCallRows adds rows for the Calls classes whose filters match a call
+func buildCallRows(blockData *sqlsol.BlockData, projection *sqlsol.Projection, event *exec.Event,
+	txOrigin *exec.Origin, getFunctionSpec FunctionSpecGetter, logger *logging.Logger) error {
+
+	callData := event.Call.CallData
+	// The state changes of calls that failed were discarded so they are not of interest
+	if event.Header.Exception != nil || callData == nil {
+		return nil
+	}
+
+	var functionID abi.FunctionID
+	selector := ""
+	if len(callData.Data) >= abi.FunctionIDSize {
+		copy(functionID[:], callData.Data)
+		selector = hex.EncodeUpperToString(functionID[:])
+	}
+	callTags := query.TagMap{selectorKey: selector}
+	var tagged query.Tagged = query.TagsFor(event, event.Call, callData, callTags)
+	var funcSpec *abi.FunctionSpec
+	funcSpecErr := fmt.Errorf("call data has no function selector")
+	if selector != "" {
+		funcSpec, funcSpecErr = getFunctionSpec(functionID, callData.Callee)
+		if funcSpecErr != nil {
+			logger.TraceMsg("could not get ABI for function call",
+				structure.ErrorKey, funcSpecErr,
+				"selector", selector,
+				"address", callData.Callee)
+		} else {
+			// Since we have the function ABI we will allow matching on ABI fields
+			tagged = query.TagsFor(event, event.Call, callData, callTags, query.TaggedPrefix("Function", funcSpec))
+		}
+	}
+
+	for _, eventClass := range projection.Spec {
+		if !eventClass.Calls {
+			continue
+		}
+		qry, err := eventClass.Query()
+		if err != nil {
+			return errors.Wrapf(err, "Error parsing query from filter string")
+		}
+		if !qry.Matches(tagged) {
+			continue
+		}
+		if funcSpecErr != nil {
+			return errors.Wrapf(funcSpecErr, "could not get ABI for function call matching projection filter "+
+				"\"%s\" with selector %s at address %v", eventClass.Filter, selector, callData.Callee)
+		}
+
+		logger.InfoMsg("Matched call", "header", event.Header, "filter", eventClass.Filter)
+
+		// unpack, decode & build call data
+		callRow, err := buildCallData(projection, eventClass, event, txOrigin, funcSpec, logger)
+		if err != nil {
+			// Unlike events anyone can make a call with whatever data they like, so data that does not fit the ABI is
+			// no reason to stop consuming
+			logger.InfoMsg("Skipping call that could not be decoded", structure.ErrorKey, err,
+				"header", event.Header, "filter", eventClass.Filter)
+			continue
+		}
+		blockData.AddRow(eventClass.TableName, callRow)
+	}
+	return nil
+}
+
 type eventSpecTagged struct {
 	Event abi.EventSpec
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/errors"
 	"github.com/hyperledger/burrow/execution/evm/abi"
 	"github.com/hyperledger/burrow/execution/exec"
 	"github.com/hyperledger/burrow/execution/solidity"
@@ -78,7 +79,8 @@ func TestBlockConsumer(t *testing.T) {
 			},
 		})
 		require.NoError(t, err)
-		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, eventCh, doneCh, logger)
+		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, spec.GetFunctionAbi, nil,
+			eventCh, doneCh, logger)
 		tables, err := consumeBlock(blockConsumer, eventCh, log)
 		require.NoError(t, err)
 		rows := tables[tableName]
@@ -103,7 +105,8 @@ func TestBlockConsumer(t *testing.T) {
 			},
 		})
 		require.NoError(t, err)
-		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, eventCh, doneCh, logger)
+		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, spec.GetFunctionAbi, nil,
+			eventCh, doneCh, logger)
 		_, err = consumeBlock(blockConsumer, eventCh, log)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "could not find ABI")
@@ -126,7 +129,8 @@ func TestBlockConsumer(t *testing.T) {
 			},
 		})
 		require.NoError(t, err)
-		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, eventCh, doneCh, logger)
+		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, spec.GetFunctionAbi, nil,
+			eventCh, doneCh, logger)
 		table, err := consumeBlock(blockConsumer, eventCh, log)
 		require.Len(t, table, 0, "should match no event")
 	})
@@ -150,7 +154,8 @@ func TestBlockConsumer(t *testing.T) {
 		spec, err := abi.ReadSpec(solidity.Abi_EventEmitter)
 		require.NoError(t, err)
 
-		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, eventCh, doneCh, logger)
+		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, spec.GetFunctionAbi, nil,
+			eventCh, doneCh, logger)
 		table, err := consumeBlock(blockConsumer, eventCh, log)
 		// Check matches
 		require.NoError(t, err)
@@ -158,13 +163,104 @@ func TestBlockConsumer(t *testing.T) {
 		require.Len(t, table[tableName], 1)
 		// Now Remove the ABI - should not match the event
 		delete(spec.EventsByID, manyTypesEventSpec.ID)
-		blockConsumer = NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, eventCh, doneCh, logger)
+		blockConsumer = NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, spec.GetFunctionAbi, nil,
+			eventCh, doneCh, logger)
 		table, err = consumeBlock(blockConsumer, eventCh, log)
 		require.NoError(t, err)
 		require.Len(t, table, 0, "should match no events")
 	})
 }
 
+func TestBlockConsumer_Calls(t *testing.T) {
+	spec, err := abi.ReadSpec([]byte(`[{"type":"function","name":"transfer","inputs":[` +
+		`{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`))
+	require.NoError(t, err)
+	transfer := spec.Functions["transfer"]
+
+	token := crypto.Address{1}
+	caller := crypto.Address{2}
+	recipient := crypto.Address{3}
+	input, _, err := spec.Pack("transfer", recipient, uint64(5))
+	require.NoError(t, err)
+	ret, err := abi.Pack(transfer.Outputs, true)
+	require.NoError(t, err)
+
+	tableName := "Transfers"
+	projectionFor := func(filter string) *sqlsol.Projection {
+		projection, err := sqlsol.NewProjection(types.ProjectionSpec{
+			{
+				TableName: tableName,
+				Filter:    filter,
+				Calls:     true,
+				FieldMappings: []*types.EventFieldMapping{
+					{Field: types.CallerLabel, Type: types.EventFieldTypeAddress, ColumnName: "sender"},
+					{Field: "to", Type: types.EventFieldTypeAddress, ColumnName: "recipient"},
+					{Field: "amount", Type: "uint256", ColumnName: "amount"},
+					{Field: "return0", Type: types.EventFieldTypeBool, ColumnName: "ok"},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return projection
+	}
+	projection := projectionFor(fmt.Sprintf("Callee = '%v' AND Selector = '%X' AND FunctionName = 'transfer'",
+		token, input[:4]))
+
+	doneCh := make(chan struct{})
+	eventCh := make(chan types.EventData, 100)
+	consume := func(getFunctionSpec FunctionSpecGetter, exception *errors.Exception,
+		callees ...crypto.Address) (types.EventDataTable, error) {
+		blockConsumer := NewBlockConsumer(projection, sqlsol.None, spec.GetEventAbi, getFunctionSpec, nil,
+			eventCh, doneCh, logging.NewNoopLogger())
+		txe := &exec.TxExecution{TxHeader: &exec.TxHeader{}}
+		for _, callee := range callees {
+			err := txe.Call(&exec.CallEvent{
+				CallData: &exec.CallData{Caller: caller, Callee: callee, Data: input},
+				Return:   ret,
+			}, exception)
+			require.NoError(t, err)
+		}
+		block := &exec.BlockExecution{Header: &tmTypes.Header{}}
+		block.AppendTxs(txe)
+		err := blockConsumer(block)
+		if err != nil {
+			return nil, err
+		}
+		return (<-eventCh).Tables[tableName], nil
+	}
+
+	rows, err := consume(spec.GetFunctionAbi, nil, token, recipient)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, caller.String(), rows[0].RowData["sender"])
+	assert.Equal(t, recipient.String(), rows[0].RowData["recipient"])
+	assert.Equal(t, "5", rows[0].RowData["amount"])
+	assert.Equal(t, true, *rows[0].RowData["ok"].(*bool))
+	assert.Equal(t, "transfer", rows[0].RowData[columns.EventName])
+
+	// Calls that failed are ignored
+	rows, err = consume(spec.GetFunctionAbi, errors.Errorf(errors.Codes.ExecutionReverted, "no"), token)
+	require.NoError(t, err)
+	assert.Len(t, rows, 0)
+
+	// Without the ABI the filter cannot match on the function name
+	rows, err = consume(abi.NewSpec().GetFunctionAbi, nil, token)
+	require.NoError(t, err)
+	assert.Len(t, rows, 0)
+
+	// But it would still match on the selector alone, for which we need the ABI to decode the call
+	projection = projectionFor(fmt.Sprintf("Selector = '%X'", input[:4]))
+	_, err = consume(abi.NewSpec().GetFunctionAbi, nil, token)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not find ABI")
+
+	// Calls with data that does not fit the ABI are skipped rather than halting the consumer
+	input = input[:abi.FunctionIDSize+10]
+	rows, err = consume(spec.GetFunctionAbi, nil, token)
+	require.NoError(t, err)
+	assert.Len(t, rows, 0)
+}
+
 const timeout = time.Second
 
 var errTimeout = fmt.Errorf("timed out after %s waiting for consumer to emit block event", timeout)
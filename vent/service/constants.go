@@ -4,3 +4,6 @@ import "github.com/hyperledger/burrow/vent/types"
 
 var tables = types.DefaultSQLTableNames
 var columns = types.DefaultSQLColumnNames
+
+// The tag on which the filters of Calls classes can match the function selector
+const selectorKey = "Selector"
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"github.com/hyperledger/burrow/rpc/rpcevents"
 	"github.com/hyperledger/burrow/rpc/rpcquery"
 	"github.com/hyperledger/burrow/vent/config"
+	"github.com/hyperledger/burrow/vent/sink"
 	"github.com/hyperledger/burrow/vent/sqldb"
 	"github.com/hyperledger/burrow/vent/sqlsol"
 	"github.com/hyperledger/burrow/vent/types"
@@ -25,7 +25,8 @@ import (
 type Consumer struct {
 	Config         *config.VentConfig
 	Logger         *logging.Logger
-	DB             *sqldb.SQLDB
+	DB             *sqldb.SQLDB // Only set when the sink is SQL
+	Sink           sink.Sink
 	GRPCConnection *grpc.ClientConn
 	// external events channel used for when vent is leveraged as a library
 	EventsChannel chan types.EventData
@@ -54,7 +55,7 @@ func NewConsumer(cfg *config.VentConfig, log *logging.Logger, eventChannel chan
 
 // Run connects to a grpc service and subscribes to log events,
 // then gets tables structures, maps them & parse event data.
-// Store data in SQL event tables, it runs forever
+// Store data in SQL event tables (or the configured sink), it runs forever
 func (c *Consumer) Run(projection *sqlsol.Projection, stream bool) error {
 	var err error
 
@@ -84,31 +85,24 @@ func (c *Consumer) Run(projection *sqlsol.Projection, stream bool) error {
 		return nil
 	}
 
-	c.Logger.InfoMsg("Connecting to SQL database")
-
-	connection := types.SQLConnection{
-		DBAdapter: c.Config.DBAdapter,
-		DBURL:     c.Config.DBURL,
-		DBSchema:  c.Config.DBSchema,
-		Log:       c.Logger,
+	if c.Config.Sink.Type == sink.SQL {
+		c.Logger.InfoMsg("Connecting to SQL database")
+	} else {
+		c.Logger.InfoMsg("Connecting to sink", "sink_type", c.Config.Sink.Type, "sink_url", c.Config.Sink.URL)
 	}
 
-	c.DB, err = sqldb.NewSQLDB(connection)
+	c.Sink, err = sink.New(c.Config, c.Done, c.Logger)
 	if err != nil {
-		return fmt.Errorf("error connecting to SQL database: %v", err)
+		return err
 	}
-	defer c.DB.Close()
-
-	err = c.DB.Init(c.Burrow.ChainID, c.Burrow.BurrowVersion)
-	if err != nil {
-		return fmt.Errorf("could not clean tables after ChainID change: %v", err)
+	defer c.Sink.Close()
+	if sqlSink, ok := c.Sink.(sink.SQLSink); ok {
+		c.DB = sqlSink.SQLDB
 	}
 
-	c.Logger.InfoMsg("Synchronizing config and database projection structures")
-
-	err = c.DB.SynchronizeDB(c.Burrow.ChainID, projection.Tables)
+	err = c.Sink.Init(c.Burrow.ChainID, c.Burrow.BurrowVersion, projection.Tables)
 	if err != nil {
-		return errors.Wrap(err, "Error trying to synchronize database")
+		return err
 	}
 
 	// doneCh is used for sending a "done" signal from each goroutine to the main thread
@@ -122,7 +116,7 @@ func (c *Consumer) Run(projection *sqlsol.Projection, stream bool) error {
 		}()
 		go c.announceEvery(c.Done)
 
-		c.Logger.InfoMsg("Getting last processed block number from SQL log table")
+		c.Logger.InfoMsg("Getting last processed block number from sink")
 
 		// NOTE [Silas]: I am preserving the comment below that dates from the early days of Vent. I have looked at the
 		// bosmarmot git history and I cannot see why the original author thought that it was the case that there was
@@ -134,7 +128,7 @@ func (c *Consumer) Run(projection *sqlsol.Projection, stream bool) error {
 		// right now there is no way to know if the last block of events was completely read
 		// so we have to begin processing from the last block number stored in database
 		// and update event data if already present
-		fromBlock, err := c.DB.LastBlockHeight(c.Burrow.ChainID)
+		fromBlock, err := c.Sink.LastBlockHeight(c.Burrow.ChainID)
 		if err != nil {
 			errCh <- errors.Wrapf(err, "Error trying to get last processed block number")
 			return
@@ -172,7 +166,8 @@ func (c *Consumer) Run(projection *sqlsol.Projection, stream bool) error {
 		c.Logger.TraceMsg("Waiting for blocks...")
 
 		err = rpcevents.ConsumeBlockExecutions(stream,
-			NewBlockConsumer(projection, c.Config.SpecOpt, abiProvider.GetEventAbi, eventCh, c.Done, c.Logger))
+			NewBlockConsumer(projection, c.Config.SpecOpt, abiProvider.GetEventAbi, abiProvider.GetFunctionAbi,
+				NewStateReader(qCli), eventCh, c.Done, c.Logger))
 
 		if err != nil {
 			if err == io.EOF {
@@ -218,9 +213,9 @@ func (c *Consumer) Run(projection *sqlsol.Projection, stream bool) error {
 }
 
 func (c *Consumer) commitBlock(projection *sqlsol.Projection, blockEvents types.EventData) error {
-	// upsert rows in specific SQL event tables and update block number
-	if err := c.DB.SetBlock(c.Burrow.ChainID, projection.Tables, blockEvents); err != nil {
-		return fmt.Errorf("error upserting rows in database: %v", err)
+	// upsert rows in specific SQL event tables (or deliver them to the sink) and update block number
+	if err := c.Sink.SetBlock(c.Burrow.ChainID, projection.Tables, blockEvents); err != nil {
+		return err
 	}
 
 	// send to the external events channel in a non-blocking manner
@@ -237,12 +232,12 @@ func (c *Consumer) Health() error {
 		return errors.New("closing service")
 	}
 
-	// check db status
-	if c.DB == nil {
+	// check db (or other sink) status
+	if c.Sink == nil {
 		return errors.New("database disconnected")
 	}
 
-	if err := c.DB.Ping(); err != nil {
+	if err := c.Sink.Ping(); err != nil {
 		return errors.New("database unavailable")
 	}
 
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"math/big"
 	"strconv"
 
@@ -35,17 +36,71 @@ func decodeEvent(eventHeader *exec.Header, log *exec.LogEvent, txOrigin *exec.Or
 
 	// for each decoded item value, stores it in given item name
 	for i, input := range evAbi.Inputs {
-		switch v := unpackedData[i].(type) {
-		case *crypto.Address:
-			data[input.Name] = v.String()
-		case *big.Int:
-			data[input.Name] = v.String()
-		case *string:
-			data[input.Name] = *v
-		default:
-			data[input.Name] = v
+		data[input.Name] = decodedValue(unpackedData[i])
+	}
+
+	return data, nil
+}
+
+// decodeCall unpacks & decodes the arguments and return values of a call. Both are keyed by position as well as by
+// their names (if they have them and the name is not already taken)
+func decodeCall(eventHeader *exec.Header, call *exec.CallEvent, txOrigin *exec.Origin,
+	funcAbi *abi.FunctionSpec) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	data[types.EventNameLabel] = funcAbi.Name
+	data[types.ChainIDLabel] = txOrigin.ChainID
+	data[types.BlockHeightLabel] = strconv.FormatUint(txOrigin.GetHeight(), 10)
+	data[types.TxIndexLabel] = strconv.FormatUint(txOrigin.GetIndex(), 10)
+	data[types.EventIndexLabel] = strconv.FormatUint(eventHeader.GetIndex(), 10)
+	data[types.EventTypeLabel] = eventHeader.GetEventType().String()
+	data[types.TxTxHashLabel] = eventHeader.TxHash.String()
+	data[types.CallerLabel] = call.CallData.Caller.String()
+	data[types.CalleeLabel] = call.CallData.Callee.String()
+
+	input := call.CallData.Data
+	if len(input) < abi.FunctionIDSize {
+		return nil, fmt.Errorf("call data of %d bytes is too short to hold a function selector", len(input))
+	}
+	args := abi.GetPackingTypes(funcAbi.Inputs)
+	if err := abi.Unpack(funcAbi.Inputs, input[abi.FunctionIDSize:], args...); err != nil {
+		return nil, errors.Wrap(err, "Could not unpack call arguments")
+	}
+	for i, arg := range funcAbi.Inputs {
+		addDecodedValue(data, types.CallArgPrefix, i, arg.Name, decodedValue(args[i]))
+	}
+
+	// Return data is empty when the function returns nothing
+	if len(funcAbi.Outputs) > 0 && len(call.Return) > 0 {
+		returns := abi.GetPackingTypes(funcAbi.Outputs)
+		if err := abi.Unpack(funcAbi.Outputs, call.Return, returns...); err != nil {
+			return nil, errors.Wrap(err, "Could not unpack call return values")
+		}
+		for i, ret := range funcAbi.Outputs {
+			addDecodedValue(data, types.CallReturnPrefix, i, ret.Name, decodedValue(returns[i]))
 		}
 	}
 
 	return data, nil
 }
+
+func addDecodedValue(data map[string]interface{}, prefix string, i int, name string, value interface{}) {
+	data[prefix+strconv.Itoa(i)] = value
+	if _, ok := data[name]; name != "" && !ok {
+		data[name] = value
+	}
+}
+
+// decodedValue converts the values unpacked from ABI data into those we store
+func decodedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *crypto.Address:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	case *string:
+		return *v
+	default:
+		return v
+	}
+}
@@ -19,9 +19,6 @@ import (
 func buildEventData(projection *sqlsol.Projection, eventClass *types.EventClass, event *exec.Event,
 	txOrigin *exec.Origin, evAbi *abi.EventSpec, logger *logging.Logger) (types.EventDataRow, error) {
 
-	// a fresh new row to store column/value data
-	row := make(map[string]interface{})
-
 	// get header & log data for the given event
 	eventHeader := event.GetHeader()
 	eventLog := event.GetLog()
@@ -34,6 +31,31 @@ func buildEventData(projection *sqlsol.Projection, eventClass *types.EventClass,
 
 	logger.InfoMsg("Decoded event", decodedData)
 
+	return buildRow(projection, eventClass, decodedData, logger), nil
+}
+
+// buildCallData builds call data from transactions
+func buildCallData(projection *sqlsol.Projection, eventClass *types.EventClass, event *exec.Event,
+	txOrigin *exec.Origin, funcAbi *abi.FunctionSpec, logger *logging.Logger) (types.EventDataRow, error) {
+
+	// decode arguments and return values using the provided abi specification
+	decodedData, err := decodeCall(event.GetHeader(), event.GetCall(), txOrigin, funcAbi)
+	if err != nil {
+		return types.EventDataRow{}, errors.Wrapf(err, "Error decoding call (filter: %s)", eventClass.Filter)
+	}
+
+	logger.InfoMsg("Decoded call", decodedData)
+
+	return buildRow(projection, eventClass, decodedData, logger), nil
+}
+
+// buildRow maps decoded event or call data to the columns of the event class table
+func buildRow(projection *sqlsol.Projection, eventClass *types.EventClass, decodedData map[string]interface{},
+	logger *logging.Logger) types.EventDataRow {
+
+	// a fresh new row to store column/value data
+	row := make(map[string]interface{})
+
 	rowAction := types.ActionUpsert
 
 	// for each data element, maps to SQL columnName and gets its value
@@ -63,7 +85,7 @@ func buildEventData(projection *sqlsol.Projection, eventClass *types.EventClass,
 		}
 	}
 
-	return types.EventDataRow{Action: rowAction, RowData: row, EventClass: eventClass}, nil
+	return types.EventDataRow{Action: rowAction, RowData: row, EventClass: eventClass}
 }
 
 // buildBlkData builds block data from block stream
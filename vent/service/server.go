@@ -53,6 +53,11 @@ func (s *Server) Run() {
 	httpServer.Shutdown(context.Background())
 }
 
+// Handle serves requests for pattern with handler alongside the health check
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
 // ServeHTTP dispatches the HTTP requests using the Server Mux
 func (s *Server) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	s.mux.ServeHTTP(resp, req)
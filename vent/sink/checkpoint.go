@@ -0,0 +1,83 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// checkpoint records the last block a sink delivered
+type checkpoint struct {
+	ChainID       string
+	BurrowVersion string
+	Height        uint64
+	// Length of a JSON Lines file after the block was written
+	Offset int64 `json:",omitempty"`
+	path   string
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{path: path}
+	bs, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read sink checkpoint: %v", err)
+	}
+	err = json.Unmarshal(bs, cp)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode sink checkpoint %s: %v", path, err)
+	}
+	return cp, nil
+}
+
+// init starts the checkpoint afresh if it is from another chain, returning whether it did so
+func (cp *checkpoint) init(chainID, burrowVersion string) (bool, error) {
+	if cp.ChainID == chainID && cp.BurrowVersion == burrowVersion {
+		return false, nil
+	}
+	changed := cp.ChainID != chainID
+	if changed {
+		cp.Height = 0
+	}
+	cp.ChainID = chainID
+	cp.BurrowVersion = burrowVersion
+	return changed, cp.save()
+}
+
+func (cp *checkpoint) lastBlockHeight(chainID string) uint64 {
+	if cp.ChainID != chainID {
+		return 0
+	}
+	return cp.Height
+}
+
+// save replaces the checkpoint file so that it is never left partially written
+func (cp *checkpoint) save() error {
+	bs, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(cp.path), filepath.Base(cp.path)+".*")
+	if err != nil {
+		return fmt.Errorf("could not write sink checkpoint: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(bs)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), cp.path)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write sink checkpoint: %v", err)
+	}
+	return nil
+}
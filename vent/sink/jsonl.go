@@ -0,0 +1,103 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/types"
+)
+
+// JSONLSink appends a line for each row to a JSON Lines file. The checkpoint records the length of the file after each
+// block so that rows from a block that was not checkpointed can be truncated, rather than repeated, on restart.
+type JSONLSink struct {
+	file       *os.File
+	checkpoint *checkpoint
+	logger     *logging.Logger
+}
+
+var _ Sink = (*JSONLSink)(nil)
+
+func NewJSONLSink(path string, cp *checkpoint, logger *logging.Logger) (*JSONLSink, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open JSON Lines file: %v", err)
+	}
+	return &JSONLSink{
+		file:       file,
+		checkpoint: cp,
+		logger:     logger,
+	}, nil
+}
+
+func (s *JSONLSink) Init(chainID, burrowVersion string, tables types.EventTables) error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	changed, err := s.checkpoint.init(chainID, burrowVersion)
+	if err != nil {
+		return err
+	}
+	if changed {
+		// Keep the rows from the previous chain, which are labelled with its ChainID
+		s.checkpoint.Offset = size
+		return s.checkpoint.save()
+	}
+	if size < s.checkpoint.Offset {
+		return fmt.Errorf("JSON Lines file %s is shorter than its checkpoint (%d < %d bytes) so may have lost rows",
+			s.file.Name(), size, s.checkpoint.Offset)
+	}
+	if size > s.checkpoint.Offset {
+		s.logger.InfoMsg("Truncating rows written after last checkpoint",
+			"height", s.checkpoint.Height,
+			"offset", s.checkpoint.Offset)
+		return s.file.Truncate(s.checkpoint.Offset)
+	}
+	return nil
+}
+
+func (s *JSONLSink) LastBlockHeight(chainID string) (uint64, error) {
+	return s.checkpoint.lastBlockHeight(chainID), nil
+}
+
+func (s *JSONLSink) SetBlock(chainID string, tables types.EventTables, eventData types.EventData) error {
+	block := NewBlock(chainID, eventData)
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	for _, row := range block.Rows {
+		row.ChainID = block.ChainID
+		row.Height = block.Height
+		err := encoder.Encode(row)
+		if err != nil {
+			return fmt.Errorf("could not encode row for table %s at height %d: %v", row.Table, block.Height, err)
+		}
+	}
+	if buf.Len() > 0 {
+		_, err := s.file.Write(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("could not write to JSON Lines file: %v", err)
+		}
+		err = s.file.Sync()
+		if err != nil {
+			return err
+		}
+	}
+	s.checkpoint.Height = block.Height
+	s.checkpoint.Offset += int64(buf.Len())
+	return s.checkpoint.save()
+}
+
+func (s *JSONLSink) Ping() error {
+	_, err := s.file.Stat()
+	return err
+}
+
+func (s *JSONLSink) Close() {
+	if err := s.file.Close(); err != nil {
+		s.logger.InfoMsg("Error closing JSON Lines file", "err", err)
+	}
+}
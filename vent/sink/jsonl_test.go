@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chainID = "SinkChain"
+
+func TestJSONLSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vent-sink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rows.jsonl")
+
+	s := newTestJSONLSink(t, path)
+	require.NoError(t, s.SetBlock(chainID, nil, eventData(1, "frog", "toad")))
+	require.NoError(t, s.SetBlock(chainID, nil, types.EventData{BlockHeight: 2}))
+	// Write a block without checkpointing it, as though vent stopped part way through
+	_, err = s.file.WriteString(`{"Table":"Names","Action":"UPSERT","Data":{"name":"newt"}}` + "\n")
+	require.NoError(t, err)
+	s.Close()
+
+	s = newTestJSONLSink(t, path)
+	height, err := s.LastBlockHeight(chainID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), height)
+	require.NoError(t, s.SetBlock(chainID, nil, eventData(3, "newt")))
+	s.Close()
+
+	rows := readRows(t, path)
+	require.Len(t, rows, 3)
+	assert.Equal(t, Row{ChainID: chainID, Height: 1, Table: "Names", Action: types.ActionUpsert,
+		Data: map[string]interface{}{"name": "frog", "address": "0102"}}, rows[0])
+	assert.Equal(t, "toad", rows[1].Data["name"])
+	assert.Equal(t, uint64(3), rows[2].Height)
+
+	// A new chain starts from the beginning
+	s = newTestJSONLSink(t, path)
+	require.NoError(t, s.Init("OtherChain", "", nil))
+	height, err = s.LastBlockHeight("OtherChain")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), height)
+	s.Close()
+	assert.Len(t, readRows(t, path), 3)
+}
+
+func newTestJSONLSink(t *testing.T, path string) *JSONLSink {
+	cp, err := loadCheckpoint(path + ".checkpoint")
+	require.NoError(t, err)
+	s, err := NewJSONLSink(path, cp, logging.NewNoopLogger())
+	require.NoError(t, err)
+	require.NoError(t, s.Init(chainID, "", nil))
+	return s
+}
+
+func eventData(height uint64, names ...string) types.EventData {
+	var table types.EventDataTable
+	for _, name := range names {
+		table = append(table, types.EventDataRow{
+			Action:  types.ActionUpsert,
+			RowData: map[string]interface{}{"name": name, "address": []byte{1, 2}},
+		})
+	}
+	return types.EventData{
+		BlockHeight: height,
+		Tables:      map[string]types.EventDataTable{"Names": table},
+	}
+}
+
+func readRows(t *testing.T, path string) []Row {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var rows []Row
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		row := Row{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+		rows = append(rows, row)
+	}
+	require.NoError(t, scanner.Err())
+	return rows
+}
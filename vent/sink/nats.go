@@ -0,0 +1,247 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/types"
+)
+
+const (
+	natsDefaultPort = "4222"
+	natsTimeout     = 10 * time.Second
+)
+
+// NATSSink publishes each block with projected rows to a NATS subject. It speaks just enough of the NATS client
+// protocol (https://docs.nats.io/reference/reference-protocols/nats-protocol) to publish, following each message with
+// a PING so that a PONG confirms the server has processed it.
+type NATSSink struct {
+	// Guards the connection, which is checked by Ping
+	sync.Mutex
+	address    string
+	subject    string
+	connect    natsConnect
+	conn       net.Conn
+	reader     *bufio.Reader
+	maxPayload int
+	checkpoint *checkpoint
+	retry      *retrier
+	logger     *logging.Logger
+}
+
+var _ Sink = (*NATSSink)(nil)
+
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+type natsInfo struct {
+	MaxPayload int `json:"max_payload"`
+}
+
+// NewNATSSink publishes to subject on the server at serverURL, of the form nats://[user:pass@|token@]host[:port]
+func NewNATSSink(serverURL, subject string, cp *checkpoint, retry *retrier, logger *logging.Logger) (*NATSSink, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("could not parse NATS server URL '%s', expected nats://host:port", serverURL)
+	}
+	if u.Scheme != "nats" {
+		return nil, fmt.Errorf("NATS server URL scheme must be nats but is '%s'", u.Scheme)
+	}
+	if strings.ContainsAny(subject, " \t\r\n") || subject == "" {
+		return nil, fmt.Errorf("invalid NATS subject '%s'", subject)
+	}
+	s := &NATSSink{
+		address: u.Host,
+		subject: subject,
+		connect: natsConnect{
+			Name: "vent",
+			Lang: "go",
+		},
+		checkpoint: cp,
+		retry:      retry,
+		logger:     logger,
+	}
+	if u.Port() == "" {
+		s.address = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			s.connect.User = u.User.Username()
+			s.connect.Pass = pass
+		} else {
+			s.connect.Token = u.User.Username()
+		}
+	}
+	return s, nil
+}
+
+func (s *NATSSink) Init(chainID, burrowVersion string, tables types.EventTables) error {
+	_, err := s.checkpoint.init(chainID, burrowVersion)
+	if err != nil {
+		return err
+	}
+	return s.retry.do(s.checkpoint.Height, func() error {
+		s.Lock()
+		defer s.Unlock()
+		return s.dial()
+	})
+}
+
+func (s *NATSSink) LastBlockHeight(chainID string) (uint64, error) {
+	return s.checkpoint.lastBlockHeight(chainID), nil
+}
+
+func (s *NATSSink) SetBlock(chainID string, tables types.EventTables, eventData types.EventData) error {
+	block := NewBlock(chainID, eventData)
+	if len(block.Rows) > 0 {
+		payload, err := json.Marshal(block)
+		if err != nil {
+			return fmt.Errorf("could not encode block %d: %v", block.Height, err)
+		}
+		err = s.retry.do(block.Height, func() error {
+			s.Lock()
+			defer s.Unlock()
+			return s.publish(payload)
+		})
+		if err != nil {
+			return fmt.Errorf("could not publish block %d to NATS: %v", block.Height, err)
+		}
+	}
+	s.checkpoint.Height = block.Height
+	return s.checkpoint.save()
+}
+
+func (s *NATSSink) dial() error {
+	conn, err := net.DialTimeout("tcp", s.address, natsTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+	err = s.handshake()
+	if err != nil {
+		s.hangUp()
+		return err
+	}
+	return nil
+}
+
+func (s *NATSSink) handshake() error {
+	err := s.conn.SetDeadline(time.Now().Add(natsTimeout))
+	if err != nil {
+		return err
+	}
+	line, err := s.readLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("expected INFO from NATS server but got: %s", line)
+	}
+	info := new(natsInfo)
+	err = json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), info)
+	if err != nil {
+		return fmt.Errorf("could not decode INFO from NATS server: %v", err)
+	}
+	s.maxPayload = info.MaxPayload
+	connect, err := json.Marshal(s.connect)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.conn, "CONNECT %s\r\nPING\r\n", connect)
+	if err != nil {
+		return err
+	}
+	return s.awaitPong()
+}
+
+func (s *NATSSink) publish(payload []byte) error {
+	if s.maxPayload > 0 && len(payload) > s.maxPayload {
+		return permanentError{fmt.Errorf("block is %d bytes but NATS server accepts at most %d",
+			len(payload), s.maxPayload)}
+	}
+	if s.conn == nil {
+		err := s.dial()
+		if err != nil {
+			return err
+		}
+	}
+	err := s.conn.SetDeadline(time.Now().Add(natsTimeout))
+	if err == nil {
+		_, err = fmt.Fprintf(s.conn, "PUB %s %d\r\n%s\r\nPING\r\n", s.subject, len(payload), payload)
+	}
+	if err == nil {
+		err = s.awaitPong()
+	}
+	if err != nil {
+		// Start again with a fresh connection
+		s.hangUp()
+		return err
+	}
+	return nil
+}
+
+func (s *NATSSink) awaitPong() error {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			_, err = fmt.Fprint(s.conn, "PONG\r\n")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS server error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// Ignore +OK and updated INFO
+	}
+}
+
+func (s *NATSSink) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *NATSSink) hangUp() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *NATSSink) Ping() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.conn == nil {
+		return fmt.Errorf("not connected to NATS server at %s", s.address)
+	}
+	return nil
+}
+
+func (s *NATSSink) Close() {
+	s.Lock()
+	defer s.Unlock()
+	s.hangUp()
+}
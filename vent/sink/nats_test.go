@@ -0,0 +1,91 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	published := make(chan string, 1)
+	go serveNATS(listener, published)
+
+	cp, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+	s, err := NewNATSSink("nats://user:pass@"+listener.Addr().String(), "vent.rows", cp,
+		newRetrier(1, 0, nil, logging.NewNoopLogger()), logging.NewNoopLogger())
+	require.NoError(t, err)
+	require.NoError(t, s.Init(chainID, "", nil))
+	defer s.Close()
+	require.NoError(t, s.Ping())
+
+	require.NoError(t, s.SetBlock(chainID, nil, eventData(4, "frog")))
+	block := new(Block)
+	require.NoError(t, json.Unmarshal([]byte(<-published), block))
+	assert.Equal(t, uint64(4), block.Height)
+	assert.Equal(t, "frog", block.Rows[0].Data["name"])
+	assert.Equal(t, uint64(4), cp.lastBlockHeight(chainID))
+
+	// The server refuses anything over its max_payload
+	require.Error(t, s.SetBlock(chainID, nil, eventData(5, strings.Repeat("frog", 64))))
+	assert.Equal(t, uint64(4), cp.lastBlockHeight(chainID))
+}
+
+func TestNewNATSSink(t *testing.T) {
+	s, err := NewNATSSink("nats://s3cr3t@localhost", "vent", nil, nil, logging.NewNoopLogger())
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:4222", s.address)
+	assert.Equal(t, "s3cr3t", s.connect.Token)
+
+	_, err = NewNATSSink("localhost:4222", "vent", nil, nil, logging.NewNoopLogger())
+	assert.Error(t, err)
+	_, err = NewNATSSink("nats://localhost:4222", "vent rows", nil, nil, logging.NewNoopLogger())
+	assert.Error(t, err)
+}
+
+// Serves just enough of the NATS protocol for a single client to publish
+func serveNATS(listener net.Listener, published chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":200}\r\n")
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "CONNECT":
+			if !strings.Contains(line, `"user":"user","pass":"pass"`) {
+				fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "PUB":
+			var size int
+			fmt.Sscan(fields[2], &size)
+			payload := make([]byte, size+2)
+			_, err = io.ReadFull(reader, payload)
+			if err != nil {
+				return
+			}
+			published <- string(payload[:size])
+		}
+	}
+}
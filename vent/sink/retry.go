@@ -0,0 +1,61 @@
+package sink
+
+import (
+	"time"
+
+	"github.com/hyperledger/burrow/logging"
+)
+
+const maxRetryBackoff = time.Minute
+
+// retrier repeats failed deliveries with exponential backoff until they succeed, the retries run out, or done is closed
+type retrier struct {
+	retries int
+	backoff time.Duration
+	done    <-chan struct{}
+	logger  *logging.Logger
+}
+
+func newRetrier(retries int, backoff time.Duration, done <-chan struct{}, logger *logging.Logger) *retrier {
+	return &retrier{
+		retries: retries,
+		backoff: backoff,
+		done:    done,
+		logger:  logger,
+	}
+}
+
+// permanentError is not worth retrying
+type permanentError struct {
+	error
+}
+
+func (r *retrier) do(height uint64, deliver func() error) error {
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		err := deliver()
+		if err == nil {
+			return nil
+		}
+		if perr, ok := err.(permanentError); ok {
+			return perr.error
+		}
+		if r.retries >= 0 && attempt >= r.retries {
+			return err
+		}
+		r.logger.InfoMsg("Could not deliver block, retrying",
+			"height", height,
+			"attempt", attempt+1,
+			"retry_in", backoff,
+			"err", err)
+		select {
+		case <-time.After(backoff):
+		case <-r.done:
+			return err
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
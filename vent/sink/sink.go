@@ -0,0 +1,149 @@
+// Package sink delivers the rows vent projects from each block to the SQL database or, in place of it, to a JSON Lines
+// file, an HTTP webhook, or a message broker. Sinks other than SQL record the last block delivered in a checkpoint
+// file that is only written once a block has been accepted, so after a restart delivery resumes from that block and
+// any block in flight is delivered again.
+package sink
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/burrow/binary"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/config"
+	"github.com/hyperledger/burrow/vent/types"
+)
+
+// Sink types selectable in config.SinkConfig
+const (
+	SQL     = ""
+	JSONL   = "jsonl"
+	Webhook = "webhook"
+	Kafka   = "kafka"
+	NATS    = "nats"
+)
+
+const defaultCheckpoint = "vent.checkpoint"
+
+// Sink receives the rows projected from each block
+type Sink interface {
+	// Init prepares the sink for the tables projected from chainID, forgetting what it was sent from any other chain
+	Init(chainID, burrowVersion string, tables types.EventTables) error
+	// LastBlockHeight returns the height of the last block delivered from chainID
+	LastBlockHeight(chainID string) (uint64, error)
+	// SetBlock returns once the rows projected from a block have been delivered
+	SetBlock(chainID string, tables types.EventTables, eventData types.EventData) error
+	Ping() error
+	Close()
+}
+
+// New returns the sink selected by cfg, which gives up retrying deliveries once done is closed
+func New(cfg *config.VentConfig, done <-chan struct{}, logger *logging.Logger) (Sink, error) {
+	sinkCfg := cfg.Sink
+	if sinkCfg.Type == SQL {
+		s, err := NewSQLSink(types.SQLConnection{
+			DBAdapter: cfg.DBAdapter,
+			DBURL:     cfg.DBURL,
+			DBSchema:  cfg.DBSchema,
+			Log:       logger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if sinkCfg.URL == "" {
+		return nil, fmt.Errorf("%s sink requires a URL", sinkCfg.Type)
+	}
+	checkpointPath := sinkCfg.Checkpoint
+	if checkpointPath == "" {
+		checkpointPath = defaultCheckpoint
+		if sinkCfg.Type == JSONL {
+			checkpointPath = sinkCfg.URL + ".checkpoint"
+		}
+	}
+	cp, err := loadCheckpoint(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	logger = logger.WithScope("Sink").With("sink_type", sinkCfg.Type)
+	retry := newRetrier(sinkCfg.Retries, sinkCfg.RetryBackoff, done, logger)
+
+	switch sinkCfg.Type {
+	case JSONL:
+		s, err := NewJSONLSink(sinkCfg.URL, cp, logger)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case Webhook:
+		return NewWebhookSink(sinkCfg.URL, cp, retry, logger), nil
+	case Kafka:
+		return NewKafkaSink(sinkCfg.URL, sinkCfg.Topic, cp, retry, logger), nil
+	case NATS:
+		s, err := NewNATSSink(sinkCfg.URL, sinkCfg.Topic, cp, retry, logger)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type '%s', expected one of '%s', '%s', '%s', or '%s'",
+			sinkCfg.Type, JSONL, Webhook, Kafka, NATS)
+	}
+}
+
+// Block is the message sent to webhooks and brokers for each block with projected rows
+type Block struct {
+	ChainID string
+	Height  uint64
+	Rows    []Row
+}
+
+// Row is a row projected into a table, JSON Lines files contain one per line
+type Row struct {
+	ChainID string `json:",omitempty"`
+	Height  uint64 `json:",omitempty"`
+	Table   string
+	Action  types.DBAction
+	Data    map[string]interface{}
+}
+
+// NewBlock collects the rows in eventData ordered by table name
+func NewBlock(chainID string, eventData types.EventData) *Block {
+	tableNames := make([]string, 0, len(eventData.Tables))
+	for name := range eventData.Tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+	block := &Block{
+		ChainID: chainID,
+		Height:  eventData.BlockHeight,
+	}
+	for _, name := range tableNames {
+		for _, row := range eventData.Tables[name] {
+			block.Rows = append(block.Rows, Row{
+				Table:  name,
+				Action: row.Action,
+				Data:   rowData(row.RowData),
+			})
+		}
+	}
+	return block
+}
+
+// Bytes are hex encoded in line with the rest of Burrow's JSON
+func rowData(data map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data))
+	for column, value := range data {
+		switch v := value.(type) {
+		case []byte:
+			value = binary.HexBytes(v)
+		case *[]byte:
+			if v != nil {
+				value = binary.HexBytes(*v)
+			}
+		}
+		out[column] = value
+	}
+	return out
+}
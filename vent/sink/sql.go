@@ -0,0 +1,45 @@
+package sink
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/burrow/vent/sqldb"
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/pkg/errors"
+)
+
+// SQLSink stores rows in the SQL database, where the _vent_chain table takes the place of a checkpoint
+type SQLSink struct {
+	*sqldb.SQLDB
+}
+
+var _ Sink = SQLSink{}
+
+func NewSQLSink(connection types.SQLConnection) (SQLSink, error) {
+	db, err := sqldb.NewSQLDB(connection)
+	if err != nil {
+		return SQLSink{}, fmt.Errorf("error connecting to SQL database: %v", err)
+	}
+	return SQLSink{SQLDB: db}, nil
+}
+
+// Init cleans the tables if chainID has changed then synchronises them with the projection
+func (s SQLSink) Init(chainID, burrowVersion string, tables types.EventTables) error {
+	err := s.SQLDB.Init(chainID, burrowVersion)
+	if err != nil {
+		return fmt.Errorf("could not clean tables after ChainID change: %v", err)
+	}
+	s.Log.InfoMsg("Synchronizing config and database projection structures")
+	err = s.SynchronizeDB(chainID, tables)
+	if err != nil {
+		return errors.Wrap(err, "Error trying to synchronize database")
+	}
+	return nil
+}
+
+func (s SQLSink) SetBlock(chainID string, tables types.EventTables, eventData types.EventData) error {
+	if err := s.SQLDB.SetBlock(chainID, tables, eventData); err != nil {
+		return fmt.Errorf("error upserting rows in database: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/types"
+)
+
+const (
+	webhookTimeout = 30 * time.Second
+	// Identifies the block so that receivers can discard deliveries they have already seen
+	IdempotencyKeyHeader = "Idempotency-Key"
+	kafkaContentType     = "application/vnd.kafka.json.v2+json"
+)
+
+// WebhookSink POSTs each block with projected rows as JSON to a URL, retrying until it receives a 2xx response
+type WebhookSink struct {
+	url         string
+	contentType string
+	encode      func(block *Block) ([]byte, error)
+	// Checks a successful response for errors reported in its body
+	check      func(body []byte) error
+	client     *http.Client
+	checkpoint *checkpoint
+	retry      *retrier
+	logger     *logging.Logger
+}
+
+var _ Sink = (*WebhookSink)(nil)
+
+func NewWebhookSink(url string, cp *checkpoint, retry *retrier, logger *logging.Logger) *WebhookSink {
+	return &WebhookSink{
+		url:         url,
+		contentType: "application/json",
+		encode: func(block *Block) ([]byte, error) {
+			return json.Marshal(block)
+		},
+		client:     &http.Client{Timeout: webhookTimeout},
+		checkpoint: cp,
+		retry:      retry,
+		logger:     logger,
+	}
+}
+
+// NewKafkaSink produces a record for each block to topic through a Kafka REST proxy
+// (https://docs.confluent.io/platform/current/kafka-rest/api.html) keyed by ChainID and height
+func NewKafkaSink(proxyURL, topic string, cp *checkpoint, retry *retrier, logger *logging.Logger) *WebhookSink {
+	s := NewWebhookSink(strings.TrimRight(proxyURL, "/")+"/topics/"+topic, cp, retry, logger)
+	s.contentType = kafkaContentType
+	s.encode = func(block *Block) ([]byte, error) {
+		return json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: blockKey(block), Value: block}}})
+	}
+	s.check = func(body []byte) error {
+		response := new(kafkaResponse)
+		err := json.Unmarshal(body, response)
+		if err != nil {
+			return fmt.Errorf("could not decode Kafka REST proxy response: %v", err)
+		}
+		for _, offset := range response.Offsets {
+			if offset.Error != "" {
+				return fmt.Errorf("could not produce to Kafka: %s", offset.Error)
+			}
+		}
+		return nil
+	}
+	return s
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value *Block `json:"value"`
+}
+
+type kafkaResponse struct {
+	Offsets []struct {
+		Error string `json:"error"`
+	} `json:"offsets"`
+}
+
+func blockKey(block *Block) string {
+	return fmt.Sprintf("%s/%d", block.ChainID, block.Height)
+}
+
+func (s *WebhookSink) Init(chainID, burrowVersion string, tables types.EventTables) error {
+	_, err := s.checkpoint.init(chainID, burrowVersion)
+	return err
+}
+
+func (s *WebhookSink) LastBlockHeight(chainID string) (uint64, error) {
+	return s.checkpoint.lastBlockHeight(chainID), nil
+}
+
+func (s *WebhookSink) SetBlock(chainID string, tables types.EventTables, eventData types.EventData) error {
+	block := NewBlock(chainID, eventData)
+	if len(block.Rows) > 0 {
+		body, err := s.encode(block)
+		if err != nil {
+			return fmt.Errorf("could not encode block %d: %v", block.Height, err)
+		}
+		err = s.retry.do(block.Height, func() error {
+			return s.post(blockKey(block), body)
+		})
+		if err != nil {
+			return fmt.Errorf("could not deliver block %d to %s: %v", block.Height, s.url, err)
+		}
+	}
+	s.checkpoint.Height = block.Height
+	return s.checkpoint.save()
+}
+
+func (s *WebhookSink) post(key string, body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	request.Header.Set("Content-Type", s.contentType)
+	request.Header.Set(IdempotencyKeyHeader, key)
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode/100 != 2 {
+		err = fmt.Errorf("received %s: %s", response.Status, bytes.TrimSpace(responseBody))
+		// Other client errors will not go away by themselves
+		if response.StatusCode/100 == 4 && response.StatusCode != http.StatusRequestTimeout &&
+			response.StatusCode != http.StatusTooManyRequests {
+			return permanentError{err}
+		}
+		return err
+	}
+	if s.check != nil {
+		return s.check(responseBody)
+	}
+	return nil
+}
+
+func (s *WebhookSink) Ping() error {
+	return nil
+}
+
+func (s *WebhookSink) Close() {
+}
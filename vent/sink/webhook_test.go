@@ -0,0 +1,101 @@
+package sink
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink(t *testing.T) {
+	var received []*Block
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "SinkChain/1", r.Header.Get(IdempotencyKeyHeader))
+		block := new(Block)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(block))
+		received = append(received, block)
+	}))
+	defer server.Close()
+
+	cp, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+	s := NewWebhookSink(server.URL, cp, newRetrier(2, 0, nil, logging.NewNoopLogger()), logging.NewNoopLogger())
+	require.NoError(t, s.Init(chainID, "", nil))
+	require.NoError(t, s.SetBlock(chainID, nil, eventData(1, "frog")))
+	// Blocks without rows are not sent
+	require.NoError(t, s.SetBlock(chainID, nil, types.EventData{BlockHeight: 2}))
+
+	require.Len(t, received, 1)
+	assert.Equal(t, uint64(1), received[0].Height)
+	assert.Equal(t, "frog", received[0].Rows[0].Data["name"])
+	cp, err := loadCheckpoint(cp.path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), cp.lastBlockHeight(chainID))
+}
+
+func TestWebhookSink_Failure(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	cp, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+	s := NewWebhookSink(server.URL, cp, newRetrier(-1, 0, nil, logging.NewNoopLogger()), logging.NewNoopLogger())
+	require.NoError(t, s.Init(chainID, "", nil))
+	// Client errors are not retried and the block is not checkpointed
+	require.Error(t, s.SetBlock(chainID, nil, eventData(5, "frog")))
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, uint64(0), cp.lastBlockHeight(chainID))
+}
+
+func TestKafkaSink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/vent", r.URL.Path)
+		assert.Equal(t, kafkaContentType, r.Header.Get("Content-Type"))
+		records := new(kafkaRecords)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(records))
+		require.Len(t, records.Records, 1)
+		if records.Records[0].Value.Height == 2 {
+			w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"no leader"}]}`))
+			return
+		}
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":7}]}`))
+	}))
+	defer server.Close()
+
+	cp, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+	s := NewKafkaSink(server.URL+"/", "vent", cp, newRetrier(1, 0, nil, logging.NewNoopLogger()),
+		logging.NewNoopLogger())
+	require.NoError(t, s.Init(chainID, "", nil))
+	require.NoError(t, s.SetBlock(chainID, nil, eventData(1, "frog")))
+	require.Error(t, s.SetBlock(chainID, nil, eventData(2, "toad")))
+	assert.Equal(t, uint64(1), cp.lastBlockHeight(chainID))
+}
+
+func newTestCheckpoint(t *testing.T) (*checkpoint, func()) {
+	dir, err := ioutil.TempDir("", "vent-sink")
+	require.NoError(t, err)
+	cp, err := loadCheckpoint(filepath.Join(dir, defaultCheckpoint))
+	require.NoError(t, err)
+	return cp, func() {
+		os.RemoveAll(dir)
+	}
+}
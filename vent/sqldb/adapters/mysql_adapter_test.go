@@ -0,0 +1,47 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/vent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLAdapter_UpsertQuery(t *testing.T) {
+	ma := NewMySQLAdapter("vent", types.DefaultSQLNames, logging.NewNoopLogger())
+	table := &types.SQLTable{
+		Name: "Names",
+		Columns: []*types.SQLTableColumn{
+			{Name: "name", Type: types.SQLColumnTypeVarchar, Length: 100, Primary: true},
+			{Name: "owner", Type: types.SQLColumnTypeVarchar, Length: 100},
+		},
+	}
+	query, _, err := ma.UpsertQuery(table, types.EventDataRow{RowData: map[string]interface{}{
+		"name":  "frog",
+		"owner": "toad",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `vent`.`Names` (`name`, `owner`) VALUES (?, ?) "+
+		"ON DUPLICATE KEY UPDATE `owner` = VALUES(`owner`);", query.Query)
+
+	query, _, err = ma.UpsertQuery(table, types.EventDataRow{RowData: map[string]interface{}{"name": "frog"}})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `vent`.`Names` (`name`, `owner`) VALUES (?, ?) "+
+		"ON DUPLICATE KEY UPDATE `name` = `name`;", query.Query)
+
+	_, _, err = ma.UpsertQuery(table, types.EventDataRow{RowData: map[string]interface{}{"owner": "toad"}})
+	assert.Error(t, err)
+}
+
+func TestMySQLAdapter_ColumnType(t *testing.T) {
+	ma := NewMySQLAdapter("", types.DefaultSQLNames, logging.NewNoopLogger())
+	// Key columns must have a bounded length
+	assert.Equal(t, "VARCHAR(255)", ma.columnType(types.SQLColumnTypeVarchar, 0, true))
+	assert.Equal(t, "TEXT", ma.columnType(types.SQLColumnTypeVarchar, 0, false))
+	assert.Equal(t, "VARCHAR(100)", ma.columnType(types.SQLColumnTypeVarchar, 100, false))
+	// uint256 does not fit so is truncated to the maximum precision
+	assert.Equal(t, "DECIMAL(65,0)", ma.columnType(types.SQLColumnTypeNumeric, 78, false))
+	assert.Equal(t, "`Names`", ma.SchemaName("Names"))
+}
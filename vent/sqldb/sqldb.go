@@ -46,6 +46,9 @@ func NewSQLDB(connection types.SQLConnection) (*SQLDB, error) {
 
 	case types.SQLiteDB:
 		db.DBAdapter = adapters.NewSQLiteAdapter(db.SQLNames, connection.Log)
+
+	case types.MySQLDB:
+		db.DBAdapter = adapters.NewMySQLAdapter(safe(connection.DBSchema), db.SQLNames, connection.Log)
 	default:
 		return nil, errors.New("invalid database adapter")
 	}
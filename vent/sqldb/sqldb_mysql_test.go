@@ -0,0 +1,25 @@
+// +build integration,mysql
+
+package sqldb_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/vent/test"
+)
+
+func TestMySQLSynchronizeDB(t *testing.T) {
+	testSynchronizeDB(t, test.MySQLVentConfig(""))
+}
+
+func TestMySQLCleanDB(t *testing.T) {
+	testCleanDB(t, test.MySQLVentConfig(""))
+}
+
+func TestMySQLSetBlock(t *testing.T) {
+	testSetBlock(t, test.MySQLVentConfig(""))
+}
+
+func TestMySQLRestore(t *testing.T) {
+	testRestore(t, test.MySQLVentConfig(""))
+}
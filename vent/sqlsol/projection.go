@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/vent/types"
 	"github.com/pkg/errors"
 	"github.com/xeipuuv/gojsonschema"
@@ -94,6 +95,15 @@ func NewProjection(spec types.ProjectionSpec) (*Projection, error) {
 			return nil, fmt.Errorf("validation error on %v: %v", eventClass, err)
 		}
 
+		if eventClass.AccountState {
+			if eventClass.Calls {
+				return nil, fmt.Errorf("EventClass %s cannot project both AccountState and Calls", eventClass.TableName)
+			}
+			if err := validateAccountClass(eventClass); err != nil {
+				return nil, err
+			}
+		}
+
 		// build columns mapping
 		var columns []*types.SQLTableColumn
 		channels := make(map[string][]string)
@@ -112,9 +122,20 @@ func NewProjection(spec types.ProjectionSpec) (*Projection, error) {
 		}
 
 		// Add the global mappings
-		if primary {
+		switch {
+		case eventClass.AccountState:
+			if !primary {
+				// Keep a row for each address at each height it changed
+				for _, mapping := range eventClass.FieldMappings {
+					if mapping.Field == types.AccountAddressLabel {
+						mapping.Primary = true
+					}
+				}
+			}
+			eventClass.FieldMappings = append(getGlobalFieldMappingsAccountState(!primary), eventClass.FieldMappings...)
+		case primary:
 			eventClass.FieldMappings = append(getGlobalFieldMappings(), eventClass.FieldMappings...)
-		} else {
+		default:
 			eventClass.FieldMappings = append(getGlobalFieldMappingsLogMode(), eventClass.FieldMappings...)
 		}
 
@@ -362,6 +383,77 @@ func getGlobalFieldMappingsLogMode() []*types.EventFieldMapping {
 	}
 }
 
+// getGlobalFieldMappingsAccountState returns global columns for account state tables, which are part of the primary
+// key in log mode so that a row is kept for every height at which an account changed
+func getGlobalFieldMappingsAccountState(logMode bool) []*types.EventFieldMapping {
+	return []*types.EventFieldMapping{
+		{
+			ColumnName: columns.ChainID,
+			Field:      types.ChainIDLabel,
+			Type:       types.EventFieldTypeString,
+			Primary:    logMode,
+		},
+		{
+			ColumnName: columns.Height,
+			Field:      types.BlockHeightLabel,
+			Type:       types.EventFieldTypeUInt,
+			Primary:    logMode,
+		},
+	}
+}
+
+// validateAccountClass checks that the field mappings of an AccountState class map the account address and otherwise
+// only account fields or storage variables of types that can be decoded
+func validateAccountClass(eventClass *types.EventClass) error {
+	if eventClass.DeleteMarkerField != "" {
+		return fmt.Errorf("no DeleteMarkerField allowed on AccountState class %v", eventClass)
+	}
+	hasAddress := false
+	for _, mapping := range eventClass.FieldMappings {
+		evmType := strings.ToLower(mapping.Type)
+		if mapping.Slot != "" {
+			_, err := mapping.StorageSlot()
+			if err != nil {
+				return err
+			}
+			size, err := types.StorageTypeSize(evmType)
+			if err != nil {
+				return fmt.Errorf("cannot decode storage variable %s: %v", mapping.Field, err)
+			}
+			if mapping.Offset < 0 || mapping.Offset+size > binary.Word256Bytes || (size == 0 && mapping.Offset != 0) {
+				return fmt.Errorf("storage variable %s of type %s does not fit at offset %d of its slot",
+					mapping.Field, mapping.Type, mapping.Offset)
+			}
+			continue
+		}
+		if mapping.Offset != 0 {
+			return fmt.Errorf("field %s has an Offset but no storage Slot", mapping.Field)
+		}
+		var ok bool
+		switch mapping.Field {
+		case types.AccountAddressLabel:
+			hasAddress = true
+			ok = evmType == types.EventFieldTypeAddress
+		case types.AccountBalanceLabel, types.AccountSequenceLabel, types.AccountPermissionsLabel:
+			ok = strings.HasPrefix(evmType, types.EventFieldTypeInt) || strings.HasPrefix(evmType, types.EventFieldTypeUInt)
+		case types.AccountRolesLabel:
+			ok = evmType == types.EventFieldTypeString
+		case types.AccountCodeHashLabel:
+			ok = strings.HasPrefix(evmType, types.EventFieldTypeBytes)
+		default:
+			return fmt.Errorf("field %s of AccountState class %s is not an account field so needs a storage Slot",
+				mapping.Field, eventClass.TableName)
+		}
+		if !ok {
+			return fmt.Errorf("account field %s cannot be mapped as type %s", mapping.Field, mapping.Type)
+		}
+	}
+	if !hasAddress {
+		return fmt.Errorf("AccountState class %s must map the %s field", eventClass.TableName, types.AccountAddressLabel)
+	}
+	return nil
+}
+
 // Merges tables a and b provided the intersection of their columns (by name) are identical
 func mergeTables(tables ...*types.SQLTable) (*types.SQLTable, error) {
 	table := &types.SQLTable{
@@ -329,3 +329,102 @@ func TestWithNoPrimaryKey(t *testing.T) {
 		require.Equal(t, c.Name == "name", c.Primary)
 	}
 }
+
+func TestAccountStateProjection(t *testing.T) {
+	tableName := "Tokens"
+	newSpec := func() types.ProjectionSpec {
+		return types.ProjectionSpec{
+			{
+				TableName:    tableName,
+				Filter:       "Address = '8A2D5DE6A0BD6CB3C0F6E9C4C4F6E94A3F9F2C8B'",
+				AccountState: true,
+				FieldMappings: []*types.EventFieldMapping{
+					{
+						Field:      types.AccountAddressLabel,
+						Type:       types.EventFieldTypeAddress,
+						ColumnName: "address",
+					},
+					{
+						Field:      types.AccountBalanceLabel,
+						Type:       "uint64",
+						ColumnName: "balance",
+					},
+					{
+						Field:      "owner",
+						Type:       types.EventFieldTypeAddress,
+						ColumnName: "owner",
+						Slot:       "0",
+					},
+					{
+						Field:      "paused",
+						Type:       types.EventFieldTypeBool,
+						ColumnName: "paused",
+						Slot:       "0",
+						Offset:     20,
+					},
+					{
+						Field:      "name",
+						Type:       types.EventFieldTypeString,
+						ColumnName: "name",
+						Slot:       "0x1",
+					},
+				},
+			},
+		}
+	}
+
+	projection, err := sqlsol.NewProjection(newSpec())
+	require.NoError(t, err)
+	// Without a primary key we keep a row for each address at each height
+	for _, c := range projection.Tables[tableName].Columns {
+		switch c.Name {
+		case columns.ChainID, columns.Height, "address":
+			require.True(t, c.Primary, c.Name)
+		default:
+			require.False(t, c.Primary, c.Name)
+		}
+	}
+	col, err := projection.GetColumn(tableName, "paused")
+	require.NoError(t, err)
+	require.Equal(t, types.SQLColumnTypeBool, col.Type)
+
+	spec := newSpec()
+	spec[0].FieldMappings[0].Primary = true
+	projection, err = sqlsol.NewProjection(spec)
+	require.NoError(t, err)
+	for _, c := range projection.Tables[tableName].Columns {
+		require.Equal(t, c.Name == "address", c.Primary, c.Name)
+	}
+
+	for name, mutate := range map[string]func(spec types.ProjectionSpec){
+		"no address": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings = spec[0].FieldMappings[1:]
+		},
+		"delete marker": func(spec types.ProjectionSpec) {
+			spec[0].DeleteMarkerField = "owner"
+		},
+		"not an account field": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings[2].Slot = ""
+		},
+		"account field type": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings[1].Type = types.EventFieldTypeString
+		},
+		"malformed slot": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings[2].Slot = "zero"
+		},
+		"overflowing offset": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings[3].Offset = 32
+		},
+		"offset of string": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings[4].Offset = 1
+		},
+		"unknown storage type": func(spec types.ProjectionSpec) {
+			spec[0].FieldMappings[3].Type = "uint7"
+		},
+	} {
+		spec := newSpec()
+		mutate(spec)
+		_, err := sqlsol.NewProjection(spec)
+		require.Error(t, err, name)
+	}
+}
@@ -31,11 +31,17 @@ func init() {
 func NewTestDB(t *testing.T, cfg *config.VentConfig) (*sqldb.SQLDB, func()) {
 	t.Helper()
 
-	if cfg.DBAdapter != types.SQLiteDB {
+	switch cfg.DBAdapter {
+	case types.PostgresDB:
 		if dbURL, ok := syscall.Getenv("DB_URL"); ok {
 			t.Logf("Using DB_URL '%s'", dbURL)
 			cfg.DBURL = dbURL
 		}
+	case types.MySQLDB:
+		if dbURL, ok := syscall.Getenv("MYSQL_DB_URL"); ok {
+			t.Logf("Using MYSQL_DB_URL '%s'", dbURL)
+			cfg.DBURL = dbURL
+		}
 	}
 
 	connection := types.SQLConnection{
@@ -59,7 +65,7 @@ func NewTestDB(t *testing.T, cfg *config.VentConfig) (*sqldb.SQLDB, func()) {
 			os.Remove(connection.DBURL + "-shm")
 			os.Remove(connection.DBURL + "-wal")
 		} else {
-			destroySchema(db, connection.DBSchema)
+			destroySchema(db, cfg.DBAdapter, connection.DBSchema)
 			db.Close()
 		}
 	}
@@ -92,9 +98,23 @@ func PostgresVentConfig(grpcAddress string) *config.VentConfig {
 	return cfg
 }
 
-func destroySchema(db *sqldb.SQLDB, dbSchema string) error {
+func MySQLVentConfig(grpcAddress string) *config.VentConfig {
+	cfg := config.DefaultVentConfig()
+	cfg.DBSchema = fmt.Sprintf("test_%s", randString(10))
+	cfg.DBAdapter = types.MySQLDB
+	cfg.DBURL = config.DefaultMySQLDBURL
+	cfg.GRPCAddr = grpcAddress
+	cfg.AnnounceEvery = time.Millisecond * 100
+	return cfg
+}
+
+func destroySchema(db *sqldb.SQLDB, dbAdapter, dbSchema string) error {
 	db.Log.InfoMsg("Dropping schema")
 	query := fmt.Sprintf("DROP SCHEMA %s CASCADE;", dbSchema)
+	if dbAdapter == types.MySQLDB {
+		// A MySQL schema is a database and dropping it drops everything in it
+		query = fmt.Sprintf("DROP DATABASE %s;", dbSchema)
+	}
 
 	db.Log.InfoMsg("Drop schema", "query", query)
 
@@ -1,8 +1,12 @@
 package types
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/alecthomas/jsonschema"
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/event/query"
 )
 
@@ -19,6 +23,15 @@ type EventClass struct {
 	TableName string
 	// Burrow event filter query in query peg grammar
 	Filter string
+	// Project the state of the accounts matching Filter (a query over account fields, as for ListAccounts), rather
+	// than events, into the table at each height at which they change. FieldMappings then map account fields and
+	// contract storage variables (those with a Slot).
+	AccountState bool `json:",omitempty"`
+	// Match Filter against the calls made in transactions, rather than their log events, mapping the arguments and
+	// return values of the called function (decoded by its ABI) by name or by position (as arg0, arg1, ...,
+	// return0, return1, ...). Filter can select calls by the 'Callee' contract and by 'Selector', the function
+	// selector in upper-case hex.
+	Calls bool `json:",omitempty"`
 	// The name of a solidity event field that when present indicates that the rest of the event should be interpreted
 	// as requesting a row deletion (rather than upsert) in the projection table.
 	DeleteMarkerField string `json:",omitempty"`
@@ -82,6 +95,21 @@ type EventFieldMapping struct {
 	// Notification channels on which submit (via a trigger) a payload that contains this column's new value (upsert) or
 	// old value (delete). The payload will contain all other values with the same channel set as a JSON object.
 	Notify []string `json:",omitempty"`
+	// For AccountState classes, the storage slot (decimal or 0x-prefixed hex, as reported in the solc storage layout)
+	// of the contract storage variable named by Field, which is decoded according to Type
+	Slot string `json:",omitempty"`
+	// The offset in bytes within Slot of a storage variable packed with others
+	Offset int `json:",omitempty"`
+}
+
+// StorageSlot parses Slot as a storage key
+func (evColumn *EventFieldMapping) StorageSlot() (binary.Word256, error) {
+	slot, ok := new(big.Int).SetString(evColumn.Slot, 0)
+	if !ok || slot.Sign() < 0 || slot.BitLen() > 256 {
+		return binary.Zero256, fmt.Errorf("storage slot '%s' of field %s is not a 256-bit unsigned integer",
+			evColumn.Slot, evColumn.Field)
+	}
+	return binary.LeftPadWord256(slot.Bytes()), nil
 }
 
 // Validate checks the structure of an EventFieldMapping
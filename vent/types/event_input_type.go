@@ -1,5 +1,11 @@
 package types
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Defined event input types - these are currently align with EVM types but they technically define a pair/mapping
 // of EVM type -> SQL type
 const (
@@ -10,3 +16,37 @@ const (
 	EventFieldTypeBool    = "bool"
 	EventFieldTypeString  = "string"
 )
+
+// StorageTypeSize returns the number of bytes a value of a solidity type occupies in contract storage, or zero for
+// the dynamically-sized string and bytes, which occupy a whole slot (and, if long, further slots).
+func StorageTypeSize(evmType string) (int, error) {
+	evmType = strings.ToLower(evmType)
+	switch {
+	case evmType == EventFieldTypeBool:
+		return 1, nil
+	case evmType == EventFieldTypeAddress:
+		return 20, nil
+	case evmType == EventFieldTypeString, evmType == EventFieldTypeBytes:
+		return 0, nil
+	case strings.HasPrefix(evmType, EventFieldTypeBytes):
+		n, err := strconv.Atoi(evmType[len(EventFieldTypeBytes):])
+		if err != nil || n < 1 || n > 32 {
+			return 0, fmt.Errorf("'%s' is not a storage type", evmType)
+		}
+		return n, nil
+	case strings.HasPrefix(evmType, EventFieldTypeUInt), strings.HasPrefix(evmType, EventFieldTypeInt):
+		bits := strings.TrimPrefix(evmType, EventFieldTypeInt)
+		if strings.HasPrefix(evmType, EventFieldTypeUInt) {
+			bits = strings.TrimPrefix(evmType, EventFieldTypeUInt)
+		}
+		if bits == "" {
+			return 32, nil
+		}
+		n, err := strconv.Atoi(bits)
+		if err != nil || n < 8 || n > 256 || n%8 != 0 {
+			return 0, fmt.Errorf("'%s' is not a storage type", evmType)
+		}
+		return n / 8, nil
+	}
+	return 0, fmt.Errorf("'%s' is not a storage type", evmType)
+}
@@ -4,4 +4,5 @@ package types
 const (
 	PostgresDB = "postgres"
 	SQLiteDB   = "sqlite"
+	MySQLDB    = "mysql"
 )
@@ -171,4 +171,19 @@ const (
 
 	// transaction related
 	TxTxHashLabel = "txHash"
+
+	// call related (for Calls event classes)
+	CallerLabel = "caller"
+	CalleeLabel = "callee"
+	// Prefixes of the positional names of call arguments and return values
+	CallArgPrefix    = "arg"
+	CallReturnPrefix = "return"
+
+	// account related (for AccountState event classes)
+	AccountAddressLabel     = "Address"
+	AccountBalanceLabel     = "Balance"
+	AccountSequenceLabel    = "Sequence"
+	AccountPermissionsLabel = "Permissions"
+	AccountRolesLabel       = "Roles"
+	AccountCodeHashLabel    = "CodeHash"
 )